@@ -0,0 +1,16 @@
+package v1
+
+const (
+	// AddressModeIP points the replicas and the sentinels at the IPs of the redis pods. A pod restarted
+	// with a new IP is a new redis for the sentinels.
+	AddressModeIP AddressMode = "ip"
+	// AddressModeHostname points the replicas and the sentinels at the DNS names of the redis pods
+	// behind their headless service, which stay the same when the pods are rescheduled. It needs
+	// redis 6.2 or later.
+	AddressModeHostname AddressMode = "hostname"
+)
+
+// HostnameMode tells if the redises and the sentinels address each other by DNS name
+func (r *RedisFailover) HostnameMode() bool {
+	return r.Spec.AddressMode == AddressModeHostname
+}
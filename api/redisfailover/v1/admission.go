@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	minSentinelNumber = 3
+)
+
+// ValidateCreate checks a new RedisFailover before it is admitted. On top of Validate, the settings the
+// operator can run with but that are not safe to start from are rejected.
+func (r *RedisFailover) ValidateCreate() error {
+	rf := r.DeepCopy()
+	if err := rf.Validate(); err != nil {
+		return err
+	}
+	return rf.validateSentinelNumber()
+}
+
+// ValidateUpdate checks a change of a RedisFailover before it is admitted. The fields the statefulsets
+// can't change are rejected, the checks of a new RF are only applied to the values changed so existing
+// ones can still be updated.
+func (r *RedisFailover) ValidateUpdate(old *RedisFailover) error {
+	rf := r.DeepCopy()
+	if err := rf.Validate(); err != nil {
+		return err
+	}
+	oldRF := old.DeepCopy()
+	oldRF.Default()
+
+	if rf.Spec.Sentinel.Replicas != oldRF.Spec.Sentinel.Replicas {
+		if err := rf.validateSentinelNumber(); err != nil {
+			return err
+		}
+	}
+
+	if rf.Spec.Redis.Port != oldRF.Spec.Redis.Port {
+		return fmt.Errorf("redis port can't be changed from %d to %d", oldRF.Spec.Redis.Port, rf.Spec.Redis.Port)
+	}
+	if from, to := oldRF.redisStorageClassName(), rf.redisStorageClassName(); !reflect.DeepEqual(from, to) {
+		return fmt.Errorf("redis storage class can't be changed from %s to %s", storageClassString(from), storageClassString(to))
+	}
+	if from, to := oldRF.Spec.Sentinel.ConfigStorage.StorageClassName, rf.Spec.Sentinel.ConfigStorage.StorageClassName; !reflect.DeepEqual(from, to) {
+		return fmt.Errorf("sentinel configStorage storage class can't be changed from %s to %s", storageClassString(from), storageClassString(to))
+	}
+	return nil
+}
+
+// validateSentinelNumber checks there are enough sentinels to keep a majority when one of them is
+// lost, and that they can't be split in two halves
+func (r *RedisFailover) validateSentinelNumber() error {
+	if !r.SentinelsAllowed() {
+		return nil
+	}
+	replicas := r.Spec.Sentinel.Replicas
+	if replicas < minSentinelNumber {
+		return fmt.Errorf("sentinel replicas can't be lower than %d, got %d", minSentinelNumber, replicas)
+	}
+	if replicas%2 == 0 {
+		return fmt.Errorf("sentinel replicas must be an odd number, got %d", replicas)
+	}
+	return nil
+}
+
+func (r *RedisFailover) redisStorageClassName() *string {
+	if r.Spec.Redis.Storage.PersistentVolumeClaim == nil {
+		return nil
+	}
+	return r.Spec.Redis.Storage.PersistentVolumeClaim.Spec.StorageClassName
+}
+
+func storageClassString(storageClass *string) string {
+	if storageClass == nil {
+		return "the default one"
+	}
+	return fmt.Sprintf("%q", *storageClass)
+}
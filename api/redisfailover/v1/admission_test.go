@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRedisFailover("test", &BootstrapSettings{Host: "127.0.0.1"})
+	rf.Spec.Redis.CustomConfig = []string{"maxmemory 1gb"}
+	rf.Default()
+
+	assert.Equal("6379", rf.Spec.BootstrapNode.Port)
+	assert.Equal(int32(defaultRedisPort), rf.Spec.Redis.Port)
+	assert.Equal(int32(defaultSentinelNumber), rf.Spec.Sentinel.Replicas)
+	assert.Equal(defaultSentinelCustomConfig, rf.Spec.Sentinel.CustomConfig)
+	// The config of the operator is not kept in the object
+	assert.Equal([]string{"maxmemory 1gb"}, rf.Spec.Redis.CustomConfig)
+}
+
+func TestValidateCreate(t *testing.T) {
+	tests := []struct {
+		name             string
+		sentinelReplicas int32
+		bootstrapNode    *BootstrapSettings
+		expectedError    string
+	}{
+		{
+			name: "Default sentinels",
+		},
+		{
+			name:             "Five sentinels",
+			sentinelReplicas: 5,
+		},
+		{
+			name:             "Even number of sentinels",
+			sentinelReplicas: 4,
+			expectedError:    "sentinel replicas must be an odd number, got 4",
+		},
+		{
+			name:             "Less than three sentinels",
+			sentinelReplicas: 1,
+			expectedError:    "sentinel replicas can't be lower than 3, got 1",
+		},
+		{
+			name:             "Sentinels not run while bootstrapping",
+			sentinelReplicas: 2,
+			bootstrapNode:    &BootstrapSettings{Host: "127.0.0.1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRedisFailover("test", test.bootstrapNode)
+			rf.Spec.Sentinel.Replicas = test.sentinelReplicas
+			err := rf.ValidateCreate()
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else if assert.Error(err) {
+				assert.Equal(test.expectedError, err.Error())
+			}
+			// The object admitted is not changed by the validation
+			assert.Empty(rf.Spec.Redis.CustomConfig)
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	storageClass := "fast"
+	otherStorageClass := "slow"
+
+	tests := []struct {
+		name          string
+		old           func(rf *RedisFailover)
+		new           func(rf *RedisFailover)
+		expectedError string
+	}{
+		{
+			name: "Replicas changed",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Redis.Replicas = 5
+			},
+		},
+		{
+			name: "Port given with its default value",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Redis.Port = defaultRedisPort
+			},
+		},
+		{
+			name: "Port changed",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Redis.Port = 6380
+			},
+			expectedError: "redis port can't be changed from 6379 to 6380",
+		},
+		{
+			name: "Redis storage class changed",
+			old: func(rf *RedisFailover) {
+				rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}}
+			},
+			new: func(rf *RedisFailover) {
+				rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &otherStorageClass}}
+			},
+			expectedError: `redis storage class can't be changed from "fast" to "slow"`,
+		},
+		{
+			name: "Sentinel storage class set",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Sentinel.ConfigStorage.StorageClassName = &storageClass
+			},
+			expectedError: `sentinel configStorage storage class can't be changed from the default one to "fast"`,
+		},
+		{
+			name: "Even number of sentinels kept",
+			old: func(rf *RedisFailover) {
+				rf.Spec.Sentinel.Replicas = 2
+			},
+			new: func(rf *RedisFailover) {
+				rf.Spec.Sentinel.Replicas = 2
+				rf.Spec.Redis.Replicas = 5
+			},
+		},
+		{
+			name: "Sentinels scaled to an even number",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Sentinel.Replicas = 4
+			},
+			expectedError: "sentinel replicas must be an odd number, got 4",
+		},
+		{
+			name: "Invalid values",
+			new: func(rf *RedisFailover) {
+				rf.Spec.Redis.CustomConfig = []string{"maxmemory"}
+			},
+			expectedError: `redis customConfig "maxmemory" is malformed, must be a parameter and a value`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			old := generateRedisFailover("test", nil)
+			if test.old != nil {
+				test.old(old)
+			}
+			rf := old.DeepCopy()
+			if test.new != nil {
+				test.new(rf)
+			}
+			err := rf.ValidateUpdate(old)
+
+			if test.expectedError == "" {
+				assert.NoError(err)
+			} else if assert.Error(err) {
+				assert.Equal(test.expectedError, err.Error())
+			}
+		})
+	}
+}
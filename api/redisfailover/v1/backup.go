@@ -0,0 +1,23 @@
+package v1
+
+// Phases of a RedisFailoverBackup
+const (
+	// BackupPending is set until a replica is chosen to take the backup from.
+	BackupPending BackupPhase = "Pending"
+	// BackupSaving waits for the BGSAVE of the replica to finish.
+	BackupSaving BackupPhase = "Saving"
+	// BackupUploading waits for the agent of the replica to upload the RDB file.
+	BackupUploading BackupPhase = "Uploading"
+	BackupCompleted BackupPhase = "Completed"
+	BackupFailed    BackupPhase = "Failed"
+)
+
+// BackupsEnabled tells if the redises run the backup agent.
+func (r *RedisFailover) BackupsEnabled() bool {
+	return r.Spec.Backup != nil
+}
+
+// Finished tells if the backup completed or failed, in both cases nothing else is done with it.
+func (b *RedisFailoverBackup) Finished() bool {
+	return b.Status.Phase == BackupCompleted || b.Status.Phase == BackupFailed
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverBackup represents a backup of the data of a Redis failover
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
+// +kubebuilder:printcolumn:name="REDISFAILOVER",type="string",JSONPath=".spec.redisFailoverName"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".status.location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=redisfailoverbackup,path=redisfailoverbackups,shortName=rfb,scope=Namespaced
+// +kubebuilder:subresource:status
+type RedisFailoverBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RedisFailoverBackupSpec   `json:"spec"`
+	Status            RedisFailoverBackupStatus `json:"status,omitempty"`
+}
+
+// RedisFailoverBackupSpec represents a Redis failover backup spec
+type RedisFailoverBackupSpec struct {
+	// RedisFailoverName is the Redis failover of the same namespace to backup, it must have backups enabled
+	RedisFailoverName string `json:"redisFailoverName"`
+}
+
+// RedisFailoverBackupStatus contains the progress and the result of a backup
+type RedisFailoverBackupStatus struct {
+	Phase          BackupPhase  `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	Node           string       `json:"node,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	Location       string       `json:"location,omitempty"`
+	Size           int64        `json:"size,omitempty"`
+	Checksum       string       `json:"checksum,omitempty"`
+}
+
+// BackupPhase is the step a backup is at
+type BackupPhase string
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverBackupList represents a Redis failover backup list
+type RedisFailoverBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RedisFailoverBackup `json:"items"`
+}
+
+// BackupSettings enables the backups of a Redis failover. An agent running next to every redis
+// uploads the RDB file of the replica chosen for each backup to the storage.
+type BackupSettings struct {
+	// Schedule in cron format to create backups at, no backup is created on its own when empty
+	Schedule string        `json:"schedule,omitempty"`
+	Storage  BackupStorage `json:"storage"`
+	Image    string        `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy            `json:"imagePullPolicy,omitempty"`
+	Resources       *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// BackupStorage is where the RDB files are uploaded to
+type BackupStorage struct {
+	S3 *S3BackupStorage `json:"s3,omitempty"`
+}
+
+// S3BackupStorage is a bucket of an S3-compatible object storage, like AWS S3 or MinIO
+type S3BackupStorage struct {
+	// Endpoint of the object storage, e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+	Region   string `json:"region,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	// Prefix of the keys of the uploaded files, they are named <prefix><namespace>/<redisfailover>/<backup>.rdb
+	Prefix string `json:"prefix,omitempty"`
+	// CredentialsSecret holds the accessKeyId and secretAccessKey keys
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecret string `json:"credentialsSecret"`
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ClusterSlots is the number of hash slots the key space of a Redis cluster is split into
+const ClusterSlots = 16384
+
+const defaultClusterShards = 3
+
+// Phases of a RedisCluster
+const (
+	// ClusterCreating is set until every slot is served by a master.
+	ClusterCreating RedisClusterPhase = "Creating"
+	ClusterReady    RedisClusterPhase = "Ready"
+	// ClusterResharding is set while slots are moved after the number of shards changed.
+	ClusterResharding RedisClusterPhase = "Resharding"
+	// ClusterDegraded is set when a shard has no master or misses replicas.
+	ClusterDegraded RedisClusterPhase = "Degraded"
+)
+
+// Validate set the values by default if not defined and checks if the values given are valid
+func (c *RedisCluster) Validate() error {
+	if len(c.Name) > maxNameLength {
+		return fmt.Errorf("name length can't be higher than %d", maxNameLength)
+	}
+
+	if c.Spec.Shards == 0 {
+		c.Spec.Shards = defaultClusterShards
+	}
+	if c.Spec.Shards < 0 || c.Spec.Shards > ClusterSlots {
+		return fmt.Errorf("shards must be between 1 and %d", ClusterSlots)
+	}
+	if c.Spec.ReplicasPerShard < 0 {
+		return errors.New("replicasPerShard can't be negative")
+	}
+
+	if len(c.Spec.Auth.Users) > 0 {
+		return errors.New("auth users are not supported on redis clusters")
+	}
+
+	if c.Spec.Redis.Image == "" {
+		c.Spec.Redis.Image = defaultImage
+	}
+	if c.Spec.Redis.Port <= 0 {
+		c.Spec.Redis.Port = defaultRedisPort
+	}
+
+	return nil
+}
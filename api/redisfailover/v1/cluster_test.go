@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClusterValidate(t *testing.T) {
+	tests := []struct {
+		name             string
+		rcName           string
+		shards           int32
+		replicasPerShard int32
+		users            []RedisUser
+		expectedError    string
+		expectedShards   int32
+	}{
+		{
+			name:           "populates default values",
+			rcName:         "test",
+			expectedShards: defaultClusterShards,
+		},
+		{
+			name:             "keeps the requested shards",
+			rcName:           "test",
+			shards:           5,
+			replicasPerShard: 2,
+			expectedShards:   5,
+		},
+		{
+			name:          "errors on too long of name",
+			rcName:        "some-super-absurdely-unnecessarily-long-name-that-will-most-definitely-fail",
+			expectedError: "name length can't be higher than 48",
+		},
+		{
+			name:          "errors on more shards than slots",
+			rcName:        "test",
+			shards:        ClusterSlots + 1,
+			expectedError: "shards must be between 1 and 16384",
+		},
+		{
+			name:             "errors on negative replicas",
+			rcName:           "test",
+			replicasPerShard: -1,
+			expectedError:    "replicasPerShard can't be negative",
+		},
+		{
+			name:          "errors on auth users",
+			rcName:        "test",
+			users:         []RedisUser{{Name: "app", SecretPath: "app"}},
+			expectedError: "auth users are not supported on redis clusters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rc := &RedisCluster{}
+			rc.Name = test.rcName
+			rc.Spec.Shards = test.shards
+			rc.Spec.ReplicasPerShard = test.replicasPerShard
+			rc.Spec.Auth.Users = test.users
+
+			err := rc.Validate()
+
+			if test.expectedError != "" {
+				assert.EqualError(err, test.expectedError)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expectedShards, rc.Spec.Shards)
+			assert.Equal(test.replicasPerShard, rc.Spec.ReplicasPerShard)
+			assert.Equal(defaultImage, rc.Spec.Redis.Image)
+			assert.Equal(int32(defaultRedisPort), rc.Spec.Redis.Port)
+		})
+	}
+}
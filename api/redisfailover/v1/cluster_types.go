@@ -0,0 +1,86 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisCluster represents a sharded Redis Cluster, with the key space split between the masters of the shards
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
+// +kubebuilder:printcolumn:name="SHARDS",type="integer",JSONPath=".spec.shards"
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".spec.replicasPerShard"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=rediscluster,path=redisclusters,shortName=rdc,scope=Namespaced
+// +kubebuilder:subresource:status
+type RedisCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RedisClusterSpec   `json:"spec"`
+	Status            RedisClusterStatus `json:"status,omitempty"`
+}
+
+// RedisClusterSpec represents a Redis cluster spec
+type RedisClusterSpec struct {
+	// Shards is the number of masters the slots are split between
+	Shards int32 `json:"shards,omitempty"`
+	// ReplicasPerShard is the number of replicas following the master of every shard
+	ReplicasPerShard int32                `json:"replicasPerShard,omitempty"`
+	Redis            RedisClusterSettings `json:"redis,omitempty"`
+	Auth             AuthSettings         `json:"auth,omitempty"`
+}
+
+// RedisClusterSettings defines the specification of the redis of every shard
+type RedisClusterSettings struct {
+	Image                    string                        `json:"image,omitempty"`
+	ImagePullPolicy          corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	Port                     int32                         `json:"port,omitempty"`
+	Resources                corev1.ResourceRequirements   `json:"resources,omitempty"`
+	CustomConfig             []string                      `json:"customConfig,omitempty"`
+	Storage                  RedisStorage                  `json:"storage,omitempty"`
+	Affinity                 *corev1.Affinity              `json:"affinity,omitempty"`
+	SecurityContext          *corev1.PodSecurityContext    `json:"securityContext,omitempty"`
+	ContainerSecurityContext *corev1.SecurityContext       `json:"containerSecurityContext,omitempty"`
+	ImagePullSecrets         []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	Tolerations              []corev1.Toleration           `json:"tolerations,omitempty"`
+	NodeSelector             map[string]string             `json:"nodeSelector,omitempty"`
+	PodAnnotations           map[string]string             `json:"podAnnotations,omitempty"`
+	ServiceAnnotations       map[string]string             `json:"serviceAnnotations,omitempty"`
+	PriorityClassName        string                        `json:"priorityClassName,omitempty"`
+	ServiceAccountName       string                        `json:"serviceAccountName,omitempty"`
+}
+
+// RedisClusterStatus contains the state of the shards of a Redis cluster
+type RedisClusterStatus struct {
+	ObservedGeneration int64             `json:"observedGeneration,omitempty"`
+	Phase              RedisClusterPhase `json:"phase,omitempty"`
+	Message            string            `json:"message,omitempty"`
+	Shards             []ShardStatus     `json:"shards,omitempty"`
+}
+
+// RedisClusterPhase summarizes the state of a Redis cluster
+type RedisClusterPhase string
+
+// ShardStatus is the state of a shard of a Redis cluster
+type ShardStatus struct {
+	Name string `json:"name"`
+	// Master is the pod working as master of the shard
+	Master string `json:"master,omitempty"`
+	// Slots is the number of slots the master of the shard serves
+	Slots int32 `json:"slots"`
+	// Replicas is the number of pods replicating from the master of the shard
+	Replicas int32 `json:"replicas"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisClusterList represents a Redis cluster list
+type RedisClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RedisCluster `json:"items"`
+}
@@ -0,0 +1,315 @@
+package v1
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const validationMarkerPrefix = "+kubebuilder:validation:"
+
+// The CRD is generated in the manifests, and copied to the chart and the kustomize base
+var redisFailoverCRDFiles = []string{
+	"../../../manifests/databases.spotahome.com_redisfailovers.yaml",
+	"../../../manifests/kustomize/base/databases.spotahome.com_redisfailovers.yaml",
+	"../../../charts/redisoperator/crds/databases.spotahome.com_redisfailovers.yaml",
+}
+
+// validationMarker is a kubebuilder validation marker of the types, at the path of the schema it applies to
+type validationMarker struct {
+	path  []string
+	name  string
+	value string
+}
+
+func (m validationMarker) String() string {
+	return strings.Join(m.path, ".") + " " + m.name + "=" + m.value
+}
+
+// The packages of the versions of the RedisFailover, by the name they are imported with
+var redisFailoverPackages = map[string]string{
+	"v1":              ".",
+	"redisfailoverv1": ".",
+	"v2":              "../v2",
+}
+
+// TestRedisFailoverCRDValidations checks the CRDs were generated again after the validation markers of
+// the types changed: every field and marker is in the schemas, and they have no CEL rule the types
+// don't declare.
+func TestRedisFailoverCRDValidations(t *testing.T) {
+	types := parseTypes(t)
+	for _, version := range []string{"v1", "v2"} {
+		for _, file := range redisFailoverCRDFiles {
+			t.Run(version+"/"+file, func(t *testing.T) {
+				testCRDValidations(t, types, version, file)
+			})
+		}
+	}
+}
+
+func testCRDValidations(t *testing.T, types map[string]typeDecl, version, file string) {
+	markers, fields := walkTypes(types, version)
+	require.NotEmpty(t, markers)
+
+	assert := assert.New(t)
+	root := loadRedisFailoverSchema(t, file, version)
+	for _, field := range fields {
+		assert.NotNil(getSchemaNode(root, field), "no schema for %s", strings.Join(field, "."))
+	}
+
+	rules := map[string]bool{}
+	for _, m := range markers {
+		node := getSchemaNode(root, m.path)
+		if !assert.NotNil(node, "no schema for %s", m) {
+			continue
+		}
+		switch m.name {
+		case "Minimum":
+			assert.Equal(parseFloat(t, m.value), node.Minimum, m.String())
+		case "Maximum":
+			assert.Equal(parseFloat(t, m.value), node.Maximum, m.String())
+		case "MinLength":
+			assert.Equal(parseInt(t, m.value), node.MinLength, m.String())
+		case "MaxItems":
+			assert.Equal(parseInt(t, m.value), node.MaxItems, m.String())
+		case "Pattern":
+			assert.Equal(strings.Trim(m.value, "`"), node.Pattern, m.String())
+		case "Enum":
+			assert.Equal(strings.Split(m.value, ";"), getEnum(t, node), m.String())
+		case "Required":
+			parent := getSchemaNode(root, m.path[:len(m.path)-1])
+			assert.Contains(parent.Required, m.path[len(m.path)-1], m.String())
+		case "XValidation":
+			rule := parseXValidation(t, m.value)
+			rules[strings.Join(m.path, ".")+" "+rule.Rule] = true
+			assert.Contains(node.XValidations, rule, m.String())
+		default:
+			t.Errorf("marker %s not checked", m)
+		}
+	}
+
+	walkSchema(root, nil, func(path []string, node *apiextensionsv1.JSONSchemaProps) {
+		for _, rule := range node.XValidations {
+			assert.True(rules[strings.Join(path, ".")+" "+rule.Rule], "rule %q of %s has no marker", rule.Rule, strings.Join(path, "."))
+		}
+	})
+}
+
+// typeDecl is a type of the API packages, with the doc comment of its declaration
+type typeDecl struct {
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+}
+
+// parseTypes returns the types of the packages of the RedisFailover versions, by their package
+// directory and name
+func parseTypes(t *testing.T) map[string]typeDecl {
+	types := map[string]typeDecl{}
+	for _, dir := range []string{".", "../v2"} {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, parser.ParseComments)
+		require.NoError(t, err)
+
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				for _, decl := range file.Decls {
+					gd, ok := decl.(*ast.GenDecl)
+					if !ok || gd.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range gd.Specs {
+						ts := spec.(*ast.TypeSpec)
+						doc := ts.Doc
+						if doc == nil && len(gd.Specs) == 1 {
+							doc = gd.Doc
+						}
+						types[dir+"/"+ts.Name.Name] = typeDecl{spec: ts, doc: doc}
+					}
+				}
+			}
+		}
+	}
+	return types
+}
+
+// walkTypes returns the validation markers and the paths of the fields of the RedisFailover spec of
+// the version given, walking the fields from RedisFailoverSpec as the generator does
+func walkTypes(types map[string]typeDecl, version string) ([]validationMarker, [][]string) {
+	markers := []validationMarker{}
+	fields := [][]string{}
+	var walkType func(path []string, name string)
+	walkType = func(path []string, name string) {
+		markers = append(markers, getValidationMarkers(path, types[name].doc)...)
+		st, ok := types[name].spec.Type.(*ast.StructType)
+		if !ok {
+			return
+		}
+		dir := name[:strings.LastIndex(name, "/")]
+		for _, field := range st.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			jsonName := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]
+			if jsonName == "" || jsonName == "-" {
+				continue
+			}
+			fieldPath := append(append([]string{}, path...), jsonName)
+			fields = append(fields, fieldPath)
+			markers = append(markers, getValidationMarkers(fieldPath, field.Doc)...)
+
+			fieldType := field.Type
+			if star, ok := fieldType.(*ast.StarExpr); ok {
+				fieldType = star.X
+			}
+			if array, ok := fieldType.(*ast.ArrayType); ok {
+				fieldPath = append(fieldPath, "[]")
+				fieldType = array.Elt
+			}
+			typeName := ""
+			switch ft := fieldType.(type) {
+			case *ast.Ident:
+				typeName = dir + "/" + ft.Name
+			case *ast.SelectorExpr:
+				if pkg, ok := ft.X.(*ast.Ident); ok && redisFailoverPackages[pkg.Name] != "" {
+					typeName = redisFailoverPackages[pkg.Name] + "/" + ft.Sel.Name
+				}
+			}
+			if _, ok := types[typeName]; ok {
+				walkType(fieldPath, typeName)
+			}
+		}
+	}
+	walkType([]string{"spec"}, redisFailoverPackages[version]+"/RedisFailoverSpec")
+	return markers, fields
+}
+
+func getValidationMarkers(path []string, doc *ast.CommentGroup) []validationMarker {
+	if doc == nil {
+		return nil
+	}
+	markers := []validationMarker{}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, validationMarkerPrefix) {
+			continue
+		}
+		text = strings.TrimPrefix(text, validationMarkerPrefix)
+		markerPath := path
+		if strings.HasPrefix(text, "items:") {
+			text = strings.TrimPrefix(text, "items:")
+			markerPath = append(append([]string{}, path...), "[]")
+		}
+		name, value, _ := strings.Cut(text, "=")
+		if args, ok := strings.CutPrefix(text, "XValidation:"); ok {
+			name, value = "XValidation", args
+		}
+		markers = append(markers, validationMarker{path: markerPath, name: name, value: value})
+	}
+	return markers
+}
+
+// parseXValidation parses the rule="...",message="..." arguments of an XValidation marker
+func parseXValidation(t *testing.T, args string) apiextensionsv1.ValidationRule {
+	rule := apiextensionsv1.ValidationRule{}
+	for args != "" {
+		key, rest, ok := strings.Cut(args, "=")
+		require.True(t, ok, "XValidation arguments %q not valid", args)
+		quoted, err := strconv.QuotedPrefix(rest)
+		require.NoError(t, err)
+		value, err := strconv.Unquote(quoted)
+		require.NoError(t, err)
+		switch key {
+		case "rule":
+			rule.Rule = value
+		case "message":
+			rule.Message = value
+		default:
+			t.Fatalf("XValidation argument %s not known", key)
+		}
+		args = strings.TrimPrefix(rest[len(quoted):], ",")
+	}
+	return rule
+}
+
+func loadRedisFailoverSchema(t *testing.T, file, version string) *apiextensionsv1.JSONSchemaProps {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal(data, crd))
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return v.Schema.OpenAPIV3Schema
+		}
+	}
+	t.Fatalf("version %s not found", version)
+	return nil
+}
+
+// getSchemaNode returns the schema of the path given, "[]" being the items of an array
+func getSchemaNode(node *apiextensionsv1.JSONSchemaProps, path []string) *apiextensionsv1.JSONSchemaProps {
+	for _, name := range path {
+		if name == "[]" {
+			if node.Items == nil {
+				return nil
+			}
+			node = node.Items.Schema
+		} else {
+			child, ok := node.Properties[name]
+			if !ok {
+				return nil
+			}
+			node = &child
+		}
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func walkSchema(node *apiextensionsv1.JSONSchemaProps, path []string, fn func(path []string, node *apiextensionsv1.JSONSchemaProps)) {
+	fn(path, node)
+	for name, child := range node.Properties {
+		child := child
+		walkSchema(&child, append(append([]string{}, path...), name), fn)
+	}
+	if node.Items != nil && node.Items.Schema != nil {
+		walkSchema(node.Items.Schema, append(append([]string{}, path...), "[]"), fn)
+	}
+}
+
+func getEnum(t *testing.T, node *apiextensionsv1.JSONSchemaProps) []string {
+	values := []string{}
+	for _, raw := range node.Enum {
+		var value string
+		require.NoError(t, json.Unmarshal(raw.Raw, &value))
+		values = append(values, value)
+	}
+	return values
+}
+
+func parseFloat(t *testing.T, value string) *float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	require.NoError(t, err)
+	return &f
+}
+
+func parseInt(t *testing.T, value string) *int64 {
+	i, err := strconv.ParseInt(value, 10, 64)
+	require.NoError(t, err)
+	return &i
+}
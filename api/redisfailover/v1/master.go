@@ -1,5 +1,22 @@
 package v1
 
+const (
+	// MasterElectionOldest promotes the oldest redis pod
+	MasterElectionOldest MasterElectionStrategy = "oldest"
+	// MasterElectionHighestOffset promotes the redis holding the most data, the one with the highest
+	// replication offset. Pods with a replica-priority of 0 are never promoted, and the oldest pod wins
+	// between redises with the same offset.
+	MasterElectionHighestOffset MasterElectionStrategy = "highestOffset"
+)
+
+const (
+	// SplitBrainResolutionManual only reports the masters, they have to be fixed by hand
+	SplitBrainResolutionManual SplitBrainResolutionMode = "manual"
+	// SplitBrainResolutionAutomatic keeps the master the sentinels agree on, or the one with the highest
+	// replication offset, and makes the other masters replicas of it. The writes they received are lost.
+	SplitBrainResolutionAutomatic SplitBrainResolutionMode = "automatic"
+)
+
 func (r *RedisFailover) MasterName() string {
 	if r.Spec.Sentinel.DisableMyMaster {
 		return r.Name
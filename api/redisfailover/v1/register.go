@@ -19,6 +19,14 @@ const (
 	RFName       = "redisfailover"
 	RFNamePlural = "redisfailovers"
 	RFScope      = apiextensionsv1.NamespaceScoped
+
+	RFBKind       = "RedisFailoverBackup"
+	RFBName       = "redisfailoverbackup"
+	RFBNamePlural = "redisfailoverbackups"
+
+	RCKind       = "RedisCluster"
+	RCName       = "rediscluster"
+	RCNamePlural = "redisclusters"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -49,6 +57,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&RedisFailover{},
 		&RedisFailoverList{},
+		&RedisFailoverBackup{},
+		&RedisFailoverBackupList{},
+		&RedisCluster{},
+		&RedisClusterList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,33 @@
+package v1
+
+import "path"
+
+// Phases of the restore of the data on creation
+const (
+	// RestoreRestoring is set while the restored pod is not the master yet.
+	RestoreRestoring RestorePhase = "Restoring"
+	// RestoreCompleted is set once the restored pod was promoted.
+	RestoreCompleted RestorePhase = "Completed"
+	// RestoreSkipped is set when the restore was requested on an existing failover.
+	RestoreSkipped RestorePhase = "Skipped"
+)
+
+// RestoreVolumeMountPath is where the volume given as restore source is mounted.
+const RestoreVolumeMountPath = "/restore"
+
+// Restoring tells if the failover waits for the restored pod to become the master.
+func (r *RedisFailover) Restoring() bool {
+	return r.Status.Restore != nil && r.Status.Restore.Phase == RestoreRestoring
+}
+
+// RestoreApplied tells if the redis pods restore the data, which is the case from the creation of the
+// failover until the restored pod is promoted, as long as the restore stays in the spec. The restore is
+// then removed from the pods, which releases the claim given as source.
+func (r *RedisFailover) RestoreApplied() bool {
+	return r.Spec.Restore != nil && r.Restoring() && r.Status.Restore.Source != ""
+}
+
+// VolumeSourceURL returns the location of the RDB file given in a volume, as seen from the pods.
+func (r *RestoreSettings) VolumeSourceURL() string {
+	return "file://" + path.Join(RestoreVolumeMountPath, r.PersistentVolumeClaim.Path)
+}
@@ -0,0 +1,54 @@
+package v1
+
+import "time"
+
+// ConfirmPasswordRotationAnnotation tells the operator the clients moved to the new password, so the
+// previous one is removed without waiting for the end of the grace period. The operator removes the
+// annotation once the rotation completed.
+const ConfirmPasswordRotationAnnotation = "redis-failover.freshworks.com/confirm-password-rotation"
+
+// defaultPasswordRotationGracePeriod is how long both passwords are accepted when the spec does not tell
+const defaultPasswordRotationGracePeriod = 5 * time.Minute
+
+// Phases of a rotation of the auth password
+const (
+	// PasswordRotationAddingPassword adds the new password to the default user, next to the old one.
+	PasswordRotationAddingPassword PasswordRotationPhase = "AddingPassword"
+	// PasswordRotationUpdatingClients moves masterauth and the sentinels auth-pass to the new password,
+	// then waits for the clients to move to it until the grace period ends or the rotation is confirmed.
+	PasswordRotationUpdatingClients PasswordRotationPhase = "UpdatingClients"
+	// PasswordRotationRemovingPassword removes the old password from the default user.
+	PasswordRotationRemovingPassword PasswordRotationPhase = "RemovingPassword"
+	// PasswordRotationCompleted is set once only the new password is accepted.
+	PasswordRotationCompleted PasswordRotationPhase = "Completed"
+)
+
+// UsesAuthSecret tells if the secret holds the password of the default user or of one of the users
+// defined in the spec.
+func (r *RedisFailover) UsesAuthSecret(name string) bool {
+	if name == "" {
+		return false
+	}
+	if r.Spec.Auth.SecretPath == name {
+		return true
+	}
+	for _, user := range r.Spec.Auth.Users {
+		if user.SecretPath == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordRotationGracePeriod returns how long both passwords are accepted during a rotation.
+func (r *RedisFailover) PasswordRotationGracePeriod() time.Duration {
+	if r.Spec.Auth.PasswordRotationGracePeriodSeconds > 0 {
+		return time.Duration(r.Spec.Auth.PasswordRotationGracePeriodSeconds) * time.Second
+	}
+	return defaultPasswordRotationGracePeriod
+}
+
+// PasswordRotationConfirmed tells if the clients were confirmed to use the new password.
+func (r *RedisFailover) PasswordRotationConfirmed() bool {
+	return r.Annotations != nil && r.Annotations[ConfirmPasswordRotationAnnotation] == "true"
+}
@@ -0,0 +1,20 @@
+package v1
+
+// Phases of the removal of the redis pods after the replicas were lowered
+const (
+	// ScaleDownMovingMaster is set while the pods are kept for the master to be moved off the ones removed.
+	ScaleDownMovingMaster ScaleDownPhase = "MovingMaster"
+	// ScaleDownResettingSentinels is set once the pods are removed, while the sentinels are reset one at
+	// a time to forget them.
+	ScaleDownResettingSentinels ScaleDownPhase = "ResettingSentinels"
+)
+
+// ScaleDownKeepsPods tells if the redis pods to remove are kept until the master is moved off them.
+func (r *RedisFailover) ScaleDownKeepsPods() bool {
+	return r.Status.ScaleDown != nil && r.Status.ScaleDown.Phase == ScaleDownMovingMaster
+}
+
+// ScaleDownResetsSentinels tells if the sentinels are reset to forget the redis pods removed.
+func (r *RedisFailover) ScaleDownResetsSentinels() bool {
+	return r.Status.ScaleDown != nil && r.Status.ScaleDown.Phase == ScaleDownResettingSentinels
+}
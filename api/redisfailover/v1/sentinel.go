@@ -0,0 +1,24 @@
+package v1
+
+const (
+	// SentinelWorkloadDeployment runs the sentinels as a deployment. A restarted sentinel starts
+	// with a new id and has to learn the other sentinels again.
+	SentinelWorkloadDeployment SentinelWorkloadType = "Deployment"
+	// SentinelWorkloadStatefulSet runs the sentinels as a statefulset with their config on a volume,
+	// so a restarted sentinel keeps its id, the sentinels it knew and the current epoch.
+	SentinelWorkloadStatefulSet SentinelWorkloadType = "StatefulSet"
+)
+
+// SentinelStatefulSet tells if the sentinels run as a statefulset
+func (r *RedisFailover) SentinelStatefulSet() bool {
+	return r.Spec.Sentinel.WorkloadType == SentinelWorkloadStatefulSet
+}
+
+// SentinelPods returns the number of sentinels to run: the requested replicas, and the ones still
+// running on the previous workload while the sentinels are moved to the one requested.
+func (r *RedisFailover) SentinelPods() int32 {
+	if r.Status.SentinelMigration != nil {
+		return r.Spec.Sentinel.Replicas + r.Status.SentinelMigration.Sentinels
+	}
+	return r.Spec.Sentinel.Replicas
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinelPods(t *testing.T) {
+	tests := []struct {
+		name        string
+		migration   *SentinelMigrationStatus
+		expectation int32
+	}{
+		{
+			name:        "no migration",
+			expectation: 3,
+		},
+		{
+			name:        "migration from a deployment",
+			migration:   &SentinelMigrationStatus{From: SentinelWorkloadDeployment, Sentinels: 2},
+			expectation: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := &RedisFailover{
+				Spec:   RedisFailoverSpec{Sentinel: SentinelSettings{Replicas: 3}},
+				Status: RedisFailoverStatus{SentinelMigration: test.migration},
+			}
+			assert.Equal(t, test.expectation, rf.SentinelPods())
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases of a RedisFailover
+const (
+	PhasePending       RedisFailoverPhase = "Pending"
+	PhaseBootstrapping RedisFailoverPhase = "Bootstrapping"
+	PhaseReady         RedisFailoverPhase = "Ready"
+	PhaseDegraded      RedisFailoverPhase = "Degraded"
+)
+
+// Condition types of a RedisFailover
+const (
+	// ConditionReady is true when the failover has converged: one master, all replicas
+	// in sync and every sentinel agreeing on the master.
+	ConditionReady = "Ready"
+	// ConditionMasterElected is true when exactly one redis node works as master.
+	ConditionMasterElected = "MasterElected"
+	// ConditionSentinelsConsistent is true when all sentinels monitor the same master
+	// and know the expected number of sentinels and slaves.
+	ConditionSentinelsConsistent = "SentinelsConsistent"
+	// ConditionDegraded is true when the last reconcile failed or not all pods are ready.
+	ConditionDegraded = "Degraded"
+)
+
+// SetStatusCondition adds or updates the condition of the given type, keeping the
+// transition time when the status does not change.
+func (r *RedisFailover) SetStatusCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&r.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: r.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// IsStatusConditionTrue returns true if the condition of the given type is present and true.
+func (r *RedisFailover) IsStatusConditionTrue(conditionType string) bool {
+	return meta.IsStatusConditionTrue(r.Status.Conditions, conditionType)
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &RedisFailover{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	assert.False(rf.IsStatusConditionTrue(ConditionReady))
+
+	rf.SetStatusCondition(ConditionReady, metav1.ConditionFalse, "NotConverged", "")
+	assert.False(rf.IsStatusConditionTrue(ConditionReady))
+	assert.Len(rf.Status.Conditions, 1)
+	assert.Equal(int64(2), rf.Status.Conditions[0].ObservedGeneration)
+
+	transition := metav1.Unix(0, 0)
+	rf.Status.Conditions[0].LastTransitionTime = transition
+
+	// Same status keeps the transition time
+	rf.SetStatusCondition(ConditionReady, metav1.ConditionFalse, "NotConverged", "still waiting")
+	assert.Equal(transition, rf.Status.Conditions[0].LastTransitionTime)
+	assert.Equal("still waiting", rf.Status.Conditions[0].Message)
+
+	// Status change updates it
+	rf.SetStatusCondition(ConditionReady, metav1.ConditionTrue, "Converged", "")
+	assert.True(rf.IsStatusConditionTrue(ConditionReady))
+	assert.NotEqual(transition, rf.Status.Conditions[0].LastTransitionTime)
+	assert.Len(rf.Status.Conditions, 1)
+}
@@ -0,0 +1,29 @@
+package v1
+
+import "strings"
+
+// SwitchoverAnnotation requests the operator to move the master to the redis pod named in its value.
+// The operator removes the annotation once the switchover is done.
+const SwitchoverAnnotation = "redis-failover.freshworks.com/switchover-to"
+
+// Results of a planned switchover
+const (
+	// SwitchoverInProgress is set once a sentinel accepted the failover, until all of them monitor the target
+	SwitchoverInProgress SwitchoverResult = "InProgress"
+	SwitchoverSucceeded  SwitchoverResult = "Succeeded"
+	SwitchoverFailed     SwitchoverResult = "Failed"
+)
+
+// SwitchoverTarget returns the name of the pod requested to become master, or an empty string
+// when no switchover is requested.
+func (r *RedisFailover) SwitchoverTarget() string {
+	if r.Annotations == nil {
+		return ""
+	}
+	return strings.TrimSpace(r.Annotations[SwitchoverAnnotation])
+}
+
+// SwitchoverInProgress tells if the sentinels are moving the master to the target of the last switchover.
+func (r *RedisFailover) SwitchoverInProgress() bool {
+	return r.Status.LastSwitchover != nil && r.Status.LastSwitchover.Result == SwitchoverInProgress
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSwitchoverTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expectation string
+	}{
+		{
+			name:        "no annotations",
+			expectation: "",
+		},
+		{
+			name:        "other annotations",
+			annotations: map[string]string{"foo": "bar"},
+			expectation: "",
+		},
+		{
+			name:        "switchover requested",
+			annotations: map[string]string{SwitchoverAnnotation: " rfr-foo-1 "},
+			expectation: "rfr-foo-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := &RedisFailover{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			assert.Equal(t, test.expectation, rf.SwitchoverTarget())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package v1
+
+// RedisTLSEnabled returns true when the redises serve over TLS.
+func (r *RedisFailover) RedisTLSEnabled() bool {
+	return r.Spec.Redis.TLS != nil
+}
+
+// SentinelTLS returns the TLS settings of the sentinels. Sentinels fall back to the redis settings,
+// so enabling TLS on the redises doesn't leave plaintext traffic to the sentinels.
+func (r *RedisFailover) SentinelTLS() *TLSSettings {
+	if r.Spec.Sentinel.TLS != nil {
+		return r.Spec.Sentinel.TLS
+	}
+	return r.Spec.Redis.TLS
+}
+
+// SentinelTLSEnabled returns true when the sentinels serve over TLS.
+func (r *RedisFailover) SentinelTLSEnabled() bool {
+	return r.SentinelTLS() != nil
+}
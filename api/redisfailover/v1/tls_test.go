@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinelTLS(t *testing.T) {
+	redisTLS := &TLSSettings{SecretName: "redis-tls"}
+	sentinelTLS := &TLSSettings{SecretName: "sentinel-tls"}
+
+	tests := []struct {
+		name        string
+		redis       *TLSSettings
+		sentinel    *TLSSettings
+		expectation *TLSSettings
+	}{
+		{
+			name:        "TLS disabled",
+			expectation: nil,
+		},
+		{
+			name:        "Sentinels use the redis settings",
+			redis:       redisTLS,
+			expectation: redisTLS,
+		},
+		{
+			name:        "Sentinels use their own settings",
+			redis:       redisTLS,
+			sentinel:    sentinelTLS,
+			expectation: sentinelTLS,
+		},
+		{
+			name:        "Only sentinels serve over TLS",
+			sentinel:    sentinelTLS,
+			expectation: sentinelTLS,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.TLS = test.redis
+			rf.Spec.Sentinel.TLS = test.sentinel
+
+			assert.Equal(test.expectation, rf.SentinelTLS())
+			assert.Equal(test.expectation != nil, rf.SentinelTLSEnabled())
+			assert.Equal(test.redis != nil, rf.RedisTLSEnabled())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,23 +13,41 @@ import (
 // +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
 // +kubebuilder:printcolumn:name="REDIS",type="integer",JSONPath=".spec.redis.replicas"
 // +kubebuilder:printcolumn:name="SENTINELS",type="integer",JSONPath=".spec.sentinel.replicas"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="MASTER",type="string",JSONPath=".status.master.podName"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:singular=redisfailover,path=redisfailovers,shortName=rf,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.redis.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 type RedisFailover struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              RedisFailoverSpec `json:"spec"`
+	Spec              RedisFailoverSpec   `json:"spec"`
+	Status            RedisFailoverStatus `json:"status,omitempty"`
 }
 
 // RedisFailoverSpec represents a Redis failover spec
+// +kubebuilder:validation:XValidation:rule="!has(self.restore) || !has(self.bootstrapNode)",message="restore can't be used with a bootstrapNode"
 type RedisFailoverSpec struct {
 	Redis          RedisSettings      `json:"redis,omitempty"`
 	Sentinel       SentinelSettings   `json:"sentinel,omitempty"`
 	Auth           AuthSettings       `json:"auth,omitempty"`
 	LabelWhitelist []string           `json:"labelWhitelist,omitempty"`
 	BootstrapNode  *BootstrapSettings `json:"bootstrapNode,omitempty"`
+	Backup         *BackupSettings    `json:"backup,omitempty"`
+	Restore        *RestoreSettings   `json:"restore,omitempty"`
+	AddressMode    AddressMode        `json:"addressMode,omitempty"`
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:items:Enum=IPv4;IPv6
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
 }
 
+// AddressMode is how the redises and the sentinels address each other
+// +kubebuilder:validation:Enum=ip;hostname
+type AddressMode string
+
 // RedisCommandRename defines the specification of a "rename-command" configuration option
 type RedisCommandRename struct {
 	From string `json:"from,omitempty"`
@@ -36,49 +55,95 @@ type RedisCommandRename struct {
 }
 
 // RedisSettings defines the specification of the redis cluster
+// +kubebuilder:validation:XValidation:rule="(has(self.port) ? self.port : 6379) == (has(oldSelf.port) ? oldSelf.port : 6379)",message="port is immutable"
 type RedisSettings struct {
-	Image                         string                            `json:"image,omitempty"`
-	ImagePullPolicy               corev1.PullPolicy                 `json:"imagePullPolicy,omitempty"`
-	Replicas                      int32                             `json:"replicas,omitempty"`
-	Port                          int32                             `json:"port,omitempty"`
-	Resources                     corev1.ResourceRequirements       `json:"resources,omitempty"`
-	CustomConfig                  []string                          `json:"customConfig,omitempty"`
-	CustomCommandRenames          []RedisCommandRename              `json:"customCommandRenames,omitempty"`
-	Command                       []string                          `json:"command,omitempty"`
-	ShutdownConfigMap             string                            `json:"shutdownConfigMap,omitempty"`
-	StartupConfigMap              string                            `json:"startupConfigMap,omitempty"`
-	Storage                       RedisStorage                      `json:"storage,omitempty"`
-	InitContainers                []corev1.Container                `json:"initContainers,omitempty"`
-	Exporter                      Exporter                          `json:"exporter,omitempty"`
-	ExtraContainers               []corev1.Container                `json:"extraContainers,omitempty"`
-	Affinity                      *corev1.Affinity                  `json:"affinity,omitempty"`
-	SecurityContext               *corev1.PodSecurityContext        `json:"securityContext,omitempty"`
-	ContainerSecurityContext      *corev1.SecurityContext           `json:"containerSecurityContext,omitempty"`
-	ImagePullSecrets              []corev1.LocalObjectReference     `json:"imagePullSecrets,omitempty"`
-	Tolerations                   []corev1.Toleration               `json:"tolerations,omitempty"`
-	TopologySpreadConstraints     []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
-	NodeSelector                  map[string]string                 `json:"nodeSelector,omitempty"`
-	PodAnnotations                map[string]string                 `json:"podAnnotations,omitempty"`
-	ServiceAnnotations            map[string]string                 `json:"serviceAnnotations,omitempty"`
-	HostNetwork                   bool                              `json:"hostNetwork,omitempty"`
-	DNSPolicy                     corev1.DNSPolicy                  `json:"dnsPolicy,omitempty"`
-	PriorityClassName             string                            `json:"priorityClassName,omitempty"`
-	ServiceAccountName            string                            `json:"serviceAccountName,omitempty"`
-	TerminationGracePeriodSeconds int64                             `json:"terminationGracePeriod,omitempty"`
-	ExtraVolumes                  []corev1.Volume                   `json:"extraVolumes,omitempty"`
-	ExtraVolumeMounts             []corev1.VolumeMount              `json:"extraVolumeMounts,omitempty"`
-	CustomLivenessProbe           *corev1.Probe                     `json:"customLivenessProbe,omitempty"`
-	CustomReadinessProbe          *corev1.Probe                     `json:"customReadinessProbe,omitempty"`
-	CustomStartupProbe            *corev1.Probe                     `json:"customStartupProbe,omitempty"`
-	DisablePodDisruptionBudget    bool                              `json:"disablePodDisruptionBudget,omitempty"`
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port      int32                       `json:"port,omitempty"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +kubebuilder:validation:items:Pattern=`^[^ ]+ .*$`
+	CustomConfig []string `json:"customConfig,omitempty"`
+	// ConfigRewrite runs redis from a writable copy of its config file, rewritten with CONFIG REWRITE every
+	// time the custom config is set, so the values set survive a restart of the redis container
+	ConfigRewrite             bool                              `json:"configRewrite,omitempty"`
+	CustomCommandRenames      []RedisCommandRename              `json:"customCommandRenames,omitempty"`
+	Command                   []string                          `json:"command,omitempty"`
+	ShutdownConfigMap         string                            `json:"shutdownConfigMap,omitempty"`
+	StartupConfigMap          string                            `json:"startupConfigMap,omitempty"`
+	Storage                   RedisStorage                      `json:"storage,omitempty"`
+	InitContainers            []corev1.Container                `json:"initContainers,omitempty"`
+	Exporter                  Exporter                          `json:"exporter,omitempty"`
+	ExtraContainers           []corev1.Container                `json:"extraContainers,omitempty"`
+	Affinity                  *corev1.Affinity                  `json:"affinity,omitempty"`
+	SecurityContext           *corev1.PodSecurityContext        `json:"securityContext,omitempty"`
+	ContainerSecurityContext  *corev1.SecurityContext           `json:"containerSecurityContext,omitempty"`
+	ImagePullSecrets          []corev1.LocalObjectReference     `json:"imagePullSecrets,omitempty"`
+	Tolerations               []corev1.Toleration               `json:"tolerations,omitempty"`
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	NodeSelector              map[string]string                 `json:"nodeSelector,omitempty"`
+	PodAnnotations            map[string]string                 `json:"podAnnotations,omitempty"`
+	ServiceAnnotations        map[string]string                 `json:"serviceAnnotations,omitempty"`
+	HostNetwork               bool                              `json:"hostNetwork,omitempty"`
+	DNSPolicy                 corev1.DNSPolicy                  `json:"dnsPolicy,omitempty"`
+	PriorityClassName         string                            `json:"priorityClassName,omitempty"`
+	ServiceAccountName        string                            `json:"serviceAccountName,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds int64                    `json:"terminationGracePeriod,omitempty"`
+	ExtraVolumes                  []corev1.Volume          `json:"extraVolumes,omitempty"`
+	ExtraVolumeMounts             []corev1.VolumeMount     `json:"extraVolumeMounts,omitempty"`
+	CustomLivenessProbe           *corev1.Probe            `json:"customLivenessProbe,omitempty"`
+	CustomReadinessProbe          *corev1.Probe            `json:"customReadinessProbe,omitempty"`
+	CustomStartupProbe            *corev1.Probe            `json:"customStartupProbe,omitempty"`
+	DisablePodDisruptionBudget    bool                     `json:"disablePodDisruptionBudget,omitempty"`
+	TLS                           *TLSSettings             `json:"tls,omitempty"`
+	MasterElection                MasterElectionStrategy   `json:"masterElection,omitempty"`
+	SplitBrainResolution          SplitBrainResolutionMode `json:"splitBrainResolution,omitempty"`
+	UpdateStrategy                RedisUpdateStrategy      `json:"updateStrategy,omitempty"`
+}
+
+// MasterElectionStrategy is how the operator chooses the redis to promote when there is no master
+// +kubebuilder:validation:Enum=oldest;highestOffset
+type MasterElectionStrategy string
+
+// SplitBrainResolutionMode is what the operator does when more than one redis works as master
+// +kubebuilder:validation:Enum=manual;automatic
+type SplitBrainResolutionMode string
+
+// RedisUpdateStrategy controls how the operator restarts the redis pods to roll out a new revision of
+// their statefulset. The replicas are restarted one at a time, the master last.
+type RedisUpdateStrategy struct {
+	// Paused stops restarting pods, the stale ones keep running until it is unset
+	Paused bool `json:"paused,omitempty"`
+	// Partition keeps the pods with an ordinal lower than it on their current revision
+	// +kubebuilder:validation:Minimum=0
+	Partition int32 `json:"partition,omitempty"`
+	// MinSyncedSeconds is how long the replicas must be in sync with the master before the next pod is restarted
+	// +kubebuilder:validation:Minimum=0
+	MinSyncedSeconds int32 `json:"minSyncedSeconds,omitempty"`
+	// MasterSwitchover moves the master to an updated replica with a sentinel failover before restarting its pod
+	MasterSwitchover bool `json:"masterSwitchover,omitempty"`
+	// MaxReplicationLag is the largest number of bytes a replica can be behind the master for the update to go on
+	// +kubebuilder:validation:Minimum=0
+	MaxReplicationLag *int64 `json:"maxReplicationLag,omitempty"`
+	// Surge adds a replica before restarting a pod, so the number of replicas in sync with the master is
+	// never below the requested one. The extra replica and its volume are removed once all the pods are updated.
+	Surge bool `json:"surge,omitempty"`
 }
 
 // SentinelSettings defines the specification of the sentinel cluster
 type SentinelSettings struct {
-	Image                      string                            `json:"image,omitempty"`
-	ImagePullPolicy            corev1.PullPolicy                 `json:"imagePullPolicy,omitempty"`
-	Replicas                   int32                             `json:"replicas,omitempty"`
-	Resources                  corev1.ResourceRequirements       `json:"resources,omitempty"`
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas  int32                       `json:"replicas,omitempty"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +kubebuilder:validation:items:Pattern=`^[^ ]+ .*$`
 	CustomConfig               []string                          `json:"customConfig,omitempty"`
 	Command                    []string                          `json:"command,omitempty"`
 	StartupConfigMap           string                            `json:"startupConfigMap,omitempty"`
@@ -106,24 +171,91 @@ type SentinelSettings struct {
 	CustomStartupProbe         *corev1.Probe                     `json:"customStartupProbe,omitempty"`
 	DisablePodDisruptionBudget bool                              `json:"disablePodDisruptionBudget,omitempty"`
 	DisableMyMaster            bool                              `json:"disableMyMaster,omitempty"`
+	TLS                        *TLSSettings                      `json:"tls,omitempty"`
+	WorkloadType               SentinelWorkloadType              `json:"workloadType,omitempty"`
+	ConfigStorage              SentinelConfigStorage             `json:"configStorage,omitempty"`
+}
+
+// SentinelWorkloadType is the kind of workload the sentinels run as
+// +kubebuilder:validation:Enum=Deployment;StatefulSet
+type SentinelWorkloadType string
+
+// SentinelConfigStorage defines the volume keeping the sentinel.conf of every sentinel when they run
+// as a statefulset
+// +kubebuilder:validation:XValidation:rule="has(self.storageClassName) == has(oldSelf.storageClassName) && (!has(self.storageClassName) || self.storageClassName == oldSelf.storageClassName)",message="storageClassName is immutable"
+type SentinelConfigStorage struct {
+	// Size of the volume, 10Mi by default
+	Size             *resource.Quantity `json:"size,omitempty"`
+	StorageClassName *string            `json:"storageClassName,omitempty"`
 }
 
 // AuthSettings contains settings about auth
 type AuthSettings struct {
-	SecretPath string `json:"secretPath,omitempty"`
+	SecretPath string      `json:"secretPath,omitempty"`
+	Users      []RedisUser `json:"users,omitempty"`
+	// PasswordRotationGracePeriodSeconds is how long both passwords are accepted during a rotation,
+	// 300 when unset. The confirmation annotation ends it early.
+	// +kubebuilder:validation:Minimum=0
+	PasswordRotationGracePeriodSeconds int32 `json:"passwordRotationGracePeriodSeconds,omitempty"`
+}
+
+// RedisUser defines an ACL user created on every redis. Its password is read from the "password" key
+// of the secret, as for the default user. Rules are ACL rules such as "~cache:*" or "+@read".
+type RedisUser struct {
+	// +kubebuilder:validation:Pattern=`^[^\s]+$`
+	Name       string   `json:"name"`
+	SecretPath string   `json:"secretPath"`
+	Rules      []string `json:"rules,omitempty"`
+}
+
+// TLSSettings references the secret holding the certificate used to serve and connect over TLS.
+// The secret uses the kubernetes.io/tls layout, as issued by cert-manager: tls.crt, tls.key and ca.crt.
+type TLSSettings struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // BootstrapSettings contains settings about a potential bootstrap node
 type BootstrapSettings struct {
-	Host           string `json:"host,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,5}$`
 	Port           string `json:"port,omitempty"`
 	AllowSentinels bool   `json:"allowSentinels,omitempty"`
 }
 
+// RestoreSettings fills a new Redis failover with the data of an RDB file. The file is written to the data
+// of the first redis pod before it starts, that pod is then promoted and the other ones replicate from it.
+// Only one of the sources can be given.
+// +kubebuilder:validation:XValidation:rule="[has(self.backupName), has(self.url), has(self.persistentVolumeClaim)].filter(x, x).size() == 1",message="restore must include exactly one of backupName, url or persistentVolumeClaim"
+type RestoreSettings struct {
+	// BackupName is a completed RedisFailoverBackup of the same namespace
+	BackupName string `json:"backupName,omitempty"`
+	// URL of the RDB file, s3:// URLs are downloaded from the storage given in the backup settings
+	URL string `json:"url,omitempty"`
+	// PersistentVolumeClaim holding the RDB file, it is mounted by every redis pod while restoring, so it must be ReadOnlyMany or ReadWriteMany
+	PersistentVolumeClaim *RestoreVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	Image                 string               `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// RestoreVolumeSource is an RDB file stored in a persistent volume claim
+type RestoreVolumeSource struct {
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+	// Path of the RDB file in the volume
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
 // Exporter defines the specification for the redis/sentinel exporter
 type Exporter struct {
-	Enabled                  bool                         `json:"enabled,omitempty"`
-	Image                    string                       `json:"image,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+	Image   string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
 	ImagePullPolicy          corev1.PullPolicy            `json:"imagePullPolicy,omitempty"`
 	ContainerSecurityContext *corev1.SecurityContext      `json:"containerSecurityContext,omitempty"`
 	Args                     []string                     `json:"args,omitempty"`
@@ -190,6 +322,111 @@ type EmbeddedObjectMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,12,rep,name=annotations"`
 }
 
+// RedisFailoverStatus represents the observed state of a Redis failover
+type RedisFailoverStatus struct {
+	Phase                   RedisFailoverPhase       `json:"phase,omitempty"`
+	ObservedGeneration      int64                    `json:"observedGeneration,omitempty"`
+	Master                  MasterStatus             `json:"master,omitempty"`
+	ReadyRedises            int32                    `json:"readyRedises,omitempty"`
+	ReadySentinels          int32                    `json:"readySentinels,omitempty"`
+	LastFailoverTime        *metav1.Time             `json:"lastFailoverTime,omitempty"`
+	LastSwitchover          *SwitchoverStatus        `json:"lastSwitchover,omitempty"`
+	PasswordRotation        *PasswordRotationStatus  `json:"passwordRotation,omitempty"`
+	LastScheduledBackupTime *metav1.Time             `json:"lastScheduledBackupTime,omitempty"`
+	Restore                 *RestoreStatus           `json:"restore,omitempty"`
+	Update                  *RedisUpdateStatus       `json:"update,omitempty"`
+	SentinelMigration       *SentinelMigrationStatus `json:"sentinelMigration,omitempty"`
+	ScaleDown               *RedisScaleDownStatus    `json:"scaleDown,omitempty"`
+	Conditions              []metav1.Condition       `json:"conditions,omitempty"`
+	// Replicas is the number of redis pods, read by the autoscalers through the scale subresource
+	Replicas int32 `json:"replicas,omitempty"`
+	// Selector selects the redis pods, for the autoscalers to read their metrics
+	Selector string `json:"selector,omitempty"`
+	// Users are the ACL users of the spec the operator created, the ones no longer defined are deleted from the redises
+	Users []string `json:"users,omitempty"`
+}
+
+// RedisFailoverPhase is a label for the condition of a Redis failover at the current time
+type RedisFailoverPhase string
+
+// MasterStatus contains the redis node currently working as master
+type MasterStatus struct {
+	PodName string `json:"podName,omitempty"`
+	IP      string `json:"ip,omitempty"`
+}
+
+// SwitchoverStatus contains the outcome of the last planned switchover
+type SwitchoverStatus struct {
+	Target  string           `json:"target,omitempty"`
+	Result  SwitchoverResult `json:"result,omitempty"`
+	Message string           `json:"message,omitempty"`
+	Time    metav1.Time      `json:"time,omitempty"`
+	// ReplicaPriority is the replica-priority the target had before the switchover, given back once it is over
+	ReplicaPriority string `json:"replicaPriority,omitempty"`
+}
+
+// SwitchoverResult is the outcome of a planned switchover
+type SwitchoverResult string
+
+// PasswordRotationStatus contains the progress of the last rotation of the auth password
+type PasswordRotationStatus struct {
+	Phase   PasswordRotationPhase `json:"phase,omitempty"`
+	Message string                `json:"message,omitempty"`
+	Time    metav1.Time           `json:"time,omitempty"`
+}
+
+// PasswordRotationPhase is the step a password rotation is at
+type PasswordRotationPhase string
+
+// RestoreStatus contains the progress of the restore of the data on creation
+type RestoreStatus struct {
+	Phase RestorePhase `json:"phase,omitempty"`
+	// Source is the location the RDB file is restored from
+	Source  string      `json:"source,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Time    metav1.Time `json:"time,omitempty"`
+}
+
+// RestorePhase is the step a restore is at
+type RestorePhase string
+
+// RedisUpdateStatus contains the progress of the restart of the redis pods to the last revision of their statefulset
+type RedisUpdateStatus struct {
+	UpdatedRedises int32 `json:"updatedRedises"`
+	Redises        int32 `json:"redises"`
+	// BlockedReason tells why the next pod is not restarted yet
+	BlockedReason string `json:"blockedReason,omitempty"`
+	// SyncedSince is when the replicas were first seen in sync with the master after the last restart
+	SyncedSince *metav1.Time `json:"syncedSince,omitempty"`
+	// SurgePod is the extra redis pod running while the pods are restarted
+	SurgePod string `json:"surgePod,omitempty"`
+}
+
+// SentinelMigrationStatus contains the progress of moving the sentinels to the workload type requested
+type SentinelMigrationStatus struct {
+	// From is the workload type the sentinels are moved from
+	From SentinelWorkloadType `json:"from"`
+	// Sentinels is the number of sentinels still running on it
+	Sentinels int32 `json:"sentinels"`
+}
+
+// RedisScaleDownStatus contains the progress of removing the redis pods after the replicas were lowered
+type RedisScaleDownStatus struct {
+	Phase ScaleDownPhase `json:"phase"`
+	// Redises is the number of redis pods before the scale down, kept until the master runs on one of
+	// the pods not removed
+	Redises int32 `json:"redises"`
+	// BlockedReason tells why the scale down does not go on
+	BlockedReason string `json:"blockedReason,omitempty"`
+	// SentinelReset is the last sentinel reset, waited for to know the topology again before the next one is
+	SentinelReset string `json:"sentinelReset,omitempty"`
+	// SentinelResetTime is when the last sentinel was reset
+	SentinelResetTime *metav1.Time `json:"sentinelResetTime,omitempty"`
+}
+
+// ScaleDownPhase is the step a scale down is at
+type ScaleDownPhase string
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // RedisFailoverList represents a Redis failover list
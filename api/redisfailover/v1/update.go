@@ -0,0 +1,20 @@
+package v1
+
+// RedisPods returns the number of redis pods to run: the ones requested, and the pods kept while a
+// scale down waits for the master to be moved off the pods to remove.
+func (r *RedisFailover) RedisPods() int32 {
+	requested := r.RequestedRedisPods()
+	if r.ScaleDownKeepsPods() && r.Status.ScaleDown.Redises > requested {
+		return r.Status.ScaleDown.Redises
+	}
+	return requested
+}
+
+// RequestedRedisPods returns the number of redis pods requested: the replicas, and the surge replica
+// while the pods are restarted with the surge update strategy.
+func (r *RedisFailover) RequestedRedisPods() int32 {
+	if r.Status.Update != nil && r.Status.Update.SurgePod != "" {
+		return r.Spec.Redis.Replicas + 1
+	}
+	return r.Spec.Redis.Replicas
+}
@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisPods(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      *RedisUpdateStatus
+		scaleDown   *RedisScaleDownStatus
+		expectation int32
+	}{
+		{
+			name:        "no update",
+			expectation: 3,
+		},
+		{
+			name:        "update without surge",
+			update:      &RedisUpdateStatus{UpdatedRedises: 1, Redises: 3},
+			expectation: 3,
+		},
+		{
+			name:        "update with surge",
+			update:      &RedisUpdateStatus{UpdatedRedises: 1, Redises: 4, SurgePod: "rfr-foo-3"},
+			expectation: 4,
+		},
+		{
+			name:        "scale down waiting for the master",
+			scaleDown:   &RedisScaleDownStatus{Phase: ScaleDownMovingMaster, Redises: 5},
+			expectation: 5,
+		},
+		{
+			name:        "scale down during an update with surge",
+			update:      &RedisUpdateStatus{UpdatedRedises: 1, Redises: 6, SurgePod: "rfr-foo-5"},
+			scaleDown:   &RedisScaleDownStatus{Phase: ScaleDownMovingMaster, Redises: 6},
+			expectation: 6,
+		},
+		{
+			name:        "scale down below the replicas requested",
+			scaleDown:   &RedisScaleDownStatus{Phase: ScaleDownMovingMaster, Redises: 2},
+			expectation: 3,
+		},
+		{
+			name:        "scale down resetting the sentinels",
+			scaleDown:   &RedisScaleDownStatus{Phase: ScaleDownResettingSentinels, Redises: 5},
+			expectation: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := &RedisFailover{
+				Spec:   RedisFailoverSpec{Redis: RedisSettings{Replicas: 3}},
+				Status: RedisFailoverStatus{Update: test.update, ScaleDown: test.scaleDown},
+			}
+			assert.Equal(t, test.expectation, rf.RedisPods())
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package v1
+
+// Users created by the operator on every redis, their passwords are generated and kept in a secret
+// owned by the RedisFailover.
+const (
+	// PingerUser is only allowed to ping, it is used by the liveness probe of the redis pods.
+	PingerUser = "pinger"
+	// OperatorUser is used by the operator to check and heal the redises when auth is enabled.
+	OperatorUser = "redis-operator"
+)
+
+const defaultUser = "default"
+
+// isReservedUser tells if the user is managed by redis or the operator and can not be defined in the spec.
+func isReservedUser(name string) bool {
+	return name == defaultUser || name == PingerUser || name == OperatorUser
+}
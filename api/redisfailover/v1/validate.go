@@ -4,6 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -12,6 +16,8 @@ const (
 
 // Validate set the values by default if not defined and checks if the values given are valid
 func (r *RedisFailover) Validate() error {
+	r.Default()
+
 	if len(r.Name) > maxNameLength {
 		return fmt.Errorf("name length can't be higher than %d", maxNameLength)
 	}
@@ -20,15 +26,166 @@ func (r *RedisFailover) Validate() error {
 		if r.Spec.BootstrapNode.Host == "" {
 			return errors.New("BootstrapNode must include a host when provided")
 		}
+		if port, err := strconv.Atoi(r.Spec.BootstrapNode.Port); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("BootstrapNode port %q is not valid", r.Spec.BootstrapNode.Port)
+		}
+	}
+
+	if err := validateCustomConfig("redis", r.Spec.Redis.CustomConfig); err != nil {
+		return err
+	}
+	if err := validateCustomConfig("sentinel", r.Spec.Sentinel.CustomConfig); err != nil {
+		return err
+	}
+
+	switch r.Spec.Redis.MasterElection {
+	case MasterElectionOldest, MasterElectionHighestOffset:
+	default:
+		return fmt.Errorf("redis masterElection %q is not valid, must be %s or %s", r.Spec.Redis.MasterElection, MasterElectionOldest, MasterElectionHighestOffset)
+	}
+
+	switch r.Spec.Redis.SplitBrainResolution {
+	case SplitBrainResolutionManual, SplitBrainResolutionAutomatic:
+	default:
+		return fmt.Errorf("redis splitBrainResolution %q is not valid, must be %s or %s", r.Spec.Redis.SplitBrainResolution, SplitBrainResolutionManual, SplitBrainResolutionAutomatic)
+	}
+
+	switch r.Spec.Sentinel.WorkloadType {
+	case SentinelWorkloadDeployment, SentinelWorkloadStatefulSet:
+	default:
+		return fmt.Errorf("sentinel workloadType %q is not valid, must be %s or %s", r.Spec.Sentinel.WorkloadType, SentinelWorkloadDeployment, SentinelWorkloadStatefulSet)
+	}
+
+	switch r.Spec.AddressMode {
+	case AddressModeIP, AddressModeHostname:
+	default:
+		return fmt.Errorf("addressMode %q is not valid, must be %s or %s", r.Spec.AddressMode, AddressModeIP, AddressModeHostname)
+	}
+
+	if err := r.validateIPFamilies(); err != nil {
+		return err
+	}
+
+	updateStrategy := r.Spec.Redis.UpdateStrategy
+	if updateStrategy.Partition < 0 {
+		return errors.New("redis updateStrategy partition can't be negative")
+	}
+	if updateStrategy.MinSyncedSeconds < 0 {
+		return errors.New("redis updateStrategy minSyncedSeconds can't be negative")
+	}
+	if updateStrategy.MaxReplicationLag != nil && *updateStrategy.MaxReplicationLag < 0 {
+		return errors.New("redis updateStrategy maxReplicationLag can't be negative")
+	}
+
+	if r.Spec.Redis.TLS != nil && r.Spec.Redis.TLS.SecretName == "" {
+		return errors.New("redis TLS must include a secretName when provided")
+	}
+
+	if r.Spec.Sentinel.TLS != nil && r.Spec.Sentinel.TLS.SecretName == "" {
+		return errors.New("sentinel TLS must include a secretName when provided")
+	}
+
+	if r.Spec.Auth.PasswordRotationGracePeriodSeconds < 0 {
+		return errors.New("auth passwordRotationGracePeriodSeconds can't be negative")
+	}
+
+	users := make(map[string]bool, len(r.Spec.Auth.Users))
+	for _, user := range r.Spec.Auth.Users {
+		if user.Name == "" || strings.ContainsAny(user.Name, " \t") {
+			return fmt.Errorf("auth user name %q is not valid", user.Name)
+		}
+		if isReservedUser(user.Name) {
+			return fmt.Errorf("auth user %s is reserved", user.Name)
+		}
+		if users[user.Name] {
+			return fmt.Errorf("auth user %s is defined more than once", user.Name)
+		}
+		users[user.Name] = true
+		if user.SecretPath == "" {
+			return fmt.Errorf("auth user %s must include a secretPath", user.Name)
+		}
+		for _, rule := range user.Rules {
+			if rule == "" || strings.ContainsAny(rule, " \t") {
+				return fmt.Errorf("auth user %s rule %q is not valid", user.Name, rule)
+			}
+		}
+	}
+
+	if r.Spec.Backup != nil {
+		if r.Spec.Backup.Schedule != "" {
+			if _, err := cron.ParseStandard(r.Spec.Backup.Schedule); err != nil {
+				return fmt.Errorf("backup schedule %q is not valid: %w", r.Spec.Backup.Schedule, err)
+			}
+		}
+		s3 := r.Spec.Backup.Storage.S3
+		if s3 == nil {
+			return errors.New("backup must include a storage")
+		}
+		if s3.Endpoint == "" || s3.Bucket == "" || s3.CredentialsSecret == "" {
+			return errors.New("backup S3 storage must include an endpoint, a bucket and a credentialsSecret")
+		}
+	}
 
-		if r.Spec.BootstrapNode.Port == "" {
-			r.Spec.BootstrapNode.Port = strconv.Itoa(defaultRedisPort)
+	if r.Spec.Restore != nil {
+		sources := 0
+		for _, set := range []bool{r.Spec.Restore.BackupName != "", r.Spec.Restore.URL != "", r.Spec.Restore.PersistentVolumeClaim != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return errors.New("restore must include exactly one of backupName, url or persistentVolumeClaim")
+		}
+		if pvc := r.Spec.Restore.PersistentVolumeClaim; pvc != nil && (pvc.ClaimName == "" || pvc.Path == "") {
+			return errors.New("restore persistentVolumeClaim must include a claimName and a path")
+		}
+		if r.Bootstrapping() {
+			return errors.New("restore can't be used with a bootstrapNode")
 		}
+	}
+
+	// The custom config of the redis is merged with the one of the operator on each reconcile, it is not
+	// defaulted so a RF bootstrapped later does not keep the config of a standalone one
+	if r.Bootstrapping() {
 		r.Spec.Redis.CustomConfig = deduplicateStr(append(bootstrappingRedisCustomConfig, r.Spec.Redis.CustomConfig...))
 	} else {
 		r.Spec.Redis.CustomConfig = deduplicateStr(append(defaultRedisCustomConfig, r.Spec.Redis.CustomConfig...))
 	}
 
+	return nil
+}
+
+// Default sets the values by default of the fields not defined. Only the ones that can be kept in the
+// object are set, so it is also used by the mutating webhook.
+func (r *RedisFailover) Default() {
+	if r.Bootstrapping() && r.Spec.BootstrapNode.Port == "" {
+		r.Spec.BootstrapNode.Port = strconv.Itoa(defaultRedisPort)
+	}
+
+	if r.Spec.Redis.MasterElection == "" {
+		r.Spec.Redis.MasterElection = MasterElectionOldest
+	}
+
+	if r.Spec.Redis.SplitBrainResolution == "" {
+		r.Spec.Redis.SplitBrainResolution = SplitBrainResolutionManual
+	}
+
+	if r.Spec.Sentinel.WorkloadType == "" {
+		r.Spec.Sentinel.WorkloadType = SentinelWorkloadDeployment
+	}
+
+	if r.Spec.AddressMode == "" {
+		r.Spec.AddressMode = AddressModeIP
+	}
+
+	if r.Spec.Backup != nil && r.Spec.Backup.Image == "" {
+		r.Spec.Backup.Image = defaultBackupAgentImage
+	}
+
+	if r.Spec.Restore != nil && r.Spec.Restore.Image == "" {
+		r.Spec.Restore.Image = defaultBackupAgentImage
+	}
+
 	if r.Spec.Redis.Image == "" {
 		r.Spec.Redis.Image = defaultImage
 	}
@@ -60,7 +217,16 @@ func (r *RedisFailover) Validate() error {
 	if len(r.Spec.Sentinel.CustomConfig) == 0 {
 		r.Spec.Sentinel.CustomConfig = defaultSentinelCustomConfig
 	}
+}
 
+// validateCustomConfig checks each line of the custom config has a parameter and a value, as they are
+// set with CONFIG SET or SENTINEL SET
+func validateCustomConfig(name string, config []string) error {
+	for _, line := range config {
+		if len(strings.Split(line, " ")) < 2 {
+			return fmt.Errorf("%s customConfig %q is malformed, must be a parameter and a value", name, line)
+		}
+	}
 	return nil
 }
 
@@ -75,3 +241,29 @@ func deduplicateStr(strSlice []string) []string {
 	}
 	return list
 }
+
+// validateIPFamilies checks the IP families of the services are the ones kubernetes knows, each given
+// once, and only one of them when the services are single-stack
+func (r *RedisFailover) validateIPFamilies() error {
+	if policy := r.Spec.IPFamilyPolicy; policy != nil {
+		switch *policy {
+		case corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+		default:
+			return fmt.Errorf("ipFamilyPolicy %q is not valid, must be %s, %s or %s", *policy, corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack)
+		}
+	}
+	families := make(map[corev1.IPFamily]bool, len(r.Spec.IPFamilies))
+	for _, family := range r.Spec.IPFamilies {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			return fmt.Errorf("ipFamilies %q is not valid, must be %s or %s", family, corev1.IPv4Protocol, corev1.IPv6Protocol)
+		}
+		if families[family] {
+			return fmt.Errorf("ipFamilies %s is given more than once", family)
+		}
+		families[family] = true
+	}
+	if len(families) > 1 && (r.Spec.IPFamilyPolicy == nil || *r.Spec.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack) {
+		return errors.New("ipFamilies can't have two families with a SingleStack ipFamilyPolicy")
+	}
+	return nil
+}
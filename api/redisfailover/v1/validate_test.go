@@ -4,18 +4,43 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestValidate(t *testing.T) {
+	maxReplicationLag := int64(1024)
+	negativeReplicationLag := int64(-1)
+	singleStack := corev1.IPFamilyPolicySingleStack
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	unknownPolicy := corev1.IPFamilyPolicy("DualStack")
+
 	tests := []struct {
 		name                   string
 		rfName                 string
 		rfBootstrapNode        *BootstrapSettings
 		rfRedisCustomConfig    []string
 		rfSentinelCustomConfig []string
+		rfRedisTLS             *TLSSettings
+		rfSentinelTLS          *TLSSettings
+		rfAuthUsers            []RedisUser
+		rfBackup               *BackupSettings
+		rfRestore              *RestoreSettings
+		rfMasterElection       MasterElectionStrategy
+		rfSplitBrain           SplitBrainResolutionMode
+		rfUpdateStrategy       RedisUpdateStrategy
+		rfSentinelWorkload     SentinelWorkloadType
+		rfAddressMode          AddressMode
+		rfIPFamilyPolicy       *corev1.IPFamilyPolicy
+		rfIPFamilies           []corev1.IPFamily
 		expectedError          string
 		expectedBootstrapNode  *BootstrapSettings
+		expectedBackup         *BackupSettings
+		expectedRestore        *RestoreSettings
+		expectedMasterElection MasterElectionStrategy
+		expectedSplitBrain     SplitBrainResolutionMode
+		expectedWorkload       SentinelWorkloadType
+		expectedAddressMode    AddressMode
 	}{
 		{
 			name:   "populates default values",
@@ -53,6 +78,30 @@ func TestValidate(t *testing.T) {
 			rfBootstrapNode:       &BootstrapSettings{Host: "127.0.0.1", Port: "6380"},
 			expectedBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "6380"},
 		},
+		{
+			name:            "Bootstrap port not numeric",
+			rfName:          "test",
+			rfBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "redis"},
+			expectedError:   `BootstrapNode port "redis" is not valid`,
+		},
+		{
+			name:            "Bootstrap port out of range",
+			rfName:          "test",
+			rfBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "70000"},
+			expectedError:   `BootstrapNode port "70000" is not valid`,
+		},
+		{
+			name:                "Malformed redis custom config",
+			rfName:              "test",
+			rfRedisCustomConfig: []string{"maxmemory-policy allkeys-lru", "maxmemory"},
+			expectedError:       `redis customConfig "maxmemory" is malformed, must be a parameter and a value`,
+		},
+		{
+			name:                   "Malformed sentinel custom config",
+			rfName:                 "test",
+			rfSentinelCustomConfig: []string{"down-after-milliseconds"},
+			expectedError:          `sentinel customConfig "down-after-milliseconds" is malformed, must be a parameter and a value`,
+		},
 		{
 			name:                "Appends applied custom config to default initial values",
 			rfName:              "test",
@@ -65,6 +114,210 @@ func TestValidate(t *testing.T) {
 			rfBootstrapNode:       &BootstrapSettings{Host: "127.0.0.1"},
 			expectedBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "6379"},
 		},
+		{
+			name:          "Redis TLS provided without a secret",
+			rfName:        "test",
+			rfRedisTLS:    &TLSSettings{},
+			expectedError: "redis TLS must include a secretName when provided",
+		},
+		{
+			name:          "Sentinel TLS provided without a secret",
+			rfName:        "test",
+			rfSentinelTLS: &TLSSettings{},
+			expectedError: "sentinel TLS must include a secretName when provided",
+		},
+		{
+			name:        "Auth users provided",
+			rfName:      "test",
+			rfAuthUsers: []RedisUser{{Name: "app", SecretPath: "app-password", Rules: []string{"~app:*", "+@all"}}},
+		},
+		{
+			name:          "Auth user with a reserved name",
+			rfName:        "test",
+			rfAuthUsers:   []RedisUser{{Name: OperatorUser, SecretPath: "operator-password"}},
+			expectedError: "auth user redis-operator is reserved",
+		},
+		{
+			name:          "Auth user defined twice",
+			rfName:        "test",
+			rfAuthUsers:   []RedisUser{{Name: "app", SecretPath: "app-password"}, {Name: "app", SecretPath: "other-password"}},
+			expectedError: "auth user app is defined more than once",
+		},
+		{
+			name:          "Auth user without a secret",
+			rfName:        "test",
+			rfAuthUsers:   []RedisUser{{Name: "app"}},
+			expectedError: "auth user app must include a secretPath",
+		},
+		{
+			name:   "Backup provided",
+			rfName: "test",
+			rfBackup: &BackupSettings{
+				Schedule: "0 3 * * *",
+				Storage:  BackupStorage{S3: &S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+			},
+			expectedBackup: &BackupSettings{
+				Schedule: "0 3 * * *",
+				Storage:  BackupStorage{S3: &S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+				Image:    defaultBackupAgentImage,
+			},
+		},
+		{
+			name:   "Backup with an invalid schedule",
+			rfName: "test",
+			rfBackup: &BackupSettings{
+				Schedule: "every day",
+				Storage:  BackupStorage{S3: &S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+			},
+			expectedError: `backup schedule "every day" is not valid: expected exactly 5 fields, found 2: [every day]`,
+		},
+		{
+			name:          "Backup without a storage",
+			rfName:        "test",
+			rfBackup:      &BackupSettings{},
+			expectedError: "backup must include a storage",
+		},
+		{
+			name:   "Backup S3 storage without a bucket",
+			rfName: "test",
+			rfBackup: &BackupSettings{
+				Storage: BackupStorage{S3: &S3BackupStorage{Endpoint: "http://minio:9000", CredentialsSecret: "minio"}},
+			},
+			expectedError: "backup S3 storage must include an endpoint, a bucket and a credentialsSecret",
+		},
+		{
+			name:            "Restore provided",
+			rfName:          "test",
+			rfRestore:       &RestoreSettings{BackupName: "backup"},
+			expectedRestore: &RestoreSettings{BackupName: "backup", Image: defaultBackupAgentImage},
+		},
+		{
+			name:          "Restore without a source",
+			rfName:        "test",
+			rfRestore:     &RestoreSettings{},
+			expectedError: "restore must include exactly one of backupName, url or persistentVolumeClaim",
+		},
+		{
+			name:          "Restore with two sources",
+			rfName:        "test",
+			rfRestore:     &RestoreSettings{BackupName: "backup", URL: "https://backups/dump.rdb"},
+			expectedError: "restore must include exactly one of backupName, url or persistentVolumeClaim",
+		},
+		{
+			name:          "Restore volume without a path",
+			rfName:        "test",
+			rfRestore:     &RestoreSettings{PersistentVolumeClaim: &RestoreVolumeSource{ClaimName: "backups"}},
+			expectedError: "restore persistentVolumeClaim must include a claimName and a path",
+		},
+		{
+			name:            "Restore while bootstrapping",
+			rfName:          "test",
+			rfBootstrapNode: &BootstrapSettings{Host: "127.0.0.1"},
+			rfRestore:       &RestoreSettings{URL: "https://backups/dump.rdb"},
+			expectedError:   "restore can't be used with a bootstrapNode",
+		},
+		{
+			name:                   "Master election by highest offset",
+			rfName:                 "test",
+			rfMasterElection:       MasterElectionHighestOffset,
+			expectedMasterElection: MasterElectionHighestOffset,
+		},
+		{
+			name:             "Unknown master election",
+			rfName:           "test",
+			rfMasterElection: "newest",
+			expectedError:    `redis masterElection "newest" is not valid, must be oldest or highestOffset`,
+		},
+		{
+			name:               "Automatic split-brain resolution",
+			rfName:             "test",
+			rfSplitBrain:       SplitBrainResolutionAutomatic,
+			expectedSplitBrain: SplitBrainResolutionAutomatic,
+		},
+		{
+			name:          "Unknown split-brain resolution",
+			rfName:        "test",
+			rfSplitBrain:  "auto",
+			expectedError: `redis splitBrainResolution "auto" is not valid, must be manual or automatic`,
+		},
+		{
+			name:             "Update strategy provided",
+			rfName:           "test",
+			rfUpdateStrategy: RedisUpdateStrategy{Partition: 1, MinSyncedSeconds: 30, MasterSwitchover: true, MaxReplicationLag: &maxReplicationLag},
+		},
+		{
+			name:             "Update strategy with a negative partition",
+			rfName:           "test",
+			rfUpdateStrategy: RedisUpdateStrategy{Partition: -1},
+			expectedError:    "redis updateStrategy partition can't be negative",
+		},
+		{
+			name:             "Update strategy with a negative replication lag",
+			rfName:           "test",
+			rfUpdateStrategy: RedisUpdateStrategy{MaxReplicationLag: &negativeReplicationLag},
+			expectedError:    "redis updateStrategy maxReplicationLag can't be negative",
+		},
+		{
+			name:               "Sentinels as a statefulset",
+			rfName:             "test",
+			rfSentinelWorkload: SentinelWorkloadStatefulSet,
+			expectedWorkload:   SentinelWorkloadStatefulSet,
+		},
+		{
+			name:               "Unknown sentinel workload type",
+			rfName:             "test",
+			rfSentinelWorkload: "DaemonSet",
+			expectedError:      `sentinel workloadType "DaemonSet" is not valid, must be Deployment or StatefulSet`,
+		},
+		{
+			name:                "Hostname address mode",
+			rfName:              "test",
+			rfAddressMode:       AddressModeHostname,
+			expectedAddressMode: AddressModeHostname,
+		},
+		{
+			name:          "Unknown address mode",
+			rfName:        "test",
+			rfAddressMode: "dns",
+			expectedError: `addressMode "dns" is not valid, must be ip or hostname`,
+		},
+		{
+			name:             "Dual-stack services",
+			rfName:           "test",
+			rfIPFamilyPolicy: &preferDualStack,
+			rfIPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+		},
+		{
+			name:             "IPv6 single-stack services",
+			rfName:           "test",
+			rfIPFamilyPolicy: &singleStack,
+			rfIPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol},
+		},
+		{
+			name:             "Unknown IP family policy",
+			rfName:           "test",
+			rfIPFamilyPolicy: &unknownPolicy,
+			expectedError:    `ipFamilyPolicy "DualStack" is not valid, must be SingleStack, PreferDualStack or RequireDualStack`,
+		},
+		{
+			name:          "Unknown IP family",
+			rfName:        "test",
+			rfIPFamilies:  []corev1.IPFamily{"IPv5"},
+			expectedError: `ipFamilies "IPv5" is not valid, must be IPv4 or IPv6`,
+		},
+		{
+			name:             "IP family given twice",
+			rfName:           "test",
+			rfIPFamilyPolicy: &preferDualStack,
+			rfIPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv6Protocol},
+			expectedError:    "ipFamilies IPv6 is given more than once",
+		},
+		{
+			name:          "Two IP families on single-stack services",
+			rfName:        "test",
+			rfIPFamilies:  []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			expectedError: "ipFamilies can't have two families with a SingleStack ipFamilyPolicy",
+		},
 	}
 
 	for _, test := range tests {
@@ -73,6 +326,18 @@ func TestValidate(t *testing.T) {
 			rf := generateRedisFailover(test.rfName, test.rfBootstrapNode)
 			rf.Spec.Redis.CustomConfig = test.rfRedisCustomConfig
 			rf.Spec.Sentinel.CustomConfig = test.rfSentinelCustomConfig
+			rf.Spec.Redis.TLS = test.rfRedisTLS
+			rf.Spec.Sentinel.TLS = test.rfSentinelTLS
+			rf.Spec.Auth.Users = test.rfAuthUsers
+			rf.Spec.Backup = test.rfBackup
+			rf.Spec.Restore = test.rfRestore
+			rf.Spec.Redis.MasterElection = test.rfMasterElection
+			rf.Spec.Redis.SplitBrainResolution = test.rfSplitBrain
+			rf.Spec.Redis.UpdateStrategy = test.rfUpdateStrategy
+			rf.Spec.Sentinel.WorkloadType = test.rfSentinelWorkload
+			rf.Spec.AddressMode = test.rfAddressMode
+			rf.Spec.IPFamilyPolicy = test.rfIPFamilyPolicy
+			rf.Spec.IPFamilies = test.rfIPFamilies
 
 			err := rf.Validate()
 
@@ -95,6 +360,23 @@ func TestValidate(t *testing.T) {
 					expectedSentinelCustomConfig = test.rfSentinelCustomConfig
 				}
 
+				expectedMasterElection := MasterElectionOldest
+				if test.expectedMasterElection != "" {
+					expectedMasterElection = test.expectedMasterElection
+				}
+				expectedSplitBrain := SplitBrainResolutionManual
+				if test.expectedSplitBrain != "" {
+					expectedSplitBrain = test.expectedSplitBrain
+				}
+				expectedWorkload := SentinelWorkloadDeployment
+				if test.expectedWorkload != "" {
+					expectedWorkload = test.expectedWorkload
+				}
+				expectedAddressMode := AddressModeIP
+				if test.expectedAddressMode != "" {
+					expectedAddressMode = test.expectedAddressMode
+				}
+
 				expectedRF := &RedisFailover{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      test.rfName,
@@ -108,7 +390,10 @@ func TestValidate(t *testing.T) {
 							Exporter: Exporter{
 								Image: defaultExporterImage,
 							},
-							CustomConfig: expectedRedisCustomConfig,
+							CustomConfig:         expectedRedisCustomConfig,
+							MasterElection:       expectedMasterElection,
+							SplitBrainResolution: expectedSplitBrain,
+							UpdateStrategy:       test.rfUpdateStrategy,
 						},
 						Sentinel: SentinelSettings{
 							Image:        defaultImage,
@@ -117,8 +402,17 @@ func TestValidate(t *testing.T) {
 							Exporter: Exporter{
 								Image: defaultSentinelExporterImage,
 							},
+							WorkloadType: expectedWorkload,
+						},
+						Auth: AuthSettings{
+							Users: test.rfAuthUsers,
 						},
-						BootstrapNode: test.expectedBootstrapNode,
+						BootstrapNode:  test.expectedBootstrapNode,
+						Backup:         test.expectedBackup,
+						Restore:        test.expectedRestore,
+						AddressMode:    expectedAddressMode,
+						IPFamilyPolicy: test.rfIPFamilyPolicy,
+						IPFamilies:     test.rfIPFamilies,
 					},
 				}
 				assert.Equal(expectedRF, rf)
@@ -0,0 +1,384 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+)
+
+// The spec of the other version is kept in an annotation when the conversion can't give it back, as a
+// v1 bootstrap port written "06379" or a v2 masterNamePolicy set to Default. It is used again on the
+// conversion back as long as the spec wasn't changed meanwhile.
+const (
+	V1SpecAnnotation = "redis-failover.freshworks.com/v1-spec"
+	V2SpecAnnotation = "redis-failover.freshworks.com/v2-spec"
+)
+
+// Sentinel directives held by the sentinel tuning
+const (
+	downAfterMillisecondsConfig = "down-after-milliseconds"
+	failoverTimeoutConfig       = "failover-timeout"
+	parallelSyncsConfig         = "parallel-syncs"
+)
+
+// ConvertTo converts the RF to the v1 RF given
+func (r *RedisFailover) ConvertTo(dst *redisfailoverv1.RedisFailover) error {
+	dst.TypeMeta = r.TypeMeta
+	dst.APIVersion = redisfailoverv1.SchemeGroupVersion.String()
+	r.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	r.Status.DeepCopyInto(&dst.Status)
+	dst.Spec = specToV1(&r.Spec)
+
+	if saved, ok := popAnnotation(&dst.Annotations, V1SpecAnnotation); ok {
+		spec := redisfailoverv1.RedisFailoverSpec{}
+		if err := json.Unmarshal([]byte(saved), &spec); err == nil && equalJSON(specFromV1(&spec), r.Spec) {
+			dst.Spec = spec
+			return nil
+		}
+	}
+	popAnnotation(&dst.Annotations, V2SpecAnnotation)
+	return keepSpec(&dst.Annotations, V2SpecAnnotation, specFromV1(&dst.Spec), r.Spec)
+}
+
+// ConvertFrom converts the v1 RF given to the RF
+func (r *RedisFailover) ConvertFrom(src *redisfailoverv1.RedisFailover) error {
+	r.TypeMeta = src.TypeMeta
+	r.APIVersion = SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&r.ObjectMeta)
+	src.Status.DeepCopyInto(&r.Status)
+	r.Spec = specFromV1(&src.Spec)
+
+	if saved, ok := popAnnotation(&r.Annotations, V2SpecAnnotation); ok {
+		spec := RedisFailoverSpec{}
+		if err := json.Unmarshal([]byte(saved), &spec); err == nil && equalJSON(specToV1(&spec), src.Spec) {
+			r.Spec = spec
+			return nil
+		}
+	}
+	popAnnotation(&r.Annotations, V1SpecAnnotation)
+	return keepSpec(&r.Annotations, V1SpecAnnotation, specToV1(&r.Spec), src.Spec)
+}
+
+func specToV1(in *RedisFailoverSpec) redisfailoverv1.RedisFailoverSpec {
+	spec := in.DeepCopy()
+	out := redisfailoverv1.RedisFailoverSpec{
+		LabelWhitelist: spec.LabelWhitelist,
+		Backup:         spec.Backup,
+		Restore:        spec.Restore,
+		AddressMode:    spec.Network.AddressMode,
+		IPFamilyPolicy: spec.Network.IPFamilyPolicy,
+		IPFamilies:     spec.Network.IPFamilies,
+	}
+
+	r := &spec.Redis
+	out.Redis = redisfailoverv1.RedisSettings{
+		Image:                         r.Image,
+		ImagePullPolicy:               r.ImagePullPolicy,
+		Replicas:                      r.Replicas,
+		Port:                          r.Port,
+		Resources:                     r.Resources,
+		CustomConfig:                  r.CustomConfig,
+		ConfigRewrite:                 r.ConfigRewrite,
+		CustomCommandRenames:          r.CustomCommandRenames,
+		Command:                       r.Command,
+		ShutdownConfigMap:             r.ShutdownConfigMap,
+		StartupConfigMap:              r.StartupConfigMap,
+		Storage:                       spec.Persistence.Redis,
+		InitContainers:                r.InitContainers,
+		Exporter:                      r.Exporter,
+		ExtraContainers:               r.ExtraContainers,
+		Affinity:                      r.Affinity,
+		SecurityContext:               r.SecurityContext,
+		ContainerSecurityContext:      r.ContainerSecurityContext,
+		ImagePullSecrets:              r.ImagePullSecrets,
+		Tolerations:                   r.Tolerations,
+		TopologySpreadConstraints:     r.TopologySpreadConstraints,
+		NodeSelector:                  r.NodeSelector,
+		PodAnnotations:                r.PodAnnotations,
+		ServiceAnnotations:            r.ServiceAnnotations,
+		HostNetwork:                   r.HostNetwork,
+		DNSPolicy:                     r.DNSPolicy,
+		PriorityClassName:             r.PriorityClassName,
+		ServiceAccountName:            r.ServiceAccountName,
+		TerminationGracePeriodSeconds: r.TerminationGracePeriodSeconds,
+		ExtraVolumes:                  r.ExtraVolumes,
+		ExtraVolumeMounts:             r.ExtraVolumeMounts,
+		CustomLivenessProbe:           r.CustomLivenessProbe,
+		CustomReadinessProbe:          r.CustomReadinessProbe,
+		CustomStartupProbe:            r.CustomStartupProbe,
+		DisablePodDisruptionBudget:    r.DisablePodDisruptionBudget,
+		TLS:                           r.TLS,
+		MasterElection:                r.MasterElection,
+		SplitBrainResolution:          r.SplitBrainResolution,
+		UpdateStrategy:                spec.UpdateStrategy,
+	}
+
+	s := &spec.Sentinel
+	out.Sentinel = redisfailoverv1.SentinelSettings{
+		Image:                      s.Image,
+		ImagePullPolicy:            s.ImagePullPolicy,
+		Replicas:                   s.Replicas,
+		Resources:                  s.Resources,
+		CustomConfig:               append(s.CustomConfig, s.Tuning.config()...),
+		Command:                    s.Command,
+		StartupConfigMap:           s.StartupConfigMap,
+		Affinity:                   s.Affinity,
+		SecurityContext:            s.SecurityContext,
+		ContainerSecurityContext:   s.ContainerSecurityContext,
+		ImagePullSecrets:           s.ImagePullSecrets,
+		Tolerations:                s.Tolerations,
+		TopologySpreadConstraints:  s.TopologySpreadConstraints,
+		NodeSelector:               s.NodeSelector,
+		PodAnnotations:             s.PodAnnotations,
+		ServiceAnnotations:         s.ServiceAnnotations,
+		InitContainers:             s.InitContainers,
+		Exporter:                   s.Exporter,
+		ExtraContainers:            s.ExtraContainers,
+		ConfigCopy:                 s.ConfigCopy,
+		HostNetwork:                s.HostNetwork,
+		DNSPolicy:                  s.DNSPolicy,
+		PriorityClassName:          s.PriorityClassName,
+		ServiceAccountName:         s.ServiceAccountName,
+		ExtraVolumes:               s.ExtraVolumes,
+		ExtraVolumeMounts:          s.ExtraVolumeMounts,
+		CustomLivenessProbe:        s.CustomLivenessProbe,
+		CustomReadinessProbe:       s.CustomReadinessProbe,
+		CustomStartupProbe:         s.CustomStartupProbe,
+		DisablePodDisruptionBudget: s.DisablePodDisruptionBudget,
+		DisableMyMaster:            s.MasterNamePolicy == MasterNameResourceName,
+		TLS:                        s.TLS,
+		WorkloadType:               s.WorkloadType,
+		ConfigStorage:              spec.Persistence.Sentinel,
+	}
+
+	if spec.Auth.PasswordSecret != nil {
+		out.Auth.SecretPath = spec.Auth.PasswordSecret.Name
+	}
+	out.Auth.PasswordRotationGracePeriodSeconds = spec.Auth.PasswordRotationGracePeriodSeconds
+	for _, user := range spec.Auth.Users {
+		out.Auth.Users = append(out.Auth.Users, redisfailoverv1.RedisUser{
+			Name:       user.Name,
+			SecretPath: user.PasswordSecret.Name,
+			Rules:      user.Rules,
+		})
+	}
+
+	if b := spec.BootstrapNode; b != nil {
+		out.BootstrapNode = &redisfailoverv1.BootstrapSettings{
+			Host:           b.Host,
+			AllowSentinels: b.AllowSentinels,
+		}
+		if b.Port != 0 {
+			out.BootstrapNode.Port = strconv.Itoa(int(b.Port))
+		}
+	}
+	return out
+}
+
+func specFromV1(in *redisfailoverv1.RedisFailoverSpec) RedisFailoverSpec {
+	spec := in.DeepCopy()
+	out := RedisFailoverSpec{
+		Persistence: PersistenceSettings{
+			Redis:    spec.Redis.Storage,
+			Sentinel: spec.Sentinel.ConfigStorage,
+		},
+		UpdateStrategy: spec.Redis.UpdateStrategy,
+		Network: NetworkSettings{
+			AddressMode:    spec.AddressMode,
+			IPFamilyPolicy: spec.IPFamilyPolicy,
+			IPFamilies:     spec.IPFamilies,
+		},
+		LabelWhitelist: spec.LabelWhitelist,
+		Backup:         spec.Backup,
+		Restore:        spec.Restore,
+	}
+
+	r := &spec.Redis
+	out.Redis = RedisSettings{
+		Image:                         r.Image,
+		ImagePullPolicy:               r.ImagePullPolicy,
+		Replicas:                      r.Replicas,
+		Port:                          r.Port,
+		Resources:                     r.Resources,
+		CustomConfig:                  r.CustomConfig,
+		ConfigRewrite:                 r.ConfigRewrite,
+		CustomCommandRenames:          r.CustomCommandRenames,
+		Command:                       r.Command,
+		ShutdownConfigMap:             r.ShutdownConfigMap,
+		StartupConfigMap:              r.StartupConfigMap,
+		InitContainers:                r.InitContainers,
+		Exporter:                      r.Exporter,
+		ExtraContainers:               r.ExtraContainers,
+		Affinity:                      r.Affinity,
+		SecurityContext:               r.SecurityContext,
+		ContainerSecurityContext:      r.ContainerSecurityContext,
+		ImagePullSecrets:              r.ImagePullSecrets,
+		Tolerations:                   r.Tolerations,
+		TopologySpreadConstraints:     r.TopologySpreadConstraints,
+		NodeSelector:                  r.NodeSelector,
+		PodAnnotations:                r.PodAnnotations,
+		ServiceAnnotations:            r.ServiceAnnotations,
+		HostNetwork:                   r.HostNetwork,
+		DNSPolicy:                     r.DNSPolicy,
+		PriorityClassName:             r.PriorityClassName,
+		ServiceAccountName:            r.ServiceAccountName,
+		TerminationGracePeriodSeconds: r.TerminationGracePeriodSeconds,
+		ExtraVolumes:                  r.ExtraVolumes,
+		ExtraVolumeMounts:             r.ExtraVolumeMounts,
+		CustomLivenessProbe:           r.CustomLivenessProbe,
+		CustomReadinessProbe:          r.CustomReadinessProbe,
+		CustomStartupProbe:            r.CustomStartupProbe,
+		DisablePodDisruptionBudget:    r.DisablePodDisruptionBudget,
+		TLS:                           r.TLS,
+		MasterElection:                r.MasterElection,
+		SplitBrainResolution:          r.SplitBrainResolution,
+	}
+
+	s := &spec.Sentinel
+	tuning, customConfig := tuningFromConfig(s.CustomConfig)
+	out.Sentinel = SentinelSettings{
+		Image:                      s.Image,
+		ImagePullPolicy:            s.ImagePullPolicy,
+		Replicas:                   s.Replicas,
+		Resources:                  s.Resources,
+		Tuning:                     tuning,
+		CustomConfig:               customConfig,
+		Command:                    s.Command,
+		StartupConfigMap:           s.StartupConfigMap,
+		Affinity:                   s.Affinity,
+		SecurityContext:            s.SecurityContext,
+		ContainerSecurityContext:   s.ContainerSecurityContext,
+		ImagePullSecrets:           s.ImagePullSecrets,
+		Tolerations:                s.Tolerations,
+		TopologySpreadConstraints:  s.TopologySpreadConstraints,
+		NodeSelector:               s.NodeSelector,
+		PodAnnotations:             s.PodAnnotations,
+		ServiceAnnotations:         s.ServiceAnnotations,
+		InitContainers:             s.InitContainers,
+		Exporter:                   s.Exporter,
+		ExtraContainers:            s.ExtraContainers,
+		ConfigCopy:                 s.ConfigCopy,
+		HostNetwork:                s.HostNetwork,
+		DNSPolicy:                  s.DNSPolicy,
+		PriorityClassName:          s.PriorityClassName,
+		ServiceAccountName:         s.ServiceAccountName,
+		ExtraVolumes:               s.ExtraVolumes,
+		ExtraVolumeMounts:          s.ExtraVolumeMounts,
+		CustomLivenessProbe:        s.CustomLivenessProbe,
+		CustomReadinessProbe:       s.CustomReadinessProbe,
+		CustomStartupProbe:         s.CustomStartupProbe,
+		DisablePodDisruptionBudget: s.DisablePodDisruptionBudget,
+		TLS:                        s.TLS,
+		WorkloadType:               s.WorkloadType,
+	}
+	if s.DisableMyMaster {
+		out.Sentinel.MasterNamePolicy = MasterNameResourceName
+	}
+
+	if spec.Auth.SecretPath != "" {
+		out.Auth.PasswordSecret = &corev1.LocalObjectReference{Name: spec.Auth.SecretPath}
+	}
+	out.Auth.PasswordRotationGracePeriodSeconds = spec.Auth.PasswordRotationGracePeriodSeconds
+	for _, user := range spec.Auth.Users {
+		out.Auth.Users = append(out.Auth.Users, RedisUser{
+			Name:           user.Name,
+			PasswordSecret: corev1.LocalObjectReference{Name: user.SecretPath},
+			Rules:          user.Rules,
+		})
+	}
+
+	if b := spec.BootstrapNode; b != nil {
+		out.BootstrapNode = &BootstrapSettings{
+			Host:           b.Host,
+			AllowSentinels: b.AllowSentinels,
+		}
+		// A port not valid is left out, the spec kept in the annotation gives it back
+		if port, err := strconv.ParseInt(b.Port, 10, 32); err == nil {
+			out.BootstrapNode.Port = int32(port)
+		}
+	}
+	return out
+}
+
+// config returns the sentinel directives of the tuning
+func (t SentinelTuning) config() []string {
+	config := []string{}
+	if t.DownAfterMilliseconds != nil {
+		config = append(config, downAfterMillisecondsConfig+" "+strconv.FormatInt(*t.DownAfterMilliseconds, 10))
+	}
+	if t.FailoverTimeout != nil {
+		config = append(config, failoverTimeoutConfig+" "+strconv.FormatInt(*t.FailoverTimeout, 10))
+	}
+	if t.ParallelSyncs != nil {
+		config = append(config, parallelSyncsConfig+" "+strconv.FormatInt(int64(*t.ParallelSyncs), 10))
+	}
+	return config
+}
+
+// tuningFromConfig moves the sentinel directives of the tuning out of the custom config given
+func tuningFromConfig(config []string) (SentinelTuning, []string) {
+	tuning := SentinelTuning{}
+	var rest []string
+	for _, line := range config {
+		param, value, _ := strings.Cut(line, " ")
+		switch param {
+		case downAfterMillisecondsConfig, failoverTimeoutConfig:
+			if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+				if param == downAfterMillisecondsConfig {
+					tuning.DownAfterMilliseconds = &v
+				} else {
+					tuning.FailoverTimeout = &v
+				}
+				continue
+			}
+		case parallelSyncsConfig:
+			if v, err := strconv.ParseInt(value, 10, 32); err == nil {
+				syncs := int32(v)
+				tuning.ParallelSyncs = &syncs
+				continue
+			}
+		}
+		rest = append(rest, line)
+	}
+	return tuning, rest
+}
+
+// keepSpec saves the original spec in the annotation given when converting back the spec converted
+// doesn't give it
+func keepSpec(annotations *map[string]string, annotation string, convertedBack, original interface{}) error {
+	if equalJSON(convertedBack, original) {
+		return nil
+	}
+	raw, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+	(*annotations)[annotation] = string(raw)
+	return nil
+}
+
+func popAnnotation(annotations *map[string]string, annotation string) (string, bool) {
+	value, ok := (*annotations)[annotation]
+	if !ok {
+		return "", false
+	}
+	delete(*annotations, annotation)
+	if len(*annotations) == 0 {
+		*annotations = nil
+	}
+	return value, true
+}
+
+func equalJSON(a, b interface{}) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(rawA, rawB)
+}
@@ -0,0 +1,277 @@
+package v2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	redisfailoverv2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
+)
+
+func int64Ptr(i int64) *int64 { return &i }
+func int32Ptr(i int32) *int32 { return &i }
+
+func generateV1RedisFailover() *redisfailoverv1.RedisFailover {
+	size := resource.MustParse("20Mi")
+	storageClass := "fast"
+	policy := corev1.IPFamilyPolicyPreferDualStack
+	return &redisfailoverv1.RedisFailover{
+		TypeMeta: metav1.TypeMeta{APIVersion: "databases.spotahome.com/v1", Kind: "RedisFailover"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "namespace",
+			Annotations: map[string]string{"team/owner": "cache"},
+		},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Redis: redisfailoverv1.RedisSettings{
+				Image:         "redis:7",
+				Replicas:      3,
+				Port:          6380,
+				CustomConfig:  []string{"maxmemory 1gb"},
+				ConfigRewrite: true,
+				Storage: redisfailoverv1.RedisStorage{
+					KeepAfterDeletion: true,
+					EmptyDir:          &corev1.EmptyDirVolumeSource{},
+				},
+				MasterElection: redisfailoverv1.MasterElectionHighestOffset,
+				UpdateStrategy: redisfailoverv1.RedisUpdateStrategy{
+					Partition:         1,
+					MasterSwitchover:  true,
+					MaxReplicationLag: int64Ptr(1024),
+				},
+			},
+			Sentinel: redisfailoverv1.SentinelSettings{
+				Replicas:        3,
+				CustomConfig:    []string{"down-after-milliseconds 5000", "failover-timeout 10000"},
+				DisableMyMaster: true,
+				WorkloadType:    redisfailoverv1.SentinelWorkloadStatefulSet,
+				ConfigStorage: redisfailoverv1.SentinelConfigStorage{
+					Size:             &size,
+					StorageClassName: &storageClass,
+				},
+			},
+			Auth: redisfailoverv1.AuthSettings{
+				SecretPath: "redis-auth",
+				Users: []redisfailoverv1.RedisUser{
+					{Name: "app", SecretPath: "app-auth", Rules: []string{"~cache:*", "+@read"}},
+				},
+				PasswordRotationGracePeriodSeconds: 600,
+			},
+			LabelWhitelist: []string{"team"},
+			BootstrapNode:  &redisfailoverv1.BootstrapSettings{Host: "10.0.0.1", Port: "6379", AllowSentinels: true},
+			AddressMode:    redisfailoverv1.AddressModeHostname,
+			IPFamilyPolicy: &policy,
+			IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+		},
+		Status: redisfailoverv1.RedisFailoverStatus{
+			Phase:              "Ready",
+			ObservedGeneration: 2,
+			Master:             redisfailoverv1.MasterStatus{PodName: "rfr-test-0", IP: "10.0.0.2"},
+		},
+	}
+}
+
+func TestConvertFromV1(t *testing.T) {
+	assert := assert.New(t)
+
+	src := generateV1RedisFailover()
+	rf := &redisfailoverv2.RedisFailover{}
+	require.NoError(t, rf.ConvertFrom(src))
+
+	assert.Equal("databases.spotahome.com/v2", rf.APIVersion)
+	assert.Equal(map[string]string{"team/owner": "cache"}, rf.Annotations)
+	assert.Equal(src.Status, rf.Status)
+	assert.Equal(redisfailoverv2.MasterNameResourceName, rf.Spec.Sentinel.MasterNamePolicy)
+	assert.Equal(redisfailoverv2.SentinelTuning{DownAfterMilliseconds: int64Ptr(5000), FailoverTimeout: int64Ptr(10000)}, rf.Spec.Sentinel.Tuning)
+	assert.Empty(rf.Spec.Sentinel.CustomConfig)
+	assert.Equal(&corev1.LocalObjectReference{Name: "redis-auth"}, rf.Spec.Auth.PasswordSecret)
+	assert.Equal([]redisfailoverv2.RedisUser{
+		{Name: "app", PasswordSecret: corev1.LocalObjectReference{Name: "app-auth"}, Rules: []string{"~cache:*", "+@read"}},
+	}, rf.Spec.Auth.Users)
+	assert.Equal(int32(600), rf.Spec.Auth.PasswordRotationGracePeriodSeconds)
+	assert.Equal(src.Spec.Redis.Storage, rf.Spec.Persistence.Redis)
+	assert.Equal(src.Spec.Sentinel.ConfigStorage, rf.Spec.Persistence.Sentinel)
+	assert.Equal(src.Spec.Redis.UpdateStrategy, rf.Spec.UpdateStrategy)
+	assert.Equal(redisfailoverv2.NetworkSettings{
+		AddressMode:    src.Spec.AddressMode,
+		IPFamilyPolicy: src.Spec.IPFamilyPolicy,
+		IPFamilies:     src.Spec.IPFamilies,
+	}, rf.Spec.Network)
+	assert.Equal(&redisfailoverv2.BootstrapSettings{Host: "10.0.0.1", Port: 6379, AllowSentinels: true}, rf.Spec.BootstrapNode)
+
+	// The source is left as it was
+	assert.Equal(generateV1RedisFailover(), src)
+}
+
+func TestConvertToV1(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv2.RedisFailover{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "databases.spotahome.com/v2", Kind: "RedisFailover"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "namespace"},
+		Spec: redisfailoverv2.RedisFailoverSpec{
+			Sentinel: redisfailoverv2.SentinelSettings{
+				CustomConfig: []string{"resolve-hostnames yes"},
+				Tuning:       redisfailoverv2.SentinelTuning{DownAfterMilliseconds: int64Ptr(1000), ParallelSyncs: int32Ptr(2)},
+			},
+			Auth:          redisfailoverv2.AuthSettings{PasswordSecret: &corev1.LocalObjectReference{Name: "redis-auth"}},
+			BootstrapNode: &redisfailoverv2.BootstrapSettings{Host: "10.0.0.1", Port: 6380},
+		},
+	}
+	dst := &redisfailoverv1.RedisFailover{}
+	require.NoError(t, rf.ConvertTo(dst))
+
+	assert.Equal("databases.spotahome.com/v1", dst.APIVersion)
+	assert.Empty(dst.Annotations)
+	assert.False(dst.Spec.Sentinel.DisableMyMaster)
+	assert.Equal([]string{"resolve-hostnames yes", "down-after-milliseconds 1000", "parallel-syncs 2"}, dst.Spec.Sentinel.CustomConfig)
+	assert.Equal("redis-auth", dst.Spec.Auth.SecretPath)
+	assert.Equal(&redisfailoverv1.BootstrapSettings{Host: "10.0.0.1", Port: "6380"}, dst.Spec.BootstrapNode)
+	assert.Equal("mymaster", dst.MasterName())
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		v1            func(rf *redisfailoverv1.RedisFailover)
+		expAnnotation bool
+	}{
+		{
+			name: "Spec converted as it is",
+		},
+		{
+			name: "Empty spec",
+			v1: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec = redisfailoverv1.RedisFailoverSpec{}
+				rf.Annotations = nil
+			},
+		},
+		{
+			name: "Bootstrap port with a leading zero",
+			v1: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.BootstrapNode.Port = "06379"
+			},
+			expAnnotation: true,
+		},
+		{
+			name: "Sentinel tuning not at the end of the custom config",
+			v1: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.Sentinel.CustomConfig = []string{"failover-timeout 10000", "resolve-hostnames yes"}
+			},
+			expAnnotation: true,
+		},
+		{
+			name: "Sentinel tuning given twice",
+			v1: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.Sentinel.CustomConfig = []string{"parallel-syncs 1", "parallel-syncs 2"}
+			},
+			expAnnotation: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			src := generateV1RedisFailover()
+			if test.v1 != nil {
+				test.v1(src)
+			}
+			rf := &redisfailoverv2.RedisFailover{}
+			require.NoError(t, rf.ConvertFrom(src))
+			_, ok := rf.Annotations[redisfailoverv2.V1SpecAnnotation]
+			assert.Equal(test.expAnnotation, ok)
+
+			dst := &redisfailoverv1.RedisFailover{}
+			require.NoError(t, rf.ConvertTo(dst))
+			assert.Equal(src, dst)
+		})
+	}
+}
+
+func TestConversionRoundTripFromV2(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          redisfailoverv2.RedisFailoverSpec
+		expAnnotation bool
+	}{
+		{
+			name: "Master name policy not given",
+			spec: redisfailoverv2.RedisFailoverSpec{
+				Sentinel: redisfailoverv2.SentinelSettings{Replicas: 3},
+			},
+		},
+		{
+			name: "Master name policy by default",
+			spec: redisfailoverv2.RedisFailoverSpec{
+				Sentinel: redisfailoverv2.SentinelSettings{MasterNamePolicy: redisfailoverv2.MasterNameDefault},
+			},
+			expAnnotation: true,
+		},
+		{
+			name: "Sentinel tuning also in the custom config",
+			spec: redisfailoverv2.RedisFailoverSpec{
+				Sentinel: redisfailoverv2.SentinelSettings{
+					CustomConfig: []string{"failover-timeout 20000"},
+					Tuning:       redisfailoverv2.SentinelTuning{FailoverTimeout: int64Ptr(10000)},
+				},
+			},
+			expAnnotation: true,
+		},
+		{
+			name: "User without a secret",
+			spec: redisfailoverv2.RedisFailoverSpec{
+				Auth: redisfailoverv2.AuthSettings{
+					PasswordSecret: &corev1.LocalObjectReference{},
+					Users:          []redisfailoverv2.RedisUser{{Name: "app"}},
+				},
+			},
+			expAnnotation: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			src := &redisfailoverv2.RedisFailover{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "databases.spotahome.com/v2", Kind: "RedisFailover"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "namespace"},
+				Spec:       test.spec,
+			}
+			dst := &redisfailoverv1.RedisFailover{}
+			require.NoError(t, src.ConvertTo(dst))
+			_, ok := dst.Annotations[redisfailoverv2.V2SpecAnnotation]
+			assert.Equal(test.expAnnotation, ok)
+
+			rf := &redisfailoverv2.RedisFailover{}
+			require.NoError(t, rf.ConvertFrom(dst))
+			assert.Equal(src, rf)
+		})
+	}
+}
+
+func TestConversionAfterChange(t *testing.T) {
+	assert := assert.New(t)
+
+	src := &redisfailoverv2.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "namespace"},
+		Spec: redisfailoverv2.RedisFailoverSpec{
+			Sentinel: redisfailoverv2.SentinelSettings{MasterNamePolicy: redisfailoverv2.MasterNameDefault},
+		},
+	}
+	v1rf := &redisfailoverv1.RedisFailover{}
+	require.NoError(t, src.ConvertTo(v1rf))
+
+	// The spec saved is not used once the v1 spec changed
+	v1rf.Spec.Sentinel.DisableMyMaster = true
+	rf := &redisfailoverv2.RedisFailover{}
+	require.NoError(t, rf.ConvertFrom(v1rf))
+	assert.Equal(redisfailoverv2.MasterNameResourceName, rf.Spec.Sentinel.MasterNamePolicy)
+	assert.Empty(rf.Annotations)
+}
@@ -0,0 +1,220 @@
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailover represents a Redis failover
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
+// +kubebuilder:printcolumn:name="REDIS",type="integer",JSONPath=".spec.redis.replicas"
+// +kubebuilder:printcolumn:name="SENTINELS",type="integer",JSONPath=".spec.sentinel.replicas"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="MASTER",type="string",JSONPath=".status.master.podName"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=redisfailover,path=redisfailovers,shortName=rf,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.redis.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:storageversion
+type RedisFailover struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RedisFailoverSpec                   `json:"spec"`
+	Status            redisfailoverv1.RedisFailoverStatus `json:"status,omitempty"`
+}
+
+// RedisFailoverSpec represents a Redis failover spec
+// +kubebuilder:validation:XValidation:rule="!has(self.restore) || !has(self.bootstrapNode)",message="restore can't be used with a bootstrapNode"
+type RedisFailoverSpec struct {
+	Redis          RedisSettings                       `json:"redis,omitempty"`
+	Sentinel       SentinelSettings                    `json:"sentinel,omitempty"`
+	Auth           AuthSettings                        `json:"auth,omitempty"`
+	Persistence    PersistenceSettings                 `json:"persistence,omitempty"`
+	UpdateStrategy redisfailoverv1.RedisUpdateStrategy `json:"updateStrategy,omitempty"`
+	Network        NetworkSettings                     `json:"network,omitempty"`
+	LabelWhitelist []string                            `json:"labelWhitelist,omitempty"`
+	BootstrapNode  *BootstrapSettings                  `json:"bootstrapNode,omitempty"`
+	Backup         *redisfailoverv1.BackupSettings     `json:"backup,omitempty"`
+	Restore        *redisfailoverv1.RestoreSettings    `json:"restore,omitempty"`
+}
+
+// RedisSettings defines the specification of the redis cluster
+// +kubebuilder:validation:XValidation:rule="(has(self.port) ? self.port : 6379) == (has(oldSelf.port) ? oldSelf.port : 6379)",message="port is immutable"
+type RedisSettings struct {
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port      int32                       `json:"port,omitempty"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +kubebuilder:validation:items:Pattern=`^[^ ]+ .*$`
+	CustomConfig []string `json:"customConfig,omitempty"`
+	// ConfigRewrite runs redis from a writable copy of its config file, rewritten with CONFIG REWRITE every
+	// time the custom config is set, so the values set survive a restart of the redis container
+	ConfigRewrite             bool                                 `json:"configRewrite,omitempty"`
+	CustomCommandRenames      []redisfailoverv1.RedisCommandRename `json:"customCommandRenames,omitempty"`
+	Command                   []string                             `json:"command,omitempty"`
+	ShutdownConfigMap         string                               `json:"shutdownConfigMap,omitempty"`
+	StartupConfigMap          string                               `json:"startupConfigMap,omitempty"`
+	InitContainers            []corev1.Container                   `json:"initContainers,omitempty"`
+	Exporter                  redisfailoverv1.Exporter             `json:"exporter,omitempty"`
+	ExtraContainers           []corev1.Container                   `json:"extraContainers,omitempty"`
+	Affinity                  *corev1.Affinity                     `json:"affinity,omitempty"`
+	SecurityContext           *corev1.PodSecurityContext           `json:"securityContext,omitempty"`
+	ContainerSecurityContext  *corev1.SecurityContext              `json:"containerSecurityContext,omitempty"`
+	ImagePullSecrets          []corev1.LocalObjectReference        `json:"imagePullSecrets,omitempty"`
+	Tolerations               []corev1.Toleration                  `json:"tolerations,omitempty"`
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint    `json:"topologySpreadConstraints,omitempty"`
+	NodeSelector              map[string]string                    `json:"nodeSelector,omitempty"`
+	PodAnnotations            map[string]string                    `json:"podAnnotations,omitempty"`
+	ServiceAnnotations        map[string]string                    `json:"serviceAnnotations,omitempty"`
+	HostNetwork               bool                                 `json:"hostNetwork,omitempty"`
+	DNSPolicy                 corev1.DNSPolicy                     `json:"dnsPolicy,omitempty"`
+	PriorityClassName         string                               `json:"priorityClassName,omitempty"`
+	ServiceAccountName        string                               `json:"serviceAccountName,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds int64                                    `json:"terminationGracePeriod,omitempty"`
+	ExtraVolumes                  []corev1.Volume                          `json:"extraVolumes,omitempty"`
+	ExtraVolumeMounts             []corev1.VolumeMount                     `json:"extraVolumeMounts,omitempty"`
+	CustomLivenessProbe           *corev1.Probe                            `json:"customLivenessProbe,omitempty"`
+	CustomReadinessProbe          *corev1.Probe                            `json:"customReadinessProbe,omitempty"`
+	CustomStartupProbe            *corev1.Probe                            `json:"customStartupProbe,omitempty"`
+	DisablePodDisruptionBudget    bool                                     `json:"disablePodDisruptionBudget,omitempty"`
+	TLS                           *redisfailoverv1.TLSSettings             `json:"tls,omitempty"`
+	MasterElection                redisfailoverv1.MasterElectionStrategy   `json:"masterElection,omitempty"`
+	SplitBrainResolution          redisfailoverv1.SplitBrainResolutionMode `json:"splitBrainResolution,omitempty"`
+}
+
+// SentinelSettings defines the specification of the sentinel cluster
+type SentinelSettings struct {
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas  int32                       `json:"replicas,omitempty"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// MasterNamePolicy is the name the sentinels monitor the master as
+	MasterNamePolicy MasterNamePolicy `json:"masterNamePolicy,omitempty"`
+	Tuning           SentinelTuning   `json:"tuning,omitempty"`
+	// CustomConfig holds the sentinel directives not covered by the tuning
+	// +kubebuilder:validation:items:Pattern=`^[^ ]+ .*$`
+	CustomConfig               []string                             `json:"customConfig,omitempty"`
+	Command                    []string                             `json:"command,omitempty"`
+	StartupConfigMap           string                               `json:"startupConfigMap,omitempty"`
+	Affinity                   *corev1.Affinity                     `json:"affinity,omitempty"`
+	SecurityContext            *corev1.PodSecurityContext           `json:"securityContext,omitempty"`
+	ContainerSecurityContext   *corev1.SecurityContext              `json:"containerSecurityContext,omitempty"`
+	ImagePullSecrets           []corev1.LocalObjectReference        `json:"imagePullSecrets,omitempty"`
+	Tolerations                []corev1.Toleration                  `json:"tolerations,omitempty"`
+	TopologySpreadConstraints  []corev1.TopologySpreadConstraint    `json:"topologySpreadConstraints,omitempty"`
+	NodeSelector               map[string]string                    `json:"nodeSelector,omitempty"`
+	PodAnnotations             map[string]string                    `json:"podAnnotations,omitempty"`
+	ServiceAnnotations         map[string]string                    `json:"serviceAnnotations,omitempty"`
+	InitContainers             []corev1.Container                   `json:"initContainers,omitempty"`
+	Exporter                   redisfailoverv1.Exporter             `json:"exporter,omitempty"`
+	ExtraContainers            []corev1.Container                   `json:"extraContainers,omitempty"`
+	ConfigCopy                 redisfailoverv1.SentinelConfigCopy   `json:"configCopy,omitempty"`
+	HostNetwork                bool                                 `json:"hostNetwork,omitempty"`
+	DNSPolicy                  corev1.DNSPolicy                     `json:"dnsPolicy,omitempty"`
+	PriorityClassName          string                               `json:"priorityClassName,omitempty"`
+	ServiceAccountName         string                               `json:"serviceAccountName,omitempty"`
+	ExtraVolumes               []corev1.Volume                      `json:"extraVolumes,omitempty"`
+	ExtraVolumeMounts          []corev1.VolumeMount                 `json:"extraVolumeMounts,omitempty"`
+	CustomLivenessProbe        *corev1.Probe                        `json:"customLivenessProbe,omitempty"`
+	CustomReadinessProbe       *corev1.Probe                        `json:"customReadinessProbe,omitempty"`
+	CustomStartupProbe         *corev1.Probe                        `json:"customStartupProbe,omitempty"`
+	DisablePodDisruptionBudget bool                                 `json:"disablePodDisruptionBudget,omitempty"`
+	TLS                        *redisfailoverv1.TLSSettings         `json:"tls,omitempty"`
+	WorkloadType               redisfailoverv1.SentinelWorkloadType `json:"workloadType,omitempty"`
+}
+
+// MasterNamePolicy is how the sentinels name the master they monitor
+// +kubebuilder:validation:Enum=Default;ResourceName
+type MasterNamePolicy string
+
+const (
+	// MasterNameDefault monitors the master as mymaster, it is the policy when none is given
+	MasterNameDefault MasterNamePolicy = "Default"
+	// MasterNameResourceName monitors the master with the name of the RedisFailover
+	MasterNameResourceName MasterNamePolicy = "ResourceName"
+)
+
+// SentinelTuning holds the sentinel directives of the failure detection and the failover. They are set
+// after the customConfig, so they win over the same directives given there.
+type SentinelTuning struct {
+	// DownAfterMilliseconds is how long the master must be unreachable to be considered down
+	// +kubebuilder:validation:Minimum=1
+	DownAfterMilliseconds *int64 `json:"downAfterMilliseconds,omitempty"`
+	// FailoverTimeout is how long a failover can take before another one is tried
+	// +kubebuilder:validation:Minimum=1
+	FailoverTimeout *int64 `json:"failoverTimeout,omitempty"`
+	// ParallelSyncs is the number of replicas reconfigured at the same time after a failover
+	// +kubebuilder:validation:Minimum=1
+	ParallelSyncs *int32 `json:"parallelSyncs,omitempty"`
+}
+
+// AuthSettings contains settings about auth
+type AuthSettings struct {
+	// PasswordSecret holds the password of the default user in its "password" key
+	PasswordSecret *corev1.LocalObjectReference `json:"passwordSecret,omitempty"`
+	Users          []RedisUser                  `json:"users,omitempty"`
+	// PasswordRotationGracePeriodSeconds is how long both passwords are accepted during a rotation,
+	// 300 when unset. The confirmation annotation ends it early.
+	// +kubebuilder:validation:Minimum=0
+	PasswordRotationGracePeriodSeconds int32 `json:"passwordRotationGracePeriodSeconds,omitempty"`
+}
+
+// RedisUser defines an ACL user created on every redis. Rules are ACL rules such as "~cache:*" or "+@read".
+type RedisUser struct {
+	// +kubebuilder:validation:Pattern=`^[^\s]+$`
+	Name string `json:"name"`
+	// PasswordSecret holds the password of the user in its "password" key
+	PasswordSecret corev1.LocalObjectReference `json:"passwordSecret"`
+	Rules          []string                    `json:"rules,omitempty"`
+}
+
+// PersistenceSettings defines the volumes keeping the data of the redises and the sentinels
+type PersistenceSettings struct {
+	Redis redisfailoverv1.RedisStorage `json:"redis,omitempty"`
+	// Sentinel is the volume keeping the sentinel.conf of every sentinel when they run as a statefulset
+	Sentinel redisfailoverv1.SentinelConfigStorage `json:"sentinel,omitempty"`
+}
+
+// NetworkSettings defines how the redises and the sentinels are reached
+type NetworkSettings struct {
+	AddressMode redisfailoverv1.AddressMode `json:"addressMode,omitempty"`
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:items:Enum=IPv4;IPv6
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+}
+
+// BootstrapSettings contains settings about a potential bootstrap node
+type BootstrapSettings struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port           int32 `json:"port,omitempty"`
+	AllowSentinels bool  `json:"allowSentinels,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverList represents a Redis failover list
+type RedisFailoverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RedisFailover `json:"items"`
+}
@@ -7,6 +7,7 @@ import (
 	http "net/http"
 
 	databasesv1 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v1"
+	databasesv2 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -15,12 +16,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	DatabasesV1() databasesv1.DatabasesV1Interface
+	DatabasesV2() databasesv2.DatabasesV2Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	databasesV1 *databasesv1.DatabasesV1Client
+	databasesV2 *databasesv2.DatabasesV2Client
 }
 
 // DatabasesV1 retrieves the DatabasesV1Client
@@ -28,6 +31,11 @@ func (c *Clientset) DatabasesV1() databasesv1.DatabasesV1Interface {
 	return c.databasesV1
 }
 
+// DatabasesV2 retrieves the DatabasesV2Client
+func (c *Clientset) DatabasesV2() databasesv2.DatabasesV2Interface {
+	return c.databasesV2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -76,6 +84,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.databasesV2, err = databasesv2.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -98,6 +110,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.databasesV1 = databasesv1.New(c)
+	cs.databasesV2 = databasesv2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
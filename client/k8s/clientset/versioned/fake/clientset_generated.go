@@ -6,6 +6,8 @@ import (
 	clientset "github.com/freshworks/redis-operator/client/k8s/clientset/versioned"
 	databasesv1 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v1"
 	fakedatabasesv1 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v1/fake"
+	databasesv2 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v2"
+	fakedatabasesv2 "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/typed/redisfailover/v2/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -76,3 +78,8 @@ var (
 func (c *Clientset) DatabasesV1() databasesv1.DatabasesV1Interface {
 	return &fakedatabasesv1.FakeDatabasesV1{Fake: &c.Fake}
 }
+
+// DatabasesV2 retrieves the DatabasesV2Client
+func (c *Clientset) DatabasesV2() databasesv2.DatabasesV2Interface {
+	return &fakedatabasesv2.FakeDatabasesV2{Fake: &c.Fake}
+}
@@ -4,6 +4,7 @@ package fake
 
 import (
 	databasesv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	databasesv2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,6 +17,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	databasesv1.AddToScheme,
+	databasesv2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -4,6 +4,7 @@ package scheme
 
 import (
 	databasesv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	databasesv2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,6 +17,7 @@ var Codecs = serializer.NewCodecFactory(Scheme)
 var ParameterCodec = runtime.NewParameterCodec(Scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	databasesv1.AddToScheme,
+	databasesv2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
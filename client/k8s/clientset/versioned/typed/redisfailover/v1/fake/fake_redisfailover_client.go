@@ -12,10 +12,18 @@ type FakeDatabasesV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeDatabasesV1) RedisClusters(namespace string) v1.RedisClusterInterface {
+	return newFakeRedisClusters(c, namespace)
+}
+
 func (c *FakeDatabasesV1) RedisFailovers(namespace string) v1.RedisFailoverInterface {
 	return newFakeRedisFailovers(c, namespace)
 }
 
+func (c *FakeDatabasesV1) RedisFailoverBackups(namespace string) v1.RedisFailoverBackupInterface {
+	return newFakeRedisFailoverBackups(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeDatabasesV1) RESTClient() rest.Interface {
@@ -2,4 +2,8 @@
 
 package v1
 
+type RedisClusterExpansion interface{}
+
 type RedisFailoverExpansion interface{}
+
+type RedisFailoverBackupExpansion interface{}
@@ -23,6 +23,8 @@ type RedisFailoversGetter interface {
 type RedisFailoverInterface interface {
 	Create(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, opts metav1.CreateOptions) (*redisfailoverv1.RedisFailover, error)
 	Update(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, opts metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, opts metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*redisfailoverv1.RedisFailover, error)
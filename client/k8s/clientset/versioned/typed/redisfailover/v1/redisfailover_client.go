@@ -12,7 +12,9 @@ import (
 
 type DatabasesV1Interface interface {
 	RESTClient() rest.Interface
+	RedisClustersGetter
 	RedisFailoversGetter
+	RedisFailoverBackupsGetter
 }
 
 // DatabasesV1Client is used to interact with features provided by the databases.spotahome.com group.
@@ -20,10 +22,18 @@ type DatabasesV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *DatabasesV1Client) RedisClusters(namespace string) RedisClusterInterface {
+	return newRedisClusters(c, namespace)
+}
+
 func (c *DatabasesV1Client) RedisFailovers(namespace string) RedisFailoverInterface {
 	return newRedisFailovers(c, namespace)
 }
 
+func (c *DatabasesV1Client) RedisFailoverBackups(namespace string) RedisFailoverBackupInterface {
+	return newRedisFailoverBackups(c, namespace)
+}
+
 // NewForConfig creates a new DatabasesV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
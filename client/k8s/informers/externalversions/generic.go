@@ -6,6 +6,7 @@ import (
 	fmt "fmt"
 
 	v1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	v2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -37,8 +38,16 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=databases.spotahome.com, Version=v1
+	case v1.SchemeGroupVersion.WithResource("redisclusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Databases().V1().RedisClusters().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("redisfailovers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Databases().V1().RedisFailovers().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("redisfailoverbackups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Databases().V1().RedisFailoverBackups().Informer()}, nil
+
+		// Group=databases.spotahome.com, Version=v2
+	case v2.SchemeGroupVersion.WithResource("redisfailovers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Databases().V2().RedisFailovers().Informer()}, nil
 
 	}
 
@@ -8,8 +8,12 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// RedisClusters returns a RedisClusterInformer.
+	RedisClusters() RedisClusterInformer
 	// RedisFailovers returns a RedisFailoverInformer.
 	RedisFailovers() RedisFailoverInformer
+	// RedisFailoverBackups returns a RedisFailoverBackupInformer.
+	RedisFailoverBackups() RedisFailoverBackupInformer
 }
 
 type version struct {
@@ -23,7 +27,17 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// RedisClusters returns a RedisClusterInformer.
+func (v *version) RedisClusters() RedisClusterInformer {
+	return &redisClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // RedisFailovers returns a RedisFailoverInformer.
 func (v *version) RedisFailovers() RedisFailoverInformer {
 	return &redisFailoverInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RedisFailoverBackups returns a RedisFailoverBackupInformer.
+func (v *version) RedisFailoverBackups() RedisFailoverBackupInformer {
+	return &redisFailoverBackupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
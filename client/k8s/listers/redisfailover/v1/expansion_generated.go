@@ -2,6 +2,14 @@
 
 package v1
 
+// RedisClusterListerExpansion allows custom methods to be added to
+// RedisClusterLister.
+type RedisClusterListerExpansion interface{}
+
+// RedisClusterNamespaceListerExpansion allows custom methods to be added to
+// RedisClusterNamespaceLister.
+type RedisClusterNamespaceListerExpansion interface{}
+
 // RedisFailoverListerExpansion allows custom methods to be added to
 // RedisFailoverLister.
 type RedisFailoverListerExpansion interface{}
@@ -9,3 +17,11 @@ type RedisFailoverListerExpansion interface{}
 // RedisFailoverNamespaceListerExpansion allows custom methods to be added to
 // RedisFailoverNamespaceLister.
 type RedisFailoverNamespaceListerExpansion interface{}
+
+// RedisFailoverBackupListerExpansion allows custom methods to be added to
+// RedisFailoverBackupLister.
+type RedisFailoverBackupListerExpansion interface{}
+
+// RedisFailoverBackupNamespaceListerExpansion allows custom methods to be added to
+// RedisFailoverBackupNamespaceLister.
+type RedisFailoverBackupNamespaceListerExpansion interface{}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/service/backup"
+)
+
+// Flags are the flags used by the backup agent.
+type Flags struct {
+	ListenAddr     string
+	RDBPath        string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3Prefix       string
+	KeyPrefix      string
+	FilesystemPath string
+	RestoreFrom    string
+	RestorePod     string
+	LogLevel       string
+}
+
+// Init initializes and parse the flags
+func (f *Flags) Init() {
+	flag.StringVar(&f.ListenAddr, "listen-address", fmt.Sprintf(":%d", backup.AgentPort), "Address to listen on for upload requests.")
+	flag.StringVar(&f.RDBPath, "rdb-path", "/data/dump.rdb", "Path of the RDB file written by redis.")
+	flag.StringVar(&f.S3Endpoint, "s3-endpoint", "", "Endpoint of the S3 compatible storage, with its scheme.")
+	flag.StringVar(&f.S3Region, "s3-region", "", "Region of the bucket.")
+	flag.StringVar(&f.S3Bucket, "s3-bucket", "", "Bucket the backups are uploaded to.")
+	flag.StringVar(&f.S3Prefix, "s3-prefix", "", "Prefix added to the key of the backups.")
+	flag.StringVar(&f.KeyPrefix, "key-prefix", "", "Prefix of the keys the agent accepts upload requests for, e.g. <namespace>/<redisfailover>/.")
+	flag.StringVar(&f.FilesystemPath, "filesystem-path", "", "Copy the backups to this directory instead of uploading them, meant for testing.")
+	flag.StringVar(&f.RestoreFrom, "restore-from", "", "Write the RDB file found at this location and exit, instead of serving upload requests.")
+	flag.StringVar(&f.RestorePod, "restore-pod", "", "Only restore when running in this pod, given by the POD_NAME environment variable.")
+	flag.StringVar(&f.LogLevel, "log-level", "info", "set log level")
+	flag.Parse()
+}
+
+func newS3Config(flags *Flags) backup.S3Config {
+	// Credentials come from the environment so they are never shown in the pod spec.
+	return backup.S3Config{
+		Endpoint:        flags.S3Endpoint,
+		Region:          flags.S3Region,
+		Bucket:          flags.S3Bucket,
+		Prefix:          flags.S3Prefix,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+	}
+}
+
+func newUploader(flags *Flags) (backup.Uploader, error) {
+	if flags.FilesystemPath != "" {
+		return backup.NewFilesystemUploader(flags.FilesystemPath), nil
+	}
+	return backup.NewS3Storage(newS3Config(flags), http.DefaultClient)
+}
+
+// restore writes the RDB file before redis starts. Only the pod meant to become the master restores it,
+// and never over an existing dataset, so a restarted pod keeps the data it has.
+func restore(flags *Flags, logger log.Logger) error {
+	if pod := os.Getenv("POD_NAME"); flags.RestorePod != "" && pod != flags.RestorePod {
+		logger.Infof("Skipping the restore in %s, it is done in %s", pod, flags.RestorePod)
+		return nil
+	}
+	if _, err := os.Stat(flags.RDBPath); err == nil {
+		logger.Infof("Skipping the restore, %s already exists", flags.RDBPath)
+		return nil
+	}
+
+	size, err := backup.Restore(context.Background(), flags.RestoreFrom, flags.RDBPath, newS3Config(flags), http.DefaultClient)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Restored %d bytes from %s to %s", size, flags.RestoreFrom, flags.RDBPath)
+	return nil
+}
+
+func run(logger log.Logger) error {
+	flags := &Flags{}
+	flags.Init()
+
+	if err := logger.Set(log.Level(strings.ToLower(flags.LogLevel))); err != nil {
+		return err
+	}
+
+	if flags.RestoreFrom != "" {
+		return restore(flags, logger)
+	}
+
+	// The token comes from the environment so it is never shown in the pod spec.
+	token := os.Getenv(backup.AgentTokenEnv)
+	if token == "" {
+		return fmt.Errorf("the %s environment variable is required to serve upload requests", backup.AgentTokenEnv)
+	}
+	if flags.KeyPrefix == "" {
+		return fmt.Errorf("the key-prefix flag is required to serve upload requests")
+	}
+
+	uploader, err := newUploader(flags)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", backup.NewAgent(flags.RDBPath, token, flags.KeyPrefix, uploader, logger))
+	logger.Infof("Listening on %s for upload requests of %s", flags.ListenAddr, flags.RDBPath)
+	return http.ListenAndServe(flags.ListenAddr, mux)
+}
+
+// Run app.
+func main() {
+	if err := run(log.Base()); err != nil {
+		fmt.Fprintf(os.Stderr, "error executing: %s", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -19,6 +21,7 @@ import (
 	"github.com/freshworks/redis-operator/log"
 	"github.com/freshworks/redis-operator/metrics"
 	"github.com/freshworks/redis-operator/operator/redisfailover"
+	"github.com/freshworks/redis-operator/operator/redisfailover/webhook"
 	"github.com/freshworks/redis-operator/service/k8s"
 	"github.com/freshworks/redis-operator/service/redis"
 )
@@ -72,17 +75,36 @@ func (m *Main) Run() error {
 		}
 	}()
 
+	// Serve the admission webhooks.
+	if m.flags.WebhookListenAddr != "" {
+		webhookServer := webhook.NewServer(m.logger)
+		go func() {
+			errC <- webhookServer.ListenAndServeTLS(m.flags.WebhookListenAddr, m.flags.WebhookCertFile, m.flags.WebhookKeyFile)
+		}()
+	}
+
 	// Kubernetes clients.
 	k8sClient, customClient, aeClientset, err := utils.CreateKubernetesClients(m.flags)
 	if err != nil {
 		return err
 	}
 
+	// Point the conversion webhook of the CRD at the operator.
+	if m.flags.WebhookListenAddr != "" && m.flags.WebhookService != "" {
+		caBundle, err := os.ReadFile(m.flags.WebhookCAFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err := webhook.ConfigureConversion(context.Background(), aeClientset, getNamespace(), m.flags.WebhookService, caBundle); err != nil {
+			return err
+		}
+	}
+
 	// Create kubernetes service.
 	k8sservice := k8s.New(k8sClient, customClient, aeClientset, m.logger, metricsRecorder)
 
 	// Create the redis clients
-	redisClient := redis.New(metricsRecorder)
+	redisClient := redis.NewWithConfig(metricsRecorder, m.flags.ToRedisClientConfig())
 
 	// Get lease lock resource namespace
 	lockNamespace := getNamespace()
@@ -93,6 +115,30 @@ func (m *Main) Run() error {
 		return err
 	}
 
+	// The backups and the redis clusters are only handled when asked for, their CRDs may not be installed.
+	if m.flags.EnableBackups {
+		backupOperator, err := redisfailover.NewBackup(m.flags.ToRedisOperatorConfig(), k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errC <- backupOperator.Run(context.Background())
+		}()
+	}
+
+	if m.flags.EnableRedisCluster {
+		clusterOperator, err := redisfailover.NewCluster(m.flags.ToRedisOperatorConfig(), k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errC <- clusterOperator.Run(context.Background())
+		}()
+	}
+
+	// Close the redis connections to the pods going away.
+	go redisfailover.EvictRedisConnections(context.Background(), k8sservice, redisClient, m.logger)
+
 	go func() {
 		errC <- redisfailoverOperator.Run(context.Background())
 	}()
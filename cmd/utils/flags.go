@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/freshworks/redis-operator/operator/redisfailover"
+	"github.com/freshworks/redis-operator/service/redis"
 	"k8s.io/client-go/util/homedir"
 )
 
@@ -22,6 +24,15 @@ type CMDFlags struct {
 	K8sQueriesBurstable      int
 	Concurrency              int
 	LogLevel                 string
+	WebhookListenAddr        string
+	WebhookCertFile          string
+	WebhookKeyFile           string
+	WebhookCAFile            string
+	WebhookService           string
+	RedisTimeout             time.Duration
+	RedisRetries             int
+	EnableBackups            bool
+	EnableRedisCluster       bool
 }
 
 // Init initializes and parse the flags
@@ -39,6 +50,15 @@ func (c *CMDFlags) Init() {
 	// reference: https://github.com/spotahome/kooper/blob/master/controller/controller.go#L89
 	flag.IntVar(&c.Concurrency, "concurrency", 3, "Number of conccurent workers meant to process events")
 	flag.StringVar(&c.LogLevel, "log-level", "info", "set log level")
+	flag.StringVar(&c.WebhookListenAddr, "webhook-listen-address", "", "Address to listen on for the admission webhooks, they are not served when empty")
+	flag.StringVar(&c.WebhookCertFile, "webhook-cert-file", "/etc/webhook/certs/tls.crt", "Path of the serving certificate of the admission webhooks")
+	flag.StringVar(&c.WebhookKeyFile, "webhook-key-file", "/etc/webhook/certs/tls.key", "Path of the key of the serving certificate of the admission webhooks")
+	flag.StringVar(&c.WebhookCAFile, "webhook-ca-file", "/etc/webhook/certs/ca.crt", "Path of the CA bundle set in the conversion webhook of the CRD, the one of the CRD is kept when the file doesn't exist")
+	flag.StringVar(&c.WebhookService, "webhook-service", "", "Name of the service of the operator the conversion webhook of the CRD points at, in the namespace of the operator. The CRD is not changed when empty")
+	flag.DurationVar(&c.RedisTimeout, "redis-timeout", redis.DefaultConfig.Timeout, "Timeout of every attempt of a call to the redises and the sentinels")
+	flag.IntVar(&c.RedisRetries, "redis-retries", redis.DefaultConfig.Retries, "Number of times a call to the redises and the sentinels failing for a transient reason is tried again, when it is safe to")
+	flag.BoolVar(&c.EnableBackups, "enable-backups", false, "Run the controller of the RedisFailoverBackups, it needs their CRD installed")
+	flag.BoolVar(&c.EnableRedisCluster, "enable-redis-cluster", false, "Run the controller of the RedisClusters, it needs their CRD installed")
 	// Parse flags
 	flag.Parse()
 
@@ -54,5 +74,15 @@ func (c *CMDFlags) ToRedisOperatorConfig() redisfailover.Config {
 		MetricsPath:              c.MetricsPath,
 		Concurrency:              c.Concurrency,
 		SupportedNamespacesRegex: c.SupportedNamespacesRegex,
+		EnableBackups:            c.EnableBackups,
+		EnableRedisCluster:       c.EnableRedisCluster,
 	}
 }
+
+// ToRedisClientConfig convert the flags to the config of the redis client
+func (c *CMDFlags) ToRedisClientConfig() redis.Config {
+	config := redis.DefaultConfig
+	config.Timeout = c.RedisTimeout
+	config.Retries = c.RedisRetries
+	return config
+}
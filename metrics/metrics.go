@@ -44,6 +44,10 @@ const (
 	NOPERM              = "REDIS_USER_DOES_NOT_HAVE_PERMISSIONS"
 	IO_TIMEOUT          = "CONNECTION_TIMEDOUT"
 	CONNECTION_REFUSED  = "CONNECTION_REFUSED"
+	TLS_HANDSHAKE       = "TLS_HANDSHAKE_FAILED"
+	LOADING             = "REDIS_LOADING_DATASET"
+	READONLY            = "REDIS_READ_ONLY_REPLICA"
+	CONFIG_REFUSED      = "REDIS_CONFIG_NOT_SETTABLE"
 
 	// Kubernetes related errors
 	K8S_FORBIDDEN_ERR = "USER_FORBIDDEN_TO_PERFORM_ACTION"
@@ -68,6 +72,23 @@ const (
 	GET_SENTINEL_MONITOR        = "SENTINEL_GET_MASTER_INSTANCE"
 	CHECK_SENTINEL_QUORUM       = "SENTINEL_CKQUORUM"
 	SLAVE_IS_READY              = "CHECK_IF_SLAVE_IS_READY"
+	SENTINEL_FAILOVER           = "SENTINEL_FORCE_FAILOVER"
+	SUBSCRIBE_SENTINEL_EVENTS   = "SENTINEL_SUBSCRIBE_EVENTS"
+	SENTINEL_CONFIG_SET         = "SENTINEL_CONFIG_SET"
+	SET_REDIS_USER              = "ACL_SET_USER"
+	DELETE_REDIS_USER           = "ACL_DELETE_USER"
+	BACKGROUND_SAVE             = "BGSAVE"
+	GET_LAST_SAVE               = "LASTSAVE"
+	GET_REPLICATION_INFO        = "INFO_REPLICATION"
+	GET_REPLICA_PRIORITY        = "CONFIG_GET_REPLICA_PRIORITY"
+	GET_REDIS_CONFIG            = "CONFIG_GET"
+	REWRITE_REDIS_CONFIG        = "CONFIG_REWRITE"
+	GET_CLUSTER_NODES           = "CLUSTER_NODES"
+	CLUSTER_MEET                = "CLUSTER_MEET"
+	CLUSTER_ADD_SLOTS           = "CLUSTER_ADDSLOTS"
+	CLUSTER_REPLICATE           = "CLUSTER_REPLICATE"
+	CLUSTER_FORGET              = "CLUSTER_FORGET"
+	CLUSTER_MIGRATE_SLOT        = "CLUSTER_MIGRATE_SLOT"
 )
 
 // MetricsTracker handles thread-safe tracking of metric updates
@@ -23,3 +23,6 @@ package mocks // import "github.com/freshworks/redis-operator/mocks"
 
 // RedisFailover Operator service Healer mocks
 //go:generate mockery --output operator/redisfailover/service --dir ../operator/redisfailover/service --name RedisFailoverHeal
+
+// Backup agent client mocks
+//go:generate mockery --output service/backup --dir ../service/backup --name AgentClient
@@ -19,6 +19,36 @@ type RedisFailover struct {
 	mock.Mock
 }
 
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *RedisFailover) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisFailover")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
 	ret := _m.Called(ctx, namespace, opts)
@@ -49,6 +79,66 @@ func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace strin
 	return r0, r1
 }
 
+// RemoveRedisFailoverAnnotation provides a mock function with given fields: ctx, namespace, name, key
+func (_m *RedisFailover) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRedisFailoverAnnotation")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, namespace, name, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, namespace, redisFailover, opts
+func (_m *RedisFailover) UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts v1.UpdateOptions) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, redisFailover, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRedisFailoverStatus")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailover, v1.UpdateOptions) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, redisFailover, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailover, v1.UpdateOptions) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, redisFailover, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisFailover, v1.UpdateOptions) error); ok {
+		r1 = rf(ctx, namespace, redisFailover, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WatchRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
 	ret := _m.Called(ctx, namespace, opts)
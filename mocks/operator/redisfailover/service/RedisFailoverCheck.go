@@ -3,8 +3,14 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
+	redis "github.com/freshworks/redis-operator/service/redis"
+
+	service "github.com/freshworks/redis-operator/operator/redisfailover/service"
+
 	time "time"
 
 	v1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
@@ -15,17 +21,17 @@ type RedisFailoverCheck struct {
 	mock.Mock
 }
 
-// CheckAllSlavesFromMaster provides a mock function with given fields: master, rFailover
-func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(master, rFailover)
+// CheckAllSlavesFromMaster provides a mock function with given fields: ctx, master, rFailover
+func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, master, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckAllSlavesFromMaster")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(master, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, master, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,9 +39,9 @@ func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover
 	return r0
 }
 
-// CheckIfMasterLocalhost provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(rFailover)
+// CheckIfMasterLocalhost provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(ctx context.Context, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckIfMasterLocalhost")
@@ -43,17 +49,17 @@ func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (bool, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,9 +85,9 @@ func (_m *RedisFailoverCheck) CheckRedisNumber(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// CheckRedisSlavesReady provides a mock function with given fields: slaveIP, rFailover
-func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(slaveIP, rFailover)
+// CheckRedisSlavesReady provides a mock function with given fields: ctx, slaveIP, rFailover
+func (_m *RedisFailoverCheck) CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, slaveIP, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckRedisSlavesReady")
@@ -89,17 +95,17 @@ func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) (bool, error)); ok {
-		return rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, slaveIP, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) bool); ok {
-		r0 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *v1.RedisFailover) error); ok {
-		r1 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,14 +113,14 @@ func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v
 	return r0, r1
 }
 
-// CheckSentinelMonitor provides a mock function with given fields: sentinel, masterName, monitor
-func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, masterName string, monitor ...string) error {
+// CheckSentinelMonitor provides a mock function with given fields: ctx, sentinel, rFailover, monitor
+func (_m *RedisFailoverCheck) CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *v1.RedisFailover, monitor ...string) error {
 	_va := make([]interface{}, len(monitor))
 	for _i := range monitor {
 		_va[_i] = monitor[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, sentinel, masterName)
+	_ca = append(_ca, ctx, sentinel, rFailover)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
@@ -123,8 +129,8 @@ func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, masterName s
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, ...string) error); ok {
-		r0 = rf(sentinel, masterName, monitor...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, ...string) error); ok {
+		r0 = rf(ctx, sentinel, rFailover, monitor...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -150,17 +156,17 @@ func (_m *RedisFailoverCheck) CheckSentinelNumber(rFailover *v1.RedisFailover) e
 	return r0
 }
 
-// CheckSentinelNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckSentinelNumberInMemory")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -168,9 +174,9 @@ func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFail
 	return r0
 }
 
-// CheckSentinelQuorum provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// CheckSentinelQuorum provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelQuorum(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckSentinelQuorum")
@@ -178,17 +184,17 @@ func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -196,17 +202,17 @@ func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckSentinelSlavesNumberInMemory")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -214,9 +220,9 @@ func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string,
 	return r0
 }
 
-// GetMasterIP provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetMasterIP provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMasterIP(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMasterIP")
@@ -224,17 +230,47 @@ func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string,
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMastersIPs provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMastersIPs(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMastersIPs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -270,9 +306,9 @@ func (_m *RedisFailoverCheck) GetMaxRedisPodTime(rFailover *v1.RedisFailover) (t
 	return r0, r1
 }
 
-// GetNumberMasters provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// GetNumberMasters provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetNumberMasters(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNumberMasters")
@@ -280,17 +316,101 @@ func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisLastSave provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetRedisLastSave(ctx context.Context, ip string, rFailover *v1.RedisFailover) (int64, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisLastSave")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (int64, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) int64); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisReplicaPriority provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetRedisReplicaPriority(ctx context.Context, ip string, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisReplicaPriority")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisReplicationInfo provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetRedisReplicationInfo(ctx context.Context, ip string, rFailover *v1.RedisFailover) (redis.ReplicationInfo, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisReplicationInfo")
+	}
+
+	var r0 redis.ReplicationInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (redis.ReplicationInfo, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) redis.ReplicationInfo); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Get(0).(redis.ReplicationInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -356,9 +476,9 @@ func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]stri
 	return r0, r1
 }
 
-// GetRedisesMasterPod provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesMasterPod provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesMasterPod(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRedisesMasterPod")
@@ -366,17 +486,17 @@ func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -384,9 +504,9 @@ func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// GetRedisesSlavesPods provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesSlavesPods provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesSlavesPods(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRedisesSlavesPods")
@@ -394,19 +514,47 @@ func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover)
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) ([]string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelMonitor provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) GetSentinelMonitor(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, sentinel, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentinelMonitor")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, sentinel, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, sentinel, rFailover)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, sentinel, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -526,6 +674,36 @@ func (_m *RedisFailoverCheck) IsSentinelRunning(rFailover *v1.RedisFailover) boo
 	return r0
 }
 
+// Observe provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) Observe(ctx context.Context, rFailover *v1.RedisFailover) (*service.ClusterObservation, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Observe")
+	}
+
+	var r0 *service.ClusterObservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (*service.ClusterObservation, error)); ok {
+		return rf(ctx, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *service.ClusterObservation); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ClusterObservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRedisFailoverCheck creates a new instance of RedisFailoverCheck. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRedisFailoverCheck(t interface {
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	v1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
@@ -13,6 +15,42 @@ type RedisFailoverHeal struct {
 	mock.Mock
 }
 
+// AddRedisPassword provides a mock function with given fields: ctx, ip, previous, rFailover
+func (_m *RedisFailoverHeal) AddRedisPassword(ctx context.Context, ip string, previous string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, previous, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRedisPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, previous, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackgroundSave provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) BackgroundSave(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackgroundSave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletePod provides a mock function with given fields: podName, rFailover
 func (_m *RedisFailoverHeal) DeletePod(podName string, rFailover *v1.RedisFailover) error {
 	ret := _m.Called(podName, rFailover)
@@ -31,17 +69,17 @@ func (_m *RedisFailoverHeal) DeletePod(podName string, rFailover *v1.RedisFailov
 	return r0
 }
 
-// MakeMaster provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// MakeMaster provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) MakeMaster(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeMaster")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,17 +87,35 @@ func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover)
 	return r0
 }
 
-// NewSentinelMonitor provides a mock function with given fields: ip, monitor, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, rFailover)
+// MakeSlaveOf provides a mock function with given fields: ctx, ip, masterIP, rFailover
+func (_m *RedisFailoverHeal) MakeSlaveOf(ctx context.Context, ip string, masterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, masterIP, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeSlaveOf")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, masterIP, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSentinelMonitor provides a mock function with given fields: ctx, ip, monitor, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewSentinelMonitor")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -67,17 +123,17 @@ func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFail
 	return r0
 }
 
-// NewSentinelMonitorWithPort provides a mock function with given fields: ip, monitor, port, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, port, rFailover)
+// NewSentinelMonitorWithPort provides a mock function with given fields: ctx, ip, monitor, port, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, port, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewSentinelMonitorWithPort")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, port, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, port, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -85,17 +141,35 @@ func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor strin
 	return r0
 }
 
-// RestoreSentinel provides a mock function with given fields: ip
-func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
-	ret := _m.Called(ip)
+// RemoveRedisPreviousPassword provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) RemoveRedisPreviousPassword(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRedisPreviousPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreSentinel provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) RestoreSentinel(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RestoreSentinel")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -103,17 +177,35 @@ func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
 	return r0
 }
 
-// SetExternalMasterOnAll provides a mock function with given fields: masterIP, masterPort, rFailover
-func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, masterPort, rFailover)
+// SentinelFailover provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverHeal) SentinelFailover(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SentinelFailover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetExternalMasterOnAll provides a mock function with given fields: ctx, masterIP, masterPort, rFailover
+func (_m *RedisFailoverHeal) SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, masterPort, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetExternalMasterOnAll")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, masterPort, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, masterPort, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -121,17 +213,17 @@ func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort
 	return r0
 }
 
-// SetMasterOnAll provides a mock function with given fields: masterIP, rFailover
-func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, rFailover)
+// SetMasterOnAll provides a mock function with given fields: ctx, masterIP, rFailover
+func (_m *RedisFailoverHeal) SetMasterOnAll(ctx context.Context, masterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetMasterOnAll")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,17 +231,17 @@ func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.Redis
 	return r0
 }
 
-// SetOldestAsMaster provides a mock function with given fields: rFailover
-func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// SetOldestAsMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) SetOldestAsMaster(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetOldestAsMaster")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -157,17 +249,71 @@ func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// SetRedisCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetRedisCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetRedisCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetRedisCustomConfig")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRedisMasterAuth provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetRedisMasterAuth(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRedisMasterAuth")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRedisUsers provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetRedisUsers(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRedisUsers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetReplicaPriority provides a mock function with given fields: ctx, ip, priority, rFailover
+func (_m *RedisFailoverHeal) SetReplicaPriority(ctx context.Context, ip string, priority string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, priority, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReplicaPriority")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, priority, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -175,17 +321,53 @@ func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.Redis
 	return r0
 }
 
-// SetSentinelCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetRoleLabel provides a mock function with given fields: podName, master, rFailover
+func (_m *RedisFailoverHeal) SetRoleLabel(podName string, master bool, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(podName, master, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRoleLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool, *v1.RedisFailover) error); ok {
+		r0 = rf(podName, master, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSentinelAuthPass provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetSentinelAuthPass(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSentinelAuthPass")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSentinelCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetSentinelCustomConfig")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
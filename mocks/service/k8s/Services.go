@@ -63,6 +63,24 @@ func (_m *Services) CreateDeployment(namespace string, deployment *appsv1.Deploy
 	return r0
 }
 
+// CreateIfNotExistsSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) CreateIfNotExistsSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIfNotExistsSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateIfNotExistsService provides a mock function with given fields: namespace, service
 func (_m *Services) CreateIfNotExistsService(namespace string, service *v1.Service) error {
 	ret := _m.Called(namespace, service)
@@ -261,6 +279,36 @@ func (_m *Services) CreatePodDisruptionBudget(namespace string, podDisruptionBud
 	return r0
 }
 
+// CreateRedisFailoverBackup provides a mock function with given fields: ctx, namespace, backup
+func (_m *Services) CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	ret := _m.Called(ctx, namespace, backup)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRedisFailoverBackup")
+	}
+
+	var r0 *redisfailoverv1.RedisFailoverBackup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)); ok {
+		return rf(ctx, namespace, backup)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) *redisfailoverv1.RedisFailoverBackup); ok {
+		r0 = rf(ctx, namespace, backup)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) error); ok {
+		r1 = rf(ctx, namespace, backup)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateRole provides a mock function with given fields: namespace, role
 func (_m *Services) CreateRole(namespace string, role *rbacv1.Role) error {
 	ret := _m.Called(namespace, role)
@@ -297,6 +345,24 @@ func (_m *Services) CreateRoleBinding(namespace string, binding *rbacv1.RoleBind
 	return r0
 }
 
+// CreateSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) CreateSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateService provides a mock function with given fields: namespace, service
 func (_m *Services) CreateService(namespace string, service *v1.Service) error {
 	ret := _m.Called(namespace, service)
@@ -369,6 +435,24 @@ func (_m *Services) DeleteDeployment(namespace string, name string) error {
 	return r0
 }
 
+// DeletePersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) DeletePersistentVolumeClaim(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePersistentVolumeClaim")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletePod provides a mock function with given fields: namespace, name
 func (_m *Services) DeletePod(namespace string, name string) error {
 	ret := _m.Called(namespace, name)
@@ -561,6 +645,36 @@ func (_m *Services) GetDeploymentPods(namespace string, name string) (*v1.PodLis
 	return r0, r1
 }
 
+// GetPersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) GetPersistentVolumeClaim(namespace string, name string) (*v1.PersistentVolumeClaim, error) {
+	ret := _m.Called(namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPersistentVolumeClaim")
+	}
+
+	var r0 *v1.PersistentVolumeClaim
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*v1.PersistentVolumeClaim, error)); ok {
+		return rf(namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *v1.PersistentVolumeClaim); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PersistentVolumeClaim)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPod provides a mock function with given fields: namespace, name
 func (_m *Services) GetPod(namespace string, name string) (*v1.Pod, error) {
 	ret := _m.Called(namespace, name)
@@ -621,6 +735,96 @@ func (_m *Services) GetPodDisruptionBudget(namespace string, name string) (*poli
 	return r0, r1
 }
 
+// GetRedisCluster provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisCluster(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisCluster, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisCluster")
+	}
+
+	var r0 *redisfailoverv1.RedisCluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisCluster, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisCluster); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisCluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisFailover")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisFailoverBackup provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedisFailoverBackup")
+	}
+
+	var r0 *redisfailoverv1.RedisFailoverBackup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailoverBackup, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailoverBackup); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRole provides a mock function with given fields: namespace, name
 func (_m *Services) GetRole(namespace string, name string) (*rbacv1.Role, error) {
 	ret := _m.Called(namespace, name)
@@ -891,6 +1095,66 @@ func (_m *Services) ListPods(namespace string) (*v1.PodList, error) {
 	return r0, r1
 }
 
+// ListRedisClusters provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) ListRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisClusterList, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRedisClusters")
+	}
+
+	var r0 *redisfailoverv1.RedisClusterList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*redisfailoverv1.RedisClusterList, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *redisfailoverv1.RedisClusterList); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisClusterList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRedisFailoverBackups provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRedisFailoverBackups")
+	}
+
+	var r0 *redisfailoverv1.RedisFailoverBackupList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *redisfailoverv1.RedisFailoverBackupList); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackupList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *Services) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
 	ret := _m.Called(ctx, namespace, opts)
@@ -921,6 +1185,36 @@ func (_m *Services) ListRedisFailovers(ctx context.Context, namespace string, op
 	return r0, r1
 }
 
+// ListSecrets provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*v1.SecretList, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSecrets")
+	}
+
+	var r0 *v1.SecretList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*v1.SecretList, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *v1.SecretList); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.SecretList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListServices provides a mock function with given fields: namespace
 func (_m *Services) ListServices(namespace string) (*v1.ServiceList, error) {
 	ret := _m.Called(namespace)
@@ -981,6 +1275,36 @@ func (_m *Services) ListStatefulSets(namespace string) (*appsv1.StatefulSetList,
 	return r0, r1
 }
 
+// RemoveRedisFailoverAnnotation provides a mock function with given fields: ctx, namespace, name, key
+func (_m *Services) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRedisFailoverAnnotation")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, namespace, name, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateConfigMap provides a mock function with given fields: namespace, configMap
 func (_m *Services) UpdateConfigMap(namespace string, configMap *v1.ConfigMap) error {
 	ret := _m.Called(namespace, configMap)
@@ -1071,6 +1395,96 @@ func (_m *Services) UpdatePodLabels(namespace string, podName string, labels map
 	return r0
 }
 
+// UpdateRedisClusterStatus provides a mock function with given fields: ctx, namespace, cluster
+func (_m *Services) UpdateRedisClusterStatus(ctx context.Context, namespace string, cluster *redisfailoverv1.RedisCluster) (*redisfailoverv1.RedisCluster, error) {
+	ret := _m.Called(ctx, namespace, cluster)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRedisClusterStatus")
+	}
+
+	var r0 *redisfailoverv1.RedisCluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisCluster) (*redisfailoverv1.RedisCluster, error)); ok {
+		return rf(ctx, namespace, cluster)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisCluster) *redisfailoverv1.RedisCluster); ok {
+		r0 = rf(ctx, namespace, cluster)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisCluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisCluster) error); ok {
+		r1 = rf(ctx, namespace, cluster)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverBackupStatus provides a mock function with given fields: ctx, namespace, backup
+func (_m *Services) UpdateRedisFailoverBackupStatus(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	ret := _m.Called(ctx, namespace, backup)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRedisFailoverBackupStatus")
+	}
+
+	var r0 *redisfailoverv1.RedisFailoverBackup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)); ok {
+		return rf(ctx, namespace, backup)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) *redisfailoverv1.RedisFailoverBackup); ok {
+		r0 = rf(ctx, namespace, backup)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) error); ok {
+		r1 = rf(ctx, namespace, backup)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, namespace, redisFailover, opts
+func (_m *Services) UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, redisFailover, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRedisFailoverStatus")
+	}
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailover, metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, redisFailover, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailover, metav1.UpdateOptions) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, redisFailover, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisFailover, metav1.UpdateOptions) error); ok {
+		r1 = rf(ctx, namespace, redisFailover, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateRole provides a mock function with given fields: namespace, role
 func (_m *Services) UpdateRole(namespace string, role *rbacv1.Role) error {
 	ret := _m.Called(namespace, role)
@@ -1107,6 +1521,24 @@ func (_m *Services) UpdateRoleBinding(namespace string, binding *rbacv1.RoleBind
 	return r0
 }
 
+// UpdateSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) UpdateSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateService provides a mock function with given fields: namespace, service
 func (_m *Services) UpdateService(namespace string, service *v1.Service) error {
 	ret := _m.Called(namespace, service)
@@ -1143,6 +1575,96 @@ func (_m *Services) UpdateStatefulSet(namespace string, statefulSet *appsv1.Stat
 	return r0
 }
 
+// WatchPods provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchPods")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchRedisClusters provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchRedisClusters")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchRedisFailoverBackups provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchRedisFailoverBackups")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WatchRedisFailovers provides a mock function with given fields: ctx, namespace, opts
 func (_m *Services) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
 	ret := _m.Called(ctx, namespace, opts)
@@ -1173,6 +1695,36 @@ func (_m *Services) WatchRedisFailovers(ctx context.Context, namespace string, o
 	return r0, r1
 }
 
+// WatchSecrets provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchSecrets")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewServices creates a new instance of Services. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewServices(t interface {
@@ -2,16 +2,231 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	tls "crypto/tls"
+
+	mock "github.com/stretchr/testify/mock"
+
+	redis "github.com/freshworks/redis-operator/service/redis"
+)
 
 // Client is an autogenerated mock type for the Client type
 type Client struct {
 	mock.Mock
 }
 
-// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// BackgroundSave provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) BackgroundSave(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackgroundSave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterAddSlots provides a mock function with given fields: ctx, ip, port, slots, username, password, tlsConfig
+func (_m *Client) ClusterAddSlots(ctx context.Context, ip string, port string, slots []int, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, slots, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterAddSlots")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []int, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, slots, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterForget provides a mock function with given fields: ctx, ip, port, nodeID, username, password, tlsConfig
+func (_m *Client) ClusterForget(ctx context.Context, ip string, port string, nodeID string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, nodeID, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterForget")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, nodeID, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterMeet provides a mock function with given fields: ctx, ip, port, nodeIP, username, password, tlsConfig
+func (_m *Client) ClusterMeet(ctx context.Context, ip string, port string, nodeIP string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, nodeIP, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterMeet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, nodeIP, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterMigrateSlot provides a mock function with given fields: ctx, sourceIP, targetIP, port, slot, username, password, tlsConfig
+func (_m *Client) ClusterMigrateSlot(ctx context.Context, sourceIP string, targetIP string, port string, slot int, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, sourceIP, targetIP, port, slot, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterMigrateSlot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, sourceIP, targetIP, port, slot, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClusterReplicate provides a mock function with given fields: ctx, ip, port, masterID, username, password, tlsConfig
+func (_m *Client) ClusterReplicate(ctx context.Context, ip string, port string, masterID string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, masterID, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClusterReplicate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, masterID, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRedisUser provides a mock function with given fields: ctx, ip, port, name, username, password, tlsConfig
+func (_m *Client) DeleteRedisUser(ctx context.Context, ip string, port string, name string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, name, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRedisUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, name, username, password, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableSentinelHostnames provides a mock function with given fields: ctx, ip, tlsConfig
+func (_m *Client) EnableSentinelHostnames(ctx context.Context, ip string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableSentinelHostnames")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Evict provides a mock function with given fields: ip
+func (_m *Client) Evict(ip string) {
+	_m.Called(ip)
+}
+
+// GetClusterNodes provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) GetClusterNodes(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) ([]redis.ClusterNode, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClusterNodes")
+	}
+
+	var r0 []redis.ClusterNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) ([]redis.ClusterNode, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) []redis.ClusterNode); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.ClusterNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastSave provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) GetLastSave(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (int64, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastSave")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (int64, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) int64); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ctx, ip, tlsConfig
+func (_m *Client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error) {
+	ret := _m.Called(ctx, ip, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNumberSentinelSlavesInMemory")
@@ -19,17 +234,17 @@ func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) (int32, error)); ok {
+		return rf(ctx, ip, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) int32); ok {
+		r0 = rf(ctx, ip, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, tlsConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,9 +252,9 @@ func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetNumberSentinelsInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// GetNumberSentinelsInMemory provides a mock function with given fields: ctx, ip, tlsConfig
+func (_m *Client) GetNumberSentinelsInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error) {
+	ret := _m.Called(ctx, ip, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNumberSentinelsInMemory")
@@ -47,17 +262,73 @@ func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) (int32, error)); ok {
+		return rf(ctx, ip, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) int32); ok {
+		r0 = rf(ctx, ip, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, tlsConfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicaPriority provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) GetReplicaPriority(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (int, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplicaPriority")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (int, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) int); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicationInfo provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) GetReplicationInfo(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (redis.ReplicationInfo, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplicationInfo")
+	}
+
+	var r0 redis.ReplicationInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (redis.ReplicationInfo, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) redis.ReplicationInfo); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
+	} else {
+		r0 = ret.Get(0).(redis.ReplicationInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,9 +336,9 @@ func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetSentinelMonitor provides a mock function with given fields: ip, masterName
-func (_m *Client) GetSentinelMonitor(ip string, masterName string) (string, string, error) {
-	ret := _m.Called(ip, masterName)
+// GetSentinelMonitor provides a mock function with given fields: ctx, ip, masterName, tlsConfig
+func (_m *Client) GetSentinelMonitor(ctx context.Context, ip string, masterName string, tlsConfig *tls.Config) (string, string, error) {
+	ret := _m.Called(ctx, ip, masterName, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSentinelMonitor")
@@ -76,23 +347,23 @@ func (_m *Client) GetSentinelMonitor(ip string, masterName string) (string, stri
 	var r0 string
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(string, string) (string, string, error)); ok {
-		return rf(ip, masterName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *tls.Config) (string, string, error)); ok {
+		return rf(ctx, ip, masterName, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(ip, masterName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *tls.Config) string); ok {
+		r0 = rf(ctx, ip, masterName, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) string); ok {
-		r1 = rf(ip, masterName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *tls.Config) string); ok {
+		r1 = rf(ctx, ip, masterName, tlsConfig)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(string, string) error); ok {
-		r2 = rf(ip, masterName)
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, *tls.Config) error); ok {
+		r2 = rf(ctx, ip, masterName, tlsConfig)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -100,9 +371,9 @@ func (_m *Client) GetSentinelMonitor(ip string, masterName string) (string, stri
 	return r0, r1, r2
 }
 
-// GetSlaveOf provides a mock function with given fields: ip, port, password
-func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, error) {
-	ret := _m.Called(ip, port, password)
+// GetSlaveOf provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) GetSlaveOf(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (string, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSlaveOf")
@@ -110,17 +381,17 @@ func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, e
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (string, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) string); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -128,9 +399,9 @@ func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, e
 	return r0, r1
 }
 
-// IsMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) IsMaster(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// IsMaster provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) IsMaster(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (bool, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IsMaster")
@@ -138,17 +409,17 @@ func (_m *Client) IsMaster(ip string, port string, password string) (bool, error
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (bool, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) bool); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -156,17 +427,17 @@ func (_m *Client) IsMaster(ip string, port string, password string) (bool, error
 	return r0, r1
 }
 
-// MakeMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) MakeMaster(ip string, port string, password string) error {
-	ret := _m.Called(ip, port, password)
+// MakeMaster provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) MakeMaster(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeMaster")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -174,17 +445,17 @@ func (_m *Client) MakeMaster(ip string, port string, password string) error {
 	return r0
 }
 
-// MakeSlaveOf provides a mock function with given fields: ip, masterIP, password
-func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error {
-	ret := _m.Called(ip, masterIP, password)
+// MakeSlaveOf provides a mock function with given fields: ctx, ip, masterIP, username, password, tlsConfig
+func (_m *Client) MakeSlaveOf(ctx context.Context, ip string, masterIP string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, masterIP, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeSlaveOf")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, masterIP, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, masterIP, username, password, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -192,17 +463,17 @@ func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error
 	return r0
 }
 
-// MakeSlaveOfWithPort provides a mock function with given fields: ip, masterIP, masterPort, password
-func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort string, password string) error {
-	ret := _m.Called(ip, masterIP, masterPort, password)
+// MakeSlaveOfWithPort provides a mock function with given fields: ctx, ip, masterIP, masterPort, username, password, tlsConfig
+func (_m *Client) MakeSlaveOfWithPort(ctx context.Context, ip string, masterIP string, masterPort string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, masterIP, masterPort, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeSlaveOfWithPort")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, masterIP, masterPort, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, masterIP, masterPort, username, password, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -210,17 +481,17 @@ func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort str
 	return r0
 }
 
-// MonitorRedis provides a mock function with given fields: ip, monitor, quorum, password, masterName
-func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, password string, masterName string) error {
-	ret := _m.Called(ip, monitor, quorum, password, masterName)
+// MonitorRedis provides a mock function with given fields: ctx, ip, monitor, quorum, password, masterName, tlsConfig
+func (_m *Client) MonitorRedis(ctx context.Context, ip string, monitor string, quorum string, password string, masterName string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, monitor, quorum, password, masterName, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MonitorRedis")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, quorum, password, masterName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, monitor, quorum, password, masterName, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -228,17 +499,17 @@ func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, passwor
 	return r0
 }
 
-// MonitorRedisWithPort provides a mock function with given fields: ip, monitor, port, quorum, password, masterName
-func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, quorum string, password string, masterName string) error {
-	ret := _m.Called(ip, monitor, port, quorum, password, masterName)
+// MonitorRedisWithPort provides a mock function with given fields: ctx, ip, monitor, port, quorum, password, masterName, tlsConfig
+func (_m *Client) MonitorRedisWithPort(ctx context.Context, ip string, monitor string, port string, quorum string, password string, masterName string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, monitor, port, quorum, password, masterName, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MonitorRedisWithPort")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, port, quorum, password, masterName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, monitor, port, quorum, password, masterName, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -246,17 +517,17 @@ func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, q
 	return r0
 }
 
-// ResetSentinel provides a mock function with given fields: ip
-func (_m *Client) ResetSentinel(ip string) error {
-	ret := _m.Called(ip)
+// ResetSentinel provides a mock function with given fields: ctx, ip, tlsConfig
+func (_m *Client) ResetSentinel(ctx context.Context, ip string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ResetSentinel")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -264,17 +535,35 @@ func (_m *Client) ResetSentinel(ip string) error {
 	return r0
 }
 
-// SentinelCheckQuorum provides a mock function with given fields: ip, masterName
-func (_m *Client) SentinelCheckQuorum(ip string, masterName string) error {
-	ret := _m.Called(ip, masterName)
+// SentinelCheckQuorum provides a mock function with given fields: ctx, ip, masterName, tlsConfig
+func (_m *Client) SentinelCheckQuorum(ctx context.Context, ip string, masterName string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, masterName, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SentinelCheckQuorum")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(ip, masterName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, masterName, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SentinelFailover provides a mock function with given fields: ctx, ip, masterName, tlsConfig
+func (_m *Client) SentinelFailover(ctx context.Context, ip string, masterName string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, masterName, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SentinelFailover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, masterName, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -282,17 +571,17 @@ func (_m *Client) SentinelCheckQuorum(ip string, masterName string) error {
 	return r0
 }
 
-// SetCustomRedisConfig provides a mock function with given fields: ip, port, configs, password
-func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	ret := _m.Called(ip, port, configs, password)
+// SetCustomRedisConfig provides a mock function with given fields: ctx, ip, port, configs, rewrite, username, password, tlsConfig
+func (_m *Client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, rewrite bool, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, configs, rewrite, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetCustomRedisConfig")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, []string, string) error); ok {
-		r0 = rf(ip, port, configs, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, bool, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, configs, rewrite, username, password, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -300,17 +589,35 @@ func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string,
 	return r0
 }
 
-// SetCustomSentinelConfig provides a mock function with given fields: ip, masterName, configs
-func (_m *Client) SetCustomSentinelConfig(ip string, masterName string, configs []string) error {
-	ret := _m.Called(ip, masterName, configs)
+// SetCustomSentinelConfig provides a mock function with given fields: ctx, ip, masterName, configs, tlsConfig
+func (_m *Client) SetCustomSentinelConfig(ctx context.Context, ip string, masterName string, configs []string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, masterName, configs, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetCustomSentinelConfig")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, []string) error); ok {
-		r0 = rf(ip, masterName, configs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, masterName, configs, tlsConfig)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRedisUser provides a mock function with given fields: ctx, ip, port, name, rules, username, password, tlsConfig
+func (_m *Client) SetRedisUser(ctx context.Context, ip string, port string, name string, rules []string, username string, password string, tlsConfig *tls.Config) error {
+	ret := _m.Called(ctx, ip, port, name, rules, username, password, tlsConfig)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRedisUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string, string, string, *tls.Config) error); ok {
+		r0 = rf(ctx, ip, port, name, rules, username, password, tlsConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -318,9 +625,9 @@ func (_m *Client) SetCustomSentinelConfig(ip string, masterName string, configs
 	return r0
 }
 
-// SlaveIsReady provides a mock function with given fields: ip, port, password
-func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// SlaveIsReady provides a mock function with given fields: ctx, ip, port, username, password, tlsConfig
+func (_m *Client) SlaveIsReady(ctx context.Context, ip string, port string, username string, password string, tlsConfig *tls.Config) (bool, error) {
+	ret := _m.Called(ctx, ip, port, username, password, tlsConfig)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SlaveIsReady")
@@ -328,17 +635,17 @@ func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, e
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) (bool, error)); ok {
+		return rf(ctx, ip, port, username, password, tlsConfig)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *tls.Config) bool); ok {
+		r0 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, *tls.Config) error); ok {
+		r1 = rf(ctx, ip, port, username, password, tlsConfig)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -346,6 +653,24 @@ func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, e
 	return r0, r1
 }
 
+// SubscribeSentinelEvents provides a mock function with given fields: ctx, ip, channels, tlsConfig, onEvent
+func (_m *Client) SubscribeSentinelEvents(ctx context.Context, ip string, channels []string, tlsConfig *tls.Config, onEvent func(string)) error {
+	ret := _m.Called(ctx, ip, channels, tlsConfig, onEvent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeSentinelEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, *tls.Config, func(string)) error); ok {
+		r0 = rf(ctx, ip, channels, tlsConfig, onEvent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
@@ -0,0 +1,222 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/backup"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+// backupTimeout is how long a backup can take to be saved and uploaded before it is failed.
+var backupTimeout = time.Hour
+
+// RedisFailoverBackupHandler takes the backups requested through RedisFailoverBackup objects.
+// A backup goes through the phases:
+//   - Pending: BGSAVE is requested on a replica of the failover.
+//   - Saving: waits for LASTSAVE of the replica to reach the start of the backup, then asks
+//     the backup agent running next to it to upload the RDB file.
+//   - Uploading: waits for the agent to finish and records where the file was stored.
+type RedisFailoverBackupHandler struct {
+	k8sservice  k8s.Services
+	rfChecker   rfservice.RedisFailoverCheck
+	rfHealer    rfservice.RedisFailoverHeal
+	agentClient backup.AgentClient
+	logger      log.Logger
+}
+
+// NewRedisFailoverBackupHandler returns a new RFB handler
+func NewRedisFailoverBackupHandler(rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, agentClient backup.AgentClient, k8sservice k8s.Services, logger log.Logger) *RedisFailoverBackupHandler {
+	return &RedisFailoverBackupHandler{
+		k8sservice:  k8sservice,
+		rfChecker:   rfChecker,
+		rfHealer:    rfHealer,
+		agentClient: agentClient,
+		logger:      logger,
+	}
+}
+
+// Handle moves the backup to its next phase and persists its status when it changed.
+func (r *RedisFailoverBackupHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	b, ok := obj.(*redisfailoverv1.RedisFailoverBackup)
+	if !ok {
+		return fmt.Errorf("can't handle the received object: not a redisfailoverbackup")
+	}
+	if b.Finished() {
+		return nil
+	}
+
+	oldStatus := b.Status.DeepCopy()
+	err := r.reconcile(ctx, b)
+
+	if !equality.Semantic.DeepEqual(oldStatus, &b.Status) {
+		if _, uerr := r.k8sservice.UpdateRedisFailoverBackupStatus(ctx, b.Namespace, b); uerr != nil {
+			r.logger.WithField("redisfailoverbackup", b.ObjectMeta.Name).WithField("namespace", b.ObjectMeta.Namespace).Warningf("Unable to update the backup status: %s", uerr.Error())
+			if err == nil {
+				err = uerr
+			}
+		}
+	}
+	return err
+}
+
+func (r *RedisFailoverBackupHandler) reconcile(ctx context.Context, b *redisfailoverv1.RedisFailoverBackup) error {
+	rf, err := r.k8sservice.GetRedisFailover(ctx, b.Namespace, b.Spec.RedisFailoverName)
+	if apierrors.IsNotFound(err) {
+		r.failBackup(b, fmt.Sprintf("redisfailover %s not found", b.Spec.RedisFailoverName))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !rf.BackupsEnabled() {
+		r.failBackup(b, fmt.Sprintf("redisfailover %s does not have backups enabled", rf.Name))
+		return nil
+	}
+	if err := rf.Validate(); err != nil {
+		return err
+	}
+
+	if b.Status.StartTime != nil && time.Since(b.Status.StartTime.Time) > backupTimeout {
+		r.failBackup(b, fmt.Sprintf("backup did not finish after %s", backupTimeout))
+		return nil
+	}
+
+	switch b.Status.Phase {
+	case "", redisfailoverv1.BackupPending:
+		return r.startBackup(ctx, b, rf)
+	case redisfailoverv1.BackupSaving:
+		return r.checkBackupSaved(ctx, b, rf)
+	case redisfailoverv1.BackupUploading:
+		return r.checkBackupUploaded(b, rf)
+	}
+	return nil
+}
+
+// startBackup requests the RDB file to a replica, so the master does not pay for the fork.
+func (r *RedisFailoverBackupHandler) startBackup(ctx context.Context, b *redisfailoverv1.RedisFailoverBackup, rf *redisfailoverv1.RedisFailover) error {
+	replicas, err := r.rfChecker.GetRedisesSlavesPods(ctx, rf)
+	if err != nil {
+		return err
+	}
+	if len(replicas) == 0 {
+		r.failBackup(b, "no replica available to take the backup from")
+		return nil
+	}
+	node := replicas[0]
+
+	ip, err := r.getPodIP(rf, node)
+	if err != nil {
+		return err
+	}
+
+	start := metav1.Now()
+	if err := r.rfHealer.BackgroundSave(ctx, ip, rf); err != nil {
+		return err
+	}
+
+	r.logger.WithField("redisfailoverbackup", b.ObjectMeta.Name).WithField("namespace", b.ObjectMeta.Namespace).Infof("Backup of redisfailover %s started on %s", rf.Name, node)
+	b.Status.Phase = redisfailoverv1.BackupSaving
+	b.Status.Node = node
+	b.Status.StartTime = &start
+	b.Status.Message = fmt.Sprintf("waiting for %s to write its RDB file", node)
+	return nil
+}
+
+// checkBackupSaved starts the upload once the replica wrote an RDB file after the start of the backup.
+func (r *RedisFailoverBackupHandler) checkBackupSaved(ctx context.Context, b *redisfailoverv1.RedisFailoverBackup, rf *redisfailoverv1.RedisFailover) error {
+	ip, err := r.getPodIP(rf, b.Status.Node)
+	if err != nil {
+		return err
+	}
+
+	lastSave, err := r.rfChecker.GetRedisLastSave(ctx, ip, rf)
+	if err != nil {
+		return err
+	}
+	if lastSave < b.Status.StartTime.Unix() {
+		return nil
+	}
+
+	token, err := rfservice.GetBackupAgentToken(r.k8sservice, rf)
+	if err != nil {
+		return err
+	}
+	if _, err := r.agentClient.StartUpload(ip, token, getBackupKey(b, rf)); err != nil {
+		return err
+	}
+	b.Status.Phase = redisfailoverv1.BackupUploading
+	b.Status.Message = fmt.Sprintf("waiting for %s to upload its RDB file", b.Status.Node)
+	return nil
+}
+
+// checkBackupUploaded records the result of the upload once the agent finished it.
+func (r *RedisFailoverBackupHandler) checkBackupUploaded(b *redisfailoverv1.RedisFailoverBackup, rf *redisfailoverv1.RedisFailover) error {
+	ip, err := r.getPodIP(rf, b.Status.Node)
+	if err != nil {
+		return err
+	}
+
+	token, err := rfservice.GetBackupAgentToken(r.k8sservice, rf)
+	if err != nil {
+		return err
+	}
+	upload, err := r.agentClient.GetUpload(ip, token, getBackupKey(b, rf))
+	if errors.Is(err, backup.ErrUploadNotFound) {
+		r.failBackup(b, fmt.Sprintf("the backup agent of %s lost the upload, it was probably restarted", b.Status.Node))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch upload.State {
+	case backup.UploadFailed:
+		r.failBackup(b, fmt.Sprintf("upload failed: %s", upload.Error))
+	case backup.UploadCompleted:
+		now := metav1.Now()
+		b.Status.Phase = redisfailoverv1.BackupCompleted
+		b.Status.Message = ""
+		b.Status.CompletionTime = &now
+		b.Status.Location = upload.Location
+		b.Status.Size = upload.Size
+		b.Status.Checksum = upload.Checksum
+		r.logger.WithField("redisfailoverbackup", b.ObjectMeta.Name).WithField("namespace", b.ObjectMeta.Namespace).Infof("Backup of redisfailover %s uploaded to %s", rf.Name, upload.Location)
+	}
+	return nil
+}
+
+func (r *RedisFailoverBackupHandler) failBackup(b *redisfailoverv1.RedisFailoverBackup, message string) {
+	r.logger.WithField("redisfailoverbackup", b.ObjectMeta.Name).WithField("namespace", b.ObjectMeta.Namespace).Warningf("Backup failed: %s", message)
+	now := metav1.Now()
+	b.Status.Phase = redisfailoverv1.BackupFailed
+	b.Status.Message = message
+	b.Status.CompletionTime = &now
+}
+
+func (r *RedisFailoverBackupHandler) getPodIP(rf *redisfailoverv1.RedisFailover, podName string) (string, error) {
+	pod, err := r.k8sservice.GetPod(rf.Namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s has no IP", podName)
+	}
+	return pod.Status.PodIP, nil
+}
+
+// getBackupKey returns the name of the uploaded file, under the prefix the agents of the failover accept.
+// The storage prefix is added by the agent.
+func getBackupKey(b *redisfailoverv1.RedisFailoverBackup, rf *redisfailoverv1.RedisFailover) string {
+	return fmt.Sprintf("%s%s.rdb", rfservice.GetBackupKeyPrefix(rf), b.Name)
+}
@@ -0,0 +1,243 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mBackup "github.com/freshworks/redis-operator/mocks/service/backup"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+	"github.com/freshworks/redis-operator/service/backup"
+)
+
+func generateRFWithBackups() *redisfailoverv1.RedisFailover {
+	rf := generateRF(false, false, false)
+	rf.Spec.Backup = &redisfailoverv1.BackupSettings{
+		Storage: redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+	}
+	return rf
+}
+
+func generateRFB(phase redisfailoverv1.BackupPhase, startTime *metav1.Time) *redisfailoverv1.RedisFailoverBackup {
+	rfb := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup",
+			Namespace: namespace,
+		},
+		Spec: redisfailoverv1.RedisFailoverBackupSpec{
+			RedisFailoverName: name,
+		},
+	}
+	rfb.Status.Phase = phase
+	rfb.Status.StartTime = startTime
+	if phase != redisfailoverv1.BackupPending {
+		rfb.Status.Node = "rfr-test-1"
+	}
+	return rfb
+}
+
+func TestRedisFailoverBackupHandle(t *testing.T) {
+	started := metav1.NewTime(time.Now().Add(-time.Minute))
+	expired := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	tests := []struct {
+		name           string
+		rfb            *redisfailoverv1.RedisFailoverBackup
+		rf             *redisfailoverv1.RedisFailover
+		rfErr          error
+		replicas       []string
+		lastSave       int64
+		upload         *backup.Upload
+		uploadErr      error
+		expPhase       redisfailoverv1.BackupPhase
+		expStatusWrite bool
+		expLocation    string
+	}{
+		{
+			name:     "Finished backups are left alone",
+			rfb:      generateRFB(redisfailoverv1.BackupCompleted, &started),
+			expPhase: redisfailoverv1.BackupCompleted,
+		},
+		{
+			name:           "Redis failover not found",
+			rfb:            generateRFB("", nil),
+			rfErr:          apierrors.NewNotFound(schema.GroupResource{}, name),
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+		{
+			name:           "Backups not enabled",
+			rfb:            generateRFB("", nil),
+			rf:             generateRF(false, false, false),
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+		{
+			name:           "No replica to take the backup from",
+			rfb:            generateRFB("", nil),
+			rf:             generateRFWithBackups(),
+			replicas:       []string{},
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+		{
+			name:           "Background save requested on a replica",
+			rfb:            generateRFB("", nil),
+			rf:             generateRFWithBackups(),
+			replicas:       []string{"rfr-test-1"},
+			expPhase:       redisfailoverv1.BackupSaving,
+			expStatusWrite: true,
+		},
+		{
+			name:     "Waiting for the background save",
+			rfb:      generateRFB(redisfailoverv1.BackupSaving, &started),
+			rf:       generateRFWithBackups(),
+			lastSave: started.Unix() - 60,
+			expPhase: redisfailoverv1.BackupSaving,
+		},
+		{
+			name:           "Background save done starts the upload",
+			rfb:            generateRFB(redisfailoverv1.BackupSaving, &started),
+			rf:             generateRFWithBackups(),
+			lastSave:       started.Unix() + 1,
+			expPhase:       redisfailoverv1.BackupUploading,
+			expStatusWrite: true,
+		},
+		{
+			name:     "Waiting for the upload",
+			rfb:      generateRFB(redisfailoverv1.BackupUploading, &started),
+			rf:       generateRFWithBackups(),
+			upload:   &backup.Upload{State: backup.UploadRunning},
+			expPhase: redisfailoverv1.BackupUploading,
+		},
+		{
+			name:           "Upload completed",
+			rfb:            generateRFB(redisfailoverv1.BackupUploading, &started),
+			rf:             generateRFWithBackups(),
+			upload:         &backup.Upload{State: backup.UploadCompleted, Location: "s3://backups/testns/test/backup.rdb", Size: 10, Checksum: "sha256:00"},
+			expPhase:       redisfailoverv1.BackupCompleted,
+			expStatusWrite: true,
+			expLocation:    "s3://backups/testns/test/backup.rdb",
+		},
+		{
+			name:           "Upload failed",
+			rfb:            generateRFB(redisfailoverv1.BackupUploading, &started),
+			rf:             generateRFWithBackups(),
+			upload:         &backup.Upload{State: backup.UploadFailed, Error: "access denied"},
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+		{
+			name:           "Upload lost by the agent",
+			rfb:            generateRFB(redisfailoverv1.BackupUploading, &started),
+			rf:             generateRFWithBackups(),
+			uploadErr:      backup.ErrUploadNotFound,
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+		{
+			name:           "Backup timed out",
+			rfb:            generateRFB(redisfailoverv1.BackupUploading, &expired),
+			rf:             generateRFWithBackups(),
+			expPhase:       redisfailoverv1.BackupFailed,
+			expStatusWrite: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			replicaIP := "0.0.0.1"
+			pod := &corev1.Pod{Status: corev1.PodStatus{PodIP: replicaIP}}
+			agentSecret := &corev1.Secret{Data: map[string][]byte{"token": []byte("token")}}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mac := &mBackup.AgentClient{}
+
+			if !test.rfb.Finished() {
+				mk.On("GetRedisFailover", mock.Anything, namespace, name).Once().Return(test.rf, test.rfErr)
+			}
+			if test.replicas != nil {
+				mrfc.On("GetRedisesSlavesPods", mock.Anything, mock.Anything).Once().Return(test.replicas, nil)
+			}
+			if len(test.replicas) > 0 {
+				mk.On("GetPod", namespace, test.replicas[0]).Once().Return(pod, nil)
+				mrfh.On("BackgroundSave", mock.Anything, replicaIP, mock.Anything).Once().Return(nil)
+			}
+			if test.lastSave != 0 {
+				mk.On("GetPod", namespace, "rfr-test-1").Once().Return(pod, nil)
+				mrfc.On("GetRedisLastSave", mock.Anything, replicaIP, mock.Anything).Once().Return(test.lastSave, nil)
+				if test.expPhase == redisfailoverv1.BackupUploading {
+					mk.On("GetSecret", namespace, "rfr-backup-agent-test").Once().Return(agentSecret, nil)
+					mac.On("StartUpload", replicaIP, "token", "testns/test/backup.rdb").Once().Return(&backup.Upload{State: backup.UploadRunning}, nil)
+				}
+			}
+			if test.upload != nil || test.uploadErr != nil {
+				mk.On("GetPod", namespace, "rfr-test-1").Once().Return(pod, nil)
+				mk.On("GetSecret", namespace, "rfr-backup-agent-test").Once().Return(agentSecret, nil)
+				mac.On("GetUpload", replicaIP, "token", "testns/test/backup.rdb").Once().Return(test.upload, test.uploadErr)
+			}
+			if test.expStatusWrite {
+				mk.On("UpdateRedisFailoverBackupStatus", mock.Anything, namespace, test.rfb).Once().Return(test.rfb, nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverBackupHandler(mrfc, mrfh, mac, mk, log.Dummy)
+			err := handler.Handle(context.TODO(), test.rfb)
+			assert.NoError(err)
+
+			assert.Equal(test.expPhase, test.rfb.Status.Phase)
+			assert.Equal(test.expLocation, test.rfb.Status.Location)
+			if test.expPhase == redisfailoverv1.BackupSaving {
+				assert.Equal("rfr-test-1", test.rfb.Status.Node)
+				assert.NotNil(test.rfb.Status.StartTime)
+			}
+			if test.expStatusWrite && test.rfb.Finished() {
+				assert.NotNil(test.rfb.Status.CompletionTime)
+			}
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+			mac.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisFailoverBackupHandleRetriesOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	rfb := generateRFB("", nil)
+	rf := generateRFWithBackups()
+
+	mk := &mK8SService.Services{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mk.On("GetRedisFailover", mock.Anything, namespace, name).Once().Return(rf, nil)
+	mk.On("GetPod", namespace, "rfr-test-1").Once().Return(&corev1.Pod{Status: corev1.PodStatus{PodIP: "0.0.0.1"}}, nil)
+	mrfc.On("GetRedisesSlavesPods", mock.Anything, mock.Anything).Once().Return([]string{"rfr-test-1"}, nil)
+	mrfh.On("BackgroundSave", mock.Anything, "0.0.0.1", mock.Anything).Once().Return(errors.New("Background save already in progress"))
+
+	handler := rfOperator.NewRedisFailoverBackupHandler(mrfc, mrfh, &mBackup.AgentClient{}, mk, log.Dummy)
+	err := handler.Handle(context.TODO(), rfb)
+
+	// The backup stays pending and nothing is written, it is tried again on the next event
+	assert.Error(err)
+	assert.Equal(redisfailoverv1.BackupPhase(""), rfb.Status.Phase)
+	mk.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
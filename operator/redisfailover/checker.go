@@ -1,92 +1,155 @@
 package redisfailover
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/metrics"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
 )
 
-// UpdateRedisesPods if the running version of pods are equal to the statefulset one
-func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
-	if err != nil {
-		return err
-	}
+// sentinelResetInterval is how long a sentinel reset by the checks has to learn the other sentinels and
+// the replicas again, before the next one is reset.
+const sentinelResetInterval = 10 * time.Second
+
+// UpdateRedisesPods if the running version of pods are equal to the statefulset one.
+// The pace of the update is controlled by the update strategy of the redis spec.
+func (r *RedisFailoverHandler) UpdateRedisesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation) error {
+	strategy := rf.Spec.Redis.UpdateStrategy
 
 	masterIP := ""
-	if !rf.Bootstrapping() {
-		masterIP, _ = r.rfChecker.GetMasterIP(rf)
+	if masters := obs.MastersIPs(); !rf.Bootstrapping() && len(masters) == 1 {
+		masterIP = masters[0]
 	}
 	// No perform updates when nodes are syncing, still not connected, etc.
-	for _, rip := range redises {
-		if rip != masterIP {
-			ready, err := r.rfChecker.CheckRedisSlavesReady(rip, rf)
-			if err != nil {
-				return err
-			}
-			if !ready {
-				return nil
-			}
+	redisesPods := []string{}
+	for _, redis := range obs.Redises {
+		if redis.IP() == masterIP {
+			continue
+		}
+		if redis.Err != nil {
+			return redis.Err
+		}
+		if !redis.Replication.IsSynced() {
+			setUpdateBlocked(rf, fmt.Sprintf("redis %s is not in sync with the master", redis.IP()), false)
+			return nil
+		}
+		if !redis.IsMaster() {
+			redisesPods = append(redisesPods, redis.Pod.Name)
 		}
 	}
 
-	ssUR, err := r.rfChecker.GetStatefulSetUpdateRevision(rf)
-	if err != nil {
-		return err
+	if strategy.MaxReplicationLag != nil && masterIP != "" {
+		if reason := getReplicationLagReason(obs, masterIP, *strategy.MaxReplicationLag); reason != "" {
+			setUpdateBlocked(rf, reason, false)
+			return nil
+		}
 	}
+	setReplicasSynced(rf)
 
-	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(rf)
+	ssUR, err := r.rfChecker.GetStatefulSetUpdateRevision(rf)
 	if err != nil {
 		return err
 	}
 
 	// Update stale pods with slave role
+	updatedPods := []string{}
 	for _, pod := range redisesPods {
 		revision, err := r.rfChecker.GetRedisRevisionHash(pod, rf)
 		if err != nil {
 			return err
 		}
-		if revision != ssUR {
-			//Delete pod and wait next round to check if the new one is synced
-			err = r.rfHealer.DeletePod(pod, rf)
-			if err != nil {
-				return err
-			}
+		if revision == ssUR {
+			updatedPods = append(updatedPods, pod)
+			continue
+		}
+		if isBelowPartition(pod, strategy.Partition) {
+			continue
+		}
+		if reason := getUpdateBlockedReason(rf); reason != "" {
+			setUpdateBlocked(rf, reason, true)
+			return nil
+		}
+		if strategy.Surge && !waitSurgePod(rf, redisesPods) {
 			return nil
 		}
+		//Delete pod and wait next round to check if the new one is synced
+		err = r.rfHealer.DeletePod(pod, rf)
+		if err != nil {
+			return err
+		}
+		setPodRestarted(rf)
+		return nil
 	}
 
 	if !rf.Bootstrapping() {
 		// Update stale pod with role master
-		master, err := r.rfChecker.GetRedisesMasterPod(rf)
-		if err != nil {
-			return err
+		redis, ok := obs.Redis(masterIP)
+		if !ok {
+			return errors.New("redis nodes known as master not found")
 		}
+		master := redis.Pod.Name
 
 		masterRevision, err := r.rfChecker.GetRedisRevisionHash(master, rf)
 		if err != nil {
 			return err
 		}
-		if masterRevision != ssUR {
+		if masterRevision != ssUR && !isBelowPartition(master, strategy.Partition) {
+			if reason := getUpdateBlockedReason(rf); reason != "" {
+				setUpdateBlocked(rf, reason, true)
+				return nil
+			}
+			if strategy.Surge && !waitSurgePod(rf, redisesPods) {
+				return nil
+			}
+			if target := getSwitchoverTarget(rf, updatedPods); strategy.MasterSwitchover && target != "" {
+				// The old master is restarted as a replica on a later round
+				return r.switchoverForUpdate(ctx, rf, master, masterIP, target)
+			}
 			err = r.rfHealer.DeletePod(master, rf)
 			if err != nil {
 				return err
 			}
+			setPodRestarted(rf)
 			return nil
 		}
+
+		if surgePod := getUpdateStatus(rf).SurgePod; surgePod != "" && master == surgePod {
+			// The surge replica was promoted, the master is moved before it is removed
+			target := getSwitchoverTarget(rf, redisesPods)
+			if target == "" {
+				setUpdateBlocked(rf, fmt.Sprintf("no replica to move the master to before removing the surge pod %s", surgePod), true)
+				return nil
+			}
+			return r.switchoverForUpdate(ctx, rf, master, masterIP, target)
+		}
 	}
 
+	if getUpdateStatus(rf).SurgePod != "" {
+		return r.removeSurgePod(rf)
+	}
 	return nil
 }
 
 // CheckAndHeal runs verifcation checks to ensure the RedisFailover is in an expected and healthy state.
 // If the checks do not match up to expectations, an attempt will be made to "heal" the RedisFailover into a healthy state.
-func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) error {
+// The redises and the sentinels are observed once, and all the fixes are decided on that observation.
+func (r *RedisFailoverHandler) CheckAndHeal(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	if rf.Bootstrapping() {
-		return r.checkAndHealBootstrapMode(rf)
+		if err := r.checkAndHealBootstrapMode(ctx, rf); err != nil {
+			return err
+		}
+		// Switchovers are rejected while bootstrapping
+		return r.checkAndHealSwitchover(ctx, rf, "", nil)
 	}
 
 	// Number of redis is equal as the set on the RF spec
@@ -110,127 +173,135 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 		return nil
 	}
 
-	nMasters, err := r.rfChecker.GetNumberMasters(rf)
+	if err := r.checkAndHealPasswordRotation(ctx, rf); err != nil {
+		return err
+	}
+
+	// Users are applied first, the checks below connect with the operator user when auth is enabled
+	err := r.applyRedisCustomConfig(ctx, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
 
-	switch nMasters {
+	obs, err := r.rfChecker.Observe(ctx, rf)
+	if err != nil {
+		return err
+	}
+	if rf.SwitchoverInProgress() {
+		// The sentinels are moving the master, nothing is fixed until they all agree on it
+		return r.checkSwitchoverInProgress(ctx, rf, obs)
+	}
+	actions := decideHealActions(rf, obs)
+
+	masters := obs.MastersIPs()
+	switch len(masters) {
 	case 0:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no masters detected"))
-		//when number of redis replicas is 1 , the redis is configured for standalone master mode
-		//Configure to master
-		if rf.Spec.Redis.Replicas == 1 {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Resource spec with standalone master - operator will set the master")
-			err = r.rfHealer.SetOldestAsMaster(rf)
-			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
-			if err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-				return err
-			}
-			return nil
-		}
-		//During the First boot(New deployment or all pods of the statefulsets have restarted),
-		//Sentinesl will not be able to choose the master , so operator should select a master
-		//Also in scenarios where Sentinels is not in a position to choose a master like , No quorum reached
-		//Operator can choose a master , These scenarios can be checked by asking the all the sentinels
-		//if its in a postion to choose a master also check if the redis is configured with local host IP as master.
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Number of Masters running is 0")
-		maxUptime, err := r.rfChecker.GetMaxRedisPodTime(rf)
-		if err != nil {
-			return err
-		}
-
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("No master avaiable but max pod up time is : %f", maxUptime.Round(time.Second).Seconds())
-		//Check If Sentinel has quorum to take a failover decision
-		noqrm_cnt, err := r.rfChecker.CheckSentinelQuorum(rf)
-		if err != nil {
-			// Sentinels are not in a situation to choose a master we pick one
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Quorum not available for sentinel to choose master,estimated unhealthy sentinels :%d , Operator to step-in", noqrm_cnt)
-			err2 := r.rfHealer.SetOldestAsMaster(rf)
-			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err2)
-			if err2 != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-				return err2
-			}
-		} else {
-			//sentinels are having a quorum to make a failover , but check if redis are not having local hostip (first boot) as master
-			status, err2 := r.rfChecker.CheckIfMasterLocalhost(rf)
-			if err2 != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("CheckIfMasterLocalhost failed retry later")
-				return err2
-			} else if status {
-				// all avaialable redis pods have local host ip as master
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("all available redis is having local loop back as master , operator initiates master selection")
-				err3 := r.rfHealer.SetOldestAsMaster(rf)
-				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err3)
-				if err3 != nil {
-					r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-					return err3
-				}
-
-			} else {
-
-				// We'll wait until failover is done
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("no master found, wait until failover or fix manually")
-				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no master not fixed, wait until failover or fix manually"))
-				return nil
-			}
-
-		}
-
+		rf.Status.Master = redisfailoverv1.MasterStatus{}
+		rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionFalse, "NoMaster", "no redis node is working as master")
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Number of Masters running is 0, max pod up time is : %f", obs.MaxRedisPodTime().Round(time.Second).Seconds())
 	case 1:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, nil)
+		if rf.Restoring() {
+			r.completeRestore(rf)
+		}
 	default:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, errors.New("multiple masters detected"))
-		return errors.New("more than one master, fix manually")
+		rf.Status.Master = redisfailoverv1.MasterStatus{}
+		rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionFalse, "MultipleMasters", fmt.Sprintf("%d redis nodes are working as master", len(masters)))
+	}
+	for _, action := range actions {
+		if action.kind.endsReconcile() {
+			return r.healMaster(ctx, rf, obs, action)
+		}
 	}
 
-	master, err := r.rfChecker.GetMasterIP(rf)
-	if err != nil {
-		return err
+	master := masters[0]
+	rf.Status.Master.IP = master
+	rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionTrue, "MasterElected", fmt.Sprintf("redis %s is the master", master))
+
+	for _, action := range actions.of(healSetRoleLabel) {
+		redis, _ := obs.Redis(action.ip)
+		if err := r.rfHealer.SetRoleLabel(redis.Pod.Name, action.ip == action.master, rf); err != nil {
+			return err
+		}
 	}
 
-	err = r.rfChecker.CheckAllSlavesFromMaster(master, rf)
+	err = actions.errOf(metrics.SLAVE_WRONG_MASTER, "")
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Slave not associated to master: %s", err.Error())
-		if err = r.rfHealer.SetMasterOnAll(master, rf); err != nil {
+		if err = r.rfHealer.SetMasterOnAll(ctx, master, rf); err != nil {
 			return err
 		}
+		// The replicas just moved are not in sync, the pods are updated on a later reconcile
+	} else if err = r.UpdateRedisesPods(ctx, rf, obs); err != nil {
+		return err
+	}
+	if rf.SwitchoverInProgress() {
+		// The master is switched over before restarting it, the sentinels are checked against the new one once it is done
+		return nil
 	}
 
-	err = r.applyRedisCustomConfig(rf)
-	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
-	if err != nil {
+	sentinels := obs.SentinelsIPs()
+	if err := r.healSentinelMonitors(ctx, rf, sentinels, actions); err != nil {
 		return err
 	}
-
-	err = r.UpdateRedisesPods(rf)
-	if err != nil {
+	if err := r.checkAndHealScaleDown(ctx, rf, master, sentinels); err != nil {
 		return err
 	}
-
-	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
-	if err != nil {
+	if rf.SwitchoverInProgress() {
+		// The pods are removed once the master is on one of the pods kept
+		return nil
+	}
+	if rf.ScaleDownResetsSentinels() {
+		// The sentinels left are reset one at a time on the next reconciles
+		return nil
+	}
+	if err := r.checkAndHealSentinels(ctx, rf, sentinels, actions); err != nil {
 		return err
 	}
+	return r.checkAndHealSwitchover(ctx, rf, master, sentinels)
+}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
-	for _, sip := range sentinels {
-		err = r.rfChecker.CheckSentinelMonitor(sip, rf.MasterName(), master, port)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
+// healMaster runs the action decided for a failover without a single master, nothing else is checked
+// until there is one
+func (r *RedisFailoverHandler) healMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation, action healAction) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	if action.reason != "" {
+		r.recorder.Event(rf, corev1.EventTypeWarning, action.reason, action.message)
+	}
+
+	switch action.kind {
+	case healPromoteRestored:
+		return r.promoteRestoredPod(ctx, rf)
+	case healPromoteOldest:
+		logger.Warningf("%s", action.message)
+		err := r.rfHealer.SetOldestAsMaster(ctx, rf)
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-			if err := r.rfHealer.NewSentinelMonitor(sip, master, rf); err != nil {
-				return err
-			}
+			logger.Errorf("Error in Setting oldest Pod as master")
+			return err
+		}
+		// The replicas and the sentinels are checked against the new master right away, on a new observation
+		r.queue.Add(rf.Namespace, rf.Name)
+		return nil
+	case healWaitFailover:
+		logger.Infof("no master found, wait until failover or fix manually")
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, action.err)
+		return nil
+	case healResolveSplitBrain:
+		return r.resolveSplitBrain(ctx, rf, obs)
+	default:
+		if action.message != "" {
+			logger.Errorf("%s", action.message)
 		}
+		return action.err
 	}
-	return r.checkAndHealSentinels(rf, sentinels)
 }
 
-func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) checkAndHealBootstrapMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 
 	if !r.rfChecker.IsRedisRunning(rf) {
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
@@ -238,22 +309,29 @@ func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.Red
 		return nil
 	}
 
-	err := r.UpdateRedisesPods(rf)
+	err := r.applyRedisCustomConfig(ctx, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
-	err = r.applyRedisCustomConfig(rf)
-	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
+
+	obs, err := r.rfChecker.Observe(ctx, rf)
+	if err != nil {
+		return err
+	}
+	err = r.UpdateRedisesPods(ctx, rf, obs)
 	if err != nil {
 		return err
 	}
 
 	bootstrapSettings := rf.Spec.BootstrapNode
-	err = r.rfHealer.SetExternalMasterOnAll(bootstrapSettings.Host, bootstrapSettings.Port, rf)
+	err = r.rfHealer.SetExternalMasterOnAll(ctx, bootstrapSettings.Host, bootstrapSettings.Port, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
+	rf.Status.Master = redisfailoverv1.MasterStatus{IP: bootstrapSettings.Host}
+	rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionTrue, "BootstrapNode", fmt.Sprintf("replicating from bootstrap node %s:%s", bootstrapSettings.Host, bootstrapSettings.Port))
 
 	if rf.SentinelsAllowed() {
 		if !r.rfChecker.IsSentinelRunning(rf) {
@@ -262,67 +340,107 @@ func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.Red
 			return nil
 		}
 
-		sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
-		if err != nil {
+		actions := decideHealActions(rf, obs)
+		sentinels := obs.SentinelsIPs()
+		if err := r.healSentinelMonitors(ctx, rf, sentinels, actions); err != nil {
 			return err
 		}
-		for _, sip := range sentinels {
-			err = r.rfChecker.CheckSentinelMonitor(sip, rf.MasterName(), bootstrapSettings.Host, bootstrapSettings.Port)
-			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
-			if err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-				if err := r.rfHealer.NewSentinelMonitorWithPort(sip, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
-					return err
-				}
-			}
-		}
-		return r.checkAndHealSentinels(rf, sentinels)
+		return r.checkAndHealSentinels(ctx, rf, sentinels, actions)
 	}
 	return nil
 }
 
-func (r *RedisFailoverHandler) applyRedisCustomConfig(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) applyRedisCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	redises, err := r.rfChecker.GetRedisesIPs(rf)
 	if err != nil {
 		return err
 	}
 	for _, rip := range redises {
-		if err := r.rfHealer.SetRedisCustomConfig(rip, rf); err != nil {
+		if err := r.rfHealer.SetRedisUsers(ctx, rip, rf); err != nil {
 			return err
 		}
+		err := r.rfHealer.SetRedisCustomConfig(ctx, rip, rf)
+		var restartErr *redis.RestartRequiredError
+		if errors.As(err, &restartErr) {
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Redis %s: %s", rip, restartErr.Error())
+			r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonRedisConfigRestartRequired, "Redis %s refused to set %s at runtime, the pods are restarted to apply it", rip, strings.Join(restartErr.Parameters, ", "))
+			// The config map and the config hash of the pods get the directives on the reconcile queued
+			r.queue.Add(rf.Namespace, rf.Name)
+			if err == error(restartErr) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// The users of the spec are on every redis and the ones removed from it are gone, they are the ones to
+	// delete once removed from the spec
+	var users []string
+	for _, user := range rf.Spec.Auth.Users {
+		users = append(users, user.Name)
 	}
+	rf.Status.Users = users
 	return nil
 }
 
-func (r *RedisFailoverHandler) checkAndHealSentinels(rf *redisfailoverv1.RedisFailover, sentinels []string) error {
+// healSentinelMonitors makes the sentinels monitor the master when the actions tell they don't
+func (r *RedisFailoverHandler) healSentinelMonitors(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string, actions healActions) error {
 	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelNumberInMemory(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip, err)
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, actions.errOf(metrics.SENTINEL_WRONG_MASTER, sip))
+	}
+	for _, action := range actions.of(healSentinelMonitor) {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", action.err.Error())
+		var err error
+		if bootstrap := rf.Spec.BootstrapNode; bootstrap != nil {
+			err = r.rfHealer.NewSentinelMonitorWithPort(ctx, action.ip, bootstrap.Host, bootstrap.Port, rf)
+		} else {
+			err = r.rfHealer.NewSentinelMonitor(ctx, action.ip, action.master, rf)
+		}
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
-				return err
-			}
+			return err
 		}
-
 	}
+	return nil
+}
+
+// checkAndHealSentinels resets the sentinels that do not know the expected topology, one per reconcile,
+// and applies their custom config. The sentinels are consistent when no action was needed for any of them.
+func (r *RedisFailoverHandler) checkAndHealSentinels(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string, actions healActions) error {
 	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelSlavesNumberInMemory(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, err)
-		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
-				return err
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip, actions.errOf(metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip))
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, actions.errOf(metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip))
+	}
+	// A single sentinel is reset per reconcile, the others keep the quorum while it learns the other
+	// sentinels and the replicas again. The next one is reset on the reconcile queued.
+	if resets := actions.of(healResetSentinel); len(resets) > 0 {
+		action := resets[0]
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch: %s. resetting", action.ip, action.err.Error())
+		if err := r.rfHealer.RestoreSentinel(ctx, action.ip, rf); err != nil {
+			return err
+		}
+		r.recorder.Event(rf, corev1.EventTypeWarning, action.reason, action.message)
+		for _, next := range resets[1:] {
+			if next.ip != action.ip {
+				r.queue.AddAfter(rf.Namespace, rf.Name, sentinelResetInterval)
+				break
 			}
 		}
 	}
 	for _, sip := range sentinels {
-		err := r.rfHealer.SetSentinelCustomConfig(sip, rf)
+		err := r.rfHealer.SetSentinelCustomConfig(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.APPLY_SENTINEL_CONFIG, sip, err)
 		if err != nil {
 			return err
 		}
 	}
+
+	if len(actions.of(healSentinelMonitor, healResetSentinel)) == 0 {
+		rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionTrue, "SentinelsAgree", "all sentinels monitor the expected master")
+	} else {
+		rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionFalse, "SentinelsHealed", "some sentinels were reconfigured during the last reconcile")
+	}
 	return nil
 }
 
@@ -1,22 +1,30 @@
 package redisfailover_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/log"
 	"github.com/freshworks/redis-operator/metrics"
 	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
 	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
 	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
 )
 
 func TestCheckAndHeal(t *testing.T) {
@@ -25,7 +33,6 @@ func TestCheckAndHeal(t *testing.T) {
 		nMasters                       int
 		nRedis                         int
 		forceNewMasterNoQrm            bool
-		forceNewMasterFirstBoot        bool
 		singleMasterTest               bool
 		slavesOK                       bool
 		sentinelMonitorOK              bool
@@ -43,7 +50,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -59,7 +65,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -76,7 +81,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -92,7 +96,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -109,7 +112,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -125,7 +127,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -142,7 +143,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         1,
 			singleMasterTest:               true,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -158,7 +158,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         1,
 			singleMasterTest:               true,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -175,7 +174,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            true,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -190,7 +188,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            true,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -206,7 +203,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            true,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -221,7 +217,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            true,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -237,7 +232,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       false,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -253,7 +247,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       false,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -270,7 +263,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              false,
 			sentinelNumberInMemoryOK:       true,
@@ -286,7 +278,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              false,
 			sentinelNumberInMemoryOK:       true,
@@ -302,7 +293,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       false,
@@ -318,7 +308,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       false,
@@ -335,7 +324,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -351,7 +339,6 @@ func TestCheckAndHeal(t *testing.T) {
 			nRedis:                         3,
 			singleMasterTest:               false,
 			forceNewMasterNoQrm:            false,
-			forceNewMasterFirstBoot:        false,
 			slavesOK:                       true,
 			sentinelMonitorOK:              true,
 			sentinelNumberInMemoryOK:       true,
@@ -527,12 +514,13 @@ func TestCheckAndHeal(t *testing.T) {
 
 			allowSentinels := true
 			bootstrappingTests := test.bootstrapping
-			bootstrapMaster := "127.0.0.1"
+			bootstrapMaster := "10.0.0.100"
 			bootstrapMasterPort := "6379"
 
 			rf := generateRF(false, bootstrappingTests, test.disableMyMaster)
 			if bootstrappingTests {
 				allowSentinels = test.allowSentinels
+				rf.Spec.BootstrapNode.Host = bootstrapMaster
 				rf.Spec.BootstrapNode.AllowSentinels = allowSentinels
 			}
 			if test.singleMasterTest {
@@ -543,7 +531,46 @@ func TestCheckAndHeal(t *testing.T) {
 			continueTests := true
 
 			master := "0.0.0.0"
-			sentinel := "1.1.1.1"
+			replicas := []string{"0.0.0.1", "0.0.0.2"}[:test.nRedis-1]
+			sentinels := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+			if !allowSentinels {
+				sentinels = nil
+			}
+
+			var obs *rfservice.ClusterObservation
+			switch {
+			case bootstrappingTests:
+				obs = generateObservation(rf, "", append([]string{master}, replicas...), sentinels)
+			case test.nMasters == 0:
+				// The replicas still replicate from the master gone
+				obs = generateObservation(rf, "", append([]string{master}, replicas...), sentinels)
+				for i := range obs.Redises {
+					obs.Redises[i].Replication.MasterHost = "0.0.0.9"
+				}
+				if test.forceNewMasterNoQrm {
+					obs.Sentinels[0].QuorumErr = errors.New("")
+					obs.Sentinels[1].QuorumErr = errors.New("")
+				}
+			default:
+				obs = generateObservation(rf, master, replicas, sentinels)
+				for i := 1; i < test.nMasters; i++ {
+					obs.Redises[i].Replication = redis.ReplicationInfo{Role: "master"}
+				}
+			}
+			if !test.slavesOK && !bootstrappingTests {
+				obs.Redises[1].Replication.MasterHost = "0.0.0.9"
+			}
+			if len(sentinels) > 0 {
+				if !test.sentinelMonitorOK {
+					obs.Sentinels[0].MonitorAddress = "0.0.0.9"
+				}
+				if !test.sentinelNumberInMemoryOK {
+					obs.Sentinels[0].Sentinels = 5
+				}
+				if !test.sentinelSlavesNumberInMemoryOK {
+					obs.Sentinels[0].Slaves = 5
+				}
+			}
 
 			config := generateConfig()
 			mk := &mK8SService.Services{}
@@ -563,47 +590,36 @@ func TestCheckAndHeal(t *testing.T) {
 			}
 
 			if bootstrappingTests && continueTests {
-				// once to get ips for config update, once for the UpdateRedisesPods go right
-				mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.1", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.2", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.3", rf).Once().Return(nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.1", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.2", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.3", rf).Once().Return(true, nil)
+				mrfc.On("GetRedisesIPs", rf).Once().Return(obs.RedisesIPs(), nil)
+				for _, rip := range obs.RedisesIPs() {
+					mrfh.On("SetRedisUsers", mock.Anything, rip, rf).Once().Return(nil)
+					mrfh.On("SetRedisCustomConfig", mock.Anything, rip, rf).Once().Return(nil)
+				}
+				mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
+				for _, pod := range obs.RedisPods {
+					mrfc.On("GetRedisRevisionHash", pod.Name, rf).Once().Return("1", nil)
+				}
 
 				if test.redisSetMasterOnAllOK {
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 				} else {
 					expErr = true
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
 				}
 			} else if continueTests {
-				mrfc.On("GetNumberMasters", rf).Once().Return(test.nMasters, nil)
+				// users and custom config are applied before observing the redises
+				mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+				mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+				mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
 				switch test.nMasters {
 				case 0:
-					//mrfc.On("GetRedisesIPs", rf).Once().Return(make([]string, test.nRedis), nil)
-					if rf.Spec.Redis.Replicas == 1 {
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
-						continueTests = false
-						break
-					}
-					mrfc.On("GetMaxRedisPodTime", rf).Once().Return(1*time.Hour, nil)
-					if test.forceNewMasterNoQrm {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(1, errors.New(""))
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
-					} else if test.forceNewMasterFirstBoot {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(true, nil)
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
-					} else {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(false, nil)
-						continueTests = false
+					if rf.Spec.Redis.Replicas == 1 || test.forceNewMasterNoQrm {
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					}
-
+					// Nothing else is checked until there is a master
+					continueTests = false
 				case 1:
 					break
 				default:
@@ -611,62 +627,41 @@ func TestCheckAndHeal(t *testing.T) {
 					expErr = true
 				}
 				if !expErr && continueTests {
-					mrfc.On("GetMasterIP", rf).Twice().Return(master, nil)
 					if test.slavesOK {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(nil)
-					} else {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(errors.New(""))
-						if test.redisSetMasterOnAllOK {
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(nil)
-						} else {
-							expErr = true
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(errors.New(""))
+						mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+						for _, pod := range obs.RedisPods {
+							mrfc.On("GetRedisRevisionHash", pod.Name, rf).Once().Return("1", nil)
 						}
-
+					} else if test.redisSetMasterOnAllOK {
+						// The pods are updated once the replicas moved are in sync
+						mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(nil)
+					} else {
+						expErr = true
+						mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(errors.New(""))
 					}
-					mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{master}, nil)
-					mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-					mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
-					mrfc.On("GetRedisesMasterPod", rf).Once().Return(master, nil)
-					mrfc.On("GetRedisRevisionHash", master, rf).Once().Return("1", nil)
-					mrfh.On("SetRedisCustomConfig", master, rf).Once().Return(nil)
 				}
 			}
 
 			if allowSentinels && !expErr && continueTests {
-				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
-				if test.sentinelMonitorOK {
-					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, rf.MasterName(), bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
-					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, rf.MasterName(), master, "0").Once().Return(nil)
-					}
-				} else {
+				sentinel := sentinels[0]
+				if !test.sentinelMonitorOK {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, rf.MasterName(), bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitorWithPort", sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+						mrfh.On("NewSentinelMonitorWithPort", mock.Anything, sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, rf.MasterName(), master, "0").Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitor", sentinel, master, rf).Once().Return(nil)
+						mrfh.On("NewSentinelMonitor", mock.Anything, sentinel, master, rf).Once().Return(nil)
 					}
 				}
-				if test.sentinelNumberInMemoryOK {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(nil)
-				} else {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+				if !test.sentinelNumberInMemoryOK || !test.sentinelSlavesNumberInMemoryOK {
+					// Reset once whatever it knows wrong
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
 				}
-				if test.sentinelSlavesNumberInMemoryOK {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(nil)
-				} else {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+				for _, sip := range sentinels {
+					mrfh.On("SetSentinelCustomConfig", mock.Anything, sip, rf).Once().Return(nil)
 				}
-				mrfh.On("SetSentinelCustomConfig", sentinel, rf).Once().Return(nil)
 			}
 
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.CheckAndHeal(rf)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
 
 			if expErr {
 				assert.Error(err)
@@ -679,6 +674,213 @@ func TestCheckAndHeal(t *testing.T) {
 	}
 }
 
+func TestCheckAndHealResetsOneSentinel(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false, false)
+	master := "0.0.0.0"
+	sentinels := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+	obs := generateObservation(rf, master, []string{"0.0.0.1", "0.0.0.2"}, sentinels)
+	// Every sentinel knows sentinels gone, only the first one is reset by this reconcile
+	for i := range obs.Sentinels {
+		obs.Sentinels[i].Sentinels = 5
+	}
+
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("IsRedisRunning", rf).Once().Return(true)
+	mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+	mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+	mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
+	mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+	for _, pod := range obs.RedisPods {
+		mrfc.On("GetRedisRevisionHash", pod.Name, rf).Once().Return("1", nil)
+	}
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+	mrfh.On("RestoreSentinel", mock.Anything, "1.1.1.1", rf).Once().Return(nil)
+	for _, sip := range sentinels {
+		mrfh.On("SetSentinelCustomConfig", mock.Anything, sip, rf).Once().Return(nil)
+	}
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+	assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+	assert.False(rf.IsStatusConditionTrue(redisfailoverv1.ConditionSentinelsConsistent))
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestCheckAndHealRecordsUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		usersErr error
+		expUsers []string
+	}{
+		{
+			name:     "Users recorded once set on every redis",
+			expUsers: []string{"app"},
+		},
+		{
+			name:     "Users not recorded when not set on every redis",
+			usersErr: errors.New(""),
+			expUsers: []string{"app", "old"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{{Name: "app", SecretPath: "app-auth"}}
+			rf.Status.Users = []string{"app", "old"}
+
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0", "1.1.1.1"}, nil)
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mrfh.On("SetRedisUsers", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+			mrfh.On("SetRedisUsers", mock.Anything, "1.1.1.1", rf).Once().Return(test.usersErr)
+			if test.usersErr == nil {
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "1.1.1.1", rf).Once().Return(nil)
+				// Stops the reconcile right after the users are applied
+				mrfc.On("Observe", mock.Anything, rf).Once().Return(nil, errors.New(""))
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			assert.Error(handler.CheckAndHeal(context.TODO(), rf))
+			assert.Equal(test.expUsers, rf.Status.Users)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealConfigRestartRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false, false)
+	master := "0.0.0.0"
+
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("IsRedisRunning", rf).Once().Return(true)
+	mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+	mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+	// The reconcile goes on, stopped right after the custom config is applied
+	mrfc.On("Observe", mock.Anything, rf).Once().Return(nil, errors.New("observe"))
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(&redis.RestartRequiredError{Parameters: []string{"bf-error-rate"}})
+
+	recorder := record.NewFakeRecorder(10)
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, recorder, log.Dummy)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	keys := make(chan types.NamespacedName, 1)
+	go handler.ReconcileQueue().Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+		keys <- key
+		return nil
+	}, log.Dummy)
+
+	assert.EqualError(handler.CheckAndHeal(context.TODO(), rf), "observe")
+	assert.Equal("Warning RedisConfigRestartRequired Redis 0.0.0.0 refused to set bf-error-rate at runtime, the pods are restarted to apply it", <-recorder.Events)
+	// The config map gets the directive on the next reconcile
+	select {
+	case key := <-keys:
+		assert.Equal(types.NamespacedName{Namespace: rf.Namespace, Name: rf.Name}, key)
+	case <-time.After(2 * time.Second):
+		assert.Fail("no reconcile queued")
+	}
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestCheckAndHealPromotesOldest(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false, false)
+	sentinels := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+	// The replicas still replicate from the master gone and the sentinels have no quorum to elect another one
+	obs := generateObservation(rf, "", []string{"0.0.0.0", "0.0.0.1", "0.0.0.2"}, sentinels)
+	for i := range obs.Redises {
+		obs.Redises[i].Replication.MasterHost = "0.0.0.9"
+	}
+	obs.Sentinels[0].QuorumErr = errors.New("")
+	obs.Sentinels[1].QuorumErr = errors.New("")
+
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("IsRedisRunning", rf).Once().Return(true)
+	mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+	mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0"}, nil)
+	mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisUsers", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+	mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+	mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	keys := make(chan types.NamespacedName, 1)
+	go handler.ReconcileQueue().Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+		keys <- key
+		return nil
+	}, log.Dummy)
+
+	assert.NoError(handler.CheckAndHeal(context.TODO(), rf))
+	// The replicas and the sentinels are moved to the new master by the reconcile queued
+	select {
+	case key := <-keys:
+		assert.Equal(types.NamespacedName{Namespace: rf.Namespace, Name: rf.Name}, key)
+	case <-time.After(2 * time.Second):
+		assert.Fail("no reconcile queued")
+	}
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+// generateObservation returns the observation of a healthy failover: the replicas replicate from the
+// master, or from the bootstrap node while bootstrapping, and the sentinels monitor it.
+func generateObservation(rf *redisfailoverv1.RedisFailover, master string, replicas []string, sentinels []string) *rfservice.ClusterObservation {
+	monitor, port := master, strconv.Itoa(int(rf.Spec.Redis.Port))
+	slaves := rf.RedisPods() - 1
+	if rf.Bootstrapping() {
+		monitor, port = rf.Spec.BootstrapNode.Host, rf.Spec.BootstrapNode.Port
+		slaves = rf.RedisPods()
+	}
+
+	obs := &rfservice.ClusterObservation{}
+	addRedis := func(ip string, role string, replication redis.ReplicationInfo) {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("rfr-%s-%d", rf.Name, len(obs.RedisPods)),
+				Labels: map[string]string{"redisfailovers-role": role},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+		obs.RedisPods = append(obs.RedisPods, pod)
+		obs.Redises = append(obs.Redises, rfservice.RedisObservation{Pod: pod, Replication: replication})
+	}
+	if master != "" {
+		addRedis(master, "master", redis.ReplicationInfo{Role: "master"})
+	}
+	for _, ip := range replicas {
+		addRedis(ip, "slave", redis.ReplicationInfo{Role: "slave", MasterHost: monitor, MasterLinkUp: true})
+	}
+	for _, ip := range sentinels {
+		obs.Sentinels = append(obs.Sentinels, rfservice.SentinelObservation{
+			IP:             ip,
+			MonitorAddress: monitor,
+			MonitorPort:    port,
+			Sentinels:      rf.SentinelPods(),
+			Slaves:         slaves,
+		})
+	}
+	return obs
+}
+
 func TestUpdate(t *testing.T) {
 	type podStatus struct {
 		pod    corev1.Pod
@@ -1128,22 +1330,18 @@ func TestUpdate(t *testing.T) {
 			config := generateConfig()
 			mrfs := &mRFService.RedisFailoverClient{}
 
-			mrfc := &mRFService.RedisFailoverCheck{}
-			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0", "0.0.0.1", "1.1.1.1"}, nil)
-
-			next := true
-			if !test.bootstrapping {
-				master := "1.1.1.1"
-				if test.noMaster {
-					master = ""
+			obs := &rfservice.ClusterObservation{}
+			for _, pod := range test.pods {
+				replication := redis.ReplicationInfo{Role: "slave", MasterHost: "10.0.0.100", MasterLinkUp: pod.ready}
+				if pod.master {
+					replication = redis.ReplicationInfo{Role: "master"}
 				}
-				mrfc.On("GetMasterIP", rf).Once().Return(master, nil)
+				obs.Redises = append(obs.Redises, rfservice.RedisObservation{Pod: pod.pod, Replication: replication})
 			}
 
+			mrfc := &mRFService.RedisFailoverCheck{}
+			next := true
 			for _, pod := range test.pods {
-				if !pod.master {
-					mrfc.On("CheckRedisSlavesReady", pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
-				}
 				if !pod.ready {
 					next = false
 					break
@@ -1152,37 +1350,23 @@ func TestUpdate(t *testing.T) {
 			mrfh := &mRFService.RedisFailoverHeal{}
 
 			if next {
-				replicas := []string{"slave1", "slave2"}
-				if test.bootstrapping || test.noMaster {
-					replicas = append(replicas, "slave3")
-				}
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return(test.ssVersion, nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return(replicas, nil)
 
 				for _, pod := range test.pods {
 					mrfc.On("GetRedisRevisionHash", pod.pod.ObjectMeta.Name, rf).Once().Return(pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey], nil)
 					if pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
 						mrfh.On("DeletePod", pod.pod.ObjectMeta.Name, rf).Once().Return(nil)
 						if pod.master == false {
-							next = false
 							break
 						}
 					}
 				}
-				fmt.Printf("%v - %v\n", test.name, next)
-				if next && !test.bootstrapping {
-					if test.noMaster {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("", errors.New(""))
-					} else {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("master", nil)
-					}
-				}
 			}
 
 			mk := &mK8SService.Services{}
 
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.UpdateRedisesPods(rf)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			err := handler.UpdateRedisesPods(context.TODO(), rf, obs)
 
 			if test.errExpected {
 				assert.Error(err)
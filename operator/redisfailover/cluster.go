@@ -0,0 +1,519 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/operator/redisfailover/util"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+const rcLabelNameKey = "redisclusters.databases.spotahome.com/name"
+
+// clusterReshardBatch is the number of slots moved on every reconcile while the cluster is resharded,
+// so a reconcile does not block the controller for long.
+var clusterReshardBatch = 256
+
+// RedisClusterHandler creates the shards of the RedisCluster objects and keeps their topology:
+//   - The redis of every shard are made meet, the first master of a shard gets the other pods as replicas.
+//   - The slots without master are assigned to the shards, evenly on a new cluster.
+//   - Failed nodes that are not a pod of the cluster anymore are forgotten by all the nodes.
+//   - When the number of shards changes the slots are moved, a batch at a time, until they are
+//     evenly split, and the shards left without slots are removed.
+type RedisClusterHandler struct {
+	k8sservice k8s.Services
+	rcClient   rfservice.RedisClusterClient
+	rcHealer   rfservice.RedisClusterHeal
+	recorder   record.EventRecorder
+	logger     log.Logger
+}
+
+// NewRedisClusterHandler returns a new RC handler
+func NewRedisClusterHandler(rcClient rfservice.RedisClusterClient, rcHealer rfservice.RedisClusterHeal, k8sservice k8s.Services, recorder record.EventRecorder, logger log.Logger) *RedisClusterHandler {
+	return &RedisClusterHandler{
+		k8sservice: k8sservice,
+		rcClient:   rcClient,
+		rcHealer:   rcHealer,
+		recorder:   recorder,
+		logger:     logger,
+	}
+}
+
+// clusterPod is a running redis of the cluster with the nodes it knows about
+type clusterPod struct {
+	name  string
+	ip    string
+	node  redis.ClusterNode
+	known []redis.ClusterNode
+}
+
+// clusterShard is a shard of the cluster with its running redis, and the one working as its master
+type clusterShard struct {
+	index  int
+	name   string
+	pods   []*clusterPod
+	master *clusterPod
+}
+
+// Handle moves the cluster towards the requested topology and persists its status when it changed.
+func (r *RedisClusterHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	rc, ok := obj.(*redisfailoverv1.RedisCluster)
+	if !ok {
+		return fmt.Errorf("can't handle the received object: not a rediscluster")
+	}
+	if err := rc.Validate(); err != nil {
+		return err
+	}
+
+	oldStatus := rc.Status.DeepCopy()
+	err := r.reconcile(ctx, rc)
+	if err != nil && rc.Status.Phase == redisfailoverv1.ClusterReady {
+		rc.Status.Phase = redisfailoverv1.ClusterDegraded
+		rc.Status.Message = err.Error()
+	}
+	rc.Status.ObservedGeneration = rc.Generation
+
+	if !equality.Semantic.DeepEqual(oldStatus, &rc.Status) {
+		if _, uerr := r.k8sservice.UpdateRedisClusterStatus(ctx, rc.Namespace, rc); uerr != nil {
+			r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Warningf("Unable to update the cluster status: %s", uerr.Error())
+			if err == nil {
+				err = uerr
+			}
+		}
+	}
+	return err
+}
+
+func (r *RedisClusterHandler) reconcile(ctx context.Context, rc *redisfailoverv1.RedisCluster) error {
+	if err := r.ensure(rc); err != nil {
+		return err
+	}
+
+	shards, ready, err := r.getShards(ctx, rc)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		r.setPendingPhase(rc, "waiting for the redis of all the shards to run")
+		return nil
+	}
+
+	if err := r.forgetFailedNodes(ctx, rc, shards); err != nil {
+		return err
+	}
+
+	met, err := r.meetNodes(ctx, rc, shards)
+	if err != nil {
+		return err
+	}
+	if met {
+		r.setPendingPhase(rc, "waiting for the redis to join the cluster")
+		return nil
+	}
+
+	if err := r.setShardsReplicas(ctx, rc, shards); err != nil {
+		return err
+	}
+
+	assigned, err := r.assignUncoveredSlots(ctx, rc, shards)
+	if err != nil {
+		return err
+	}
+	if assigned {
+		r.setPendingPhase(rc, "waiting for the slots to be assigned")
+		return nil
+	}
+
+	resharding, err := r.reshard(ctx, rc, shards)
+	if err != nil {
+		return err
+	}
+	if !resharding {
+		if err := r.removeDrainedShards(rc, shards); err != nil {
+			return err
+		}
+	}
+
+	r.updateClusterStatus(rc, shards, resharding)
+	return nil
+}
+
+func (r *RedisClusterHandler) ensure(rc *redisfailoverv1.RedisCluster) error {
+	labels := util.MergeLabels(defaultLabels, map[string]string{rcLabelNameKey: rc.Name}, rc.Labels)
+	ownerRefs := []metav1.OwnerReference{
+		*metav1.NewControllerRef(rc, redisfailoverv1.VersionKind(redisfailoverv1.RCKind)),
+	}
+
+	if err := r.rcClient.EnsureClusterConfigMap(rc, labels, ownerRefs); err != nil {
+		return err
+	}
+	if err := r.rcClient.EnsureClusterService(rc, labels, ownerRefs); err != nil {
+		return err
+	}
+	for shard := 0; shard < int(rc.Spec.Shards); shard++ {
+		if err := r.rcClient.EnsureClusterShard(rc, shard, labels, ownerRefs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getShards returns the shards with the nodes their redis know about. It is not ready until all
+// the redis requested for every shard run.
+func (r *RedisClusterHandler) getShards(ctx context.Context, rc *redisfailoverv1.RedisCluster) ([]*clusterShard, bool, error) {
+	rcShards, err := r.rcClient.GetClusterShards(rc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	expectedPods := 1 + int(rc.Spec.ReplicasPerShard)
+	shards := []*clusterShard{}
+	for _, rcShard := range rcShards {
+		shard := &clusterShard{index: rcShard.Index, name: rfservice.GetRedisClusterShardName(rc, rcShard.Index)}
+		for _, pod := range rcShard.Pods {
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || pod.Status.PodIP == "" {
+				return nil, false, nil
+			}
+			shard.pods = append(shard.pods, &clusterPod{name: pod.Name, ip: pod.Status.PodIP})
+		}
+		if r.isActiveShard(rc, shard) && len(shard.pods) != expectedPods {
+			return nil, false, nil
+		}
+		shards = append(shards, shard)
+	}
+	if len(shards) < int(rc.Spec.Shards) {
+		return nil, false, nil
+	}
+
+	for _, shard := range shards {
+		for _, pod := range shard.pods {
+			known, err := r.rcHealer.GetClusterNodes(ctx, pod.ip, rc)
+			if err != nil {
+				return nil, false, err
+			}
+			pod.known = known
+			for _, node := range known {
+				if node.Myself() {
+					pod.node = node
+				}
+			}
+		}
+	}
+	return shards, true, nil
+}
+
+func (r *RedisClusterHandler) isActiveShard(rc *redisfailoverv1.RedisCluster, shard *clusterShard) bool {
+	return shard.index < int(rc.Spec.Shards)
+}
+
+// forgetFailedNodes makes every redis forget the failed nodes that are not a running pod anymore,
+// like the previous identity of a pod restarted without its data.
+func (r *RedisClusterHandler) forgetFailedNodes(ctx context.Context, rc *redisfailoverv1.RedisCluster, shards []*clusterShard) error {
+	current := map[string]bool{}
+	for _, shard := range shards {
+		for _, pod := range shard.pods {
+			current[pod.node.ID] = true
+		}
+	}
+
+	forgotten := map[string]bool{}
+	for _, shard := range shards {
+		for _, pod := range shard.pods {
+			for _, node := range pod.known {
+				// A replica can't forget its master, it forgets it once it follows another one
+				if !node.Failed() || current[node.ID] || pod.node.MasterID == node.ID {
+					continue
+				}
+				if err := r.rcHealer.ClusterForget(ctx, pod.ip, node.ID, rc); err != nil {
+					return err
+				}
+				forgotten[node.ID] = true
+			}
+		}
+	}
+
+	for id := range forgotten {
+		r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Failed node %s forgotten", id)
+		r.recorder.Eventf(rc, corev1.EventTypeNormal, rfservice.EventReasonClusterNodeForgotten, "Failed node %s forgotten by the cluster", id)
+	}
+	return nil
+}
+
+// meetNodes makes the redis unknown to the first one join the cluster. It tells if any was met,
+// so the topology is not changed until the cluster agrees on its nodes.
+func (r *RedisClusterHandler) meetNodes(ctx context.Context, rc *redisfailoverv1.RedisCluster, shards []*clusterShard) (bool, error) {
+	var entry *clusterPod
+	for _, shard := range shards {
+		if len(shard.pods) > 0 {
+			entry = shard.pods[0]
+			break
+		}
+	}
+	if entry == nil {
+		return false, nil
+	}
+
+	known := map[string]bool{}
+	for _, node := range entry.known {
+		known[node.ID] = true
+	}
+
+	met := false
+	for _, shard := range shards {
+		for _, pod := range shard.pods {
+			if known[pod.node.ID] {
+				continue
+			}
+			if err := r.rcHealer.ClusterMeet(ctx, pod.ip, entry.ip, rc); err != nil {
+				return false, err
+			}
+			met = true
+		}
+	}
+	return met, nil
+}
+
+// setShardsReplicas chooses the master of every shard, the redis already serving slots if there is
+// one, and makes the other redis of the shard replicate it.
+func (r *RedisClusterHandler) setShardsReplicas(ctx context.Context, rc *redisfailoverv1.RedisCluster, shards []*clusterShard) error {
+	for _, shard := range shards {
+		for _, pod := range shard.pods {
+			if pod.node.IsMaster() && len(pod.node.Slots) > 0 {
+				shard.master = pod
+				break
+			}
+		}
+		if shard.master == nil {
+			for _, pod := range shard.pods {
+				if pod.node.IsMaster() {
+					shard.master = pod
+					break
+				}
+			}
+		}
+		if shard.master == nil || !r.isActiveShard(rc, shard) {
+			continue
+		}
+
+		for _, pod := range shard.pods {
+			if pod == shard.master || pod.node.MasterID == shard.master.node.ID {
+				continue
+			}
+			if pod.node.IsMaster() && len(pod.node.Slots) > 0 {
+				r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Warningf("Redis %s of shard %s serves slots but is not its master", pod.name, shard.name)
+				continue
+			}
+			if err := r.rcHealer.ClusterReplicate(ctx, pod.ip, shard.master.node.ID, rc); err != nil {
+				return err
+			}
+			pod.node.MasterID = shard.master.node.ID
+		}
+	}
+	return nil
+}
+
+// assignUncoveredSlots gives the slots no master serves to the shards below their share.
+func (r *RedisClusterHandler) assignUncoveredSlots(ctx context.Context, rc *redisfailoverv1.RedisCluster, shards []*clusterShard) (bool, error) {
+	covered := make([]bool, redisfailoverv1.ClusterSlots)
+	for _, shard := range shards {
+		if shard.master == nil {
+			continue
+		}
+		for _, slot := range shard.master.node.Slots {
+			covered[slot] = true
+		}
+	}
+	uncovered := []int{}
+	for slot, ok := range covered {
+		if !ok {
+			uncovered = append(uncovered, slot)
+		}
+	}
+	if len(uncovered) == 0 {
+		return false, nil
+	}
+
+	active := r.getActiveShardsWithMaster(rc, shards)
+	if len(active) == 0 {
+		return false, nil
+	}
+	targets := getSlotsTargets(int(rc.Spec.Shards))
+	for i, shard := range active {
+		count := targets[shard.index] - len(shard.master.node.Slots)
+		// The last shard takes what is left, the resharding evens the shards afterwards
+		if i == len(active)-1 || count > len(uncovered) {
+			count = len(uncovered)
+		}
+		if count <= 0 {
+			continue
+		}
+		slots := uncovered[:count]
+		uncovered = uncovered[count:]
+		if err := r.rcHealer.ClusterAddSlots(ctx, shard.master.ip, slots, rc); err != nil {
+			return false, err
+		}
+		r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("%d slots assigned to shard %s", len(slots), shard.name)
+		r.recorder.Eventf(rc, corev1.EventTypeNormal, rfservice.EventReasonClusterSlotsAssigned, "%d slots assigned to shard %s", len(slots), shard.name)
+	}
+	return true, nil
+}
+
+// reshard moves a batch of slots from the shards above their share, the removed ones included,
+// to the shards below it. It tells if any slot had to be moved.
+func (r *RedisClusterHandler) reshard(ctx context.Context, rc *redisfailoverv1.RedisCluster, shards []*clusterShard) (bool, error) {
+	active := r.getActiveShardsWithMaster(rc, shards)
+	if len(active) != int(rc.Spec.Shards) {
+		return false, nil
+	}
+	targets := getSlotsTargets(int(rc.Spec.Shards))
+
+	donors := []*clusterShard{}
+	receivers := []*clusterShard{}
+	for _, shard := range shards {
+		if shard.master == nil {
+			continue
+		}
+		target := 0
+		if r.isActiveShard(rc, shard) {
+			target = targets[shard.index]
+		}
+		if len(shard.master.node.Slots) > target {
+			donors = append(donors, shard)
+		} else if len(shard.master.node.Slots) < target {
+			receivers = append(receivers, shard)
+		}
+	}
+	if len(donors) == 0 {
+		return false, nil
+	}
+
+	budget := clusterReshardBatch
+	for _, donor := range donors {
+		target := 0
+		if r.isActiveShard(rc, donor) {
+			target = targets[donor.index]
+		}
+		for _, receiver := range receivers {
+			excess := len(donor.master.node.Slots) - target
+			deficit := targets[receiver.index] - len(receiver.master.node.Slots)
+			count := min(excess, deficit, budget)
+			if count <= 0 {
+				continue
+			}
+			// The highest slots of the donor are moved, so the shards keep contiguous ranges
+			slots := append([]int{}, donor.master.node.Slots...)
+			sort.Ints(slots)
+			moved := slots[len(slots)-count:]
+			for _, slot := range moved {
+				if err := r.rcHealer.ClusterMigrateSlot(ctx, donor.master.ip, receiver.master.ip, slot, rc); err != nil {
+					return false, err
+				}
+			}
+			donor.master.node.Slots = slots[:len(slots)-count]
+			receiver.master.node.Slots = append(receiver.master.node.Slots, moved...)
+			budget -= count
+
+			r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("%d slots migrated from shard %s to shard %s", count, donor.name, receiver.name)
+			r.recorder.Eventf(rc, corev1.EventTypeNormal, rfservice.EventReasonClusterSlotsMigrated, "%d slots migrated from shard %s to shard %s", count, donor.name, receiver.name)
+		}
+	}
+	return true, nil
+}
+
+// removeDrainedShards deletes the shards above the requested number once they serve no slot, their
+// nodes are forgotten by the cluster once they are gone.
+func (r *RedisClusterHandler) removeDrainedShards(rc *redisfailoverv1.RedisCluster, shards []*clusterShard) error {
+	for _, shard := range shards {
+		if r.isActiveShard(rc, shard) {
+			continue
+		}
+		if shard.master != nil && len(shard.master.node.Slots) > 0 {
+			continue
+		}
+		if err := r.rcClient.DeleteClusterShard(rc, shard.index); err != nil {
+			return err
+		}
+		r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Shard %s removed", shard.name)
+		r.recorder.Eventf(rc, corev1.EventTypeNormal, rfservice.EventReasonClusterShardRemoved, "Shard %s removed after its slots were moved", shard.name)
+	}
+	return nil
+}
+
+func (r *RedisClusterHandler) getActiveShardsWithMaster(rc *redisfailoverv1.RedisCluster, shards []*clusterShard) []*clusterShard {
+	active := []*clusterShard{}
+	for _, shard := range shards {
+		if r.isActiveShard(rc, shard) && shard.master != nil {
+			active = append(active, shard)
+		}
+	}
+	return active
+}
+
+func (r *RedisClusterHandler) updateClusterStatus(rc *redisfailoverv1.RedisCluster, shards []*clusterShard, resharding bool) {
+	status := []redisfailoverv1.ShardStatus{}
+	healthy := true
+	removed := false
+	for _, shard := range shards {
+		shardStatus := redisfailoverv1.ShardStatus{Name: shard.name}
+		if shard.master != nil {
+			shardStatus.Master = shard.master.name
+			shardStatus.Slots = int32(len(shard.master.node.Slots))
+			for _, pod := range shard.pods {
+				if pod.node.MasterID == shard.master.node.ID {
+					shardStatus.Replicas++
+				}
+			}
+		}
+		if !r.isActiveShard(rc, shard) {
+			removed = true
+		} else if shard.master == nil || shardStatus.Replicas != rc.Spec.ReplicasPerShard {
+			healthy = false
+		}
+		status = append(status, shardStatus)
+	}
+	rc.Status.Shards = status
+
+	switch {
+	case resharding || removed:
+		rc.Status.Phase = redisfailoverv1.ClusterResharding
+		rc.Status.Message = fmt.Sprintf("moving the slots to %d shards", rc.Spec.Shards)
+	case healthy:
+		rc.Status.Phase = redisfailoverv1.ClusterReady
+		rc.Status.Message = ""
+	default:
+		r.setPendingPhase(rc, "a shard has no master or misses replicas")
+	}
+}
+
+// setPendingPhase keeps a new cluster creating, a cluster that was ready before is degraded.
+func (r *RedisClusterHandler) setPendingPhase(rc *redisfailoverv1.RedisCluster, message string) {
+	if rc.Status.Phase == "" || rc.Status.Phase == redisfailoverv1.ClusterCreating {
+		rc.Status.Phase = redisfailoverv1.ClusterCreating
+	} else {
+		rc.Status.Phase = redisfailoverv1.ClusterDegraded
+	}
+	rc.Status.Message = message
+}
+
+// getSlotsTargets returns the number of slots of every shard when they are evenly split.
+func getSlotsTargets(shards int) []int {
+	targets := make([]int, shards)
+	for i := range targets {
+		targets[i] = redisfailoverv1.ClusterSlots / shards
+		if i < redisfailoverv1.ClusterSlots%shards {
+			targets[i]++
+		}
+	}
+	return targets
+}
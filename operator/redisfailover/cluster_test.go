@@ -0,0 +1,332 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+func generateRC(shards int32, phase redisfailoverv1.RedisClusterPhase) *redisfailoverv1.RedisCluster {
+	return &redisfailoverv1.RedisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: redisfailoverv1.RedisClusterSpec{
+			Shards:           shards,
+			ReplicasPerShard: 1,
+		},
+		Status: redisfailoverv1.RedisClusterStatus{
+			Phase: phase,
+		},
+	}
+}
+
+// testClusterNode is a node of the cluster as every other node sees it
+type testClusterNode struct {
+	id     string
+	ip     string
+	master string
+	slots  []int
+	failed bool
+}
+
+// clusterView returns CLUSTER NODES as answered by the node with the myself id
+func clusterView(myself string, nodes []testClusterNode) []redis.ClusterNode {
+	view := []redis.ClusterNode{}
+	for _, n := range nodes {
+		node := redis.ClusterNode{ID: n.id, IP: n.ip, MasterID: n.master, Slots: n.slots}
+		if n.id == myself {
+			node.Flags = append(node.Flags, "myself")
+		}
+		if n.master == "" {
+			node.Flags = append(node.Flags, "master")
+		} else {
+			node.Flags = append(node.Flags, "slave")
+		}
+		if n.failed {
+			node.Flags = append(node.Flags, "fail")
+		}
+		view = append(view, node)
+	}
+	return view
+}
+
+func slotsRange(first, last int) []int {
+	slots := []int{}
+	for slot := first; slot <= last; slot++ {
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+func runningPod(podName, ip string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: podName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+}
+
+func generateClusterShards(shards int) []rfservice.RedisClusterShard {
+	ips := [][]string{{"10.0.0.0", "10.0.0.1"}, {"10.0.1.0", "10.0.1.1"}, {"10.0.2.0", "10.0.2.1"}}
+	names := [][]string{{"rfc-test-0-0", "rfc-test-0-1"}, {"rfc-test-1-0", "rfc-test-1-1"}, {"rfc-test-2-0", "rfc-test-2-1"}}
+	rcShards := []rfservice.RedisClusterShard{}
+	for i := 0; i < shards; i++ {
+		rcShards = append(rcShards, rfservice.RedisClusterShard{
+			Index: i,
+			Pods:  []corev1.Pod{runningPod(names[i][0], ips[i][0]), runningPod(names[i][1], ips[i][1])},
+		})
+	}
+	return rcShards
+}
+
+// readyClusterNodes returns two shards with a replica each, serving half of the slots each
+func readyClusterNodes() []testClusterNode {
+	return []testClusterNode{
+		{id: "a0", ip: "10.0.0.0", slots: slotsRange(0, 8191)},
+		{id: "a1", ip: "10.0.0.1", master: "a0"},
+		{id: "b0", ip: "10.0.1.0", slots: slotsRange(8192, 16383)},
+		{id: "b1", ip: "10.0.1.1", master: "b0"},
+	}
+}
+
+func mockClusterEnsure(mrcc *mRFService.RedisClusterClient, rc *redisfailoverv1.RedisCluster) {
+	mrcc.On("EnsureClusterConfigMap", rc, mock.Anything, mock.Anything).Once().Return(nil)
+	mrcc.On("EnsureClusterService", rc, mock.Anything, mock.Anything).Once().Return(nil)
+	for shard := 0; shard < int(rc.Spec.Shards); shard++ {
+		mrcc.On("EnsureClusterShard", rc, shard, mock.Anything, mock.Anything).Once().Return(nil)
+	}
+}
+
+func mockClusterViews(mrch *mRFService.RedisClusterHeal, rc *redisfailoverv1.RedisCluster, nodes []testClusterNode, views map[string][]testClusterNode) {
+	for _, node := range nodes {
+		if node.failed {
+			continue
+		}
+		view := nodes
+		if v, ok := views[node.id]; ok {
+			view = v
+		}
+		mrch.On("GetClusterNodes", mock.Anything, node.ip, rc).Once().Return(clusterView(node.id, view), nil)
+	}
+}
+
+func TestRedisClusterHandlerPodsNotRunning(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := generateRC(2, "")
+	shards := generateClusterShards(2)
+	shards[1].Pods[1].Status = corev1.PodStatus{Phase: corev1.PodPending}
+
+	mk := &mK8SService.Services{}
+	mrcc := &mRFService.RedisClusterClient{}
+	mrch := &mRFService.RedisClusterHeal{}
+	mockClusterEnsure(mrcc, rc)
+	mrcc.On("GetClusterShards", rc).Once().Return(shards, nil)
+	mk.On("UpdateRedisClusterStatus", mock.Anything, namespace, rc).Once().Return(rc, nil)
+
+	handler := rfOperator.NewRedisClusterHandler(mrcc, mrch, mk, record.NewFakeRecorder(10), log.Dummy)
+	err := handler.Handle(context.TODO(), rc)
+
+	assert.NoError(err)
+	assert.Equal(redisfailoverv1.ClusterCreating, rc.Status.Phase)
+	mk.AssertExpectations(t)
+	mrcc.AssertExpectations(t)
+	mrch.AssertExpectations(t)
+}
+
+func TestRedisClusterHandlerBootstrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodes   []testClusterNode
+		views   map[string][]testClusterNode
+		expMeet bool
+	}{
+		{
+			name: "New nodes meet the first one",
+			nodes: []testClusterNode{
+				{id: "a0", ip: "10.0.0.0"},
+				{id: "a1", ip: "10.0.0.1"},
+				{id: "b0", ip: "10.0.1.0"},
+				{id: "b1", ip: "10.0.1.1"},
+			},
+			views: map[string][]testClusterNode{
+				"a0": {{id: "a0", ip: "10.0.0.0"}},
+				"a1": {{id: "a1", ip: "10.0.0.1"}},
+				"b0": {{id: "b0", ip: "10.0.1.0"}},
+				"b1": {{id: "b1", ip: "10.0.1.1"}},
+			},
+			expMeet: true,
+		},
+		{
+			name: "Nodes that met get their replicas and slots",
+			nodes: []testClusterNode{
+				{id: "a0", ip: "10.0.0.0"},
+				{id: "a1", ip: "10.0.0.1"},
+				{id: "b0", ip: "10.0.1.0"},
+				{id: "b1", ip: "10.0.1.1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rc := generateRC(2, "")
+
+			mk := &mK8SService.Services{}
+			mrcc := &mRFService.RedisClusterClient{}
+			mrch := &mRFService.RedisClusterHeal{}
+			mockClusterEnsure(mrcc, rc)
+			mrcc.On("GetClusterShards", rc).Once().Return(generateClusterShards(2), nil)
+			mockClusterViews(mrch, rc, test.nodes, test.views)
+
+			if test.expMeet {
+				mrch.On("ClusterMeet", mock.Anything, "10.0.0.1", "10.0.0.0", rc).Once().Return(nil)
+				mrch.On("ClusterMeet", mock.Anything, "10.0.1.0", "10.0.0.0", rc).Once().Return(nil)
+				mrch.On("ClusterMeet", mock.Anything, "10.0.1.1", "10.0.0.0", rc).Once().Return(nil)
+			} else {
+				mrch.On("ClusterReplicate", mock.Anything, "10.0.0.1", "a0", rc).Once().Return(nil)
+				mrch.On("ClusterReplicate", mock.Anything, "10.0.1.1", "b0", rc).Once().Return(nil)
+				mrch.On("ClusterAddSlots", mock.Anything, "10.0.0.0", slotsRange(0, 8191), rc).Once().Return(nil)
+				mrch.On("ClusterAddSlots", mock.Anything, "10.0.1.0", slotsRange(8192, 16383), rc).Once().Return(nil)
+			}
+			mk.On("UpdateRedisClusterStatus", mock.Anything, namespace, rc).Once().Return(rc, nil)
+
+			handler := rfOperator.NewRedisClusterHandler(mrcc, mrch, mk, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.Handle(context.TODO(), rc)
+
+			assert.NoError(err)
+			assert.Equal(redisfailoverv1.ClusterCreating, rc.Status.Phase)
+			mk.AssertExpectations(t)
+			mrcc.AssertExpectations(t)
+			mrch.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisClusterHandlerForgetFailedNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := generateRC(2, redisfailoverv1.ClusterReady)
+	// The replica of the first shard was restarted without its data and joined with a new id
+	nodes := append(readyClusterNodes(), testClusterNode{id: "old", ip: "10.0.0.9", master: "a0", failed: true})
+
+	mk := &mK8SService.Services{}
+	mrcc := &mRFService.RedisClusterClient{}
+	mrch := &mRFService.RedisClusterHeal{}
+	mockClusterEnsure(mrcc, rc)
+	mrcc.On("GetClusterShards", rc).Once().Return(generateClusterShards(2), nil)
+	mockClusterViews(mrch, rc, nodes, nil)
+	for _, ip := range []string{"10.0.0.0", "10.0.0.1", "10.0.1.0", "10.0.1.1"} {
+		mrch.On("ClusterForget", mock.Anything, ip, "old", rc).Once().Return(nil)
+	}
+	mk.On("UpdateRedisClusterStatus", mock.Anything, namespace, rc).Once().Return(rc, nil)
+
+	recorder := record.NewFakeRecorder(10)
+	handler := rfOperator.NewRedisClusterHandler(mrcc, mrch, mk, recorder, log.Dummy)
+	err := handler.Handle(context.TODO(), rc)
+
+	assert.NoError(err)
+	assert.Equal(redisfailoverv1.ClusterReady, rc.Status.Phase)
+	assert.Equal([]redisfailoverv1.ShardStatus{
+		{Name: "rfc-test-0", Master: "rfc-test-0-0", Slots: 8192, Replicas: 1},
+		{Name: "rfc-test-1", Master: "rfc-test-1-0", Slots: 8192, Replicas: 1},
+	}, rc.Status.Shards)
+	assert.Len(recorder.Events, 1)
+	mk.AssertExpectations(t)
+	mrcc.AssertExpectations(t)
+	mrch.AssertExpectations(t)
+}
+
+func TestRedisClusterHandlerReshard(t *testing.T) {
+	tests := []struct {
+		name         string
+		shards       int32
+		nodes        []testClusterNode
+		expMigrated  int
+		expRemoved   bool
+		expPhase     redisfailoverv1.RedisClusterPhase
+		expStatusLen int
+	}{
+		{
+			name:   "Added shard receives a batch of slots",
+			shards: 3,
+			nodes: append(readyClusterNodes(),
+				testClusterNode{id: "c0", ip: "10.0.2.0"},
+				testClusterNode{id: "c1", ip: "10.0.2.1", master: "c0"},
+			),
+			expMigrated:  256,
+			expPhase:     redisfailoverv1.ClusterResharding,
+			expStatusLen: 3,
+		},
+		{
+			name:   "Drained shard is removed",
+			shards: 2,
+			nodes: append(readyClusterNodes(),
+				testClusterNode{id: "c0", ip: "10.0.2.0"},
+				testClusterNode{id: "c1", ip: "10.0.2.1", master: "c0"},
+			),
+			expRemoved:   true,
+			expPhase:     redisfailoverv1.ClusterResharding,
+			expStatusLen: 3,
+		},
+		{
+			name:         "Balanced cluster is ready",
+			shards:       2,
+			nodes:        readyClusterNodes(),
+			expPhase:     redisfailoverv1.ClusterReady,
+			expStatusLen: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rc := generateRC(test.shards, redisfailoverv1.ClusterReady)
+
+			mk := &mK8SService.Services{}
+			mrcc := &mRFService.RedisClusterClient{}
+			mrch := &mRFService.RedisClusterHeal{}
+			mockClusterEnsure(mrcc, rc)
+			mrcc.On("GetClusterShards", rc).Once().Return(generateClusterShards(len(test.nodes)/2), nil)
+			mockClusterViews(mrch, rc, test.nodes, nil)
+			if test.expMigrated > 0 {
+				mrch.On("ClusterMigrateSlot", mock.Anything, "10.0.0.0", "10.0.2.0", mock.Anything, rc).Times(test.expMigrated).Return(nil)
+			}
+			if test.expRemoved {
+				mrcc.On("DeleteClusterShard", rc, 2).Once().Return(nil)
+			}
+			mk.On("UpdateRedisClusterStatus", mock.Anything, namespace, rc).Maybe().Return(rc, nil)
+
+			handler := rfOperator.NewRedisClusterHandler(mrcc, mrch, mk, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.Handle(context.TODO(), rc)
+
+			assert.NoError(err)
+			assert.Equal(test.expPhase, rc.Status.Phase)
+			assert.Len(rc.Status.Shards, test.expStatusLen)
+			if test.expMigrated > 0 {
+				assert.Equal(int32(8192-test.expMigrated), rc.Status.Shards[0].Slots)
+				assert.Equal(int32(test.expMigrated), rc.Status.Shards[2].Slots)
+			}
+			mk.AssertExpectations(t)
+			mrcc.AssertExpectations(t)
+			mrch.AssertExpectations(t)
+		})
+	}
+}
@@ -6,4 +6,9 @@ type Config struct {
 	MetricsPath              string
 	Concurrency              int
 	SupportedNamespacesRegex string
+	// EnableBackups runs the RedisFailoverBackup controller. The failovers only create scheduled backups and
+	// restore from a backup name when it is set.
+	EnableBackups bool
+	// EnableRedisCluster runs the RedisCluster controller.
+	EnableRedisCluster bool
 }
@@ -0,0 +1,236 @@
+package redisfailover
+
+import (
+	"errors"
+	"fmt"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/metrics"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// healActionKind is a fix of the failover decided on an observation
+type healActionKind int
+
+const (
+	// healPromoteOldest promotes the oldest redis, or the one holding the most data, to master
+	healPromoteOldest healActionKind = iota
+	// healPromoteRestored promotes the redis restoring the data to master
+	healPromoteRestored
+	// healWaitFailover leaves the sentinels the time to choose a master
+	healWaitFailover
+	// healResolveSplitBrain keeps one of the masters and makes the others replicas of it
+	healResolveSplitBrain
+	// healFail stops the reconcile with the error of the action, the operator can't fix the failover
+	healFail
+	// healSetRoleLabel labels the pod of the redis with the role it has
+	healSetRoleLabel
+	// healSetMasterOnAll makes all the redises replicas of the master
+	healSetMasterOnAll
+	// healSentinelMonitor makes the sentinel monitor the master
+	healSentinelMonitor
+	// healResetSentinel resets the sentinel so it forgets the sentinels and replicas gone
+	healResetSentinel
+)
+
+// endsReconcile tells if nothing else is checked after the action, the failover has no single master
+func (k healActionKind) endsReconcile() bool {
+	switch k {
+	case healPromoteOldest, healPromoteRestored, healWaitFailover, healResolveSplitBrain, healFail:
+		return true
+	}
+	return false
+}
+
+// healAction is a fix of the failover and why it is needed
+type healAction struct {
+	kind healActionKind
+	// ip is the redis or the sentinel fixed, when the action is about one of them
+	ip string
+	// master is the master the redises replicate from or the sentinels monitor
+	master string
+	// check is the property of the check metric failing, err why it fails
+	check string
+	err   error
+	// reason and message are the event recorded, none when the reason is empty
+	reason  string
+	message string
+}
+
+type healActions []healAction
+
+// of returns the actions of the given kinds, in their order
+func (a healActions) of(kinds ...healActionKind) healActions {
+	actions := healActions{}
+	for _, action := range a {
+		for _, kind := range kinds {
+			if action.kind == kind {
+				actions = append(actions, action)
+				break
+			}
+		}
+	}
+	return actions
+}
+
+// errOf returns why the check fails for the redis or the sentinel, nil when it passes
+func (a healActions) errOf(check string, ip string) error {
+	for _, action := range a {
+		if action.check == check && action.ip == ip {
+			return action.err
+		}
+	}
+	return nil
+}
+
+// decideHealActions returns the fixes the failover needs as it was observed. It only looks at the
+// observation and the spec, so all the checks of a reconcile agree on the state of the failover.
+func decideHealActions(rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation) healActions {
+	if rf.Bootstrapping() {
+		bootstrap := rf.Spec.BootstrapNode
+		return decideSentinelActions(rf, obs, bootstrap.Host, bootstrap.Port)
+	}
+
+	masters := obs.MastersIPs()
+	switch len(masters) {
+	case 0:
+		return decideNoMasterActions(rf, obs)
+	case 1:
+	default:
+		if rf.Spec.Redis.SplitBrainResolution != redisfailoverv1.SplitBrainResolutionAutomatic {
+			return healActions{{
+				kind:    healFail,
+				err:     errors.New("more than one master, fix manually"),
+				reason:  rfservice.EventReasonMultipleMasters,
+				message: fmt.Sprintf("%d redis nodes are working as master, fix manually", len(masters)),
+			}}
+		}
+		// The replicas and sentinels are checked against the remaining master on the next reconcile
+		return healActions{{
+			kind:    healResolveSplitBrain,
+			reason:  rfservice.EventReasonMultipleMasters,
+			message: fmt.Sprintf("%d redis nodes are working as master, resolving the split-brain", len(masters)),
+		}}
+	}
+
+	master := masters[0]
+	actions := healActions{}
+	for _, r := range obs.Redises {
+		if !r.HasRoleLabel(r.IP() == master) {
+			actions = append(actions, healAction{kind: healSetRoleLabel, ip: r.IP(), master: master})
+		}
+	}
+	if err := checkAllSlavesFromMaster(rf, obs, master); err != nil {
+		actions = append(actions, healAction{kind: healSetMasterOnAll, master: master, check: metrics.SLAVE_WRONG_MASTER, err: err})
+	}
+	return append(actions, decideSentinelActions(rf, obs, master, getRedisPort(rf.Spec.Redis.Port))...)
+}
+
+// decideNoMasterActions chooses between waiting for the sentinels to failover, and promoting a redis when
+// they can't: on the first boot of the redises, or without quorum.
+func decideNoMasterActions(rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation) healActions {
+	// Until the restored pod is the master, it is the only one holding the data
+	if rf.Restoring() {
+		return healActions{{kind: healPromoteRestored}}
+	}
+	// When number of redis replicas is 1, the redis is configured for standalone master mode
+	if rf.Spec.Redis.Replicas == 1 {
+		return healActions{{kind: healPromoteOldest, message: "Resource spec with standalone master - operator will set the master"}}
+	}
+	if unhealthy, err := obs.SentinelQuorum(rf); err != nil {
+		return healActions{{
+			kind:    healPromoteOldest,
+			err:     err,
+			reason:  rfservice.EventReasonNoQuorum,
+			message: fmt.Sprintf("No master and sentinels have no quorum (%d unhealthy), promoting the oldest pod", unhealthy),
+		}}
+	}
+	localhost, err := obs.MastersOnLocalhost()
+	if err != nil {
+		return healActions{{kind: healFail, err: err, message: "CheckIfMasterLocalhost failed retry later"}}
+	}
+	if localhost {
+		return healActions{{
+			kind:    healPromoteOldest,
+			reason:  rfservice.EventReasonMastersOnLocalhost,
+			message: "No master and all redis replicate from localhost, promoting the oldest pod",
+		}}
+	}
+	return healActions{{
+		kind:    healWaitFailover,
+		err:     errors.New("no master not fixed, wait until failover or fix manually"),
+		reason:  rfservice.EventReasonNoMaster,
+		message: "No master found, waiting for sentinels to failover",
+	}}
+}
+
+// checkAllSlavesFromMaster fails when a redis replicates from another master, or did not answer
+func checkAllSlavesFromMaster(rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation, master string) error {
+	masterAddress := obs.RedisAddress(rf, master)
+	for _, r := range obs.Redises {
+		if r.Err != nil {
+			return r.Err
+		}
+		if slaveOf := r.Replication.MasterHost; slaveOf != "" && !redis.SameAddress(slaveOf, masterAddress) {
+			return fmt.Errorf("slave %s don't have the master %s, has %s", r.IP(), master, slaveOf)
+		}
+	}
+	return nil
+}
+
+// decideSentinelActions fixes the sentinels not monitoring the master given, then resets the ones knowing
+// other sentinels or replicas than the expected ones
+func decideSentinelActions(rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation, master string, port string) healActions {
+	actions := healActions{}
+	monitorAddress := obs.RedisAddress(rf, master)
+	for _, s := range obs.Sentinels {
+		err := s.MonitorErr
+		if err == nil && (!redis.SameAddress(s.MonitorAddress, monitorAddress) || s.MonitorPort != port) {
+			err = fmt.Errorf("sentinel monitoring %s:%s instead %s:%s", s.MonitorAddress, s.MonitorPort, monitorAddress, port)
+		}
+		if err != nil {
+			actions = append(actions, healAction{kind: healSentinelMonitor, ip: s.IP, master: master, check: metrics.SENTINEL_WRONG_MASTER, err: err})
+		}
+	}
+
+	for _, s := range obs.Sentinels {
+		err := s.SentinelsErr
+		if err == nil && s.Sentinels != rf.SentinelPods() {
+			err = errors.New("sentinels in memory mismatch")
+		}
+		if err != nil {
+			actions = append(actions, healAction{
+				kind:    healResetSentinel,
+				ip:      s.IP,
+				check:   metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH,
+				err:     err,
+				reason:  rfservice.EventReasonSentinelReset,
+				message: fmt.Sprintf("Reset sentinel %s because it knew an unexpected number of sentinels", s.IP),
+			})
+		}
+	}
+
+	slaves := rf.RedisPods() - 1
+	if rf.Bootstrapping() {
+		// All the redises replicate from the bootstrap node
+		slaves = rf.RedisPods()
+	}
+	for _, s := range obs.Sentinels {
+		err := s.SlavesErr
+		if err == nil && s.Slaves != slaves {
+			err = errors.New("redis slaves in sentinel memory mismatch")
+		}
+		if err != nil {
+			actions = append(actions, healAction{
+				kind:    healResetSentinel,
+				ip:      s.IP,
+				check:   metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH,
+				err:     err,
+				reason:  rfservice.EventReasonSentinelReset,
+				message: fmt.Sprintf("Reset sentinel %s because it knew an unexpected number of slaves", s.IP),
+			})
+		}
+	}
+	return actions
+}
@@ -0,0 +1,253 @@
+package redisfailover
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/metrics"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// decisionRF is a failover of 3 redises and 3 sentinels
+func decisionRF() *redisfailoverv1.RedisFailover {
+	return &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Redis:    redisfailoverv1.RedisSettings{Replicas: 3, Port: 6379},
+			Sentinel: redisfailoverv1.SentinelSettings{Replicas: 3},
+		},
+	}
+}
+
+// decisionObservation is a healthy failover with the master on the first pod, every replica in sync and
+// every sentinel monitoring the master
+func decisionObservation() *rfservice.ClusterObservation {
+	obs := &rfservice.ClusterObservation{}
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		role, replication := "slave", redis.ReplicationInfo{Role: "slave", MasterHost: "10.0.0.1", MasterLinkUp: true}
+		if i == 0 {
+			role, replication = "master", redis.ReplicationInfo{Role: "master"}
+		}
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rfr-test-%d", i), Labels: map[string]string{"redisfailovers-role": role}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+		obs.RedisPods = append(obs.RedisPods, pod)
+		obs.Redises = append(obs.Redises, rfservice.RedisObservation{Pod: pod, Replication: replication})
+	}
+	for _, ip := range []string{"10.0.1.1", "10.0.1.2", "10.0.1.3"} {
+		obs.Sentinels = append(obs.Sentinels, rfservice.SentinelObservation{IP: ip, MonitorAddress: "10.0.0.1", MonitorPort: "6379", Sentinels: 3, Slaves: 2})
+	}
+	return obs
+}
+
+// noMaster makes every redis of the observation a replica of the master given
+func noMaster(obs *rfservice.ClusterObservation, masterHost string) {
+	for i := range obs.Redises {
+		obs.Redises[i].Replication = redis.ReplicationInfo{Role: "slave", MasterHost: masterHost}
+	}
+}
+
+func TestDecideHealActions(t *testing.T) {
+	type expAction struct {
+		kind   healActionKind
+		ip     string
+		check  string
+		reason string
+	}
+	tests := []struct {
+		name       string
+		rf         func(rf *redisfailoverv1.RedisFailover)
+		obs        func(obs *rfservice.ClusterObservation)
+		expActions []expAction
+	}{
+		{
+			name:       "Healthy failover needs nothing",
+			expActions: []expAction{},
+		},
+		{
+			name: "No master waits for the sentinels",
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "10.0.0.9")
+			},
+			expActions: []expAction{{kind: healWaitFailover, reason: rfservice.EventReasonNoMaster}},
+		},
+		{
+			name: "No master without quorum promotes the oldest pod",
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "10.0.0.9")
+				obs.Sentinels[0].QuorumErr = errors.New("NOQUORUM")
+				obs.Sentinels[1].QuorumErr = errors.New("NOQUORUM")
+			},
+			expActions: []expAction{{kind: healPromoteOldest, reason: rfservice.EventReasonNoQuorum}},
+		},
+		{
+			name: "No master with too few sentinels promotes the oldest pod",
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "10.0.0.9")
+				obs.Sentinels = obs.Sentinels[:1]
+			},
+			expActions: []expAction{{kind: healPromoteOldest, reason: rfservice.EventReasonNoQuorum}},
+		},
+		{
+			name: "No master with all redises on localhost promotes the oldest pod",
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "127.0.0.1")
+			},
+			expActions: []expAction{{kind: healPromoteOldest, reason: rfservice.EventReasonMastersOnLocalhost}},
+		},
+		{
+			name: "No master with a redis not answering fails",
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "127.0.0.1")
+				obs.Redises[2] = rfservice.RedisObservation{Pod: obs.Redises[2].Pod, Err: errors.New("i/o timeout")}
+			},
+			expActions: []expAction{{kind: healFail}},
+		},
+		{
+			name: "Standalone redis is promoted",
+			rf: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.Redis.Replicas = 1
+			},
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "10.0.0.9")
+			},
+			expActions: []expAction{{kind: healPromoteOldest}},
+		},
+		{
+			name: "Restoring failover promotes the restored pod",
+			rf: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Status.Restore = &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreRestoring}
+			},
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "127.0.0.1")
+			},
+			expActions: []expAction{{kind: healPromoteRestored}},
+		},
+		{
+			name: "Multiple masters are fixed manually",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Redises[1].Replication = redis.ReplicationInfo{Role: "master"}
+			},
+			expActions: []expAction{{kind: healFail, reason: rfservice.EventReasonMultipleMasters}},
+		},
+		{
+			name: "Multiple masters are resolved automatically",
+			rf: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.Redis.SplitBrainResolution = redisfailoverv1.SplitBrainResolutionAutomatic
+			},
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Redises[1].Replication = redis.ReplicationInfo{Role: "master"}
+			},
+			expActions: []expAction{{kind: healResolveSplitBrain, reason: rfservice.EventReasonMultipleMasters}},
+		},
+		{
+			name: "Replica of another master is moved to the master",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Redises[2].Replication.MasterHost = "10.0.0.9"
+			},
+			expActions: []expAction{{kind: healSetMasterOnAll, check: metrics.SLAVE_WRONG_MASTER}},
+		},
+		{
+			name: "Replica not answering is moved to the master",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Redises[2] = rfservice.RedisObservation{Pod: obs.Redises[2].Pod, Err: errors.New("i/o timeout")}
+			},
+			expActions: []expAction{{kind: healSetMasterOnAll, check: metrics.SLAVE_WRONG_MASTER}},
+		},
+		{
+			name: "Pods labeled with the wrong role are relabeled",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Redises[0].Pod.Labels = nil
+				obs.Redises[1].Pod.Labels = map[string]string{"redisfailovers-role": "master"}
+			},
+			expActions: []expAction{
+				{kind: healSetRoleLabel, ip: "10.0.0.1"},
+				{kind: healSetRoleLabel, ip: "10.0.0.2"},
+			},
+		},
+		{
+			name: "Sentinel monitoring another master monitors the master",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Sentinels[1].MonitorAddress = "10.0.0.9"
+				obs.Sentinels[2].MonitorPort = "6380"
+			},
+			expActions: []expAction{
+				{kind: healSentinelMonitor, ip: "10.0.1.2", check: metrics.SENTINEL_WRONG_MASTER},
+				{kind: healSentinelMonitor, ip: "10.0.1.3", check: metrics.SENTINEL_WRONG_MASTER},
+			},
+		},
+		{
+			name: "Sentinels knowing other sentinels or replicas are reset",
+			obs: func(obs *rfservice.ClusterObservation) {
+				obs.Sentinels[0].Slaves = 4
+				obs.Sentinels[2].Sentinels = 5
+			},
+			expActions: []expAction{
+				{kind: healResetSentinel, ip: "10.0.1.3", check: metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, reason: rfservice.EventReasonSentinelReset},
+				{kind: healResetSentinel, ip: "10.0.1.1", check: metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, reason: rfservice.EventReasonSentinelReset},
+			},
+		},
+		{
+			name: "Sentinel not answering is fixed",
+			obs: func(obs *rfservice.ClusterObservation) {
+				err := errors.New("i/o timeout")
+				obs.Sentinels[0] = rfservice.SentinelObservation{IP: "10.0.1.1", MonitorErr: err, SentinelsErr: err, SlavesErr: err, QuorumErr: err}
+			},
+			expActions: []expAction{
+				{kind: healSentinelMonitor, ip: "10.0.1.1", check: metrics.SENTINEL_WRONG_MASTER},
+				{kind: healResetSentinel, ip: "10.0.1.1", check: metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, reason: rfservice.EventReasonSentinelReset},
+				{kind: healResetSentinel, ip: "10.0.1.1", check: metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, reason: rfservice.EventReasonSentinelReset},
+			},
+		},
+		{
+			name: "Bootstrapping sentinels monitor the bootstrap node with all the redises as replicas",
+			rf: func(rf *redisfailoverv1.RedisFailover) {
+				rf.Spec.BootstrapNode = &redisfailoverv1.BootstrapSettings{Host: "10.0.2.1", Port: "6380"}
+			},
+			obs: func(obs *rfservice.ClusterObservation) {
+				noMaster(obs, "10.0.2.1")
+				for i := range obs.Sentinels {
+					obs.Sentinels[i].MonitorAddress, obs.Sentinels[i].MonitorPort, obs.Sentinels[i].Slaves = "10.0.2.1", "6380", 3
+				}
+				obs.Sentinels[0].MonitorPort = "6379"
+			},
+			expActions: []expAction{
+				{kind: healSentinelMonitor, ip: "10.0.1.1", check: metrics.SENTINEL_WRONG_MASTER},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := decisionRF()
+			if test.rf != nil {
+				test.rf(rf)
+			}
+			obs := decisionObservation()
+			if test.obs != nil {
+				test.obs(obs)
+			}
+
+			actions := decideHealActions(rf, obs)
+
+			got := []expAction{}
+			for _, action := range actions {
+				got = append(got, expAction{kind: action.kind, ip: action.ip, check: action.check, reason: action.reason})
+				if action.check != "" {
+					assert.Error(action.err)
+				}
+			}
+			assert.Equal(test.expActions, got)
+		})
+	}
+}
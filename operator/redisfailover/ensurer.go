@@ -9,7 +9,8 @@ import (
 
 // Ensure is called to ensure all of the resources associated with a RedisFailover are created
 func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
-	if rf.Spec.Redis.Exporter.Enabled {
+	// The headless service gives the DNS names of the redis pods in hostname mode
+	if rf.Spec.Redis.Exporter.Enabled || rf.HostnameMode() {
 		if err := w.rfService.EnsureRedisService(rf, labels, or); err != nil {
 			return err
 		}
@@ -43,6 +44,14 @@ func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels
 	if err := w.rfService.EnsureRedisReadinessConfigMap(rf, labels, or); err != nil {
 		return err
 	}
+	if err := w.rfService.EnsureRedisUsersSecret(rf, labels, or); err != nil {
+		return err
+	}
+	if rf.BackupsEnabled() {
+		if err := w.rfService.EnsureBackupAgentSecret(rf, labels, or); err != nil {
+			return err
+		}
+	}
 	if err := w.rfService.EnsureRedisConfigMap(rf, labels, or); err != nil {
 		return err
 	}
@@ -51,7 +60,19 @@ func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels
 	}
 
 	if sentinelsAllowed {
-		if err := w.rfService.EnsureSentinelDeployment(rf, labels, or); err != nil {
+		if rf.SentinelStatefulSet() {
+			if err := w.rfService.EnsureSentinelHeadlessService(rf, labels, or); err != nil {
+				return err
+			}
+			if err := w.rfService.EnsureSentinelStatefulSet(rf, labels, or); err != nil {
+				return err
+			}
+		} else {
+			if err := w.rfService.EnsureSentinelDeployment(rf, labels, or); err != nil {
+				return err
+			}
+		}
+		if err := w.rfService.EnsureSentinelMigration(rf); err != nil {
 			return err
 		}
 	}
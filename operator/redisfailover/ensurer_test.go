@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/log"
@@ -24,6 +25,7 @@ func generateConfig() rfOperator.Config {
 	return rfOperator.Config{
 		ListenAddress: "1234",
 		MetricsPath:   "/awesome",
+		EnableBackups: true,
 	}
 }
 
@@ -65,6 +67,9 @@ func TestEnsure(t *testing.T) {
 		exporter                    bool
 		bootstrapping               bool
 		bootstrappingAllowSentinels bool
+		sentinelStatefulSet         bool
+		hostnameMode                bool
+		backups                     bool
 	}{
 		{
 			name:                        "Call everything, use exporter",
@@ -90,6 +95,18 @@ func TestEnsure(t *testing.T) {
 			bootstrapping:               true,
 			bootstrappingAllowSentinels: true,
 		},
+		{
+			name:                "Call everything, run the sentinels as a statefulset",
+			sentinelStatefulSet: true,
+		},
+		{
+			name:         "Call everything, keep the redis service for the hostnames",
+			hostnameMode: true,
+		},
+		{
+			name:    "Call everything, generate the token of the backup agents",
+			backups: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -100,13 +117,24 @@ func TestEnsure(t *testing.T) {
 			if test.bootstrapping {
 				rf.Spec.BootstrapNode.AllowSentinels = test.bootstrappingAllowSentinels
 			}
+			if test.sentinelStatefulSet {
+				rf.Spec.Sentinel.WorkloadType = redisfailoverv1.SentinelWorkloadStatefulSet
+			}
+			if test.hostnameMode {
+				rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+			}
+			if test.backups {
+				rf.Spec.Backup = &redisfailoverv1.BackupSettings{
+					Storage: redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+				}
+			}
 
 			config := generateConfig()
 			mk := &mK8SService.Services{}
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 			mrfs := &mRFService.RedisFailoverClient{}
-			if test.exporter {
+			if test.exporter || test.hostnameMode {
 				mrfs.On("EnsureRedisService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			} else {
 				mrfs.On("EnsureNotPresentRedisService", rf).Once().Return(nil)
@@ -115,18 +143,28 @@ func TestEnsure(t *testing.T) {
 			if !test.bootstrapping || test.bootstrappingAllowSentinels {
 				mrfs.On("EnsureSentinelService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 				mrfs.On("EnsureSentinelConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
-				mrfs.On("EnsureSentinelDeployment", rf, mock.Anything, mock.Anything).Once().Return(nil)
+				if test.sentinelStatefulSet {
+					mrfs.On("EnsureSentinelHeadlessService", rf, mock.Anything, mock.Anything).Once().Return(nil)
+					mrfs.On("EnsureSentinelStatefulSet", rf, mock.Anything, mock.Anything).Once().Return(nil)
+				} else {
+					mrfs.On("EnsureSentinelDeployment", rf, mock.Anything, mock.Anything).Once().Return(nil)
+				}
+				mrfs.On("EnsureSentinelMigration", rf).Once().Return(nil)
 			}
 
 			mrfs.On("EnsureRedisMasterService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisSlaveService", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisUsersSecret", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			if test.backups {
+				mrfs.On("EnsureBackupAgentSecret", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			}
 			mrfs.On("EnsureRedisConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisShutdownConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisReadinessConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			mrfs.On("EnsureRedisStatefulset", rf, mock.Anything, mock.Anything).Once().Return(nil)
 
 			// Create the Kops client and call the valid logic.
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
 			err := handler.Ensure(rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
 
 			assert.NoError(err)
@@ -0,0 +1,61 @@
+package redisfailover
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// podWatchRetryInterval is the wait before watching the pods again once the watch ended
+const podWatchRetryInterval = 5 * time.Second
+
+// EvictRedisConnections closes the connections the redis client keeps to the pods of the operator as soon as
+// they go away, so their IP given to another pod is never called with them. It runs until the context is done.
+func EvictRedisConnections(ctx context.Context, cli k8s.Services, redisClient redis.Client, logger log.Logger) {
+	logger = logger.WithField("operator", "redisfailover")
+	selector := labels.SelectorFromSet(defaultLabels).String()
+	for {
+		watcher, err := cli.WatchPods(ctx, "", metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			logger.Warningf("Unable to watch the pods, the redis connections are only closed once idle: %v", err)
+		} else {
+			evictOnPodEvents(ctx, watcher, redisClient)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(podWatchRetryInterval):
+		}
+	}
+}
+
+// evictOnPodEvents evicts the connections to the pods deleted or being deleted, until the watch ends.
+func evictOnPodEvents(ctx context.Context, watcher watch.Interface, redisClient redis.Client) {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok || pod.Status.PodIP == "" {
+				continue
+			}
+			if event.Type == watch.Deleted || pod.DeletionTimestamp != nil {
+				redisClient.Evict(pod.Status.PodIP)
+			}
+		}
+	}
+}
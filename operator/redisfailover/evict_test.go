@@ -0,0 +1,67 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/freshworks/redis-operator/log"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/freshworks/redis-operator/mocks/service/redis"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestEvictRedisConnections(t *testing.T) {
+	assert := assert.New(t)
+
+	podWatcher := watch.NewFake()
+
+	mk := &mK8SService.Services{}
+	mk.On("WatchPods", mock.Anything, "", metav1.ListOptions{LabelSelector: "app.kubernetes.io/managed-by=redis-operator"}).Once().Return(podWatcher, nil)
+	evicted := make(chan string, 2)
+	mr := &mRedisService.Client{}
+	mr.On("Evict", mock.Anything).Run(func(args mock.Arguments) { evicted <- args.String(0) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		rfOperator.EvictRedisConnections(ctx, mk, mr, log.Dummy)
+		close(done)
+	}()
+
+	pod := func(ip string) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{PodIP: ip}}
+	}
+	terminating := pod("10.0.0.2")
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	// Only the pods going away have their connections closed
+	podWatcher.Add(pod("10.0.0.1"))
+	podWatcher.Modify(pod("10.0.0.1"))
+	podWatcher.Modify(terminating)
+	podWatcher.Delete(pod("10.0.0.3"))
+
+	for _, expected := range []string{"10.0.0.2", "10.0.0.3"} {
+		select {
+		case ip := <-evicted:
+			assert.Equal(expected, ip)
+		case <-time.After(time.Second):
+			t.Fatalf("connections to %s not evicted", expected)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("eviction not stopped with its context")
+	}
+	mr.AssertNumberOfCalls(t, "Evict", 2)
+	mk.AssertExpectations(t)
+}
@@ -8,56 +8,183 @@ import (
 	"github.com/spotahome/kooper/v2/controller"
 	"github.com/spotahome/kooper/v2/controller/leaderelection"
 	kooperlog "github.com/spotahome/kooper/v2/log"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfscheme "github.com/freshworks/redis-operator/client/k8s/clientset/versioned/scheme"
 	"github.com/freshworks/redis-operator/log"
 	"github.com/freshworks/redis-operator/metrics"
 	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/backup"
 	"github.com/freshworks/redis-operator/service/k8s"
 	"github.com/freshworks/redis-operator/service/redis"
 )
 
 const (
-	resync       = 30 * time.Second
-	operatorName = "redis-operator"
-	lockKey      = "redis-failover-lease"
+	resync         = 30 * time.Second
+	operatorName   = "redis-operator"
+	lockKey        = "redis-failover-lease"
+	backupLockKey  = "redis-failover-backup-lease"
+	clusterLockKey = "redis-cluster-lease"
 )
 
 // New will create an operator that is responsible of managing all the required stuff
 // to create redis failovers.
 func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+	// Create the recorder publishing the actions taken on the redis failovers as kubernetes events.
+	eventRecorder := NewEventRecorder(k8sClient, logger)
+
 	// Create internal services.
 	rfService := rfservice.NewRedisFailoverKubeClient(k8sService, logger, kooperMetricsRecorder)
 	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
-	rfHealer := rfservice.NewRedisFailoverHealer(k8sService, redisClient, logger)
+	rfHealer := rfservice.NewRedisFailoverHealer(k8sService, redisClient, eventRecorder, logger)
 
 	// Create the handlers.
-	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, k8sService, kooperMetricsRecorder, logger)
+	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, k8sService, kooperMetricsRecorder, eventRecorder, logger)
 	rfRetriever := NewRedisFailoverRetriever(cfg, k8sService)
 
-	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailover")}
+	operatorLogger := logger.WithField("operator", "redisfailover")
+	kooperLogger := kooperlogger{Logger: operatorLogger}
 	// Leader election service.
 	leSVC, err := leaderelection.NewDefault(lockKey, lockNamespace, k8sClient, kooperLogger)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create our controller.
-	return controller.New(&controller.Config{
+	// Create our controller, the leader election is run around it and the reconcile queue.
+	ctrl, err := controller.New(&controller.Config{
 		Handler:           rfHandler,
 		Retriever:         rfRetriever,
-		LeaderElector:     leSVC,
 		MetricsRecorder:   kooperMetricsRecorder,
 		Logger:            kooperLogger,
 		Name:              "redisfailover",
 		ResyncInterval:    resync,
 		ConcurrentWorkers: cfg.Concurrency,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	isNamespaceSupported := func(rf redisfailoverv1.RedisFailover) bool {
+		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(rf.Namespace))
+		return match
+	}
+	return &redisFailoverController{
+		controller: ctrl,
+		leader:     leSVC,
+		handler:    rfHandler,
+		sentinels:  NewSentinelEventSubscriber(k8sService, redisClient, rfHandler.ReconcileQueue(), isNamespaceSupported, operatorLogger),
+		secrets:    NewSecretInformer(k8sService, rfHandler.ReconcileQueue(), isNamespaceSupported, operatorLogger),
+		workers:    cfg.Concurrency,
+		logger:     operatorLogger,
+	}, nil
+}
+
+// redisFailoverController runs the controller of the redis failovers along with the reconciles the
+// handler queues itself, and the subscriptions to the sentinels and the informer of the secrets queueing
+// them, only while leading.
+type redisFailoverController struct {
+	controller controller.Controller
+	leader     leaderelection.Runner
+	handler    *RedisFailoverHandler
+	sentinels  *SentinelEventSubscriber
+	secrets    *SecretInformer
+	workers    int
+	logger     log.Logger
+}
+
+// Run satisfies controller.Controller.
+func (c *redisFailoverController) Run(ctx context.Context) error {
+	return c.leader.Run(func() error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go c.handler.ReconcileQueue().Run(ctx, c.workers, c.handler.Reconcile, c.logger)
+		go c.sentinels.Run(ctx)
+		go c.secrets.Run(ctx)
+		return c.controller.Run(ctx)
+	})
+}
+
+// NewBackup will create an operator that is responsible of taking the backups requested through
+// redis failover backups.
+func NewBackup(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+	eventRecorder := NewEventRecorder(k8sClient, logger)
+
+	// Create internal services.
+	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
+	rfHealer := rfservice.NewRedisFailoverHealer(k8sService, redisClient, eventRecorder, logger)
+
+	// Create the handlers.
+	rfbHandler := NewRedisFailoverBackupHandler(rfChecker, rfHealer, backup.NewAgentClient(), k8sService, logger)
+	rfbRetriever := NewRedisFailoverBackupRetriever(cfg, k8sService)
+
+	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailoverbackup")}
+	// Leader election service.
+	leSVC, err := leaderelection.NewDefault(backupLockKey, lockNamespace, k8sClient, kooperLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create our controller.
+	return controller.New(&controller.Config{
+		Handler:           rfbHandler,
+		Retriever:         rfbRetriever,
+		LeaderElector:     leSVC,
+		MetricsRecorder:   kooperMetricsRecorder,
+		Logger:            kooperLogger,
+		Name:              "redisfailoverbackup",
+		ResyncInterval:    resync,
+		ConcurrentWorkers: cfg.Concurrency,
+	})
+}
+
+// NewCluster will create an operator that is responsible of managing the shards of the redis clusters.
+func NewCluster(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+	eventRecorder := NewEventRecorder(k8sClient, logger)
+
+	// Create internal services.
+	rcClient := rfservice.NewRedisClusterKubeClient(k8sService, logger, kooperMetricsRecorder)
+	rcHealer := rfservice.NewRedisClusterHealer(k8sService, redisClient, logger)
+
+	// Create the handlers.
+	rcHandler := NewRedisClusterHandler(rcClient, rcHealer, k8sService, eventRecorder, logger)
+	rcRetriever := NewRedisClusterRetriever(cfg, k8sService)
+
+	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "rediscluster")}
+	// Leader election service.
+	leSVC, err := leaderelection.NewDefault(clusterLockKey, lockNamespace, k8sClient, kooperLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create our controller.
+	return controller.New(&controller.Config{
+		Handler:           rcHandler,
+		Retriever:         rcRetriever,
+		LeaderElector:     leSVC,
+		MetricsRecorder:   kooperMetricsRecorder,
+		Logger:            kooperLogger,
+		Name:              "rediscluster",
+		ResyncInterval:    resync,
+		ConcurrentWorkers: cfg.Concurrency,
+	})
+}
+
+// NewEventRecorder returns a recorder that publishes events on the redis failovers
+// through the kubernetes API.
+func NewEventRecorder(k8sClient kubernetes.Interface, logger log.Logger) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(logger.Debugf)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sClient.CoreV1().Events("")})
+	return broadcaster.NewRecorder(rfscheme.Scheme, corev1.EventSource{Component: operatorName})
 }
 
 func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retriever {
@@ -86,6 +213,9 @@ func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retrieve
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 			watcher, err := cli.WatchRedisFailovers(context.Background(), "", options)
+			if err != nil {
+				return nil, err
+			}
 			watcher = watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
 				rf, ok := event.Object.(*redisfailoverv1.RedisFailover)
 				if !ok {
@@ -93,7 +223,85 @@ func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retrieve
 				}
 				return event, isNamespaceSupported(*rf)
 			})
-			return watcher, err
+			return watcher, nil
+		},
+	})
+}
+
+func NewRedisFailoverBackupRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+	isNamespaceSupported := func(namespace string) bool {
+		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(namespace))
+		return match
+	}
+
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			rfbList, err := cli.ListRedisFailoverBackups(context.Background(), "", options)
+			if err != nil {
+				return rfbList, err
+			}
+
+			targetRFBList := make([]redisfailoverv1.RedisFailoverBackup, 0)
+			for _, rfb := range rfbList.Items {
+				if isNamespaceSupported(rfb.Namespace) {
+					targetRFBList = append(targetRFBList, rfb)
+				}
+			}
+			rfbList.Items = targetRFBList
+
+			return rfbList, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watcher, err := cli.WatchRedisFailoverBackups(context.Background(), "", options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+				rfb, ok := event.Object.(*redisfailoverv1.RedisFailoverBackup)
+				if !ok {
+					return event, false
+				}
+				return event, isNamespaceSupported(rfb.Namespace)
+			}), nil
+		},
+	})
+}
+
+func NewRedisClusterRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+	isNamespaceSupported := func(namespace string) bool {
+		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(namespace))
+		return match
+	}
+
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			rcList, err := cli.ListRedisClusters(context.Background(), "", options)
+			if err != nil {
+				return rcList, err
+			}
+
+			targetRCList := make([]redisfailoverv1.RedisCluster, 0)
+			for _, rc := range rcList.Items {
+				if isNamespaceSupported(rc.Namespace) {
+					targetRCList = append(targetRCList, rc)
+				}
+			}
+			rcList.Items = targetRCList
+
+			return rcList, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watcher, err := cli.WatchRedisClusters(context.Background(), "", options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+				rc, ok := event.Object.(*redisfailoverv1.RedisCluster)
+				if !ok {
+					return event, false
+				}
+				return event, isNamespaceSupported(rc.Namespace)
+			}), nil
 		},
 	})
 }
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"regexp"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/log"
@@ -31,34 +34,61 @@ var (
 // resources that a RF needs.
 type RedisFailoverHandler struct {
 	config     Config
-	k8sservice k8s.Service
+	k8sservice k8s.Services
 	rfService  rfservice.RedisFailoverClient
 	rfChecker  rfservice.RedisFailoverCheck
 	rfHealer   rfservice.RedisFailoverHeal
 	mClient    metrics.Recorder
+	recorder   record.EventRecorder
+	queue      *ReconcileQueue
+	locks      keyLocks
 	logger     log.Logger
 }
 
 // NewRedisFailoverHandler returns a new RF handler
-func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Service, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
+func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Services, mClient metrics.Recorder, recorder record.EventRecorder, logger log.Logger) *RedisFailoverHandler {
 	return &RedisFailoverHandler{
 		config:     config,
 		rfService:  rfService,
 		rfChecker:  rfChecker,
 		rfHealer:   rfHealer,
 		mClient:    mClient,
+		recorder:   recorder,
 		k8sservice: k8sservice,
+		queue:      NewReconcileQueue(),
 		logger:     logger,
 	}
 }
 
+// ReconcileQueue returns the queue the handler asks for the next reconcile of a redis failover through,
+// when it goes on with an operation it does not wait for.
+func (r *RedisFailoverHandler) ReconcileQueue() *ReconcileQueue {
+	return r.queue
+}
+
+// Reconcile handles the redis failover of the key as it currently is, the ones gone meanwhile are ignored.
+func (r *RedisFailoverHandler) Reconcile(ctx context.Context, key types.NamespacedName) error {
+	rf, err := r.k8sservice.GetRedisFailover(ctx, key.Namespace, key.Name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.Handle(ctx, rf)
+}
+
 // Handle will ensure the redis failover is in the expected state.
-func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) error {
+func (r *RedisFailoverHandler) Handle(ctx context.Context, obj runtime.Object) error {
 	rf, ok := obj.(*redisfailoverv1.RedisFailover)
 	if !ok {
 		return fmt.Errorf("can't handle the received object: not a redisfailover")
 	}
 
+	// The failovers queued are reconciled next to the controller, never twice at the same time.
+	unlock := r.locks.lock(rf.Namespace, rf.Name)
+	defer unlock()
+
 	if rf.Annotations != nil {
 		skipReconcile, ok := rf.Annotations["redis-failover.freshworks.com/skip-reconcile"]
 		if ok && skipReconcile == "true" {
@@ -67,8 +97,24 @@ func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) err
 		}
 	}
 
+	// Keep the status as received so we only write it back when something changed.
+	oldStatus := rf.Status.DeepCopy()
+
 	if err := rf.Validate(); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
+		return err
+	}
+
+	if err := r.CheckRestore(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
+		return err
+	}
+
+	if err := r.CheckScaleDown(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
 		return err
 	}
 
@@ -81,15 +127,24 @@ func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) err
 
 	if err := r.Ensure(rf, labels, oRefs, r.mClient); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
+		return err
+	}
+
+	if err := r.CheckAndHeal(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
 		return err
 	}
 
-	if err := r.CheckAndHeal(rf); err != nil {
+	if err := r.CheckScheduledBackup(ctx, rf); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		r.UpdateStatus(ctx, rf, oldStatus, err)
 		return err
 	}
 
 	r.mClient.SetClusterOK(rf.Namespace, rf.Name)
+	r.UpdateStatus(ctx, rf, oldStatus, nil)
 	return nil
 }
 
@@ -0,0 +1,108 @@
+package redisfailover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/freshworks/redis-operator/log"
+)
+
+// reconcileMaxRetries is the number of times a reconcile asked for is retried once it failed. The
+// failovers still failing are left to the next resync.
+const reconcileMaxRetries = 5
+
+// ReconcileQueue carries the redis failovers to reconcile before the next resync: the ones going on with
+// an operation the reconcile does not wait for, the ones their sentinels published a failover of and the
+// ones their status could not be written for. A failover asked for several times before it is reconciled
+// is only reconciled once.
+type ReconcileQueue struct {
+	queue workqueue.TypedRateLimitingInterface[types.NamespacedName]
+}
+
+// NewReconcileQueue returns an empty queue, worked by Run.
+func NewReconcileQueue() *ReconcileQueue {
+	return &ReconcileQueue{
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[types.NamespacedName](),
+			workqueue.TypedRateLimitingQueueConfig[types.NamespacedName]{Name: "redisfailover-reconcile"},
+		),
+	}
+}
+
+// Add asks for a reconcile of the redis failover.
+func (q *ReconcileQueue) Add(namespace, name string) {
+	q.queue.Add(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// AddAfter asks for a reconcile of the redis failover once the delay passed.
+func (q *ReconcileQueue) AddAfter(namespace, name string, delay time.Duration) {
+	q.queue.AddAfter(types.NamespacedName{Namespace: namespace, Name: name}, delay)
+}
+
+// AddRateLimited asks for a reconcile of the redis failover after a delay growing with the reconciles of
+// it that failed in a row.
+func (q *ReconcileQueue) AddRateLimited(namespace, name string) {
+	q.queue.AddRateLimited(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// Run reconciles the redis failovers taken from the queue with the given number of workers, until the
+// context is done. A failed reconcile is retried with a growing delay. The queue can't be run again.
+func (q *ReconcileQueue) Run(ctx context.Context, workers int, reconcile func(ctx context.Context, key types.NamespacedName) error, logger log.Logger) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q.process(ctx, reconcile, logger) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	q.queue.ShutDown()
+	wg.Wait()
+}
+
+func (q *ReconcileQueue) process(ctx context.Context, reconcile func(ctx context.Context, key types.NamespacedName) error, logger log.Logger) bool {
+	key, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(key)
+
+	err := reconcile(ctx, key)
+	if err == nil {
+		q.queue.Forget(key)
+		return true
+	}
+	if q.queue.NumRequeues(key) < reconcileMaxRetries {
+		logger.WithField("redisfailover", key.Name).WithField("namespace", key.Namespace).Debugf("Reconcile failed, retrying: %v", err)
+		q.queue.AddRateLimited(key)
+		return true
+	}
+	logger.WithField("redisfailover", key.Name).WithField("namespace", key.Namespace).Warningf("Reconcile failed, left to the next resync: %v", err)
+	q.queue.Forget(key)
+	return true
+}
+
+// keyLocks serializes the reconciles of every redis failover, whether they come from the controller or
+// from the reconcile queue.
+type keyLocks struct {
+	locks sync.Map
+}
+
+// lock waits for the other reconciles of the redis failover and returns the function ending this one.
+func (l *keyLocks) lock(namespace, name string) func() {
+	m, _ := l.locks.LoadOrStore(types.NamespacedName{Namespace: namespace, Name: name}, &sync.Mutex{})
+	mu := m.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
@@ -0,0 +1,160 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+// CheckRestore starts the restore requested in the spec when the failover is created. The source is resolved
+// once and kept in the status, the redis statefulset is then generated with the init container restoring it.
+// The status is persisted right away, so the restore is never taken for one requested on an existing failover.
+func (r *RedisFailoverHandler) CheckRestore(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if rf.Spec.Restore == nil || rf.Status.Restore != nil {
+		return nil
+	}
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	_, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf))
+	switch {
+	case err == nil:
+		logger.Warningf("Restore ignored, it is only applied when the failover is created")
+		r.recorder.Event(rf, corev1.EventTypeWarning, rfservice.EventReasonRestoreSkipped, "Restore ignored, it is only applied when the failover is created")
+		rf.Status.Restore = &redisfailoverv1.RestoreStatus{
+			Phase:   redisfailoverv1.RestoreSkipped,
+			Message: "restore is only applied when the failover is created",
+			Time:    metav1.Now(),
+		}
+		return nil
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+
+	source, err := r.getRestoreSource(ctx, rf)
+	if err != nil {
+		return err
+	}
+
+	rf.Status.Restore = &redisfailoverv1.RestoreStatus{
+		Phase:   redisfailoverv1.RestoreRestoring,
+		Source:  source,
+		Message: fmt.Sprintf("waiting for pod %s to restore the data", rfservice.GetRedisRestorePodName(rf)),
+		Time:    metav1.Now(),
+	}
+	updated, err := r.k8sservice.UpdateRedisFailoverStatus(ctx, rf.Namespace, rf, metav1.UpdateOptions{})
+	if err != nil {
+		rf.Status.Restore = nil
+		return err
+	}
+	// Keep the new resource version so the status can still be written at the end of the reconcile.
+	rf.ResourceVersion = updated.ResourceVersion
+
+	logger.Infof("Restoring the data from %s", source)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonRestoreStarted, "Restoring the data from %s", source)
+	return nil
+}
+
+// getRestoreSource returns the location of the RDB file, as read by the restore init container.
+func (r *RedisFailoverHandler) getRestoreSource(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
+	restore := rf.Spec.Restore
+	source := restore.URL
+	switch {
+	case restore.BackupName != "":
+		if !r.config.EnableBackups {
+			return "", fmt.Errorf("restoring backup %s needs the operator to run with --enable-backups", restore.BackupName)
+		}
+		b, err := r.k8sservice.GetRedisFailoverBackup(ctx, rf.Namespace, restore.BackupName)
+		if err != nil {
+			return "", err
+		}
+		if b.Status.Phase != redisfailoverv1.BackupCompleted {
+			return "", fmt.Errorf("backup %s to restore is not completed", b.Name)
+		}
+		source = b.Status.Location
+	case restore.PersistentVolumeClaim != nil:
+		if err := r.checkRestoreClaim(rf); err != nil {
+			return "", err
+		}
+		source = restore.VolumeSourceURL()
+	}
+
+	if strings.HasPrefix(source, "s3://") && !rf.BackupsEnabled() {
+		return "", fmt.Errorf("restoring %s needs the backup settings to reach the storage", source)
+	}
+	return source, nil
+}
+
+// checkRestoreClaim makes sure the claim to restore from can be mounted by all the redis pods, which mount it
+// while restoring: the pods would otherwise stay pending on a claim already mounted on another node.
+func (r *RedisFailoverHandler) checkRestoreClaim(rf *redisfailoverv1.RedisFailover) error {
+	claimName := rf.Spec.Restore.PersistentVolumeClaim.ClaimName
+	pvc, err := r.k8sservice.GetPersistentVolumeClaim(rf.Namespace, claimName)
+	if err != nil {
+		return err
+	}
+	if rf.Spec.Redis.Replicas <= 1 {
+		return nil
+	}
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == corev1.ReadOnlyMany || mode == corev1.ReadWriteMany {
+			return nil
+		}
+	}
+	return fmt.Errorf("claim %s to restore from must be %s or %s, it is mounted by the %d redis pods", claimName, corev1.ReadOnlyMany, corev1.ReadWriteMany, rf.Spec.Redis.Replicas)
+}
+
+// promoteRestoredPod replaces the promotion of the oldest pod while restoring: the pod holding the restored
+// data is made master once it is up, so a replica with an empty dataset is never promoted.
+func (r *RedisFailoverHandler) promoteRestoredPod(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	podName := rfservice.GetRedisRestorePodName(rf)
+
+	pod, err := r.k8sservice.GetPod(rf.Namespace, podName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err != nil || !isRedisContainerRunning(pod) {
+		logger.Infof("No master, waiting for pod %s to restore the data", podName)
+		r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonRestoreWaiting, "Waiting for pod %s to restore the data before electing a master", podName)
+		return nil
+	}
+
+	ip := pod.Status.PodIP
+	if err := r.rfHealer.MakeMaster(ctx, ip, rf); err != nil {
+		return err
+	}
+	if err := r.rfHealer.SetMasterOnAll(ctx, ip, rf); err != nil {
+		return err
+	}
+	r.completeRestore(rf)
+	return nil
+}
+
+// completeRestore records that the restored pod was promoted, the failover is then healed as usual.
+func (r *RedisFailoverHandler) completeRestore(rf *redisfailoverv1.RedisFailover) {
+	podName := rfservice.GetRedisRestorePodName(rf)
+	rf.Status.Restore.Phase = redisfailoverv1.RestoreCompleted
+	rf.Status.Restore.Message = fmt.Sprintf("data restored in pod %s", podName)
+	rf.Status.Restore.Time = metav1.Now()
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Restore completed, %s is the master", podName)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonRestoreCompleted, "Data restored from %s, pod %s is the master", rf.Status.Restore.Source, podName)
+}
+
+func isRedisContainerRunning(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == "redis" {
+			return cs.State.Running != nil
+		}
+	}
+	return false
+}
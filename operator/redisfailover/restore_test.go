@@ -0,0 +1,230 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestCheckRestore(t *testing.T) {
+	completed := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+		Status:     redisfailoverv1.RedisFailoverBackupStatus{Phase: redisfailoverv1.BackupCompleted, Location: "s3://backups/testns/backup.rdb"},
+	}
+	saving := completed.DeepCopy()
+	saving.Status = redisfailoverv1.RedisFailoverBackupStatus{Phase: redisfailoverv1.BackupSaving}
+
+	tests := []struct {
+		name            string
+		restore         *redisfailoverv1.RestoreSettings
+		backupsEnabled  bool
+		noController    bool
+		existing        bool
+		backup          *redisfailoverv1.RedisFailoverBackup
+		claimModes      []corev1.PersistentVolumeAccessMode
+		expErr          bool
+		expPhase        redisfailoverv1.RestorePhase
+		expSource       string
+		expStatusUpdate bool
+	}{
+		{
+			name: "No restore requested",
+		},
+		{
+			name:     "Restore requested on an existing failover",
+			restore:  &redisfailoverv1.RestoreSettings{URL: "https://backups/dump.rdb"},
+			existing: true,
+			expPhase: redisfailoverv1.RestoreSkipped,
+		},
+		{
+			name:            "Restore from a URL",
+			restore:         &redisfailoverv1.RestoreSettings{URL: "https://backups/dump.rdb"},
+			expPhase:        redisfailoverv1.RestoreRestoring,
+			expSource:       "https://backups/dump.rdb",
+			expStatusUpdate: true,
+		},
+		{
+			name:            "Restore from a volume",
+			restore:         &redisfailoverv1.RestoreSettings{PersistentVolumeClaim: &redisfailoverv1.RestoreVolumeSource{ClaimName: "backups", Path: "testns/dump.rdb"}},
+			claimModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany},
+			expPhase:        redisfailoverv1.RestoreRestoring,
+			expSource:       "file:///restore/testns/dump.rdb",
+			expStatusUpdate: true,
+		},
+		{
+			name:       "Restore from a volume mounted by a single pod",
+			restore:    &redisfailoverv1.RestoreSettings{PersistentVolumeClaim: &redisfailoverv1.RestoreVolumeSource{ClaimName: "backups", Path: "testns/dump.rdb"}},
+			claimModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			expErr:     true,
+		},
+		{
+			name:            "Restore from a backup",
+			restore:         &redisfailoverv1.RestoreSettings{BackupName: "backup"},
+			backupsEnabled:  true,
+			backup:          completed,
+			expPhase:        redisfailoverv1.RestoreRestoring,
+			expSource:       "s3://backups/testns/backup.rdb",
+			expStatusUpdate: true,
+		},
+		{
+			name:    "Restore from a backup still running",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "backup"},
+			backup:  saving,
+			expErr:  true,
+		},
+		{
+			name:           "Restore from a backup without the backup controller",
+			restore:        &redisfailoverv1.RestoreSettings{BackupName: "backup"},
+			backupsEnabled: true,
+			noController:   true,
+			expErr:         true,
+		},
+		{
+			name:    "Restore from s3 without the backup settings",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "backup"},
+			backup:  completed,
+			expErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			if test.backupsEnabled {
+				rf = generateRFWithBackups()
+			}
+			rf.Spec.Restore = test.restore
+
+			mk := &mK8SService.Services{}
+			if test.restore != nil {
+				if test.existing {
+					mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(&appsv1.StatefulSet{}, nil)
+				} else {
+					mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(nil, apierrors.NewNotFound(schema.GroupResource{}, name))
+				}
+			}
+			if test.claimModes != nil {
+				pvc := &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{AccessModes: test.claimModes}}
+				mk.On("GetPersistentVolumeClaim", namespace, "backups").Once().Return(pvc, nil)
+			}
+			if test.backup != nil {
+				mk.On("GetRedisFailoverBackup", mock.Anything, namespace, "backup").Once().Return(test.backup, nil)
+			}
+			if test.expStatusUpdate {
+				updated := rf.DeepCopy()
+				updated.ResourceVersion = "2"
+				mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(updated, nil)
+			}
+
+			config := generateConfig()
+			config.EnableBackups = !test.noController
+			handler := rfOperator.NewRedisFailoverHandler(config, &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.CheckRestore(context.TODO(), rf)
+
+			if test.expErr {
+				assert.Error(err)
+				assert.Nil(rf.Status.Restore)
+			} else {
+				assert.NoError(err)
+				if test.expPhase == "" {
+					assert.Nil(rf.Status.Restore)
+				} else if assert.NotNil(rf.Status.Restore) {
+					assert.Equal(test.expPhase, rf.Status.Restore.Phase)
+					assert.Equal(test.expSource, rf.Status.Restore.Source)
+				}
+			}
+			if test.expStatusUpdate {
+				assert.Equal("2", rf.ResourceVersion)
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealRestore(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		expPromoted bool
+	}{
+		{
+			name: "Restored pod not created yet",
+		},
+		{
+			name: "Restored pod still restoring",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				PodIP: "0.0.0.1",
+			}},
+		},
+		{
+			name: "Restored pod up",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				PodIP:             "0.0.0.1",
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "redis", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+			}},
+			expPromoted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Spec.Restore = &redisfailoverv1.RestoreSettings{URL: "https://backups/dump.rdb"}
+			rf.Status.Restore = &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreRestoring, Source: "https://backups/dump.rdb"}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.1", "0.0.0.2"}, nil)
+			mrfh.On("SetRedisUsers", mock.Anything, mock.Anything, rf).Twice().Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, mock.Anything, rf).Twice().Return(nil)
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(generateObservation(rf, "", []string{"0.0.0.1", "0.0.0.2"}, nil), nil)
+			if test.pod == nil {
+				mk.On("GetPod", namespace, "rfr-test-0").Once().Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "rfr-test-0"))
+			} else {
+				mk.On("GetPod", namespace, "rfr-test-0").Once().Return(test.pod, nil)
+			}
+			if test.expPromoted {
+				mrfh.On("MakeMaster", mock.Anything, "0.0.0.1", rf).Once().Return(nil)
+				mrfh.On("SetMasterOnAll", mock.Anything, "0.0.0.1", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, mk, metrics.Dummy, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			assert.NoError(err)
+
+			if test.expPromoted {
+				assert.Equal(redisfailoverv1.RestoreCompleted, rf.Status.Restore.Phase)
+			} else {
+				assert.Equal(redisfailoverv1.RestoreRestoring, rf.Status.Restore.Phase)
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
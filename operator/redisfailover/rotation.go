@@ -0,0 +1,127 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+// checkAndHealPasswordRotation moves the redises and the sentinels to a new auth password without refusing
+// the clients that still use the previous one. The new password is first accepted next to the previous one
+// and used for the replication and by the sentinels. Both are accepted until the grace period ends or the
+// rotation is confirmed, checked on the next reconciles, and only then is the new one left as the only one
+// accepted. Every step can be applied again, so a rotation that fails half way resumes on the next reconcile.
+func (r *RedisFailoverHandler) checkAndHealPasswordRotation(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if rf.Spec.Auth.SecretPath == "" {
+		return nil
+	}
+
+	previous, err := r.rfService.EnsureRedisPasswordRotation(rf)
+	if err != nil || previous == "" {
+		return err
+	}
+
+	redises, err := r.rfChecker.GetRedisesIPs(rf)
+	if err != nil {
+		return err
+	}
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		return err
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	if rf.Status.PasswordRotation == nil || rf.Status.PasswordRotation.Phase == redisfailoverv1.PasswordRotationCompleted {
+		logger.Infof("Rotating the auth password")
+		r.recorder.Event(rf, corev1.EventTypeNormal, rfservice.EventReasonPasswordRotationStarted, "Rotating the auth password")
+	}
+
+	// Once the clients were moved the steps already done are applied again, for the pods restarted meanwhile
+	var phase redisfailoverv1.PasswordRotationPhase
+	if rf.Status.PasswordRotation != nil {
+		phase = rf.Status.PasswordRotation.Phase
+	}
+	waiting := phase == redisfailoverv1.PasswordRotationUpdatingClients
+	removing := phase == redisfailoverv1.PasswordRotationRemovingPassword
+	if !waiting && !removing {
+		setPasswordRotationPhase(rf, redisfailoverv1.PasswordRotationAddingPassword, fmt.Sprintf("adding the new password on %d redises", len(redises)))
+	}
+	for _, rip := range redises {
+		if err := r.rfHealer.AddRedisPassword(ctx, rip, previous, rf); err != nil {
+			return err
+		}
+	}
+
+	if !waiting && !removing {
+		setPasswordRotationPhase(rf, redisfailoverv1.PasswordRotationUpdatingClients, fmt.Sprintf("using the new password for the replication and on %d sentinels, both passwords are accepted for %s", len(sentinels), rf.PasswordRotationGracePeriod()))
+	}
+	for _, rip := range redises {
+		if err := r.rfHealer.SetRedisMasterAuth(ctx, rip, rf); err != nil {
+			return err
+		}
+	}
+	for _, sip := range sentinels {
+		if err := r.rfHealer.SetSentinelAuthPass(ctx, sip, rf); err != nil {
+			return err
+		}
+	}
+
+	// The grace period counts from the time the clients were first moved to the new password
+	if !removing && !rf.PasswordRotationConfirmed() {
+		if remaining := rf.PasswordRotationGracePeriod() - time.Since(rf.Status.PasswordRotation.Time.Time); remaining > 0 {
+			logger.Debugf("Waiting %s for the clients to use the new password", remaining.Round(time.Second))
+			r.queue.AddAfter(rf.Namespace, rf.Name, remaining)
+			return nil
+		}
+	}
+
+	setPasswordRotationPhase(rf, redisfailoverv1.PasswordRotationRemovingPassword, fmt.Sprintf("removing the previous password from %d redises", len(redises)))
+	for _, rip := range redises {
+		if err := r.rfHealer.RemoveRedisPreviousPassword(ctx, rip, rf); err != nil {
+			return err
+		}
+	}
+	if err := r.rfService.FinishRedisPasswordRotation(rf); err != nil {
+		return err
+	}
+	if err := r.clearPasswordRotationConfirmation(rf); err != nil {
+		return err
+	}
+
+	setPasswordRotationPhase(rf, redisfailoverv1.PasswordRotationCompleted, "only the new password is accepted")
+	logger.Infof("Auth password rotated")
+	r.recorder.Event(rf, corev1.EventTypeNormal, rfservice.EventReasonPasswordRotationCompleted, "Auth password rotated, only the new password is accepted")
+	return nil
+}
+
+// clearPasswordRotationConfirmation removes the confirmation of the rotation completed, so the next
+// rotation waits for its own.
+func (r *RedisFailoverHandler) clearPasswordRotationConfirmation(rf *redisfailoverv1.RedisFailover) error {
+	if _, ok := rf.Annotations[redisfailoverv1.ConfirmPasswordRotationAnnotation]; !ok {
+		return nil
+	}
+	updated, err := r.k8sservice.RemoveRedisFailoverAnnotation(context.TODO(), rf.Namespace, rf.Name, redisfailoverv1.ConfirmPasswordRotationAnnotation)
+	if err != nil {
+		return err
+	}
+	// Keep the new resource version so the status can still be written at the end of the reconcile.
+	rf.ResourceVersion = updated.ResourceVersion
+	rf.Annotations = updated.Annotations
+	return nil
+}
+
+// setPasswordRotationPhase keeps the step the rotation is at in the status. When a step fails the
+// rotation stays at it until the next reconcile.
+func setPasswordRotationPhase(rf *redisfailoverv1.RedisFailover, phase redisfailoverv1.PasswordRotationPhase, message string) {
+	rf.Status.PasswordRotation = &redisfailoverv1.PasswordRotationStatus{
+		Phase:   phase,
+		Message: message,
+		Time:    metav1.Now(),
+	}
+}
@@ -0,0 +1,165 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestCheckAndHealPasswordRotation(t *testing.T) {
+	tests := []struct {
+		name          string
+		previous      string
+		phase         redisfailoverv1.PasswordRotationPhase
+		phaseAge      time.Duration
+		confirmed     bool
+		masterAuthErr error
+		expErr        bool
+		expRemoved    bool
+		expPhase      redisfailoverv1.PasswordRotationPhase
+		expEvents     int
+	}{
+		{
+			name: "No rotation in progress",
+		},
+		{
+			name:      "Rotation keeps both passwords once the clients are moved",
+			previous:  "oldpass",
+			expPhase:  redisfailoverv1.PasswordRotationUpdatingClients,
+			expEvents: 1,
+		},
+		{
+			name:     "Rotation waits for the end of the grace period",
+			previous: "oldpass",
+			phase:    redisfailoverv1.PasswordRotationUpdatingClients,
+			phaseAge: time.Minute,
+			expPhase: redisfailoverv1.PasswordRotationUpdatingClients,
+		},
+		{
+			name:       "Rotation completes once the grace period ended",
+			previous:   "oldpass",
+			phase:      redisfailoverv1.PasswordRotationUpdatingClients,
+			phaseAge:   10 * time.Minute,
+			expRemoved: true,
+			expPhase:   redisfailoverv1.PasswordRotationCompleted,
+			expEvents:  1,
+		},
+		{
+			name:       "Rotation confirmed completes before the end of the grace period",
+			previous:   "oldpass",
+			phase:      redisfailoverv1.PasswordRotationUpdatingClients,
+			phaseAge:   time.Minute,
+			confirmed:  true,
+			expRemoved: true,
+			expPhase:   redisfailoverv1.PasswordRotationCompleted,
+			expEvents:  1,
+		},
+		{
+			name:       "Rotation failing to remove the previous password resumes at once",
+			previous:   "oldpass",
+			phase:      redisfailoverv1.PasswordRotationRemovingPassword,
+			phaseAge:   time.Second,
+			expRemoved: true,
+			expPhase:   redisfailoverv1.PasswordRotationCompleted,
+			expEvents:  1,
+		},
+		{
+			name:          "Rotation stops at the failing step",
+			previous:      "oldpass",
+			masterAuthErr: errors.New("NOPERM"),
+			expErr:        true,
+			expPhase:      redisfailoverv1.PasswordRotationUpdatingClients,
+			expEvents:     1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Spec.Auth.SecretPath = "redis-auth"
+			if test.phase != "" {
+				rf.Status.PasswordRotation = &redisfailoverv1.PasswordRotationStatus{Phase: test.phase, Time: metav1.NewTime(time.Now().Add(-test.phaseAge))}
+			}
+			if test.confirmed {
+				rf.Annotations = map[string]string{redisfailoverv1.ConfirmPasswordRotationAnnotation: "true"}
+			}
+
+			master := "0.0.0.0"
+			sentinel := "1.1.1.1"
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfs.On("EnsureRedisPasswordRotation", rf).Once().Return(test.previous, nil)
+
+			if test.previous != "" {
+				mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
+				mrfh.On("AddRedisPassword", mock.Anything, master, test.previous, rf).Once().Return(nil)
+				mrfh.On("SetRedisMasterAuth", mock.Anything, master, rf).Once().Return(test.masterAuthErr)
+				if test.masterAuthErr == nil {
+					mrfh.On("SetSentinelAuthPass", mock.Anything, sentinel, rf).Once().Return(nil)
+				}
+			}
+			if test.expRemoved {
+				mrfh.On("RemoveRedisPreviousPassword", mock.Anything, master, rf).Once().Return(nil)
+				mrfs.On("FinishRedisPasswordRotation", rf).Once().Return(nil)
+			}
+			if test.confirmed {
+				// The confirmation is left to the next rotation
+				mk.On("RemoveRedisFailoverAnnotation", mock.Anything, namespace, name, redisfailoverv1.ConfirmPasswordRotationAnnotation).Once().Return(rf, nil)
+			}
+
+			if !test.expErr {
+				// Healthy failover with a single sentinel
+				mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+				mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+				mrfc.On("Observe", mock.Anything, rf).Once().Return(generateObservation(rf, master, nil, []string{sentinel}), nil)
+				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+				mrfc.On("GetRedisRevisionHash", "rfr-test-0", rf).Once().Return("1", nil)
+				mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, recorder, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			if test.expPhase == "" {
+				assert.Nil(rf.Status.PasswordRotation)
+			} else if assert.NotNil(rf.Status.PasswordRotation) {
+				assert.Equal(test.expPhase, rf.Status.PasswordRotation.Phase)
+			}
+			assert.Len(recorder.Events, test.expEvents)
+
+			mk.AssertExpectations(t)
+			mrfs.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+// sentinelResetTimeout is how long a sentinel reset during a scale down has to learn the topology
+// again, before the next one is reset.
+var sentinelResetTimeout = 30 * time.Second
+
+// CheckScaleDown follows the removal of redis pods when the replicas were lowered. The statefulset
+// controller deletes the highest ordinals first, so while the master runs on one of the pods to remove
+// they are kept, and the master is moved by checkAndHealScaleDown. The pods are released on the first
+// reconcile that finds the master on a pod kept, and the sentinels are then reset to forget them. It runs
+// before the statefulset is ensured, so the lower replicas are never applied before.
+func (r *RedisFailoverHandler) CheckScaleDown(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if rf.Bootstrapping() {
+		// The master is outside of the failover
+		rf.Status.ScaleDown = nil
+		return nil
+	}
+
+	ss, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf))
+	if apierrors.IsNotFound(err) {
+		rf.Status.ScaleDown = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	redises := rf.RequestedRedisPods()
+	running := int32(1)
+	if ss.Spec.Replicas != nil {
+		running = *ss.Spec.Replicas
+	}
+	if running <= redises {
+		if rf.ScaleDownKeepsPods() {
+			// The replicas were raised again before the master was moved
+			rf.Status.ScaleDown = nil
+		}
+		return nil
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	var reason string
+	master, err := r.rfChecker.GetRedisesMasterPod(ctx, rf)
+	switch {
+	case err != nil:
+		reason = fmt.Sprintf("unable to find the master: %s", err.Error())
+	case !isBelowPartition(master, redises):
+		reason = fmt.Sprintf("master runs on pod %s, which is removed", master)
+	default:
+		logger.Infof("Master runs on pod %s, removing %d redis pods", master, running-redises)
+		if rf.ScaleDownKeepsPods() {
+			r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonScaleDownReleased, "Master runs on pod %s, removing %d redis pods", master, running-redises)
+		}
+		rf.Status.ScaleDown = &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: running}
+		return nil
+	}
+
+	if !rf.ScaleDownKeepsPods() {
+		logger.Infof("Keeping %d redis pods until the master is moved: %s", running, reason)
+		r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonScaleDownPending, "Keeping %d redis pods until the master is moved: %s", running, reason)
+	}
+	rf.Status.ScaleDown = &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: running, BlockedReason: reason}
+	return nil
+}
+
+// checkAndHealScaleDown goes on with the scale down in progress: it moves the master off the pods to
+// remove, or resets the sentinels once they are removed.
+func (r *RedisFailoverHandler) checkAndHealScaleDown(ctx context.Context, rf *redisfailoverv1.RedisFailover, masterIP string, sentinels []string) error {
+	switch {
+	case rf.ScaleDownKeepsPods():
+		return r.moveMasterForScaleDown(ctx, rf, masterIP, sentinels)
+	case rf.ScaleDownResetsSentinels():
+		return r.resetSentinelsForScaleDown(ctx, rf, sentinels)
+	}
+	return nil
+}
+
+// moveMasterForScaleDown moves the master to a replica in sync running on one of the pods kept by the
+// scale down. The pods are removed on the reconcile following the switchover.
+func (r *RedisFailoverHandler) moveMasterForScaleDown(ctx context.Context, rf *redisfailoverv1.RedisFailover, masterIP string, sentinels []string) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+	redises := rf.RequestedRedisPods()
+	master, target, targetIP := "", "", ""
+	for _, pod := range pods.Items {
+		if pod.Status.PodIP == masterIP {
+			master = pod.Name
+		}
+	}
+	if master != "" && isBelowPartition(master, redises) {
+		// Released by the next reconcile
+		return nil
+	}
+	for _, pod := range pods.Items {
+		if !isBelowPartition(pod.Name, redises) || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || pod.Status.PodIP == "" {
+			continue
+		}
+		ready, err := r.rfChecker.CheckRedisSlavesReady(ctx, pod.Status.PodIP, rf)
+		if err != nil {
+			return err
+		}
+		if ready {
+			target, targetIP = pod.Name, pod.Status.PodIP
+			break
+		}
+	}
+	if target == "" {
+		rf.Status.ScaleDown.BlockedReason = "no replica in sync on the pods kept to move the master to"
+		return nil
+	}
+
+	logger.Infof("Switching over master from %s to %s (%s) for the scale down", master, target, targetIP)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonSwitchoverStarted, "Switching over master from pod %s to pod %s for the scale down", master, target)
+
+	priority, err := r.raiseReplicaPriority(ctx, rf, targetIP)
+	if err != nil {
+		return err
+	}
+	if err := r.sentinelFailover(ctx, rf, sentinels); err != nil {
+		r.restoreReplicaPriority(ctx, rf, target, targetIP, priority)
+		logger.Warningf("Switchover to %s for the scale down failed: %s", target, err.Error())
+		r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonSwitchoverFailed, "Switchover to pod %s for the scale down failed: %s", target, err.Error())
+		rf.Status.ScaleDown.BlockedReason = fmt.Sprintf("switchover to %s failed: %s", target, err.Error())
+		return nil
+	}
+	r.setSwitchoverInProgress(rf, target, priority, fmt.Sprintf("switching over master from pod %s to pod %s for the scale down", master, target))
+	rf.Status.ScaleDown.BlockedReason = fmt.Sprintf("waiting for the sentinels to move the master to %s", target)
+	return nil
+}
+
+// resetSentinelsForScaleDown resets the sentinels still knowing the redis pods removed. They are reset one
+// per reconcile, each one learning the other sentinels and the replicas again before the next is reset, so
+// the sentinels able to agree on a failover are always a quorum. The sentinel reset is kept in the status
+// and checked on the next reconciles instead of waited for in this one.
+func (r *RedisFailoverHandler) resetSentinelsForScaleDown(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	scaleDown := rf.Status.ScaleDown
+
+	if sip := scaleDown.SentinelReset; sip != "" {
+		if r.rfChecker.CheckSentinelNumberInMemory(ctx, sip, rf) != nil || r.rfChecker.CheckSentinelSlavesNumberInMemory(ctx, sip, rf) != nil {
+			if scaleDown.SentinelResetTime != nil && time.Since(scaleDown.SentinelResetTime.Time) <= sentinelResetTimeout {
+				r.queue.AddAfter(rf.Namespace, rf.Name, switchoverPollInterval)
+				return nil
+			}
+			// The next reconcile resets the sentinels still knowing the pods removed, this one included
+			scaleDown.BlockedReason = fmt.Sprintf("sentinel %s does not know the sentinels and replicas expected %s after its reset", sip, sentinelResetTimeout)
+			logger.Warningf("Scale down waiting for sentinel %s: %s", sip, scaleDown.BlockedReason)
+			scaleDown.SentinelReset, scaleDown.SentinelResetTime = "", nil
+			r.queue.AddAfter(rf.Namespace, rf.Name, switchoverPollInterval)
+			return nil
+		}
+		scaleDown.SentinelReset, scaleDown.SentinelResetTime = "", nil
+	}
+
+	for _, sip := range sentinels {
+		if err := r.rfChecker.CheckSentinelSlavesNumberInMemory(ctx, sip, rf); err == nil {
+			continue
+		}
+		logger.Infof("Resetting sentinel %s to forget the redis pods removed", sip)
+		if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
+			return err
+		}
+		r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonSentinelReset, "Reset sentinel %s to forget the redis pods removed", sip)
+		now := metav1.Now()
+		scaleDown.SentinelReset, scaleDown.SentinelResetTime = sip, &now
+		scaleDown.BlockedReason = fmt.Sprintf("waiting for sentinel %s to know the sentinels and replicas after its reset", sip)
+		r.queue.AddAfter(rf.Namespace, rf.Name, switchoverPollInterval)
+		return nil
+	}
+
+	logger.Infof("Scale down from %d redis pods completed", scaleDown.Redises)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonScaleDownCompleted, "Scale down from %d to %d redis pods completed", scaleDown.Redises, rf.Spec.Redis.Replicas)
+	rf.Status.ScaleDown = nil
+	return nil
+}
@@ -0,0 +1,381 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestCheckScaleDown(t *testing.T) {
+	tests := []struct {
+		name         string
+		bootstrap    bool
+		noSS         bool
+		ssReplicas   int32
+		scaleDown    *redisfailoverv1.RedisScaleDownStatus
+		master       string
+		masterErr    error
+		expScaleDown *redisfailoverv1.RedisScaleDownStatus
+		expEvent     bool
+	}{
+		{
+			name: "Statefulset not created yet",
+			noSS: true,
+		},
+		{
+			name:       "Replicas not lowered",
+			ssReplicas: 3,
+		},
+		{
+			name:         "Replicas lowered with the master kept",
+			ssReplicas:   5,
+			master:       "rfr-test-1",
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: 5},
+		},
+		{
+			name:       "Replicas lowered with the master removed",
+			ssReplicas: 5,
+			master:     "rfr-test-4",
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{
+				Phase:         redisfailoverv1.ScaleDownMovingMaster,
+				Redises:       5,
+				BlockedReason: "master runs on pod rfr-test-4, which is removed",
+			},
+			expEvent: true,
+		},
+		{
+			name:       "Replicas lowered without a master",
+			ssReplicas: 5,
+			masterErr:  errors.New("redis nodes known as master not found"),
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{
+				Phase:         redisfailoverv1.ScaleDownMovingMaster,
+				Redises:       5,
+				BlockedReason: "unable to find the master: redis nodes known as master not found",
+			},
+			expEvent: true,
+		},
+		{
+			name:       "Scale down still waiting for the master",
+			ssReplicas: 5,
+			scaleDown:  &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 5},
+			master:     "rfr-test-3",
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{
+				Phase:         redisfailoverv1.ScaleDownMovingMaster,
+				Redises:       5,
+				BlockedReason: "master runs on pod rfr-test-3, which is removed",
+			},
+		},
+		{
+			name:         "Scale down released once the master moved",
+			ssReplicas:   5,
+			scaleDown:    &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 5},
+			master:       "rfr-test-0",
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: 5},
+			expEvent:     true,
+		},
+		{
+			name:         "Sentinels reset once the pods removed",
+			ssReplicas:   3,
+			scaleDown:    &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: 5},
+			expScaleDown: &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: 5},
+		},
+		{
+			name:       "Scale down cancelled by raising the replicas",
+			ssReplicas: 3,
+			scaleDown:  &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 3},
+		},
+		{
+			name:      "Bootstrapping",
+			bootstrap: true,
+			scaleDown: &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, test.bootstrap, false)
+			rf.Status.ScaleDown = test.scaleDown
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if !test.bootstrap {
+				if test.noSS {
+					mk.On("GetStatefulSet", namespace, "rfr-test").Once().Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "rfr-test"))
+				} else {
+					mk.On("GetStatefulSet", namespace, "rfr-test").Once().Return(&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: &test.ssReplicas}}, nil)
+				}
+			}
+			if test.ssReplicas > 3 {
+				mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return(test.master, test.masterErr)
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, recorder, log.Dummy)
+			err := handler.CheckScaleDown(context.TODO(), rf)
+			assert.NoError(err)
+			assert.Equal(test.expScaleDown, rf.Status.ScaleDown)
+			assert.Equal(test.expEvent, len(recorder.Events) > 0)
+			if rf.ScaleDownKeepsPods() {
+				assert.Equal(test.ssReplicas, rf.RedisPods())
+			} else {
+				assert.Equal(rf.Spec.Redis.Replicas, rf.RedisPods())
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealScaleDown(t *testing.T) {
+	tests := []struct {
+		name             string
+		replicaReady     bool
+		sentinelFailover error
+		expFailover      bool
+		expInProgress    bool
+		expMaster        string
+		expBlockedReason string
+	}{
+		{
+			name:             "No replica in sync on the pods kept",
+			replicaReady:     false,
+			expMaster:        "0.0.0.4",
+			expBlockedReason: "no replica in sync on the pods kept to move the master to",
+		},
+		{
+			name:             "Sentinel refuses the failover",
+			replicaReady:     true,
+			sentinelFailover: errors.New("NOGOODSLAVE"),
+			expFailover:      true,
+			expMaster:        "0.0.0.4",
+			expBlockedReason: "switchover to rfr-test-0 failed: NOGOODSLAVE",
+		},
+		{
+			name:             "Master moving to a pod kept",
+			replicaReady:     true,
+			expFailover:      true,
+			expInProgress:    true,
+			expMaster:        "0.0.0.4",
+			expBlockedReason: "waiting for the sentinels to move the master to rfr-test-0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Status.ScaleDown = &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 5, BlockedReason: "master runs on pod rfr-test-4, which is removed"}
+
+			master := "0.0.0.4"
+			targetIP := "0.0.0.0"
+			sentinel := "1.1.1.1"
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			// Healthy failover with a single sentinel, the master on the last pod
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			obs := generateObservation(rf, master, nil, []string{sentinel})
+			obs.RedisPods[0].Name = "rfr-test-4"
+			obs.Redises[0].Pod.Name = "rfr-test-4"
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+			mrfc.On("GetRedisRevisionHash", "rfr-test-4", rf).Once().Return("1", nil)
+			mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+			if !test.expInProgress {
+				// The sentinels are checked when the master is not moving
+				mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+			}
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: targetIP}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-4"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: master}},
+				},
+			}
+			mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(pods, nil)
+			mrfc.On("CheckRedisSlavesReady", mock.Anything, targetIP, rf).Once().Return(test.replicaReady, nil)
+			if test.expFailover {
+				mrfc.On("GetRedisReplicaPriority", mock.Anything, targetIP, rf).Once().Return("100", nil)
+				mrfh.On("SetReplicaPriority", mock.Anything, targetIP, "1", rf).Once().Return(nil)
+				mrfh.On("SentinelFailover", mock.Anything, sentinel, rf).Once().Return(test.sentinelFailover)
+				if !test.expInProgress {
+					// The priority is restored once the switchover is over
+					mrfh.On("SetReplicaPriority", mock.Anything, targetIP, "100", rf).Once().Return(nil)
+				}
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, recorder, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			assert.NoError(err)
+			assert.Equal(test.expMaster, rf.Status.Master.IP)
+			assert.Equal(test.expBlockedReason, rf.Status.ScaleDown.BlockedReason)
+			assert.Equal(test.expInProgress, rf.SwitchoverInProgress())
+			assert.Equal(int32(5), rf.RedisPods())
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealScaleDownSentinels(t *testing.T) {
+	mismatch := errors.New("redis slaves in sentinel memory mismatch")
+	tests := []struct {
+		name             string
+		reset            string
+		resetAgo         time.Duration
+		resetKnows       bool
+		slaves           map[string]error
+		expReset         string
+		expRestored      string
+		expBlockedReason string
+		expCompleted     bool
+	}{
+		{
+			name:             "First sentinel knowing the pods removed is reset",
+			slaves:           map[string]error{"1.1.1.2": mismatch, "1.1.1.3": mismatch},
+			expReset:         "1.1.1.2",
+			expRestored:      "1.1.1.2",
+			expBlockedReason: "waiting for sentinel 1.1.1.2 to know the sentinels and replicas after its reset",
+		},
+		{
+			name:     "Sentinel reset is waited for",
+			reset:    "1.1.1.2",
+			resetAgo: time.Second,
+			slaves:   map[string]error{"1.1.1.2": mismatch, "1.1.1.3": mismatch},
+			expReset: "1.1.1.2",
+		},
+		{
+			name:             "Next sentinel is reset once the previous one knows the topology",
+			reset:            "1.1.1.2",
+			resetAgo:         time.Second,
+			resetKnows:       true,
+			slaves:           map[string]error{"1.1.1.3": mismatch},
+			expReset:         "1.1.1.3",
+			expRestored:      "1.1.1.3",
+			expBlockedReason: "waiting for sentinel 1.1.1.3 to know the sentinels and replicas after its reset",
+		},
+		{
+			name:             "Sentinel not knowing the topology in time is given up",
+			reset:            "1.1.1.2",
+			resetAgo:         time.Minute,
+			slaves:           map[string]error{"1.1.1.2": mismatch, "1.1.1.3": mismatch},
+			expBlockedReason: "sentinel 1.1.1.2 does not know the sentinels and replicas expected 30s after its reset",
+		},
+		{
+			name:         "Scale down completes once all the sentinels forgot the pods removed",
+			reset:        "1.1.1.3",
+			resetAgo:     time.Second,
+			resetKnows:   true,
+			expCompleted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Status.ScaleDown = &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownResettingSentinels, Redises: 5}
+			if test.reset != "" {
+				resetTime := metav1.NewTime(time.Now().Add(-test.resetAgo))
+				rf.Status.ScaleDown.SentinelReset, rf.Status.ScaleDown.SentinelResetTime = test.reset, &resetTime
+			}
+
+			master := "0.0.0.0"
+			sentinels := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(generateObservation(rf, master, nil, sentinels), nil)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+			mrfc.On("GetRedisRevisionHash", "rfr-test-0", rf).Once().Return("1", nil)
+			mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+
+			if test.reset != "" {
+				var knows error
+				if !test.resetKnows {
+					knows = mismatch
+				}
+				mrfc.On("CheckSentinelNumberInMemory", mock.Anything, test.reset, rf).Once().Return(knows)
+			}
+			for _, sip := range sentinels {
+				mrfc.On("CheckSentinelSlavesNumberInMemory", mock.Anything, sip, rf).Maybe().Return(test.slaves[sip])
+			}
+			if test.expRestored != "" {
+				mrfh.On("RestoreSentinel", mock.Anything, test.expRestored, rf).Once().Return(nil)
+			}
+			if test.expCompleted {
+				// Then configured as on any reconcile
+				for _, sip := range sentinels {
+					mrfh.On("SetSentinelCustomConfig", mock.Anything, sip, rf).Once().Return(nil)
+				}
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			assert.NoError(err)
+			if test.expCompleted {
+				assert.Nil(rf.Status.ScaleDown)
+			} else if assert.NotNil(rf.Status.ScaleDown) {
+				assert.Equal(test.expReset, rf.Status.ScaleDown.SentinelReset)
+				assert.Equal(test.expReset != "", rf.Status.ScaleDown.SentinelResetTime != nil)
+				assert.Equal(test.expBlockedReason, rf.Status.ScaleDown.BlockedReason)
+
+				// The sentinels are checked again on the next reconcile
+				ctx, cancel := context.WithCancel(context.TODO())
+				keys := make(chan types.NamespacedName, 1)
+				go handler.ReconcileQueue().Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+					keys <- key
+					return nil
+				}, log.Dummy)
+				select {
+				case key := <-keys:
+					assert.Equal(name, key.Name)
+				case <-time.After(2 * time.Second):
+					t.Fatal("no reconcile queued")
+				}
+				cancel()
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
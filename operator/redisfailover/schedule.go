@@ -0,0 +1,58 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+)
+
+// CheckScheduledBackup creates a RedisFailoverBackup when the backup schedule of the failover is due.
+// The backup is named after the time it was scheduled at, so failing to record it in the status
+// never creates it twice. Backups missed while the operator was down are replaced by a single one. Nothing
+// is created while the backup controller is not run, there would be nobody to take the backups.
+func (r *RedisFailoverHandler) CheckScheduledBackup(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if !r.config.EnableBackups || !rf.BackupsEnabled() || rf.Spec.Backup.Schedule == "" {
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(rf.Spec.Backup.Schedule)
+	if err != nil {
+		return err
+	}
+
+	last := rf.CreationTimestamp.Time
+	if rf.Status.LastScheduledBackupTime != nil {
+		last = rf.Status.LastScheduledBackupTime.Time
+	}
+	next := schedule.Next(last)
+	now := time.Now()
+	if now.Before(next) {
+		return nil
+	}
+
+	b := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%d", rf.Name, next.Unix()),
+			Namespace:       rf.Namespace,
+			Labels:          r.getLabels(rf),
+			OwnerReferences: r.createOwnerReferences(rf),
+		},
+		Spec: redisfailoverv1.RedisFailoverBackupSpec{
+			RedisFailoverName: rf.Name,
+		},
+	}
+	if _, err := r.k8sservice.CreateRedisFailoverBackup(ctx, rf.Namespace, b); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Scheduled backup %s created", b.Name)
+	scheduled := metav1.NewTime(now)
+	rf.Status.LastScheduledBackupTime = &scheduled
+	return nil
+}
@@ -0,0 +1,88 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestCheckScheduledBackup(t *testing.T) {
+	tests := []struct {
+		name          string
+		schedule      string
+		lastScheduled time.Time
+		noController  bool
+		expCreate     bool
+	}{
+		{
+			name:          "No schedule",
+			lastScheduled: time.Now().Add(-48 * time.Hour),
+		},
+		{
+			name:          "Schedule not due",
+			schedule:      "0 0 1 1 *",
+			lastScheduled: time.Now().Add(-time.Minute),
+		},
+		{
+			name:          "Schedule due",
+			schedule:      "0 * * * *",
+			lastScheduled: time.Now().Add(-2 * time.Hour),
+			expCreate:     true,
+		},
+		{
+			name:          "Schedule due without the backup controller",
+			schedule:      "0 * * * *",
+			lastScheduled: time.Now().Add(-2 * time.Hour),
+			noController:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRFWithBackups()
+			rf.Spec.Backup.Schedule = test.schedule
+			lastScheduled := metav1.NewTime(test.lastScheduled)
+			rf.Status.LastScheduledBackupTime = &lastScheduled
+
+			mk := &mK8SService.Services{}
+			var created *redisfailoverv1.RedisFailoverBackup
+			if test.expCreate {
+				mk.On("CreateRedisFailoverBackup", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					created = args.Get(2).(*redisfailoverv1.RedisFailoverBackup)
+				}).Return(nil, nil)
+			}
+
+			config := generateConfig()
+			config.EnableBackups = !test.noController
+			handler := rfOperator.NewRedisFailoverHandler(config, &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			err := handler.CheckScheduledBackup(context.TODO(), rf)
+			assert.NoError(err)
+
+			if test.expCreate {
+				if assert.NotNil(created) {
+					assert.Equal(name, created.Spec.RedisFailoverName)
+					assert.Regexp("^test-[0-9]+$", created.Name)
+					assert.Len(created.OwnerReferences, 1)
+				}
+				assert.True(rf.Status.LastScheduledBackupTime.After(test.lastScheduled))
+			} else {
+				assert.Equal(lastScheduled, *rf.Status.LastScheduledBackupTime)
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
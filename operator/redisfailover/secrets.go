@@ -0,0 +1,101 @@
+package redisfailover
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+// SecretInformer queues a reconcile of the redis failovers using one of the secrets holding their
+// passwords as soon as it changes. That way a new password is applied right away instead of on the next
+// resync. Only the metadata of the secrets is kept in its cache.
+type SecretInformer struct {
+	cli         k8s.Services
+	informer    cache.SharedIndexInformer
+	isSupported func(rf redisfailoverv1.RedisFailover) bool
+	queue       *ReconcileQueue
+	logger      log.Logger
+}
+
+// NewSecretInformer returns an informer queueing the reconciles in the given queue.
+func NewSecretInformer(cli k8s.Services, queue *ReconcileQueue, isSupported func(rf redisfailoverv1.RedisFailover) bool, logger log.Logger) *SecretInformer {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return cli.ListSecrets(ctx, "", options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return cli.WatchSecrets(ctx, "", options)
+		},
+	}, &corev1.Secret{}, 0, cache.Indexers{})
+
+	s := &SecretInformer{
+		cli:         cli,
+		informer:    informer,
+		isSupported: isSupported,
+		queue:       queue,
+		logger:      logger,
+	}
+	_ = informer.SetTransform(stripSecretData)
+	_, _ = informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		// The secrets listed on start are left to the first reconcile of their failovers
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				s.enqueue(obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldObj.(*corev1.Secret).ResourceVersion != newObj.(*corev1.Secret).ResourceVersion {
+				s.enqueue(newObj)
+			}
+		},
+	})
+	return s
+}
+
+// Run informs of the changes of the secrets until the context is done.
+func (s *SecretInformer) Run(ctx context.Context) {
+	s.informer.RunWithContext(ctx)
+}
+
+// enqueue queues a reconcile of every redis failover using the secret.
+func (s *SecretInformer) enqueue(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	rfList, err := s.cli.ListRedisFailovers(context.Background(), secret.Namespace, metav1.ListOptions{})
+	if err != nil {
+		s.logger.WithField("namespace", secret.Namespace).Warningf("Unable to list the failovers using secret %s, they are reconciled on resync: %v", secret.Name, err)
+		return
+	}
+	for _, rf := range rfList.Items {
+		if s.isSupported(rf) && rf.UsesAuthSecret(secret.Name) {
+			s.queue.Add(rf.Namespace, rf.Name)
+		}
+	}
+}
+
+// stripSecretData drops the data of the secrets, the informer only tells which secret changed.
+func stripSecretData(obj interface{}) (interface{}, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return obj, nil
+	}
+	return &corev1.Secret{
+		TypeMeta: secret.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secret.Name,
+			Namespace:       secret.Namespace,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+		},
+	}, nil
+}
@@ -0,0 +1,158 @@
+package redisfailover_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/freshworks/redis-operator/mocks/service/redis"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestSecretInformer(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false, false)
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	other := generateRF(false, false, false)
+	other.Name = "other"
+
+	secretWatcher := watch.NewFake()
+	listed := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: namespace, ResourceVersion: "1"}, Data: map[string][]byte{"password": []byte("old")}}
+
+	mk := &mK8SService.Services{}
+	mk.On("ListSecrets", mock.Anything, "", mock.Anything).Once().Return(&corev1.SecretList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: []corev1.Secret{*listed}}, nil)
+	mk.On("WatchSecrets", mock.Anything, "", mock.Anything).Once().Return(secretWatcher, nil)
+	mk.On("ListRedisFailovers", mock.Anything, "otherns", metav1.ListOptions{}).Once().Return(&redisfailoverv1.RedisFailoverList{}, nil)
+	mk.On("ListRedisFailovers", mock.Anything, namespace, metav1.ListOptions{}).Once().Return(&redisfailoverv1.RedisFailoverList{Items: []redisfailoverv1.RedisFailover{*rf, *other}}, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	queue := rfOperator.NewReconcileQueue()
+	informer := rfOperator.NewSecretInformer(mk, queue, func(redisfailoverv1.RedisFailover) bool { return true }, log.Dummy)
+	go informer.Run(ctx)
+	keys := make(chan types.NamespacedName, 2)
+	go queue.Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+		keys <- key
+		return nil
+	}, log.Dummy)
+
+	// The secrets listed on start are ignored, so are the secrets not used by any failover. The one
+	// holding the password queues a reconcile of its failover only.
+	secretWatcher.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "otherns", ResourceVersion: "2"}})
+	secretWatcher.Modify(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: namespace, ResourceVersion: "3"}, Data: map[string][]byte{"password": []byte("new")}})
+	select {
+	case key := <-keys:
+		assert.Equal(types.NamespacedName{Namespace: namespace, Name: name}, key)
+	case <-time.After(time.Second):
+		t.Fatal("no reconcile queued")
+	}
+	select {
+	case key := <-keys:
+		t.Fatalf("unexpected reconcile of %s", key)
+	case <-time.After(100 * time.Millisecond):
+	}
+	mk.AssertExpectations(t)
+}
+
+func TestSentinelEventSubscriber(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false, false)
+
+	podWatcher := watch.NewFake()
+
+	mk := &mK8SService.Services{}
+	mk.On("WatchPods", mock.Anything, "", metav1.ListOptions{LabelSelector: "app.kubernetes.io/component=sentinel,app.kubernetes.io/managed-by=redis-operator"}).Once().Return(podWatcher, nil)
+	mk.On("GetRedisFailover", mock.Anything, namespace, name).Return(rf, nil)
+
+	// The sentinel publishes a failover as soon as it is subscribed to, then waits for the end of the subscription
+	unsubscribed := make(chan struct{})
+	mr := &mRedisService.Client{}
+	mr.On("SubscribeSentinelEvents", mock.Anything, "1.1.1.1", []string{"+switch-master", "+sdown", "+odown", "+failover-end"}, (*tls.Config)(nil), mock.Anything).Once().Run(func(args mock.Arguments) {
+		args.Get(4).(func(string))("+switch-master")
+		<-args.Get(0).(context.Context).Done()
+		close(unsubscribed)
+	}).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	queue := rfOperator.NewReconcileQueue()
+	subscriber := rfOperator.NewSentinelEventSubscriber(mk, mr, queue, func(redisfailoverv1.RedisFailover) bool { return true }, log.Dummy)
+	go subscriber.Run(ctx)
+	keys := make(chan types.NamespacedName, 1)
+	go queue.Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+		keys <- key
+		return nil
+	}, log.Dummy)
+
+	sentinel := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rfs-test-0",
+			Namespace: namespace,
+			Labels:    map[string]string{"redisfailovers.databases.spotahome.com/name": name},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "1.1.1.1"},
+	}
+
+	// Pods not running yet are not subscribed to, the failover of the sentinel is reconciled on its events
+	podWatcher.Add(&corev1.Pod{ObjectMeta: sentinel.ObjectMeta, Status: corev1.PodStatus{Phase: corev1.PodPending}})
+	podWatcher.Modify(sentinel)
+	select {
+	case key := <-keys:
+		assert.Equal(types.NamespacedName{Namespace: namespace, Name: name}, key)
+	case <-time.After(time.Second):
+		t.Fatal("no reconcile queued")
+	}
+
+	// The subscription ends with the sentinel pod
+	podWatcher.Delete(sentinel)
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("subscription not ended with its pod")
+	}
+	mk.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestReconcileQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	queue := rfOperator.NewReconcileQueue()
+	keys := make(chan types.NamespacedName, 2)
+	failed := false
+	go queue.Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+		keys <- key
+		if !failed {
+			failed = true
+			return errors.New("wanted error")
+		}
+		return nil
+	}, log.Dummy)
+
+	// The failovers queued are reconciled once the delay passed, the failed reconciles are retried
+	queue.AddAfter(namespace, name, 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		select {
+		case key := <-keys:
+			assert.Equal(types.NamespacedName{Namespace: namespace, Name: name}, key)
+		case <-time.After(time.Second):
+			t.Fatal("no reconcile done")
+		}
+	}
+}
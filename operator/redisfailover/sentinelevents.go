@@ -0,0 +1,166 @@
+package redisfailover
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/operator/redisfailover/util"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// sentinelEventChannels are the events the sentinels publish when a master goes down or is replaced
+var sentinelEventChannels = []string{"+switch-master", "+sdown", "+odown", "+failover-end"}
+
+// sentinelSubscribeRetryInterval is the wait before subscribing again to a sentinel once the subscription ended
+const sentinelSubscribeRetryInterval = 5 * time.Second
+
+// sentinelPodsSelector selects the sentinel pods of all the redis failovers of the operator
+var sentinelPodsSelector = labels.SelectorFromSet(util.MergeLabels(defaultLabels, map[string]string{
+	"app.kubernetes.io/component": "sentinel",
+})).String()
+
+// sentinelSubscription is the subscription to the events of a sentinel pod, running until canceled
+type sentinelSubscription struct {
+	ip     string
+	cancel context.CancelFunc
+}
+
+// SentinelEventSubscriber queues a reconcile of the redis failover of a sentinel as soon as the sentinel
+// publishes a failover of its master. That way the master pod is labeled right after a failover instead
+// of on the next resync. The sentinel pods are watched to subscribe to the events of every running one.
+type SentinelEventSubscriber struct {
+	cli           k8s.Services
+	redisClient   redis.Client
+	logger        log.Logger
+	isSupported   func(rf redisfailoverv1.RedisFailover) bool
+	subscriptions map[string]sentinelSubscription
+	queue         *ReconcileQueue
+}
+
+// NewSentinelEventSubscriber returns a subscriber queueing the reconciles in the given queue.
+func NewSentinelEventSubscriber(cli k8s.Services, redisClient redis.Client, queue *ReconcileQueue, isSupported func(rf redisfailoverv1.RedisFailover) bool, logger log.Logger) *SentinelEventSubscriber {
+	return &SentinelEventSubscriber{
+		cli:           cli,
+		redisClient:   redisClient,
+		logger:        logger,
+		isSupported:   isSupported,
+		subscriptions: map[string]sentinelSubscription{},
+		queue:         queue,
+	}
+}
+
+// Run subscribes to the sentinels until the context is done. The watch of the sentinel pods ended is
+// started again after a while, without access to the pods the failovers are only reconciled on resync.
+func (w *SentinelEventSubscriber) Run(ctx context.Context) {
+	for {
+		if err := w.watch(ctx); err != nil {
+			w.logger.Warningf("Unable to watch the sentinel pods, the failovers are only reconciled on resync: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sentinelSubscribeRetryInterval):
+		}
+	}
+}
+
+// watch subscribes to the sentinels of the pods watched until the watch ends. The subscriptions end
+// with it, the new watch lists the running pods again.
+func (w *SentinelEventSubscriber) watch(ctx context.Context) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	podWatcher, err := w.cli.WatchPods(watchCtx, "", metav1.ListOptions{LabelSelector: sentinelPodsSelector})
+	if err != nil {
+		return err
+	}
+	defer podWatcher.Stop()
+	defer func() {
+		for key, subscription := range w.subscriptions {
+			subscription.cancel()
+			delete(w.subscriptions, key)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-podWatcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			w.updateSubscription(watchCtx, event)
+		}
+	}
+}
+
+// updateSubscription subscribes to the events of the sentinel pod while it runs, and ends the
+// subscription once it is going away. A pod given another IP is subscribed to again.
+func (w *SentinelEventSubscriber) updateSubscription(ctx context.Context, event watch.Event) {
+	pod, ok := event.Object.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	key := pod.Namespace + "/" + pod.Name
+	failover := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[rfLabelNameKey]}
+	running := event.Type != watch.Deleted && pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && failover.Name != ""
+	if subscription, ok := w.subscriptions[key]; ok {
+		if running && subscription.ip == pod.Status.PodIP {
+			return
+		}
+		subscription.cancel()
+		delete(w.subscriptions, key)
+	}
+	if !running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.subscriptions[key] = sentinelSubscription{ip: pod.Status.PodIP, cancel: cancel}
+	go w.subscribe(ctx, failover, pod.Status.PodIP)
+}
+
+// subscribe subscribes to the events of the sentinel of the redis failover until the context is done.
+// The subscription lost is started again after a while, the events published meanwhile are only
+// noticed on the next resync.
+func (w *SentinelEventSubscriber) subscribe(ctx context.Context, failover types.NamespacedName, ip string) {
+	logger := w.logger.WithField("redisfailover", failover.Name).WithField("namespace", failover.Namespace)
+	onEvent := func(channel string) {
+		logger.Debugf("Sentinel %s published %s, reconciling", ip, channel)
+		w.queue.Add(failover.Namespace, failover.Name)
+	}
+
+	for {
+		rf, err := w.cli.GetRedisFailover(ctx, failover.Namespace, failover.Name)
+		if err == nil {
+			if !w.isSupported(*rf) {
+				return
+			}
+			var tlsConfig *tls.Config
+			if tlsConfig, err = k8s.GetSentinelTLSConfig(w.cli, rf); err == nil {
+				err = w.redisClient.SubscribeSentinelEvents(ctx, ip, sentinelEventChannels, tlsConfig, onEvent)
+			}
+		}
+		if err != nil {
+			logger.Debugf("Subscription to the events of sentinel %s ended: %v", ip, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sentinelSubscribeRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+// getRedisAddress returns how the other redises and the sentinels address the redis with the given IP:
+// by the IP, or by the DNS name of its pod in hostname mode. An address that is not the one of a redis
+// pod, like the one of a bootstrap node, is returned as it is.
+func getRedisAddress(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover, ip string) (string, error) {
+	if !rf.HostnameMode() {
+		return ip, nil
+	}
+	rps, err := k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return "", err
+	}
+	return getRedisAddressFromPods(rf, rps.Items, ip), nil
+}
+
+// getRedisAddressFromPods is getRedisAddress with the redis pods already listed
+func getRedisAddressFromPods(rf *redisfailoverv1.RedisFailover, pods []corev1.Pod, ip string) string {
+	if !rf.HostnameMode() {
+		return ip
+	}
+	for _, pod := range pods {
+		if pod.Status.PodIP == ip {
+			return GetRedisPodHostname(rf, pod.Name)
+		}
+	}
+	return ip
+}
+
+// getRedisIP returns the IP of the redis pod with the given address, the reverse of getRedisAddress
+func getRedisIP(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover, address string) (string, error) {
+	if !rf.HostnameMode() {
+		return address, nil
+	}
+	rps, err := k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return "", err
+	}
+	for _, rp := range rps.Items {
+		if GetRedisPodHostname(rf, rp.Name) == address {
+			return rp.Status.PodIP, nil
+		}
+	}
+	return address, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -21,25 +22,31 @@ import (
 type RedisFailoverCheck interface {
 	CheckRedisNumber(rFailover *redisfailoverv1.RedisFailover) error
 	CheckSentinelNumber(rFailover *redisfailoverv1.RedisFailover) error
-	CheckAllSlavesFromMaster(master string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error)
-	CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error)
-	CheckSentinelMonitor(sentinel, masterName string, monitor ...string) error
-	GetMasterIP(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetNumberMasters(rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover, monitor ...string) error
+	GetMasterIP(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetNumberMasters(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
 	GetRedisesIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetSentinelsIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetMaxRedisPodTime(rFailover *redisfailoverv1.RedisFailover) (time.Duration, error)
-	GetRedisesSlavesPods(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetRedisesSlavesPods(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetStatefulSetUpdateRevision(rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetRedisRevisionHash(podName string, rFailover *redisfailoverv1.RedisFailover) (string, error)
-	CheckRedisSlavesReady(slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	GetRedisLastSave(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (int64, error)
+	GetRedisReplicaPriority(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetMastersIPs(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetSentinelMonitor(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetRedisReplicationInfo(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (redis.ReplicationInfo, error)
 	IsRedisRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool
+	Observe(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (*ClusterObservation, error)
 }
 
 // RedisFailoverChecker is our implementation of RedisFailoverCheck interface
@@ -66,7 +73,7 @@ func (r *RedisFailoverChecker) CheckRedisNumber(rf *redisfailoverv1.RedisFailove
 	if err != nil {
 		return err
 	}
-	if rf.Spec.Redis.Replicas != *ss.Spec.Replicas {
+	if rf.RedisPods() != *ss.Spec.Replicas {
 		return errors.New("number of redis pods differ from specification")
 	}
 	return nil
@@ -74,11 +81,21 @@ func (r *RedisFailoverChecker) CheckRedisNumber(rf *redisfailoverv1.RedisFailove
 
 // CheckSentinelNumber controlls that the number of deployed sentinel is the same than the requested on the spec
 func (r *RedisFailoverChecker) CheckSentinelNumber(rf *redisfailoverv1.RedisFailover) error {
-	d, err := r.k8sService.GetDeployment(rf.Namespace, GetSentinelName(rf))
-	if err != nil {
-		return err
+	var replicas *int32
+	if rf.SentinelStatefulSet() {
+		ss, err := r.k8sService.GetStatefulSet(rf.Namespace, GetSentinelName(rf))
+		if err != nil {
+			return err
+		}
+		replicas = ss.Spec.Replicas
+	} else {
+		d, err := r.k8sService.GetDeployment(rf.Namespace, GetSentinelName(rf))
+		if err != nil {
+			return err
+		}
+		replicas = d.Spec.Replicas
 	}
-	if rf.Spec.Sentinel.Replicas != *d.Spec.Replicas {
+	if rf.Spec.Sentinel.Replicas != *replicas {
 		return errors.New("number of sentinel pods differ from specification")
 	}
 	return nil
@@ -103,18 +120,24 @@ func (r *RedisFailoverChecker) setSlaveLabelIfNecessary(namespace string, pod co
 }
 
 // CheckAllSlavesFromMaster controlls that all slaves have the same master (the real one)
-func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(ctx context.Context, master string, rf *redisfailoverv1.RedisFailover) error {
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	rport := getRedisPort(rf.Spec.Redis.Port)
+	masterAddress := getRedisAddressFromPods(rf, rps.Items, master)
 	for _, rp := range rps.Items {
 		if rp.Status.PodIP == master {
 			err = r.setMasterLabelIfNecessary(rf.Namespace, rp)
@@ -128,12 +151,12 @@ func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redis
 			}
 		}
 
-		slave, err := r.redisClient.GetSlaveOf(rp.Status.PodIP, rport, password)
+		slave, err := r.redisClient.GetSlaveOf(ctx, rp.Status.PodIP, rport, username, password, tlsConfig)
 		if err != nil {
 			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod ip: %s", rp.Status.PodIP)
 			return err
 		}
-		if slave != "" && slave != master {
+		if slave != "" && !redis.SameAddress(slave, masterAddress) {
 			return fmt.Errorf("slave %s don't have the master %s, has %s", rp.Status.PodIP, master, slave)
 		}
 	}
@@ -141,11 +164,15 @@ func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redis
 }
 
 // CheckSentinelNumberInMemory controls that the provided sentinel has only the living sentinels on its memory.
-func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(ctx, sentinel, tlsConfig)
 	if err != nil {
 		return err
-	} else if nSentinels != rf.Spec.Sentinel.Replicas {
+	} else if nSentinels != rf.SentinelPods() {
 		return errors.New("sentinels in memory mismatch")
 	}
 	return nil
@@ -156,7 +183,7 @@ func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *
 // This function returns true if it all available pods have local host ip as master,
 // false if atleast one of the ip is not local hostip
 // false and error if any function fails
-func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+func (r *RedisFailoverChecker) CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
 
 	var lhmaster = 0
 	redisIps, err := r.GetRedisesIPs(rFailover)
@@ -164,14 +191,20 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 		r.logger.Warningf("CheckIfMasterLocalhost GetRedisesIPs Failed- unable to fetch any redis Ips Currently")
 		return false, errors.New("unable to fetch any redis Ips Currently")
 	}
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
 	if err != nil {
-		r.logger.Errorf("CheckIfMasterLocalhost -- GetRedisPassword Failed")
+		r.logger.Errorf("CheckIfMasterLocalhost -- getOperatorCredentials Failed")
+		return false, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
+	if err != nil {
+		r.logger.Errorf("CheckIfMasterLocalhost -- GetRedisTLSConfig Failed")
 		return false, err
 	}
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, sip := range redisIps {
-		master, err := r.redisClient.GetSlaveOf(sip, rport, password)
+		master, err := r.redisClient.GetSlaveOf(ctx, sip, rport, username, password, tlsConfig)
 		if err != nil {
 			r.logger.Warningf("CheckIfMasterLocalhost -- GetSlaveOf Failed")
 			return false, err
@@ -179,7 +212,7 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 			r.logger.Warningf("CheckIfMasterLocalhost -- Master already available ?? check manually")
 			return false, errors.New("unexpected master state, fix manually")
 		} else {
-			if master == "127.0.0.1" {
+			if redis.IsLoopback(master) {
 				lhmaster++
 			}
 		}
@@ -194,7 +227,7 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 
 // This function will call the sentinel client apis to check with sentinel if the sentinel is in a state
 // to heal the redis system
-func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error) {
 
 	var unhealthyCnt = -1
 
@@ -209,9 +242,15 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 		return unhealthyCnt, errors.New("insufficnet sentinel to reach Quorum")
 	}
 
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rFailover)
+	if err != nil {
+		r.logger.Warningf("CheckSentinelQuorum Error in getting sentinel TLS config")
+		return unhealthyCnt, err
+	}
+
 	unhealthyCnt = 0
 	for _, sip := range sentinels {
-		err = r.redisClient.SentinelCheckQuorum(sip, rFailover.MasterName())
+		err = r.redisClient.SentinelCheckQuorum(ctx, sip, rFailover.MasterName(), tlsConfig)
 		if err != nil {
 			unhealthyCnt += 1
 		} else {
@@ -227,17 +266,21 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 }
 
 // CheckSentinelSlavesNumberInMemory controls that the provided sentinel has only the expected slaves number.
-func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(ctx, sentinel, tlsConfig)
 	if err != nil {
 		return err
 	} else {
 		if rf.Bootstrapping() {
-			if nSlaves != rf.Spec.Redis.Replicas {
+			if nSlaves != rf.RedisPods() {
 				return errors.New("redis slaves in sentinel memory mismatch")
 			}
 		} else {
-			if nSlaves != rf.Spec.Redis.Replicas-1 {
+			if nSlaves != rf.RedisPods()-1 {
 				return errors.New("redis slaves in sentinel memory mismatch")
 			}
 		}
@@ -247,30 +290,42 @@ func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string
 }
 
 // CheckSentinelMonitor controls if the sentinels are monitoring the expected master
-func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel, masterName string, monitor ...string) error {
-	monitorIP := monitor[0]
+func (r *RedisFailoverChecker) CheckSentinelMonitor(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover, monitor ...string) error {
 	monitorPort := ""
 	if len(monitor) > 1 {
 		monitorPort = monitor[1]
 	}
-	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(sentinel, masterName)
+	monitorIP, err := getRedisAddress(r.k8sService, rf, monitor[0])
 	if err != nil {
 		return err
 	}
-	if actualMonitorIP != monitorIP || (monitorPort != "" && monitorPort != actualMonitorPort) {
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(ctx, sentinel, rf.MasterName(), tlsConfig)
+	if err != nil {
+		return err
+	}
+	if !redis.SameAddress(actualMonitorIP, monitorIP) || (monitorPort != "" && monitorPort != actualMonitorPort) {
 		return fmt.Errorf("sentinel monitoring %s:%s instead %s:%s", actualMonitorIP, actualMonitorPort, monitorIP, monitorPort)
 	}
 	return nil
 }
 
 // GetMasterIP connects to all redis and returns the master of the redis failover
-func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetMasterIP(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return "", err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return "", err
 	}
@@ -278,7 +333,7 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 	masters := []string{}
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(ctx, rip, rport, username, password, tlsConfig)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -295,7 +350,7 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 }
 
 // GetNumberMasters returns the number of redis nodes that are working as a master
-func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) GetNumberMasters(ctx context.Context, rf *redisfailoverv1.RedisFailover) (int, error) {
 	nMasters := 0
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
@@ -303,15 +358,21 @@ func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailove
 		return nMasters, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
 	if err != nil {
 		r.logger.Errorf("Error getting password: %s", err.Error())
 		return nMasters, err
 	}
 
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		r.logger.Errorf("Error getting TLS config: %s", err.Error())
+		return nMasters, err
+	}
+
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(ctx, rip, rport, username, password, tlsConfig)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -341,7 +402,7 @@ func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover)
 // GetSentinelsIPs returns the IPs of the Sentinel nodes
 func (r *RedisFailoverChecker) GetSentinelsIPs(rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	sentinels := []string{}
-	rps, err := r.k8sService.GetDeploymentPods(rf.Namespace, GetSentinelName(rf))
+	rps, err := r.getSentinelPods(rf)
 	if err != nil {
 		return nil, err
 	}
@@ -375,14 +436,19 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 }
 
 // GetRedisesSlavesPods returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+func (r *RedisFailoverChecker) GetRedisesSlavesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return nil, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return redises, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return redises, err
 	}
@@ -390,7 +456,7 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(ctx, rp.Status.PodIP, rport, username, password, tlsConfig)
 			if err != nil {
 				return []string{}, err
 			}
@@ -403,13 +469,18 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 }
 
 // GetRedisesMasterPod returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error) {
 	rps, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetRedisName(rFailover))
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
+	if err != nil {
+		return "", err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
 	if err != nil {
 		return "", err
 	}
@@ -417,7 +488,7 @@ func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.Re
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(ctx, rp.Status.PodIP, rport, username, password, tlsConfig)
 			if err != nil {
 				return "", err
 			}
@@ -465,14 +536,116 @@ func (r *RedisFailoverChecker) GetRedisRevisionHash(podName string, rFailover *r
 }
 
 // CheckRedisSlavesReady returns true if the slave is ready (sync, connected, etc)
-func (r *RedisFailoverChecker) CheckRedisSlavesReady(ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+func (r *RedisFailoverChecker) CheckRedisSlavesReady(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
+	if err != nil {
+		return false, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
 	if err != nil {
 		return false, err
 	}
 
 	port := getRedisPort(rFailover.Spec.Redis.Port)
-	return r.redisClient.SlaveIsReady(ip, port, password)
+	return r.redisClient.SlaveIsReady(ctx, ip, port, username, password, tlsConfig)
+}
+
+// GetRedisLastSave returns the unix time of the last RDB file successfully written by the redis
+func (r *RedisFailoverChecker) GetRedisLastSave(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (int64, error) {
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
+	if err != nil {
+		return 0, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
+	if err != nil {
+		return 0, err
+	}
+
+	port := getRedisPort(rFailover.Spec.Redis.Port)
+	return r.redisClient.GetLastSave(ctx, ip, port, username, password, tlsConfig)
+}
+
+// GetRedisReplicaPriority returns the replica-priority of the redis, 0 when it is never promoted
+func (r *RedisFailoverChecker) GetRedisReplicaPriority(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (string, error) {
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
+	if err != nil {
+		return "", err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
+	if err != nil {
+		return "", err
+	}
+
+	port := getRedisPort(rFailover.Spec.Redis.Port)
+	priority, err := r.redisClient.GetReplicaPriority(ctx, ip, port, username, password, tlsConfig)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(priority), nil
+}
+
+// GetMastersIPs returns the IPs of the redis nodes that are working as a master
+func (r *RedisFailoverChecker) GetMastersIPs(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	rips, err := r.GetRedisesIPs(rf)
+	if err != nil {
+		return nil, err
+	}
+
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	masters := []string{}
+	rport := getRedisPort(rf.Spec.Redis.Port)
+	for _, rip := range rips {
+		master, err := r.redisClient.IsMaster(ctx, rip, rport, username, password, tlsConfig)
+		if err != nil {
+			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
+			continue
+		}
+		if master {
+			masters = append(masters, rip)
+		}
+	}
+	return masters, nil
+}
+
+// GetSentinelMonitor returns the IP of the master monitored by the sentinel
+func (r *RedisFailoverChecker) GetSentinelMonitor(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) (string, error) {
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return "", err
+	}
+	address, _, err := r.redisClient.GetSentinelMonitor(ctx, sentinel, rf.MasterName(), tlsConfig)
+	if err != nil {
+		return "", err
+	}
+	return getRedisIP(r.k8sService, rf, address)
+}
+
+// GetRedisReplicationInfo returns the replication id and offset of the redis
+func (r *RedisFailoverChecker) GetRedisReplicationInfo(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (redis.ReplicationInfo, error) {
+	username, password, err := getOperatorCredentials(r.k8sService, rFailover)
+	if err != nil {
+		return redis.ReplicationInfo{}, err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rFailover)
+	if err != nil {
+		return redis.ReplicationInfo{}, err
+	}
+
+	port := getRedisPort(rFailover.Spec.Redis.Port)
+	return r.redisClient.GetReplicationInfo(ctx, ip, port, username, password, tlsConfig)
 }
 
 // IsRedisRunning returns true if all the pods are Running
@@ -483,10 +656,19 @@ func (r *RedisFailoverChecker) IsRedisRunning(rFailover *redisfailoverv1.RedisFa
 
 // IsSentinelRunning returns true if all the pods are Running
 func (r *RedisFailoverChecker) IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool {
-	dp, err := r.k8sService.GetDeploymentPods(rFailover.Namespace, GetSentinelName(rFailover))
+	dp, err := r.getSentinelPods(rFailover)
 	return err == nil && len(dp.Items) > int(rFailover.Spec.Sentinel.Replicas-1) && AreAllRunning(dp, int(rFailover.Spec.Sentinel.Replicas))
 }
 
+// getSentinelPods returns the pods of the sentinel workload requested. The pods of the workload the
+// sentinels are moved from have the same labels, so they are returned too while they are moved.
+func (r *RedisFailoverChecker) getSentinelPods(rf *redisfailoverv1.RedisFailover) (*corev1.PodList, error) {
+	if rf.SentinelStatefulSet() {
+		return r.k8sService.GetStatefulSetPods(rf.Namespace, GetSentinelName(rf))
+	}
+	return r.k8sService.GetDeploymentPods(rf.Namespace, GetSentinelName(rf))
+}
+
 // IsClusterRunning returns true if all the pods in the given redisfailover are Running
 func (r *RedisFailoverChecker) IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool {
 	return r.IsSentinelRunning(rFailover) && r.IsRedisRunning(rFailover)
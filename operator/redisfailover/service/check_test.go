@@ -1,6 +1,8 @@
 package service_test
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"testing"
 	"time"
@@ -18,6 +20,7 @@ import (
 	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
 	mRedisService "github.com/freshworks/redis-operator/mocks/service/redis"
 	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
 )
 
 func generateRF(args ...bool) *redisfailoverv1.RedisFailover {
@@ -182,7 +185,7 @@ func TestCheckAllSlavesFromMasterGetStatefulSetError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -206,11 +209,11 @@ func TestCheckAllSlavesFromMasterGetSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "", "0", "").Once().Return("", errors.New(""))
+	mr.On("GetSlaveOf", mock.Anything, "", "0", "", "", (*tls.Config)(nil)).Once().Return("", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -234,11 +237,11 @@ func TestCheckAllSlavesFromMasterDifferentMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("0.0.0.0", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -262,14 +265,98 @@ func TestCheckAllSlavesFromMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("1.1.1.1", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
+func TestCheckAllSlavesFromMasterHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"},
+				Status:     corev1.PodStatus{PodIP: "0.0.0.0", Phase: corev1.PodRunning},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"},
+				Status:     corev1.PodStatus{PodIP: "1.1.1.1", Phase: corev1.PodRunning},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return("", nil)
+	mr.On("GetSlaveOf", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return("rfr-test-0.rfr-test.testns.svc", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
+func TestCheckAllSlavesFromMasterIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{Status: corev1.PodStatus{PodIP: "fd00::1", Phase: corev1.PodRunning}},
+			{Status: corev1.PodStatus{PodIP: "fd00::2", Phase: corev1.PodRunning}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "fd00::1", "0", "", "", (*tls.Config)(nil)).Once().Return("", nil)
+	mr.On("GetSlaveOf", mock.Anything, "fd00::2", "0", "", "", (*tls.Config)(nil)).Once().Return("fd00:0:0:0:0:0:0:1", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "fd00::1", rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
+func TestCheckIfMasterLocalhostIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{Status: corev1.PodStatus{PodIP: "fd00::1", Phase: corev1.PodRunning}},
+			{Status: corev1.PodStatus{PodIP: "fd00::2", Phase: corev1.PodRunning}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "fd00::1", "0", "", "", (*tls.Config)(nil)).Once().Return("::1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "fd00::2", "0", "", "", (*tls.Config)(nil)).Once().Return("127.0.0.1", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	localhost, err := checker.CheckIfMasterLocalhost(context.TODO(), rf)
+	assert.NoError(err)
+	assert.True(localhost)
+}
+
 func TestCheckSentinelNumberInMemoryGetDeploymentPodsError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -277,11 +364,11 @@ func TestCheckSentinelNumberInMemoryGetDeploymentPodsError(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(0), errors.New("expected error"))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -292,11 +379,11 @@ func TestCheckSentinelNumberInMemoryGetNumberSentinelInMemoryError(t *testing.T)
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -307,11 +394,11 @@ func TestCheckSentinelNumberInMemoryNumberMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -322,11 +409,28 @@ func TestCheckSentinelNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
+	assert.NoError(err)
+}
+
+func TestCheckSentinelNumberInMemoryDuringMigration(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.WorkloadType = redisfailoverv1.SentinelWorkloadStatefulSet
+	rf.Status.SentinelMigration = &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment, Sentinels: 2}
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(5), nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
@@ -337,11 +441,11 @@ func TestCheckSentinelSlavesNumberInMemoryGetNumberSentinelSlavesInMemoryError(t
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -352,11 +456,11 @@ func TestCheckSentinelSlavesNumberInMemoryReplicasMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -368,89 +472,179 @@ func TestCheckSentinelSlavesNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorGetSentinelMonitorError(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("", "", errors.New(""))
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("", "", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("2.2.2.2", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("2.2.2.2", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitor(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.NoError(err)
 }
 
+func TestCheckSentinelMonitorHostname(t *testing.T) {
+	tests := []struct {
+		name      string
+		monitor   string
+		expectErr bool
+	}{
+		{
+			name:    "Sentinel monitoring the pod of the master",
+			monitor: "rfr-test-0.rfr-test.testns.svc",
+		},
+		{
+			name:      "Sentinel monitoring another pod",
+			monitor:   "rfr-test-1.rfr-test.testns.svc",
+			expectErr: true,
+		},
+		{
+			name:      "Sentinel monitoring the IP of the master",
+			monitor:   "1.1.1.1",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"}, Status: corev1.PodStatus{PodIP: "1.1.1.1"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"}, Status: corev1.PodStatus{PodIP: "2.2.2.2"}},
+				},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+			mr := &mRedisService.Client{}
+			mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return(test.monitor, "6379", nil)
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+			err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
+			if test.expectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestGetSentinelMonitorHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"}, Status: corev1.PodStatus{PodIP: "1.1.1.1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"}, Status: corev1.PodStatus{PodIP: "2.2.2.2"}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("rfr-test-1.rfr-test.testns.svc", "6379", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	ip, err := checker.GetSentinelMonitor(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	assert.Equal("2.2.2.2", ip)
+}
+
 func TestCheckSentinelMonitorWithPort(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "1.1.1.1", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6379")
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorWithPortMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "0.0.0.0", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "0.0.0.0", "6379")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorWithPortIPMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0", "mymaster").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "mymaster", (*tls.Config)(nil)).Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "mymaster", "1.1.1.1", "6380")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6380")
 	assert.Error(err)
 }
 
@@ -465,7 +659,7 @@ func TestGetMasterIPGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -488,11 +682,11 @@ func TestGetMasterIPIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(false, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -521,12 +715,12 @@ func TestGetMasterIPMultipleMastersError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -555,12 +749,12 @@ func TestGetMasterIP(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	master, err := checker.GetMasterIP(rf)
+	master, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal("0.0.0.0", master, "the master should be the expected")
 }
@@ -576,7 +770,7 @@ func TestGetNumberMastersGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -599,11 +793,11 @@ func TestGetNumberMastersIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -632,16 +826,58 @@ func TestGetNumberMasters(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(1, masterNumber, "the master number should be ok")
 }
 
+func TestGetMastersIPs(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "2.2.2.2",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "2.2.2.2", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	masters, err := checker.GetMastersIPs(context.TODO(), rf)
+	assert.NoError(err)
+	assert.Equal([]string{"0.0.0.0", "2.2.2.2"}, masters)
+}
+
 func TestGetNumberMastersTwo(t *testing.T) {
 	assert := assert.New(t)
 
@@ -667,12 +903,12 @@ func TestGetNumberMastersTwo(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(2, masterNumber, "the master number should be ok")
 }
@@ -772,21 +1008,21 @@ func TestGetRedisPodsNames(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-	master, err := checker.GetRedisesMasterPod(rf)
+	master, err := checker.GetRedisesMasterPod(context.TODO(), rf)
 
 	assert.NoError(err)
 
 	assert.Equal(master, "master")
 
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(true, nil)
 
-	namePods, err := checker.GetRedisesSlavesPods(rf)
+	namePods, err := checker.GetRedisesSlavesPods(context.TODO(), rf)
 
 	assert.NoError(err)
 
@@ -975,3 +1211,83 @@ func TestClusterRunning(t *testing.T) {
 	assert.False(checker.IsClusterRunning(rf))
 
 }
+
+func TestGetSentinelsIPsFromStatefulSet(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.WorkloadType = redisfailoverv1.SentinelWorkloadStatefulSet
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodPending,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	sentinels, err := checker.GetSentinelsIPs(rf)
+	assert.NoError(err)
+	assert.Equal([]string{"0.0.0.0"}, sentinels)
+	ms.AssertExpectations(t)
+}
+
+func TestObserve(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	redisPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"}, Status: corev1.PodStatus{PodIP: "0.0.0.0", Phase: corev1.PodRunning}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"}, Status: corev1.PodStatus{PodIP: "0.0.0.1", Phase: corev1.PodRunning}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-2"}, Status: corev1.PodStatus{PodIP: "0.0.0.2", Phase: corev1.PodPending}},
+		},
+	}
+	sentinelPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{Status: corev1.PodStatus{PodIP: "1.1.1.1", Phase: corev1.PodRunning}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(redisPods, nil)
+	ms.On("GetDeploymentPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(sentinelPods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetReplicationInfo", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(redis.ReplicationInfo{Role: "master"}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, "0.0.0.1", "0", "", "", (*tls.Config)(nil)).Once().Return(redis.ReplicationInfo{}, errors.New("i/o timeout"))
+	mr.On("GetSentinelMonitor", mock.Anything, "1.1.1.1", mock.Anything, (*tls.Config)(nil)).Once().Return("0.0.0.0", "0", nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(1), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", (*tls.Config)(nil)).Once().Return(int32(2), nil)
+	mr.On("SentinelCheckQuorum", mock.Anything, "1.1.1.1", mock.Anything, (*tls.Config)(nil)).Once().Return(nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	obs, err := checker.Observe(context.TODO(), rf)
+	assert.NoError(err)
+	// Every pod is listed, only the running ones are called
+	assert.Len(obs.RedisPods, 3)
+	assert.Equal([]string{"0.0.0.0", "0.0.0.1"}, obs.RedisesIPs())
+	assert.Equal([]string{"0.0.0.0"}, obs.MastersIPs())
+	if replica, ok := obs.Redis("0.0.0.1"); assert.True(ok) {
+		assert.Error(replica.Err)
+	}
+	assert.Equal([]rfservice.SentinelObservation{{IP: "1.1.1.1", MonitorAddress: "0.0.0.0", MonitorPort: "0", Sentinels: 1, Slaves: 2}}, obs.Sentinels)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
@@ -17,6 +17,9 @@ type RedisFailoverClient interface {
 	EnsureSentinelService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureSentinelConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureSentinelDeployment(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelHeadlessService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelStatefulSet(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelMigration(rFailover *redisfailoverv1.RedisFailover) error
 	EnsureRedisStatefulset(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureRedisService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureRedisMasterService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
@@ -24,6 +27,10 @@ type RedisFailoverClient interface {
 	EnsureRedisShutdownConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureRedisReadinessConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureRedisConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisUsersSecret(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureBackupAgentSecret(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisPasswordRotation(rFailover *redisfailoverv1.RedisFailover) (string, error)
+	FinishRedisPasswordRotation(rFailover *redisfailoverv1.RedisFailover) error
 	EnsureNotPresentRedisService(rFailover *redisfailoverv1.RedisFailover) error
 }
 
@@ -97,6 +104,28 @@ func (r *RedisFailoverKubeClient) EnsureSentinelDeployment(rf *redisfailoverv1.R
 	return err
 }
 
+// EnsureSentinelHeadlessService makes sure the headless service of the sentinel statefulset exists
+func (r *RedisFailoverKubeClient) EnsureSentinelHeadlessService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	svc := generateSentinelHeadlessService(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateService(rf.Namespace, svc)
+	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
+	return err
+}
+
+// EnsureSentinelStatefulSet makes sure the sentinel statefulset exists in the desired state
+func (r *RedisFailoverKubeClient) EnsureSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	if !rf.Spec.Sentinel.DisablePodDisruptionBudget {
+		if err := r.ensurePodDisruptionBudget(rf, sentinelName, sentinelRoleName, labels, ownerRefs); err != nil {
+			return err
+		}
+	}
+	ss := generateSentinelStatefulSet(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
+
+	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
+	return err
+}
+
 // EnsureRedisStatefulset makes sure the redis statefulset exists in the desired state
 func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	if !rf.Spec.Redis.DisablePodDisruptionBudget {
@@ -119,13 +148,44 @@ func (r *RedisFailoverKubeClient) EnsureRedisConfigMap(rf *redisfailoverv1.Redis
 		return err
 	}
 
-	cm := generateRedisConfigMap(rf, labels, ownerRefs, password)
+	users, err := getRedisUsers(r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+
+	cm := generateRedisConfigMap(rf, labels, ownerRefs, password, users)
 	err = r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
 
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
 }
 
+// EnsureRedisUsersSecret makes sure the secret with the passwords of the users created by the operator exists.
+// The passwords are generated along with the secret and kept as they are afterwards.
+func (r *RedisFailoverKubeClient) EnsureRedisUsersSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	secret, err := generateRedisUsersSecret(rf, labels, ownerRefs)
+	if err != nil {
+		return err
+	}
+	err = r.K8SService.CreateIfNotExistsSecret(rf.Namespace, secret)
+
+	r.setEnsureOperationMetrics(secret.Namespace, secret.Name, "Secret", rf.Name, err)
+	return err
+}
+
+// EnsureBackupAgentSecret makes sure the secret with the token the backup agents accept the requests of the
+// operator with exists. The token is generated along with the secret and kept as it is afterwards.
+func (r *RedisFailoverKubeClient) EnsureBackupAgentSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	secret, err := generateBackupAgentSecret(rf, labels, ownerRefs)
+	if err != nil {
+		return err
+	}
+	err = r.K8SService.CreateIfNotExistsSecret(rf.Namespace, secret)
+
+	r.setEnsureOperationMetrics(secret.Namespace, secret.Name, "Secret", rf.Name, err)
+	return err
+}
+
 // EnsureRedisShutdownConfigMap makes sure the redis configmap with shutdown script exists
 func (r *RedisFailoverKubeClient) EnsureRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	if rf.Spec.Redis.ShutdownConfigMap != "" {
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	"github.com/freshworks/redis-operator/operator/redisfailover/util"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+// RedisClusterShard is a shard of a redis cluster with its pods, ordered by name
+type RedisClusterShard struct {
+	Index int
+	Pods  []corev1.Pod
+}
+
+// RedisClusterClient has the methods a Redis cluster controller needs to talk with K8s
+type RedisClusterClient interface {
+	EnsureClusterConfigMap(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureClusterService(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureClusterShard(rc *redisfailoverv1.RedisCluster, shard int, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	GetClusterShards(rc *redisfailoverv1.RedisCluster) ([]RedisClusterShard, error)
+	DeleteClusterShard(rc *redisfailoverv1.RedisCluster, shard int) error
+}
+
+// RedisClusterKubeClient implements the required methods to talk with kubernetes
+type RedisClusterKubeClient struct {
+	K8SService    k8s.Services
+	logger        log.Logger
+	metricsClient metrics.Recorder
+}
+
+// NewRedisClusterKubeClient creates a new RedisClusterKubeClient
+func NewRedisClusterKubeClient(k8sService k8s.Services, logger log.Logger, metricsClient metrics.Recorder) *RedisClusterKubeClient {
+	return &RedisClusterKubeClient{
+		K8SService:    k8sService,
+		logger:        logger,
+		metricsClient: metricsClient,
+	}
+}
+
+// EnsureClusterConfigMap makes sure the configmap with the redis configuration of the cluster exists
+func (r *RedisClusterKubeClient) EnsureClusterConfigMap(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	password, err := k8s.GetRedisClusterPassword(r.K8SService, rc)
+	if err != nil {
+		return err
+	}
+
+	cm := generateClusterConfigMap(rc, labels, ownerRefs, password)
+	err = r.K8SService.CreateOrUpdateConfigMap(rc.Namespace, cm)
+
+	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rc.Name, err)
+	return err
+}
+
+// EnsureClusterService makes sure the headless service of the cluster exists
+func (r *RedisClusterKubeClient) EnsureClusterService(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	svc := generateClusterService(rc, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateService(rc.Namespace, svc)
+
+	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rc.Name, err)
+	return err
+}
+
+// EnsureClusterShard makes sure the statefulset of the shard, and its pdb when it has replicas, exist in the desired state
+func (r *RedisClusterKubeClient) EnsureClusterShard(rc *redisfailoverv1.RedisCluster, shard int, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	if rc.Spec.ReplicasPerShard > 0 {
+		// Only one redis of the shard can be disrupted at a time
+		pdbLabels := util.MergeLabels(labels, generateClusterShardLabels(rc, shard))
+		pdb := generatePodDisruptionBudget(GetRedisClusterShardName(rc, shard), rc.Namespace, pdbLabels, ownerRefs, intstr.FromInt(int(rc.Spec.ReplicasPerShard)))
+		err := r.K8SService.CreateOrUpdatePodDisruptionBudget(rc.Namespace, pdb)
+		r.setEnsureOperationMetrics(pdb.Namespace, pdb.Name, "PodDisruptionBudget", rc.Name, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	ss := generateClusterShardStatefulSet(rc, shard, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateStatefulSet(rc.Namespace, ss)
+
+	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rc.Name, err)
+	return err
+}
+
+// GetClusterShards returns the shards that have a statefulset, including the ones left over after
+// the number of shards was reduced, with their pods.
+func (r *RedisClusterKubeClient) GetClusterShards(rc *redisfailoverv1.RedisCluster) ([]RedisClusterShard, error) {
+	ssList, err := r.K8SService.ListStatefulSets(rc.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := k8slabels.SelectorFromSet(generateSelectorLabels(clusterRoleName, rc.Name))
+	shards := []RedisClusterShard{}
+	for _, ss := range ssList.Items {
+		if !selector.Matches(k8slabels.Set(ss.Labels)) {
+			continue
+		}
+		index, err := strconv.Atoi(ss.Labels[clusterShardLabelKey])
+		if err != nil {
+			continue
+		}
+		pods, err := r.K8SService.GetStatefulSetPods(rc.Namespace, ss.Name)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+		shards = append(shards, RedisClusterShard{Index: index, Pods: pods.Items})
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Index < shards[j].Index })
+	return shards, nil
+}
+
+// DeleteClusterShard removes the statefulset and the pdb of a shard
+func (r *RedisClusterKubeClient) DeleteClusterShard(rc *redisfailoverv1.RedisCluster, shard int) error {
+	name := GetRedisClusterShardName(rc, shard)
+	if err := r.K8SService.DeletePodDisruptionBudget(rc.Namespace, name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return r.K8SService.DeleteStatefulSet(rc.Namespace, name)
+}
+
+func (r *RedisClusterKubeClient) setEnsureOperationMetrics(objectNamespace string, objectName string, objectKind string, ownerName string, err error) {
+	if nil != err {
+		r.metricsClient.RecordEnsureOperation(objectNamespace, objectName, objectKind, ownerName, metrics.FAIL)
+	}
+	r.metricsClient.RecordEnsureOperation(objectNamespace, objectName, objectKind, ownerName, metrics.SUCCESS)
+}
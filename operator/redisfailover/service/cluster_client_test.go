@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+func generateRC() *redisfailoverv1.RedisCluster {
+	rc := &redisfailoverv1.RedisCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: redisfailoverv1.RedisClusterSpec{
+			Shards:           3,
+			ReplicasPerShard: 1,
+		},
+	}
+	_ = rc.Validate()
+	return rc
+}
+
+func TestEnsureClusterConfigMap(t *testing.T) {
+	tests := []struct {
+		name         string
+		secretPath   string
+		customConfig []string
+		expConfig    string
+	}{
+		{
+			name: "Without password",
+			expConfig: `cluster-enabled yes
+cluster-config-file /data/nodes.conf
+cluster-node-timeout 5000
+port 6379
+dir /data
+tcp-keepalive 60
+save 900 1
+save 300 10
+`,
+		},
+		{
+			name:         "With password and custom config",
+			secretPath:   "redis-auth",
+			customConfig: []string{"maxmemory 1gb"},
+			expConfig: `cluster-enabled yes
+cluster-config-file /data/nodes.conf
+cluster-node-timeout 5000
+port 6379
+dir /data
+tcp-keepalive 60
+save 900 1
+save 300 10
+maxmemory 1gb
+
+masterauth secret
+requirepass secret`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rc := generateRC()
+			rc.Spec.Auth.SecretPath = test.secretPath
+			rc.Spec.Redis.CustomConfig = test.customConfig
+
+			ms := &mK8SService.Services{}
+			if test.secretPath != "" {
+				ms.On("GetSecret", namespace, test.secretPath).Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("secret")}}, nil)
+			}
+			var generated *corev1.ConfigMap
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				generated = args.Get(1).(*corev1.ConfigMap)
+			}).Return(nil)
+
+			client := rfservice.NewRedisClusterKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureClusterConfigMap(rc, nil, nil)
+
+			assert.NoError(err)
+			assert.Equal("rfc-test", generated.Name)
+			assert.Equal(test.expConfig, generated.Data["redis.conf"])
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnsureClusterShard(t *testing.T) {
+	tests := []struct {
+		name             string
+		replicasPerShard int32
+		expPDB           bool
+	}{
+		{
+			name:             "Shard with replicas has a pdb",
+			replicasPerShard: 2,
+			expPDB:           true,
+		},
+		{
+			name: "Shard without replicas",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rc := generateRC()
+			rc.Spec.ReplicasPerShard = test.replicasPerShard
+
+			ms := &mK8SService.Services{}
+			if test.expPDB {
+				ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					pdb := args.Get(1).(*policyv1.PodDisruptionBudget)
+					assert.Equal("rfc-test-1", pdb.Name)
+					assert.Equal(test.replicasPerShard, pdb.Spec.MinAvailable.IntVal)
+				}).Return(nil)
+			}
+			var generated *appsv1.StatefulSet
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				generated = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisClusterKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureClusterShard(rc, 1, nil, nil)
+
+			assert.NoError(err)
+			assert.Equal("rfc-test-1", generated.Name)
+			assert.Equal("rfc-test", generated.Spec.ServiceName)
+			assert.Equal(1+test.replicasPerShard, *generated.Spec.Replicas)
+			assert.Equal("1", generated.Spec.Selector.MatchLabels["redisclusters-shard"])
+			assert.Equal("rediscluster", generated.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+			assert.Equal([]string{"redis-server", "/redis/redis.conf"}, generated.Spec.Template.Spec.Containers[0].Command)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetClusterShards(t *testing.T) {
+	assert := assert.New(t)
+
+	rc := generateRC()
+	shardLabels := func(shard string) map[string]string {
+		return map[string]string{
+			"app.kubernetes.io/name":      name,
+			"app.kubernetes.io/component": "rediscluster",
+			"app.kubernetes.io/part-of":   "redis-failover",
+			"redisclusters-shard":         shard,
+		}
+	}
+	statefulSets := &appsv1.StatefulSetList{
+		Items: []appsv1.StatefulSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfc-test-1", Labels: shardLabels("1")}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test", Labels: map[string]string{"app.kubernetes.io/name": name}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfc-test-0", Labels: shardLabels("0")}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("ListStatefulSets", namespace).Once().Return(statefulSets, nil)
+	ms.On("GetStatefulSetPods", namespace, "rfc-test-0").Once().Return(&corev1.PodList{Items: []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rfc-test-0-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rfc-test-0-0"}},
+	}}, nil)
+	ms.On("GetStatefulSetPods", namespace, "rfc-test-1").Once().Return(&corev1.PodList{}, nil)
+
+	client := rfservice.NewRedisClusterKubeClient(ms, log.Dummy, metrics.Dummy)
+	shards, err := client.GetClusterShards(rc)
+
+	assert.NoError(err)
+	if assert.Len(shards, 2) {
+		assert.Equal(0, shards[0].Index)
+		assert.Equal("rfc-test-0-0", shards[0].Pods[0].Name)
+		assert.Equal("rfc-test-0-1", shards[0].Pods[1].Name)
+		assert.Equal(1, shards[1].Index)
+	}
+	ms.AssertExpectations(t)
+}
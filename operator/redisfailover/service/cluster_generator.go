@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/operator/redisfailover/util"
+)
+
+// Template used to build the configuration of the redis of a cluster, the nodes file is kept
+// with the data so a restarted pod joins the cluster with the same node id.
+const clusterConfigTemplate = `cluster-enabled yes
+cluster-config-file /data/nodes.conf
+cluster-node-timeout {{.NodeTimeout}}
+port {{.Spec.Redis.Port}}
+dir /data
+tcp-keepalive 60
+save 900 1
+save 300 10
+{{- range .Spec.Redis.CustomConfig}}
+{{.}}
+{{- end}}
+`
+
+func generateClusterConfigMap(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference, password string) *corev1.ConfigMap {
+	name := GetRedisClusterName(rc)
+	labels = util.MergeLabels(labels, generateSelectorLabels(clusterRoleName, rc.Name))
+
+	tmpl, err := template.New("rediscluster").Parse(clusterConfigTemplate)
+	if err != nil {
+		panic(err)
+	}
+
+	var tplOutput bytes.Buffer
+	data := struct {
+		*redisfailoverv1.RedisCluster
+		NodeTimeout int
+	}{rc, clusterNodeTimeout}
+	if err := tmpl.Execute(&tplOutput, data); err != nil {
+		panic(err)
+	}
+
+	redisConfigFileContent := tplOutput.String()
+	if password != "" {
+		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s\nrequirepass %s", redisConfigFileContent, password, password)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rc.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Data: map[string]string{
+			redisConfigFileName: redisConfigFileContent,
+		},
+	}
+}
+
+// generateClusterService returns the headless service of all the redis of the cluster. The pods are
+// published before they are ready, so they can be reached while the cluster is bootstrapped.
+func generateClusterService(rc *redisfailoverv1.RedisCluster, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	name := GetRedisClusterName(rc)
+
+	selectorLabels := generateSelectorLabels(clusterRoleName, rc.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rc.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+			Annotations:     rc.Spec.Redis.ServiceAnnotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Port:     rc.Spec.Redis.Port,
+					Protocol: corev1.ProtocolTCP,
+					Name:     "redis",
+				},
+			},
+			Selector: selectorLabels,
+		},
+	}
+}
+
+// generateClusterShardLabels returns the labels selecting the pods of a shard of the cluster
+func generateClusterShardLabels(rc *redisfailoverv1.RedisCluster, shard int) map[string]string {
+	return util.MergeLabels(generateSelectorLabels(clusterRoleName, rc.Name), map[string]string{
+		clusterShardLabelKey: strconv.Itoa(shard),
+	})
+}
+
+// generateClusterShardStatefulSet returns the statefulset running the master and the replicas of a shard
+func generateClusterShardStatefulSet(rc *redisfailoverv1.RedisCluster, shard int, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.StatefulSet {
+	name := GetRedisClusterShardName(rc, shard)
+	replicas := 1 + rc.Spec.ReplicasPerShard
+
+	selectorLabels := generateClusterShardLabels(rc, shard)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	dataVolumeName := redisStorageVolumeName
+	volumes := []corev1.Volume{
+		{
+			Name: clusterConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: GetRedisClusterName(rc),
+					},
+				},
+			},
+		},
+	}
+	switch {
+	case rc.Spec.Redis.Storage.PersistentVolumeClaim != nil:
+		dataVolumeName = rc.Spec.Redis.Storage.PersistentVolumeClaim.Name
+	case rc.Spec.Redis.Storage.EmptyDir != nil:
+		volumes = append(volumes, corev1.Volume{
+			Name: redisStorageVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: rc.Spec.Redis.Storage.EmptyDir,
+			},
+		})
+	default:
+		volumes = append(volumes, corev1.Volume{
+			Name: redisStorageVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	probe := &corev1.Probe{
+		InitialDelaySeconds: graceTime,
+		TimeoutSeconds:      5,
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"sh",
+					"-c",
+					fmt.Sprintf("redis-cli -h 127.0.0.1 -p %v ping | grep PONG", rc.Spec.Redis.Port),
+				},
+			},
+		},
+	}
+
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rc.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName:         GetRedisClusterName(rc),
+			Replicas:            &replicas,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: rc.Spec.Redis.PodAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					Affinity:           getAffinity(rc.Spec.Redis.Affinity, selectorLabels),
+					Tolerations:        rc.Spec.Redis.Tolerations,
+					NodeSelector:       rc.Spec.Redis.NodeSelector,
+					SecurityContext:    getSecurityContext(rc.Spec.Redis.SecurityContext),
+					ImagePullSecrets:   rc.Spec.Redis.ImagePullSecrets,
+					PriorityClassName:  rc.Spec.Redis.PriorityClassName,
+					ServiceAccountName: rc.Spec.Redis.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:            "redis",
+							Image:           rc.Spec.Redis.Image,
+							ImagePullPolicy: pullPolicy(rc.Spec.Redis.ImagePullPolicy),
+							SecurityContext: getContainerSecurityContext(rc.Spec.Redis.ContainerSecurityContext),
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "redis",
+									ContainerPort: rc.Spec.Redis.Port,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      clusterConfigVolumeName,
+									MountPath: "/redis",
+								},
+								{
+									Name:      dataVolumeName,
+									MountPath: "/data",
+								},
+							},
+							Command: []string{
+								"redis-server",
+								fmt.Sprintf("/redis/%s", redisConfigFileName),
+							},
+							Resources:      rc.Spec.Redis.Resources,
+							LivenessProbe:  probe,
+							ReadinessProbe: probe,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	if rc.Spec.Auth.SecretPath != "" {
+		// redis-cli reads the password of the probes from REDISCLI_AUTH
+		ss.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+			{
+				Name: "REDISCLI_AUTH",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: rc.Spec.Auth.SecretPath,
+						},
+						Key: "password",
+					},
+				},
+			},
+		}
+	}
+
+	if rc.Spec.Redis.Storage.PersistentVolumeClaim != nil {
+		pvc := corev1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "PersistentVolumeClaim",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        rc.Spec.Redis.Storage.PersistentVolumeClaim.Name,
+				Labels:      rc.Spec.Redis.Storage.PersistentVolumeClaim.Labels,
+				Annotations: rc.Spec.Redis.Storage.PersistentVolumeClaim.Annotations,
+			},
+			Spec: rc.Spec.Redis.Storage.PersistentVolumeClaim.Spec,
+		}
+		if !rc.Spec.Redis.Storage.KeepAfterDeletion {
+			// Set an owner reference so the persistent volumes are deleted when the RC is
+			pvc.OwnerReferences = ownerRefs
+		}
+		ss.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			pvc,
+		}
+	}
+
+	return ss
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// RedisClusterHeal defines the interface able to read and change the topology of the redis clusters
+type RedisClusterHeal interface {
+	GetClusterNodes(ctx context.Context, ip string, rc *redisfailoverv1.RedisCluster) ([]redis.ClusterNode, error)
+	ClusterMeet(ctx context.Context, ip string, nodeIP string, rc *redisfailoverv1.RedisCluster) error
+	ClusterAddSlots(ctx context.Context, ip string, slots []int, rc *redisfailoverv1.RedisCluster) error
+	ClusterReplicate(ctx context.Context, ip string, masterID string, rc *redisfailoverv1.RedisCluster) error
+	ClusterForget(ctx context.Context, ip string, nodeID string, rc *redisfailoverv1.RedisCluster) error
+	ClusterMigrateSlot(ctx context.Context, sourceIP string, targetIP string, slot int, rc *redisfailoverv1.RedisCluster) error
+}
+
+// RedisClusterHealer is our implementation of RedisClusterHeal interface
+type RedisClusterHealer struct {
+	k8sService  k8s.Services
+	redisClient redis.Client
+	logger      log.Logger
+}
+
+// NewRedisClusterHealer creates an object of the RedisClusterHealer struct
+func NewRedisClusterHealer(k8sService k8s.Services, redisClient redis.Client, logger log.Logger) *RedisClusterHealer {
+	logger = logger.With("service", "redis.clusterhealer")
+	return &RedisClusterHealer{
+		k8sService:  k8sService,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// GetClusterNodes returns the nodes of the cluster as seen by the given redis
+func (r *RedisClusterHealer) GetClusterNodes(ctx context.Context, ip string, rc *redisfailoverv1.RedisCluster) ([]redis.ClusterNode, error) {
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return nil, err
+	}
+	return r.redisClient.GetClusterNodes(ctx, ip, getRedisPort(rc.Spec.Redis.Port), "", password, (*tls.Config)(nil))
+}
+
+// ClusterMeet makes the redis join the cluster the given node is part of
+func (r *RedisClusterHealer) ClusterMeet(ctx context.Context, ip string, nodeIP string, rc *redisfailoverv1.RedisCluster) error {
+	r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Making redis %s meet %s", ip, nodeIP)
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.ClusterMeet(ctx, ip, getRedisPort(rc.Spec.Redis.Port), nodeIP, "", password, (*tls.Config)(nil))
+}
+
+// ClusterAddSlots assigns the given slots to the redis
+func (r *RedisClusterHealer) ClusterAddSlots(ctx context.Context, ip string, slots []int, rc *redisfailoverv1.RedisCluster) error {
+	r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Assigning %d slots to redis %s", len(slots), ip)
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.ClusterAddSlots(ctx, ip, getRedisPort(rc.Spec.Redis.Port), slots, "", password, (*tls.Config)(nil))
+}
+
+// ClusterReplicate makes the redis a replica of the given master
+func (r *RedisClusterHealer) ClusterReplicate(ctx context.Context, ip string, masterID string, rc *redisfailoverv1.RedisCluster) error {
+	r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Making redis %s replica of node %s", ip, masterID)
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.ClusterReplicate(ctx, ip, getRedisPort(rc.Spec.Redis.Port), masterID, "", password, (*tls.Config)(nil))
+}
+
+// ClusterForget removes the node from the ones known by the redis
+func (r *RedisClusterHealer) ClusterForget(ctx context.Context, ip string, nodeID string, rc *redisfailoverv1.RedisCluster) error {
+	r.logger.WithField("rediscluster", rc.ObjectMeta.Name).WithField("namespace", rc.ObjectMeta.Namespace).Infof("Making redis %s forget node %s", ip, nodeID)
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.ClusterForget(ctx, ip, getRedisPort(rc.Spec.Redis.Port), nodeID, "", password, (*tls.Config)(nil))
+}
+
+// ClusterMigrateSlot moves a slot with its keys from the source master to the target one
+func (r *RedisClusterHealer) ClusterMigrateSlot(ctx context.Context, sourceIP string, targetIP string, slot int, rc *redisfailoverv1.RedisCluster) error {
+	password, err := k8s.GetRedisClusterPassword(r.k8sService, rc)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.ClusterMigrateSlot(ctx, sourceIP, targetIP, getRedisPort(rc.Spec.Redis.Port), slot, "", password, (*tls.Config)(nil))
+}
@@ -13,17 +13,42 @@ const (
 	exporterDefaultLimitMemory    = "100Mi"
 )
 
+// variables refering to the sentinel config volume
+const (
+	sentinelConfigWritableVolumeName = "sentinel-config-writable"
+	sentinelConfigStorageSize        = "10Mi"
+)
+
+// variables refering to the backup agent
+const (
+	backupAgentContainerName   = "backup-agent"
+	backupAgentPortName        = "backup-agent"
+	backupAgentCommand         = "/usr/local/bin/redis-backup-agent"
+	backupAgentRDBPath         = "/data/dump.rdb"
+	backupAccessKeyIDKey       = "accessKeyId"
+	backupSecretAccessKeyKey   = "secretAccessKey"
+	backupAgentTokenKey        = "token"
+	backupDefaultRequestCPU    = "10m"
+	backupDefaultRequestMemory = "50Mi"
+	backupDefaultLimitMemory   = "200Mi"
+	restoreContainerName       = "restore"
+	restoreVolumeName          = "restore-source"
+)
+
 const (
 	baseName               = "rf"
 	sentinelName           = "s"
 	sentinelRoleName       = "sentinel"
 	sentinelConfigFileName = "sentinel.conf"
+	sentinelHeadlessSuffix = "headless"
 	redisConfigFileName    = "redis.conf"
 	redisName              = "r"
 	redisMasterName        = "rm"
 	redisSlaveName         = "rs"
 	redisShutdownName      = "r-s"
 	redisReadinessName     = "r-readiness"
+	redisUsersName         = "r-users"
+	redisBackupAgentName   = "r-backup-agent"
 	redisRoleName          = "redis"
 	appLabel               = "redis-failover"
 	hostnameTopologyKey    = "kubernetes.io/hostname"
@@ -34,3 +59,12 @@ const (
 	redisRoleLabelMaster = "master"
 	redisRoleLabelSlave  = "slave"
 )
+
+// variables refering to the redis clusters
+const (
+	clusterName             = "c"
+	clusterRoleName         = "rediscluster"
+	clusterShardLabelKey    = "redisclusters-shard"
+	clusterConfigVolumeName = "redis-cluster-config"
+	clusterNodeTimeout      = 5000
+)
@@ -0,0 +1,50 @@
+package service
+
+// Reasons of the events published on the RedisFailover and RedisCluster objects
+const (
+	// Actions taken by the healer
+	EventReasonMasterPromoted           = "MasterPromoted"
+	EventReasonPromotedOldestPod        = "PromotedOldestPod"
+	EventReasonPromotedHighestOffsetPod = "PromotedHighestOffsetPod"
+	EventReasonReplicaReconfigured      = "ReplicaReconfigured"
+	EventReasonSentinelMonitorUpdated   = "SentinelMonitorUpdated"
+	EventReasonSentinelReset            = "SentinelReset"
+	EventReasonPodDeleted               = "PodDeleted"
+	EventReasonSplitBrainResolved       = "SplitBrainResolved"
+
+	// Custom config redis refused to set at runtime, applied by restarting the pods
+	EventReasonRedisConfigRestartRequired = "RedisConfigRestartRequired"
+
+	// Problems detected by the checker that trigger an action
+	EventReasonNoQuorum           = "NoQuorum"
+	EventReasonMastersOnLocalhost = "MastersOnLocalhost"
+	EventReasonMultipleMasters    = "MultipleMasters"
+	EventReasonNoMaster           = "NoMaster"
+
+	// Progress of a planned switchover
+	EventReasonSwitchoverPending   = "SwitchoverPending"
+	EventReasonSwitchoverStarted   = "SwitchoverStarted"
+	EventReasonSwitchoverSucceeded = "SwitchoverSucceeded"
+	EventReasonSwitchoverFailed    = "SwitchoverFailed"
+
+	// Progress of the removal of redis pods after the replicas were lowered
+	EventReasonScaleDownPending   = "ScaleDownPending"
+	EventReasonScaleDownReleased  = "ScaleDownReleased"
+	EventReasonScaleDownCompleted = "ScaleDownCompleted"
+
+	// Progress of a rotation of the auth password
+	EventReasonPasswordRotationStarted   = "PasswordRotationStarted"
+	EventReasonPasswordRotationCompleted = "PasswordRotationCompleted"
+
+	// Progress of the restore of the data on creation
+	EventReasonRestoreStarted   = "RestoreStarted"
+	EventReasonRestoreSkipped   = "RestoreSkipped"
+	EventReasonRestoreWaiting   = "RestoreWaiting"
+	EventReasonRestoreCompleted = "RestoreCompleted"
+
+	// Changes to the topology of a redis cluster
+	EventReasonClusterNodeForgotten = "ClusterNodeForgotten"
+	EventReasonClusterSlotsAssigned = "ClusterSlotsAssigned"
+	EventReasonClusterSlotsMigrated = "ClusterSlotsMigrated"
+	EventReasonClusterShardRemoved  = "ClusterShardRemoved"
+)
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"text/template"
@@ -15,23 +17,38 @@ import (
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/operator/redisfailover/util"
+	"github.com/freshworks/redis-operator/service/backup"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
 )
 
 const (
 	redisConfigurationVolumeName = "redis-config"
 	// Template used to build the Redis configuration
 	redisConfigTemplate = `slaveof 127.0.0.1 {{.Spec.Redis.Port}}
+{{- if .RedisTLSEnabled}}
+port 0
+tls-port {{.Spec.Redis.Port}}
+tls-cert-file /tls/tls.crt
+tls-key-file /tls/tls.key
+tls-ca-cert-file /tls/ca.crt
+tls-replication yes
+{{- else}}
 port {{.Spec.Redis.Port}}
+{{- end}}
 tcp-keepalive 60
 save 900 1
 save 300 10
-user pinger -@all +ping on >pingpass
 {{- range .Spec.Redis.CustomCommandRenames}}
 rename-command "{{.From}}" "{{.To}}"
 {{- end}}
 `
 
 	sentinelConfigTemplate = `
+{{- if .HostnameMode -}}
+sentinel resolve-hostnames yes
+sentinel announce-hostnames yes
+{{ end -}}
 {{- if .Spec.Sentinel.DisableMyMaster -}}
 sentinel monitor {{.Name}} 127.0.0.1 {{.Spec.Redis.Port}} 2
 sentinel down-after-milliseconds {{.Name}} 1000
@@ -43,13 +60,37 @@ sentinel down-after-milliseconds mymaster 1000
 sentinel failover-timeout mymaster 3000
 sentinel parallel-syncs mymaster 2
 {{- end -}}
+{{- if .SentinelTLSEnabled}}
+port 0
+tls-port 26379
+tls-cert-file /tls/tls.crt
+tls-key-file /tls/tls.key
+tls-ca-cert-file /tls/ca.crt
+{{- if .RedisTLSEnabled}}
+tls-replication yes
+{{- end}}
+{{- end -}}
 `
 
+	redisConfigWritableVolumeName          = "redis-config-writable"
 	redisShutdownConfigurationVolumeName   = "redis-shutdown-config"
 	redisStartupConfigurationVolumeName    = "redis-startup-config"
 	redisReadinessVolumeName               = "redis-readiness-config"
 	redisStorageVolumeName                 = "redis-data"
 	sentinelStartupConfigurationVolumeName = "sentinel-startup-config"
+	redisTLSVolumeName                     = "redis-tls"
+	sentinelTLSVolumeName                  = "sentinel-tls"
+
+	// Where the TLS secrets are mounted. The redis pods also mount the sentinel secret, so the
+	// shutdown script can reach the sentinels.
+	tlsMountPath         = "/tls"
+	sentinelTLSMountPath = "/sentinel-tls"
+
+	redisPingerPasswordEnv = "REDIS_PINGER_PASSWORD"
+
+	// Set on the redis pods with the hash of the custom config only read at startup, so the pods are
+	// restarted when it changes
+	redisConfigHashAnnotation = "redis-failover.freshworks.com/config-hash"
 
 	graceTime = 30
 )
@@ -80,6 +121,42 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 					Protocol:   "TCP",
 				},
 			},
+			IPFamilyPolicy: rf.Spec.IPFamilyPolicy,
+			IPFamilies:     rf.Spec.IPFamilies,
+		},
+	}
+}
+
+// generateSentinelHeadlessService gives a stable name to the pods of the sentinel statefulset
+func generateSentinelHeadlessService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	name := GetSentinelHeadlessName(rf)
+	namespace := rf.Namespace
+
+	sentinelTargetPort := intstr.FromInt(26379)
+	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector:                 selectorLabels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "sentinel",
+					Port:       26379,
+					TargetPort: sentinelTargetPort,
+					Protocol:   "TCP",
+				},
+			},
+			IPFamilyPolicy: rf.Spec.IPFamilyPolicy,
+			IPFamilies:     rf.Spec.IPFamilies,
 		},
 	}
 }
@@ -97,7 +174,7 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 	}
 	annotations := util.MergeLabels(defaultAnnotations, rf.Spec.Redis.ServiceAnnotations)
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -115,9 +192,22 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 					Name:     exporterPortName,
 				},
 			},
-			Selector: selectorLabels,
+			Selector:       selectorLabels,
+			IPFamilyPolicy: rf.Spec.IPFamilyPolicy,
+			IPFamilies:     rf.Spec.IPFamilies,
 		},
 	}
+	if rf.HostnameMode() {
+		// The DNS names of the pods have to resolve before they are ready, a replica syncing from the
+		// master is not ready yet
+		svc.Spec.PublishNotReadyAddresses = true
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Port:     rf.Spec.Redis.Port,
+			Protocol: corev1.ProtocolTCP,
+			Name:     "redis",
+		})
+	}
+	return svc
 }
 
 func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
@@ -148,7 +238,9 @@ func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[st
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
-			Selector: selectorLabels,
+			Selector:       selectorLabels,
+			IPFamilyPolicy: rf.Spec.IPFamilyPolicy,
+			IPFamilies:     rf.Spec.IPFamilies,
 		},
 	}
 }
@@ -181,7 +273,9 @@ func generateRedisSlaveService(rf *redisfailoverv1.RedisFailover, labels map[str
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
-			Selector: selectorLabels,
+			Selector:       selectorLabels,
+			IPFamilyPolicy: rf.Spec.IPFamilyPolicy,
+			IPFamilies:     rf.Spec.IPFamilies,
 		},
 	}
 }
@@ -217,7 +311,7 @@ func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[str
 	}
 }
 
-func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string) *corev1.ConfigMap {
+func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string, users []redisUser) *corev1.ConfigMap {
 	name := GetRedisName(rf)
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
 
@@ -233,6 +327,16 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 
 	redisConfigFileContent := tplOutput.String()
 
+	// The custom config set at runtime is applied by the operator, the one only read at startup
+	// restarts the pods
+	for _, config := range getRestartRequiredConfig(rf) {
+		redisConfigFileContent = fmt.Sprintf("%s%s\n", redisConfigFileContent, config)
+	}
+
+	for _, user := range users {
+		redisConfigFileContent = fmt.Sprintf("%suser %s %s\n", redisConfigFileContent, user.name, strings.Join(user.aclRules(), " "))
+	}
+
 	if password != "" {
 		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s\nrequirepass %s", redisConfigFileContent, password, password)
 	}
@@ -250,6 +354,52 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 	}
 }
 
+func generateRedisUsersSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) (*corev1.Secret, error) {
+	name := GetRedisUsersSecretName(rf)
+	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
+
+	data := map[string][]byte{}
+	for _, user := range []string{redisfailoverv1.PingerUser, redisfailoverv1.OperatorUser} {
+		password, err := generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		data[user] = password
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+func generateBackupAgentSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) (*corev1.Secret, error) {
+	name := GetBackupAgentSecretName(rf)
+	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
+
+	token, err := generatePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{backupAgentTokenKey: token},
+	}, nil
+}
+
 func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.ConfigMap {
 	name := GetRedisShutdownConfigMapName(rf)
 	port := rf.Spec.Redis.Port
@@ -257,17 +407,31 @@ func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels ma
 	rfName := strings.ReplaceAll(strings.ToUpper(rf.Name), "-", "_")
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
-	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name %[3]v | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
-if [ "$master" = "$(hostname -i)" ]; then
-redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} SENTINEL failover %[3]v
+	redisTLSArgs := ""
+	if rf.RedisTLSEnabled() {
+		redisTLSArgs = getRedisCliTLSArgs(tlsMountPath)
+	}
+	sentinelTLSArgs := ""
+	if rf.SentinelTLSEnabled() {
+		sentinelTLSArgs = getRedisCliTLSArgs(sentinelTLSMountPath)
+	}
+	// The pod has an IP of each family on dual-stack clusters, and the sentinels give the master by the
+	// DNS name of its pod in hostname mode
+	isMaster := `[ -n "$master" ] && hostname -i | tr ' ' '\n' | grep -qxF "$master"`
+	if rf.HostnameMode() {
+		isMaster = fmt.Sprintf(`[ "$master" = "%s" ]`, GetRedisPodHostname(rf, "$(hostname)"))
+	}
+	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL}%[5]v --csv SENTINEL get-master-addr-by-name %[3]v | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
+if %[6]v; then
+redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL}%[5]v SENTINEL failover %[3]v
 sleep 31
 fi
-cmd="redis-cli -p %[2]v"
+cmd="redis-cli -p %[2]v%[4]v"
 if [ ! -z "${REDIS_PASSWORD}" ]; then
 	export REDISCLI_AUTH=${REDIS_PASSWORD}
 fi
 save_command="${cmd} save"
-eval $save_command`, rfName, port, rf.MasterName())
+eval $save_command`, rfName, port, rf.MasterName(), redisTLSArgs, sentinelTLSArgs, isMaster)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -288,13 +452,17 @@ func generateRedisReadinessConfigMap(rf *redisfailoverv1.RedisFailover, labels m
 	namespace := rf.Namespace
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
+	tlsArgs := ""
+	if rf.RedisTLSEnabled() {
+		tlsArgs = getRedisCliTLSArgs(tlsMountPath)
+	}
 	readinessContent := fmt.Sprintf(`ROLE="role"
 ROLE_MASTER="role:master"
 ROLE_SLAVE="role:slave"
 IN_SYNC="master_sync_in_progress:1"
-NO_MASTER="master_host:127.0.0.1"
+NO_MASTER="-w -e master_host:127.0.0.1 -e master_host:::1"
 
-cmd="redis-cli -p %[1]v"
+cmd="redis-cli -p %[1]v%[2]v"
 if [ ! -z "${REDIS_PASSWORD}" ]; then
 	export REDISCLI_AUTH=${REDIS_PASSWORD}
 fi
@@ -327,7 +495,7 @@ case $role in
 		*)
 				echo "unexpected"
 				exit 1
-esac`, port)
+esac`, port, tlsArgs)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -354,6 +522,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 	volumeMounts := getRedisVolumeMounts(rf)
 	volumes := getRedisVolumes(rf)
 	terminationGracePeriodSeconds := getTerminationGracePeriodSeconds(rf)
+	replicas := rf.RedisPods()
 
 	ss := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -365,7 +534,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 		},
 		Spec: appsv1.StatefulSetSpec{
 			ServiceName: name,
-			Replicas:    &rf.Spec.Redis.Replicas,
+			Replicas:    &replicas,
 			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
 				Type: appsv1.OnDeleteStatefulSetStrategyType,
 			},
@@ -376,7 +545,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: rf.Spec.Redis.PodAnnotations,
+					Annotations: getRedisPodAnnotations(rf),
 				},
 				Spec: corev1.PodSpec{
 					Affinity:                      getAffinity(rf.Spec.Redis.Affinity, labels),
@@ -448,6 +617,10 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 	if rf.Spec.Redis.CustomLivenessProbe != nil {
 		ss.Spec.Template.Spec.Containers[0].LivenessProbe = rf.Spec.Redis.CustomLivenessProbe
 	} else {
+		tlsArgs := ""
+		if rf.RedisTLSEnabled() {
+			tlsArgs = getRedisCliTLSArgs(tlsMountPath)
+		}
 		ss.Spec.Template.Spec.Containers[0].LivenessProbe = &corev1.Probe{
 			InitialDelaySeconds: graceTime,
 			TimeoutSeconds:      5,
@@ -458,7 +631,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 					Command: []string{
 						"sh",
 						"-c",
-						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v --user pinger --pass pingpass --no-auth-warning ping | grep PONG", rf.Spec.Redis.Port),
+						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v%[2]v --user %[3]v --pass ${%[4]v} --no-auth-warning ping | grep PONG", rf.Spec.Redis.Port, tlsArgs, redisfailoverv1.PingerUser, redisPingerPasswordEnv),
 					},
 				},
 			},
@@ -500,6 +673,18 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 		ss.Spec.Template.Spec.Containers = append(ss.Spec.Template.Spec.Containers, exporter)
 	}
 
+	if rf.BackupsEnabled() {
+		ss.Spec.Template.Spec.Containers = append(ss.Spec.Template.Spec.Containers, createBackupAgentContainer(rf))
+	}
+
+	if rf.Spec.Redis.ConfigRewrite {
+		ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, createRedisConfigCopyContainer(rf))
+	}
+
+	if rf.RestoreApplied() {
+		ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, createRestoreContainer(rf))
+	}
+
 	if rf.Spec.Redis.InitContainers != nil {
 		initContainers := getInitContainersWithRedisEnv(rf)
 		ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, initContainers...)
@@ -512,23 +697,22 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 
 	redisEnv := getRedisEnv(rf)
 	ss.Spec.Template.Spec.Containers[0].Env = append(ss.Spec.Template.Spec.Containers[0].Env, redisEnv...)
+	ss.Spec.Template.Spec.Containers[0].Env = append(ss.Spec.Template.Spec.Containers[0].Env, getRedisPingerPasswordEnv(rf))
+	if rf.HostnameMode() {
+		ss.Spec.Template.Spec.Containers[0].Env = append(ss.Spec.Template.Spec.Containers[0].Env, getPodNameEnv())
+	}
 
 	return ss
 }
 
 func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.Deployment {
 	name := GetSentinelName(rf)
-	configMapName := GetSentinelName(rf)
 	namespace := rf.Namespace
 
-	sentinelCommand := getSentinelCommand(rf)
 	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
 	labels = util.MergeLabels(labels, selectorLabels)
 
-	volumeMounts := getSentinelVolumeMounts(rf)
-	volumes := getSentinelVolumes(rf, configMapName)
-
-	sd := &appsv1.Deployment{
+	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -540,83 +724,158 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
-					Annotations: rf.Spec.Sentinel.PodAnnotations,
+			Template: generateSentinelPodTemplate(rf, labels),
+		},
+	}
+}
+
+// generateSentinelStatefulSet runs the sentinels with their config on a volume claimed for every pod.
+// Its pods have the same labels as the ones of the deployment, so the sentinels of both are seen
+// while they are moved from one to the other.
+func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.StatefulSet {
+	name := GetSentinelName(rf)
+	namespace := rf.Namespace
+
+	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	storageSize := resource.MustParse(sentinelConfigStorageSize)
+	if rf.Spec.Sentinel.ConfigStorage.Size != nil {
+		storageSize = *rf.Spec.Sentinel.ConfigStorage.Size
+	}
+
+	template := generateSentinelPodTemplate(rf, labels)
+	// The config is only copied on the first start, the sentinel rewrites it with its id and the
+	// sentinels it knows afterwards
+	template.Spec.InitContainers[0].Command = []string{
+		"sh",
+		"-c",
+		fmt.Sprintf("[ -f /redis-writable/%[1]s ] || cp /redis/%[1]s /redis-writable/%[1]s", sentinelConfigFileName),
+	}
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName:         GetSentinelHeadlessName(rf),
+			Replicas:            &rf.Spec.Sentinel.Replicas,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			Template: template,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: sentinelConfigWritableVolumeName,
+						// Set an owner reference so the volumes are deleted with the RF
+						OwnerReferences: ownerRefs,
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						StorageClassName: rf.Spec.Sentinel.ConfigStorage.StorageClassName,
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: storageSize,
+							},
+						},
+					},
 				},
-				Spec: corev1.PodSpec{
-					Affinity:                  getAffinity(rf.Spec.Sentinel.Affinity, labels),
-					Tolerations:               rf.Spec.Sentinel.Tolerations,
-					TopologySpreadConstraints: rf.Spec.Sentinel.TopologySpreadConstraints,
-					NodeSelector:              rf.Spec.Sentinel.NodeSelector,
-					SecurityContext:           getSecurityContext(rf.Spec.Sentinel.SecurityContext),
-					HostNetwork:               rf.Spec.Sentinel.HostNetwork,
-					DNSPolicy:                 getDnsPolicy(rf.Spec.Sentinel.DNSPolicy),
-					ImagePullSecrets:          rf.Spec.Sentinel.ImagePullSecrets,
-					PriorityClassName:         rf.Spec.Sentinel.PriorityClassName,
-					ServiceAccountName:        rf.Spec.Sentinel.ServiceAccountName,
-					InitContainers: []corev1.Container{
+			},
+		},
+	}
+}
+
+func generateSentinelPodTemplate(rf *redisfailoverv1.RedisFailover, labels map[string]string) corev1.PodTemplateSpec {
+	configMapName := GetSentinelName(rf)
+	sentinelCommand := getSentinelCommand(rf)
+	volumeMounts := getSentinelVolumeMounts(rf)
+	volumes := getSentinelVolumes(rf, configMapName)
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      labels,
+			Annotations: rf.Spec.Sentinel.PodAnnotations,
+		},
+		Spec: corev1.PodSpec{
+			Affinity:                  getAffinity(rf.Spec.Sentinel.Affinity, labels),
+			Tolerations:               rf.Spec.Sentinel.Tolerations,
+			TopologySpreadConstraints: rf.Spec.Sentinel.TopologySpreadConstraints,
+			NodeSelector:              rf.Spec.Sentinel.NodeSelector,
+			SecurityContext:           getSecurityContext(rf.Spec.Sentinel.SecurityContext),
+			HostNetwork:               rf.Spec.Sentinel.HostNetwork,
+			DNSPolicy:                 getDnsPolicy(rf.Spec.Sentinel.DNSPolicy),
+			ImagePullSecrets:          rf.Spec.Sentinel.ImagePullSecrets,
+			PriorityClassName:         rf.Spec.Sentinel.PriorityClassName,
+			ServiceAccountName:        rf.Spec.Sentinel.ServiceAccountName,
+			InitContainers: []corev1.Container{
+				{
+					Name:            "sentinel-config-copy",
+					Image:           rf.Spec.Sentinel.Image,
+					ImagePullPolicy: pullPolicy(rf.Spec.Sentinel.ImagePullPolicy),
+					SecurityContext: getContainerSecurityContext(rf.Spec.Sentinel.ConfigCopy.ContainerSecurityContext),
+					VolumeMounts: []corev1.VolumeMount{
 						{
-							Name:            "sentinel-config-copy",
-							Image:           rf.Spec.Sentinel.Image,
-							ImagePullPolicy: pullPolicy(rf.Spec.Sentinel.ImagePullPolicy),
-							SecurityContext: getContainerSecurityContext(rf.Spec.Sentinel.ConfigCopy.ContainerSecurityContext),
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "sentinel-config",
-									MountPath: "/redis",
-								},
-								{
-									Name:      "sentinel-config-writable",
-									MountPath: "/redis-writable",
-								},
-							},
-							Command: []string{
-								"cp",
-								fmt.Sprintf("/redis/%s", sentinelConfigFileName),
-								fmt.Sprintf("/redis-writable/%s", sentinelConfigFileName),
-							},
-							Resources: corev1.ResourceRequirements{
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("10m"),
-									corev1.ResourceMemory: resource.MustParse("32Mi"),
-								},
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("10m"),
-									corev1.ResourceMemory: resource.MustParse("32Mi"),
-								},
-							},
+							Name:      "sentinel-config",
+							MountPath: "/redis",
+						},
+						{
+							Name:      sentinelConfigWritableVolumeName,
+							MountPath: "/redis-writable",
 						},
 					},
-					Containers: []corev1.Container{
+					Command: []string{
+						"cp",
+						fmt.Sprintf("/redis/%s", sentinelConfigFileName),
+						fmt.Sprintf("/redis-writable/%s", sentinelConfigFileName),
+					},
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("10m"),
+							corev1.ResourceMemory: resource.MustParse("32Mi"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("10m"),
+							corev1.ResourceMemory: resource.MustParse("32Mi"),
+						},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            "sentinel",
+					Image:           rf.Spec.Sentinel.Image,
+					ImagePullPolicy: pullPolicy(rf.Spec.Sentinel.ImagePullPolicy),
+					SecurityContext: getContainerSecurityContext(rf.Spec.Sentinel.ContainerSecurityContext),
+					Ports: []corev1.ContainerPort{
 						{
-							Name:            "sentinel",
-							Image:           rf.Spec.Sentinel.Image,
-							ImagePullPolicy: pullPolicy(rf.Spec.Sentinel.ImagePullPolicy),
-							SecurityContext: getContainerSecurityContext(rf.Spec.Sentinel.ContainerSecurityContext),
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "sentinel",
-									ContainerPort: 26379,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							VolumeMounts: volumeMounts,
-							Command:      sentinelCommand,
-							Resources:    rf.Spec.Sentinel.Resources,
+							Name:          "sentinel",
+							ContainerPort: 26379,
+							Protocol:      corev1.ProtocolTCP,
 						},
 					},
-					Volumes: volumes,
+					VolumeMounts: volumeMounts,
+					Command:      sentinelCommand,
+					Resources:    rf.Spec.Sentinel.Resources,
 				},
 			},
+			Volumes: volumes,
 		},
 	}
 
+	tlsArgs := ""
+	if rf.SentinelTLSEnabled() {
+		tlsArgs = getRedisCliTLSArgs(tlsMountPath)
+	}
+
 	if rf.Spec.Sentinel.CustomLivenessProbe != nil {
-		sd.Spec.Template.Spec.Containers[0].LivenessProbe = rf.Spec.Sentinel.CustomLivenessProbe
+		template.Spec.Containers[0].LivenessProbe = rf.Spec.Sentinel.CustomLivenessProbe
 	} else {
-		sd.Spec.Template.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+		template.Spec.Containers[0].LivenessProbe = &corev1.Probe{
 			InitialDelaySeconds: graceTime,
 			TimeoutSeconds:      5,
 			ProbeHandler: corev1.ProbeHandler{
@@ -624,7 +883,7 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 					Command: []string{
 						"sh",
 						"-c",
-						"redis-cli -h $(hostname) -p 26379 ping",
+						fmt.Sprintf("redis-cli -h $(hostname) -p 26379%s ping", tlsArgs),
 					},
 				},
 			},
@@ -632,10 +891,10 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 	}
 
 	if rf.Spec.Sentinel.CustomReadinessProbe != nil {
-		sd.Spec.Template.Spec.Containers[0].ReadinessProbe = rf.Spec.Sentinel.CustomReadinessProbe
+		template.Spec.Containers[0].ReadinessProbe = rf.Spec.Sentinel.CustomReadinessProbe
 	} else {
-		probeCommand := fmt.Sprintf("redis-cli -h $(hostname) -p 26379 sentinel get-master-addr-by-name %s | head -n 1 | grep -vq '127.0.0.1'", rf.MasterName())
-		sd.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		probeCommand := fmt.Sprintf("redis-cli -h $(hostname) -p 26379%s sentinel get-master-addr-by-name %s | head -n 1 | grep -vq '127.0.0.1'", tlsArgs, rf.MasterName())
+		template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
 			InitialDelaySeconds: graceTime,
 			TimeoutSeconds:      5,
 			ProbeHandler: corev1.ProbeHandler{
@@ -651,9 +910,9 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 	}
 
 	if rf.Spec.Sentinel.CustomStartupProbe != nil {
-		sd.Spec.Template.Spec.Containers[0].StartupProbe = rf.Spec.Sentinel.CustomStartupProbe
+		template.Spec.Containers[0].StartupProbe = rf.Spec.Sentinel.CustomStartupProbe
 	} else if rf.Spec.Sentinel.StartupConfigMap != "" {
-		sd.Spec.Template.Spec.Containers[0].StartupProbe = &corev1.Probe{
+		template.Spec.Containers[0].StartupProbe = &corev1.Probe{
 			InitialDelaySeconds: graceTime,
 			TimeoutSeconds:      5,
 			FailureThreshold:    6,
@@ -668,17 +927,17 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 
 	if rf.Spec.Sentinel.Exporter.Enabled {
 		exporter := createSentinelExporterContainer(rf)
-		sd.Spec.Template.Spec.Containers = append(sd.Spec.Template.Spec.Containers, exporter)
+		template.Spec.Containers = append(template.Spec.Containers, exporter)
 	}
 	if rf.Spec.Sentinel.InitContainers != nil {
-		sd.Spec.Template.Spec.InitContainers = append(sd.Spec.Template.Spec.InitContainers, rf.Spec.Sentinel.InitContainers...)
+		template.Spec.InitContainers = append(template.Spec.InitContainers, rf.Spec.Sentinel.InitContainers...)
 	}
 
 	if rf.Spec.Sentinel.ExtraContainers != nil {
-		sd.Spec.Template.Spec.Containers = append(sd.Spec.Template.Spec.Containers, rf.Spec.Sentinel.ExtraContainers...)
+		template.Spec.Containers = append(template.Spec.Containers, rf.Spec.Sentinel.ExtraContainers...)
 	}
 
-	return sd
+	return template
 }
 
 func generatePodDisruptionBudget(name string, namespace string, labels map[string]string, ownerRefs []metav1.OwnerReference, minAvailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
@@ -742,9 +1001,137 @@ func createRedisExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Cont
 	redisEnv := getRedisEnv(rf)
 	container.Env = append(container.Env, redisEnv...)
 
+	if rf.RedisTLSEnabled() {
+		container.Env = append(container.Env, getExporterTLSEnv()...)
+		container.VolumeMounts = append(container.VolumeMounts, getTLSVolumeMount(redisTLSVolumeName, tlsMountPath))
+	}
+
 	return container
 }
 
+// createBackupAgentContainer returns the agent uploading the RDB file of the redis when the operator takes a backup.
+// It shares the data volume with redis and reads the storage credentials from the secret given in the spec.
+func createBackupAgentContainer(rf *redisfailoverv1.RedisFailover) corev1.Container {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(backupDefaultRequestCPU),
+			corev1.ResourceMemory: resource.MustParse(backupDefaultRequestMemory),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(backupDefaultLimitMemory),
+		},
+	}
+	if rf.Spec.Backup.Resources != nil {
+		resources = *rf.Spec.Backup.Resources
+	}
+
+	s3 := rf.Spec.Backup.Storage.S3
+	return corev1.Container{
+		Name:            backupAgentContainerName,
+		Image:           rf.Spec.Backup.Image,
+		ImagePullPolicy: pullPolicy(rf.Spec.Backup.ImagePullPolicy),
+		Command: []string{
+			backupAgentCommand,
+			"--rdb-path=" + backupAgentRDBPath,
+			"--s3-endpoint=" + s3.Endpoint,
+			"--s3-region=" + s3.Region,
+			"--s3-bucket=" + s3.Bucket,
+			"--s3-prefix=" + s3.Prefix,
+			"--key-prefix=" + GetBackupKeyPrefix(rf),
+		},
+		Env: append(getBackupCredentialsEnv(s3), corev1.EnvVar{
+			Name: backup.AgentTokenEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: GetBackupAgentSecretName(rf)},
+					Key:                  backupAgentTokenKey,
+				},
+			},
+		}),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          backupAgentPortName,
+				ContainerPort: backup.AgentPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      getRedisDataVolumeName(rf),
+				MountPath: "/data",
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: getContainerSecurityContext(rf.Spec.Redis.ContainerSecurityContext),
+		Resources:       resources,
+	}
+}
+
+// createRestoreContainer returns the init container writing the RDB file to restore before redis starts.
+// It runs in every pod but only restores in the first one, which is promoted once it is up.
+func createRestoreContainer(rf *redisfailoverv1.RedisFailover) corev1.Container {
+	command := []string{
+		backupAgentCommand,
+		"--restore-from=" + rf.Status.Restore.Source,
+		"--restore-pod=" + GetRedisRestorePodName(rf),
+		"--rdb-path=" + backupAgentRDBPath,
+	}
+	env := []corev1.EnvVar{getPodNameEnv()}
+	if rf.BackupsEnabled() {
+		s3 := rf.Spec.Backup.Storage.S3
+		command = append(command, "--s3-endpoint="+s3.Endpoint, "--s3-region="+s3.Region)
+		env = append(env, getBackupCredentialsEnv(s3)...)
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      getRedisDataVolumeName(rf),
+			MountPath: "/data",
+		},
+	}
+	if rf.Spec.Restore.PersistentVolumeClaim != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      restoreVolumeName,
+			MountPath: redisfailoverv1.RestoreVolumeMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return corev1.Container{
+		Name:            restoreContainerName,
+		Image:           rf.Spec.Restore.Image,
+		ImagePullPolicy: pullPolicy(rf.Spec.Restore.ImagePullPolicy),
+		Command:         command,
+		Env:             env,
+		VolumeMounts:    volumeMounts,
+		SecurityContext: getContainerSecurityContext(rf.Spec.Redis.ContainerSecurityContext),
+	}
+}
+
+// getBackupCredentialsEnv returns the credentials of the storage, read by the backup agent from the environment.
+func getBackupCredentialsEnv(s3 *redisfailoverv1.S3BackupStorage) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: "AWS_ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: s3.CredentialsSecret},
+					Key:                  backupAccessKeyIDKey,
+				},
+			},
+		},
+		{
+			Name: "AWS_SECRET_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: s3.CredentialsSecret},
+					Key:                  backupSecretAccessKeyKey,
+				},
+			},
+		},
+	}
+}
+
 func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Container {
 	resources := exporterDefaultResourceRequirements
 	if rf.Spec.Sentinel.Exporter.Resources != nil {
@@ -768,7 +1155,7 @@ func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.C
 			Value: fmt.Sprintf("0.0.0.0:%[1]v", sentinelExporterPort),
 		}, corev1.EnvVar{
 			Name:  "REDIS_ADDR",
-			Value: "redis://127.0.0.1:26379",
+			Value: fmt.Sprintf("%s://127.0.0.1:26379", getRedisURLScheme(rf.SentinelTLSEnabled())),
 		},
 		),
 		Ports: []corev1.ContainerPort{
@@ -781,6 +1168,11 @@ func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.C
 		Resources: resources,
 	}
 
+	if rf.SentinelTLSEnabled() {
+		container.Env = append(container.Env, getExporterTLSEnv()...)
+		container.VolumeMounts = append(container.VolumeMounts, getTLSVolumeMount(sentinelTLSVolumeName, tlsMountPath))
+	}
+
 	return container
 }
 
@@ -882,6 +1274,13 @@ func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMoun
 		},
 	}
 
+	if rf.Spec.Redis.ConfigRewrite {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      redisConfigWritableVolumeName,
+			MountPath: "/redis-writable",
+		})
+	}
+
 	if rf.Spec.Redis.StartupConfigMap != "" {
 		startupVolumeMount := corev1.VolumeMount{
 			Name:      redisStartupConfigurationVolumeName,
@@ -891,6 +1290,14 @@ func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMoun
 		volumeMounts = append(volumeMounts, startupVolumeMount)
 	}
 
+	if rf.RedisTLSEnabled() {
+		volumeMounts = append(volumeMounts, getTLSVolumeMount(redisTLSVolumeName, tlsMountPath))
+	}
+
+	if rf.SentinelTLSEnabled() {
+		volumeMounts = append(volumeMounts, getTLSVolumeMount(sentinelTLSVolumeName, sentinelTLSMountPath))
+	}
+
 	if rf.Spec.Redis.ExtraVolumeMounts != nil {
 		volumeMounts = append(volumeMounts, rf.Spec.Redis.ExtraVolumeMounts...)
 	}
@@ -901,7 +1308,7 @@ func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMoun
 func getSentinelVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
 		{
-			Name:      "sentinel-config-writable",
+			Name:      sentinelConfigWritableVolumeName,
 			MountPath: "/redis",
 		},
 	}
@@ -913,6 +1320,9 @@ func getSentinelVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeM
 		}
 		volumeMounts = append(volumeMounts, startupVolumeMount)
 	}
+	if rf.SentinelTLSEnabled() {
+		volumeMounts = append(volumeMounts, getTLSVolumeMount(sentinelTLSVolumeName, tlsMountPath))
+	}
 	if rf.Spec.Sentinel.ExtraVolumeMounts != nil {
 		volumeMounts = append(volumeMounts, rf.Spec.Sentinel.ExtraVolumeMounts...)
 	}
@@ -961,6 +1371,15 @@ func getRedisVolumes(rf *redisfailoverv1.RedisFailover) []corev1.Volume {
 		},
 	}
 
+	if rf.Spec.Redis.ConfigRewrite {
+		volumes = append(volumes, corev1.Volume{
+			Name: redisConfigWritableVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
 	if rf.Spec.Redis.StartupConfigMap != "" {
 		startupVolumeName := rf.Spec.Redis.StartupConfigMap
 		startupVolume := corev1.Volume{
@@ -977,10 +1396,30 @@ func getRedisVolumes(rf *redisfailoverv1.RedisFailover) []corev1.Volume {
 		volumes = append(volumes, startupVolume)
 	}
 
+	if rf.RedisTLSEnabled() {
+		volumes = append(volumes, getTLSVolume(redisTLSVolumeName, rf.Spec.Redis.TLS.SecretName))
+	}
+
+	if rf.SentinelTLSEnabled() {
+		volumes = append(volumes, getTLSVolume(sentinelTLSVolumeName, rf.SentinelTLS().SecretName))
+	}
+
 	if rf.Spec.Redis.ExtraVolumes != nil {
 		volumes = append(volumes, rf.Spec.Redis.ExtraVolumes...)
 	}
 
+	if rf.RestoreApplied() && rf.Spec.Restore.PersistentVolumeClaim != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: restoreVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: rf.Spec.Restore.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+	}
+
 	dataVolume := getRedisDataVolume(rf)
 	if dataVolume != nil {
 		volumes = append(volumes, *dataVolume)
@@ -1003,12 +1442,15 @@ func getSentinelVolumes(rf *redisfailoverv1.RedisFailover, configMapName string)
 				},
 			},
 		},
-		{
-			Name: "sentinel-config-writable",
+	}
+	// The statefulset claims a volume for it instead
+	if !rf.SentinelStatefulSet() {
+		volumes = append(volumes, corev1.Volume{
+			Name: sentinelConfigWritableVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
-		},
+		})
 	}
 
 	if rf.Spec.Sentinel.StartupConfigMap != "" {
@@ -1027,6 +1469,10 @@ func getSentinelVolumes(rf *redisfailoverv1.RedisFailover, configMapName string)
 		volumes = append(volumes, startupVolume)
 	}
 
+	if rf.SentinelTLSEnabled() {
+		volumes = append(volumes, getTLSVolume(sentinelTLSVolumeName, rf.SentinelTLS().SecretName))
+	}
+
 	if rf.Spec.Sentinel.ExtraVolumes != nil {
 		volumes = append(volumes, rf.Spec.Sentinel.ExtraVolumes...)
 	}
@@ -1034,6 +1480,61 @@ func getSentinelVolumes(rf *redisfailoverv1.RedisFailover, configMapName string)
 	return volumes
 }
 
+func getTLSVolume(name, secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
+
+func getTLSVolumeMount(name, mountPath string) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      name,
+		MountPath: mountPath,
+		ReadOnly:  true,
+	}
+}
+
+// getRedisCliTLSArgs returns the redis-cli flags to connect over TLS with the certificate mounted in the given path
+func getRedisCliTLSArgs(mountPath string) string {
+	return fmt.Sprintf(" --tls --cert %[1]s/%[2]s --key %[1]s/%[3]s --cacert %[1]s/%[4]s", mountPath, k8s.TLSCertKey, k8s.TLSKeyKey, k8s.TLSCAKey)
+}
+
+func getRedisURLScheme(tls bool) string {
+	if tls {
+		return "rediss"
+	}
+	return "redis"
+}
+
+// getExporterTLSEnv configures the exporters to connect over TLS. They connect through the loopback
+// interface, that the certificates don't usually hold, so the server certificate isn't verified.
+// The traffic never leaves the pod.
+func getExporterTLSEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "REDIS_EXPORTER_TLS_CLIENT_CERT_FILE",
+			Value: fmt.Sprintf("%s/%s", tlsMountPath, k8s.TLSCertKey),
+		},
+		{
+			Name:  "REDIS_EXPORTER_TLS_CLIENT_KEY_FILE",
+			Value: fmt.Sprintf("%s/%s", tlsMountPath, k8s.TLSKeyKey),
+		},
+		{
+			Name:  "REDIS_EXPORTER_TLS_CA_CERT_FILE",
+			Value: fmt.Sprintf("%s/%s", tlsMountPath, k8s.TLSCAKey),
+		},
+		{
+			Name:  "REDIS_EXPORTER_SKIP_TLS_VERIFICATION",
+			Value: "true",
+		},
+	}
+}
+
 func getRedisDataVolume(rf *redisfailoverv1.RedisFailover) *corev1.Volume {
 	// This will find the volumed desired by the user. If no volume defined
 	// an EmptyDir will be used by default
@@ -1072,9 +1573,79 @@ func getRedisCommand(rf *redisfailoverv1.RedisFailover) []string {
 	if len(rf.Spec.Redis.Command) > 0 {
 		return rf.Spec.Redis.Command
 	}
-	return []string{
+	configPath := "/redis"
+	if rf.Spec.Redis.ConfigRewrite {
+		configPath = "/redis-writable"
+	}
+	command := []string{
 		"redis-server",
-		fmt.Sprintf("/redis/%s", redisConfigFileName),
+		fmt.Sprintf("%s/%s", configPath, redisConfigFileName),
+	}
+	if rf.HostnameMode() {
+		// The replicas are known to the master and the sentinels by the DNS name of their pod
+		command = append(command, "--replica-announce-ip", GetRedisPodHostname(rf, "$(POD_NAME)"))
+	}
+	return command
+}
+
+// getRestartRequiredConfig returns the lines of the custom config redis only reads at startup
+func getRestartRequiredConfig(rf *redisfailoverv1.RedisFailover) []string {
+	configs := []string{}
+	for _, config := range rf.Spec.Redis.CustomConfig {
+		if redis.RestartRequired(config) {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+// getRedisPodAnnotations adds the hash of the custom config only read at startup to the pod annotations,
+// so changing it restarts the pods
+func getRedisPodAnnotations(rf *redisfailoverv1.RedisFailover) map[string]string {
+	configs := getRestartRequiredConfig(rf)
+	if len(configs) == 0 {
+		return rf.Spec.Redis.PodAnnotations
+	}
+	sum := sha256.Sum256([]byte(strings.Join(configs, "\n")))
+	return util.MergeAnnotations(rf.Spec.Redis.PodAnnotations, map[string]string{
+		redisConfigHashAnnotation: hex.EncodeToString(sum[:]),
+	})
+}
+
+// createRedisConfigCopyContainer copies the config to a volume redis can rewrite. The copy is made when
+// the pod is created, so the rewrites survive a restart of the redis container, and a pod created
+// again starts from the config map.
+func createRedisConfigCopyContainer(rf *redisfailoverv1.RedisFailover) corev1.Container {
+	return corev1.Container{
+		Name:            "redis-config-copy",
+		Image:           rf.Spec.Redis.Image,
+		ImagePullPolicy: pullPolicy(rf.Spec.Redis.ImagePullPolicy),
+		SecurityContext: getContainerSecurityContext(rf.Spec.Redis.ContainerSecurityContext),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      redisConfigurationVolumeName,
+				MountPath: "/redis",
+			},
+			{
+				Name:      redisConfigWritableVolumeName,
+				MountPath: "/redis-writable",
+			},
+		},
+		Command: []string{
+			"cp",
+			fmt.Sprintf("/redis/%s", redisConfigFileName),
+			fmt.Sprintf("/redis-writable/%s", redisConfigFileName),
+		},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
 	}
 }
 
@@ -1127,12 +1698,39 @@ func getContainersWithRedisEnv(cs []corev1.Container, e []corev1.EnvVar) []corev
 	return containers
 }
 
+// getPodNameEnv exposes the name of the pod to its container
+func getPodNameEnv() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "POD_NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}
+}
+
+// getRedisPingerPasswordEnv exposes the password of the pinger user to the liveness probe
+func getRedisPingerPasswordEnv(rf *redisfailoverv1.RedisFailover) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: redisPingerPasswordEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: GetRedisUsersSecretName(rf),
+				},
+				Key: redisfailoverv1.PingerUser,
+			},
+		},
+	}
+}
+
 func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 	var env []corev1.EnvVar
 
 	env = append(env, corev1.EnvVar{
 		Name:  "REDIS_ADDR",
-		Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", rf.Spec.Redis.Port),
+		Value: fmt.Sprintf("%s://127.0.0.1:%v", getRedisURLScheme(rf.RedisTLSEnabled()), rf.Spec.Redis.Port),
 	})
 
 	env = append(env, corev1.EnvVar{
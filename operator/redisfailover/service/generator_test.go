@@ -1,7 +1,10 @@
 package service_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -548,6 +551,7 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 	tests := []struct {
 		name             string
 		givenCommands    []string
+		configRewrite    bool
 		expectedCommands []string
 	}{
 		{
@@ -558,6 +562,15 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 				"/redis/redis.conf",
 			},
 		},
+		{
+			name:          "Config rewritten",
+			givenCommands: []string{},
+			configRewrite: true,
+			expectedCommands: []string{
+				"redis-server",
+				"/redis-writable/redis.conf",
+			},
+		},
 		{
 			name: "Given commands should be used in redis container",
 			givenCommands: []string{
@@ -577,6 +590,7 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 		// Generate a default RedisFailover and attaching the required storage
 		rf := generateRF()
 		rf.Spec.Redis.Command = test.givenCommands
+		rf.Spec.Redis.ConfigRewrite = test.configRewrite
 
 		gotCommands := []string{}
 
@@ -648,9 +662,15 @@ func TestSentinelDeploymentCommands(t *testing.T) {
 }
 
 func TestRedisStatefulSetPodAnnotations(t *testing.T) {
+	hash := func(config string) string {
+		sum := sha256.Sum256([]byte(config))
+		return hex.EncodeToString(sum[:])
+	}
+
 	tests := []struct {
 		name                   string
 		givenPodAnnotations    map[string]string
+		givenCustomConfig      []string
 		expectedPodAnnotations map[string]string
 	}{
 		{
@@ -669,6 +689,22 @@ func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 				"path/to/annotation": "here",
 			},
 		},
+		{
+			name:                   "Custom config set at runtime",
+			givenCustomConfig:      []string{"maxmemory 1gb"},
+			expectedPodAnnotations: nil,
+		},
+		{
+			name: "Custom config only read at startup",
+			givenPodAnnotations: map[string]string{
+				"some": "annotation",
+			},
+			givenCustomConfig: []string{"maxmemory 1gb", "databases 32", "io-threads 4"},
+			expectedPodAnnotations: map[string]string{
+				"some": "annotation",
+				"redis-failover.freshworks.com/config-hash": hash("databases 32\nio-threads 4"),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -677,6 +713,7 @@ func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 		// Generate a default RedisFailover and attaching the required annotations
 		rf := generateRF()
 		rf.Spec.Redis.PodAnnotations = test.givenPodAnnotations
+		rf.Spec.Redis.CustomConfig = test.givenCustomConfig
 
 		gotPodAnnotations := map[string]string{}
 
@@ -2195,6 +2232,17 @@ func TestRedisEnv(t *testing.T) {
 					Name:  "REDIS_USER",
 					Value: "default",
 				},
+				{
+					Name: "REDIS_PINGER_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "rfr-users-test",
+							},
+							Key: "pinger",
+						},
+					},
+				},
 			},
 		},
 		{
@@ -2224,6 +2272,17 @@ func TestRedisEnv(t *testing.T) {
 						},
 					},
 				},
+				{
+					Name: "REDIS_PINGER_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "rfr-users-test",
+							},
+							Key: "pinger",
+						},
+					},
+				},
 			},
 		},
 	}
@@ -2378,7 +2437,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 						Command: []string{
 							"sh",
 							"-c",
-							"redis-cli -h 127.0.0.1 -p ${REDIS_PORT} --user pinger --pass pingpass --no-auth-warning ping | grep PONG",
+							"redis-cli -h 127.0.0.1 -p ${REDIS_PORT} --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
 						},
 					},
 				},
@@ -2393,7 +2452,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 						Command: []string{
 							"sh",
 							"-c",
-							"redis-cli -h 127.0.0.1 -p ${REDIS_PORT} --user pinger --pass pingpass --no-auth-warning ping | grep PONG",
+							"redis-cli -h 127.0.0.1 -p ${REDIS_PORT} --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
 						},
 					},
 				},
@@ -2412,7 +2471,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 						Command: []string{
 							"sh",
 							"-c",
-							"redis-cli -h $(hostname) -p 6379 --user pinger --pass pingpass --no-auth-warning ping | grep PONG",
+							"redis-cli -h $(hostname) -p 6379 --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
 						},
 					},
 				},
@@ -2826,7 +2885,7 @@ func TestDisableMyMaster(t *testing.T) {
 			},
 			expectedRedisShutdownSHScriptConfigMap: &corev1.ConfigMap{
 				Data: map[string]string{
-					"shutdown.sh": "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ \"$master\" = \"$(hostname -i)\" ]; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover mymaster\nsleep 31\nfi\ncmd=\"redis-cli -p 0\"\nif [ ! -z \"${REDIS_PASSWORD}\" ]; then\n\texport REDISCLI_AUTH=${REDIS_PASSWORD}\nfi\nsave_command=\"${cmd} save\"\neval $save_command",
+					"shutdown.sh": "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ -n \"$master\" ] && hostname -i | tr ' ' '\\n' | grep -qxF \"$master\"; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover mymaster\nsleep 31\nfi\ncmd=\"redis-cli -p 0\"\nif [ ! -z \"${REDIS_PASSWORD}\" ]; then\n\texport REDISCLI_AUTH=${REDIS_PASSWORD}\nfi\nsave_command=\"${cmd} save\"\neval $save_command",
 				},
 			},
 		},
@@ -2853,7 +2912,7 @@ func TestDisableMyMaster(t *testing.T) {
 			},
 			expectedRedisShutdownSHScriptConfigMap: &corev1.ConfigMap{
 				Data: map[string]string{
-					"shutdown.sh": "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name test | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ \"$master\" = \"$(hostname -i)\" ]; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover test\nsleep 31\nfi\ncmd=\"redis-cli -p 0\"\nif [ ! -z \"${REDIS_PASSWORD}\" ]; then\n\texport REDISCLI_AUTH=${REDIS_PASSWORD}\nfi\nsave_command=\"${cmd} save\"\neval $save_command",
+					"shutdown.sh": "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name test | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ -n \"$master\" ] && hostname -i | tr ' ' '\\n' | grep -qxF \"$master\"; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover test\nsleep 31\nfi\ncmd=\"redis-cli -p 0\"\nif [ ! -z \"${REDIS_PASSWORD}\" ]; then\n\texport REDISCLI_AUTH=${REDIS_PASSWORD}\nfi\nsave_command=\"${cmd} save\"\neval $save_command",
 				},
 			},
 		},
@@ -2898,3 +2957,638 @@ func TestDisableMyMaster(t *testing.T) {
 		assert.Equal(test.expectedRedisShutdownSHScriptConfigMap.Data, generatedRedisConfigMap.Data)
 	}
 }
+
+func TestTLS(t *testing.T) {
+	tlsArgs := " --tls --cert /tls/tls.crt --key /tls/tls.key --cacert /tls/ca.crt"
+	sentinelTLSArgs := " --tls --cert /sentinel-tls/tls.crt --key /sentinel-tls/tls.key --cacert /sentinel-tls/ca.crt"
+	tests := []struct {
+		name                       string
+		redisTLS                   *redisfailoverv1.TLSSettings
+		sentinelTLS                *redisfailoverv1.TLSSettings
+		expectedRedisConf          string
+		expectedSentinelConf       string
+		expectedShutdown           string
+		expectedRedisLiveness      string
+		expectedSentinelLiveness   string
+		expectedRedisVolumes       []string
+		expectedSentinelSecret     string
+		expectedRedisExporterAddr  string
+		expectedSentinelExportAddr string
+	}{
+		{
+			name:                       "TLS disabled",
+			expectedRedisConf:          "slaveof 127.0.0.1 6379\nport 6379\ntcp-keepalive 60",
+			expectedSentinelConf:       "sentinel monitor mymaster 127.0.0.1 6379 2\nsentinel down-after-milliseconds mymaster 1000\nsentinel failover-timeout mymaster 3000\nsentinel parallel-syncs mymaster 2",
+			expectedShutdown:           "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ -n \"$master\" ] && hostname -i | tr ' ' '\\n' | grep -qxF \"$master\"; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL} SENTINEL failover mymaster\nsleep 31\nfi\ncmd=\"redis-cli -p 6379\"",
+			expectedRedisLiveness:      "redis-cli -h $(hostname) -p 6379 --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
+			expectedSentinelLiveness:   "redis-cli -h $(hostname) -p 26379 ping",
+			expectedRedisVolumes:       []string{},
+			expectedRedisExporterAddr:  "redis://127.0.0.1:6379",
+			expectedSentinelExportAddr: "redis://127.0.0.1:26379",
+		},
+		{
+			name:                       "TLS on redis and sentinels",
+			redisTLS:                   &redisfailoverv1.TLSSettings{SecretName: "redis-tls-secret"},
+			expectedRedisConf:          "slaveof 127.0.0.1 6379\nport 0\ntls-port 6379\ntls-cert-file /tls/tls.crt\ntls-key-file /tls/tls.key\ntls-ca-cert-file /tls/ca.crt\ntls-replication yes\ntcp-keepalive 60",
+			expectedSentinelConf:       "sentinel monitor mymaster 127.0.0.1 6379 2\nsentinel down-after-milliseconds mymaster 1000\nsentinel failover-timeout mymaster 3000\nsentinel parallel-syncs mymaster 2\nport 0\ntls-port 26379\ntls-cert-file /tls/tls.crt\ntls-key-file /tls/tls.key\ntls-ca-cert-file /tls/ca.crt\ntls-replication yes",
+			expectedShutdown:           "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL}" + sentinelTLSArgs + " --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ -n \"$master\" ] && hostname -i | tr ' ' '\\n' | grep -qxF \"$master\"; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL}" + sentinelTLSArgs + " SENTINEL failover mymaster\nsleep 31\nfi\ncmd=\"redis-cli -p 6379" + tlsArgs + "\"",
+			expectedRedisLiveness:      "redis-cli -h $(hostname) -p 6379" + tlsArgs + " --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
+			expectedSentinelLiveness:   "redis-cli -h $(hostname) -p 26379" + tlsArgs + " ping",
+			expectedRedisVolumes:       []string{"redis-tls-secret", "redis-tls-secret"},
+			expectedSentinelSecret:     "redis-tls-secret",
+			expectedRedisExporterAddr:  "rediss://127.0.0.1:6379",
+			expectedSentinelExportAddr: "rediss://127.0.0.1:26379",
+		},
+		{
+			name:                       "TLS on sentinels only",
+			sentinelTLS:                &redisfailoverv1.TLSSettings{SecretName: "sentinel-tls-secret"},
+			expectedRedisConf:          "slaveof 127.0.0.1 6379\nport 6379\ntcp-keepalive 60",
+			expectedSentinelConf:       "sentinel monitor mymaster 127.0.0.1 6379 2\nsentinel down-after-milliseconds mymaster 1000\nsentinel failover-timeout mymaster 3000\nsentinel parallel-syncs mymaster 2\nport 0\ntls-port 26379\ntls-cert-file /tls/tls.crt\ntls-key-file /tls/tls.key\ntls-ca-cert-file /tls/ca.crt",
+			expectedShutdown:           "master=$(redis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL}" + sentinelTLSArgs + " --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\\\"' |cut -d' ' -f1)\nif [ -n \"$master\" ] && hostname -i | tr ' ' '\\n' | grep -qxF \"$master\"; then\nredis-cli -h ${RFS_TEST_SERVICE_HOST} -p ${RFS_TEST_SERVICE_PORT_SENTINEL}" + sentinelTLSArgs + " SENTINEL failover mymaster\nsleep 31\nfi\ncmd=\"redis-cli -p 6379\"",
+			expectedRedisLiveness:      "redis-cli -h $(hostname) -p 6379 --user pinger --pass ${REDIS_PINGER_PASSWORD} --no-auth-warning ping | grep PONG",
+			expectedSentinelLiveness:   "redis-cli -h $(hostname) -p 26379" + tlsArgs + " ping",
+			expectedRedisVolumes:       []string{"sentinel-tls-secret"},
+			expectedSentinelSecret:     "sentinel-tls-secret",
+			expectedRedisExporterAddr:  "redis://127.0.0.1:6379",
+			expectedSentinelExportAddr: "rediss://127.0.0.1:26379",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Port = 6379
+			rf.Spec.Redis.TLS = test.redisTLS
+			rf.Spec.Sentinel.TLS = test.sentinelTLS
+			rf.Spec.Redis.Exporter.Enabled = true
+			rf.Spec.Sentinel.Exporter.Enabled = true
+
+			var redisConf, sentinelConf, shutdown string
+			var ss *appsv1.StatefulSet
+			var sd *appsv1.Deployment
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Return(&corev1.Secret{Data: map[string][]byte{"pinger": []byte("pingpass"), "redis-operator": []byte("operatorpass")}}, nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+			ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				sd = args.Get(1).(*appsv1.Deployment)
+			}).Return(nil)
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Run(func(args mock.Arguments) {
+				cm := args.Get(1).(*corev1.ConfigMap)
+				if c, ok := cm.Data["redis.conf"]; ok {
+					redisConf = c
+				}
+				if c, ok := cm.Data["sentinel.conf"]; ok {
+					sentinelConf = c
+				}
+				if c, ok := cm.Data["shutdown.sh"]; ok {
+					shutdown = c
+				}
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{}))
+
+			assert.Equal(test.expectedRedisConf, redisConf[:strings.Index(redisConf, "\nsave")])
+			assert.Equal(test.expectedSentinelConf, sentinelConf)
+			assert.True(strings.HasPrefix(shutdown, test.expectedShutdown), shutdown)
+
+			redisSecrets := []string{}
+			for _, v := range ss.Spec.Template.Spec.Volumes {
+				if v.Secret != nil {
+					redisSecrets = append(redisSecrets, v.Secret.SecretName)
+				}
+			}
+			assert.Equal(test.expectedRedisVolumes, redisSecrets)
+			assert.Equal(test.expectedRedisLiveness, ss.Spec.Template.Spec.Containers[0].LivenessProbe.Exec.Command[2])
+			assert.Equal(test.expectedRedisExporterAddr, getEnvValue(ss.Spec.Template.Spec.Containers[1].Env, "REDIS_ADDR"))
+
+			sentinelSecret := ""
+			for _, v := range sd.Spec.Template.Spec.Volumes {
+				if v.Secret != nil {
+					sentinelSecret = v.Secret.SecretName
+				}
+			}
+			assert.Equal(test.expectedSentinelSecret, sentinelSecret)
+			assert.Equal(test.expectedSentinelLiveness, sd.Spec.Template.Spec.Containers[0].LivenessProbe.Exec.Command[2])
+			assert.Equal(test.expectedSentinelExportAddr, getEnvValue(sd.Spec.Template.Spec.Containers[1].Env, "REDIS_ADDR"))
+		})
+	}
+}
+
+func getEnvValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func TestRedisUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{
+		{Name: "app", SecretPath: "app-auth", Rules: []string{"~app:*", "+@all"}},
+	}
+
+	var usersSecret *corev1.Secret
+	var redisConf string
+
+	ms := &mK8SService.Services{}
+	ms.On("CreateIfNotExistsSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		usersSecret = args.Get(1).(*corev1.Secret)
+	}).Return(nil)
+	ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("defaultpass")}}, nil)
+	ms.On("GetSecret", namespace, "app-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("apppass")}}, nil)
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		redisConf = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureRedisUsersSecret(rf, nil, []metav1.OwnerReference{}))
+
+	assert.Equal("rfr-users-test", usersSecret.Name)
+	assert.Len(usersSecret.Data["pinger"], 64)
+	assert.Len(usersSecret.Data["redis-operator"], 64)
+	assert.NotEqual(usersSecret.Data["pinger"], usersSecret.Data["redis-operator"])
+
+	ms.On("GetSecret", namespace, "rfr-users-test").Once().Return(usersSecret, nil)
+	assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+
+	hash := func(password []byte) string {
+		sum := sha256.Sum256(password)
+		return hex.EncodeToString(sum[:])
+	}
+	assert.Contains(redisConf, fmt.Sprintf("\nuser pinger on #%s -@all +ping\n", hash(usersSecret.Data["pinger"])))
+	assert.Contains(redisConf, fmt.Sprintf("\nuser redis-operator on #%s -@all +ping +info +slaveof +replicaof +config|get +config|set +config|rewrite +bgsave +lastsave +acl|setuser +acl|deluser\n", hash(usersSecret.Data["redis-operator"])))
+	assert.Contains(redisConf, fmt.Sprintf("\nuser app on #%s ~app:* +@all\n", hash([]byte("apppass"))))
+	assert.NotContains(redisConf, "apppass")
+	assert.True(strings.HasSuffix(redisConf, "\nmasterauth defaultpass\nrequirepass defaultpass"), redisConf)
+	ms.AssertExpectations(t)
+}
+
+func TestRedisConfigRestartRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.CustomConfig = []string{"maxmemory 1gb", "databases 32", "IO-THREADS 4"}
+	rf.Spec.Redis.ConfigRewrite = true
+
+	var redisConf string
+	var ss *appsv1.StatefulSet
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, "rfr-users-test").Once().Return(&corev1.Secret{Data: map[string][]byte{"pinger": []byte("pingerpass"), "redis-operator": []byte("operatorpass")}}, nil)
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		redisConf = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+	}).Return(nil)
+	ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+	ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		ss = args.Get(1).(*appsv1.StatefulSet)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+
+	// Only the config redis reads at startup is written in the file
+	assert.Contains(redisConf, "\ndatabases 32\nIO-THREADS 4\n")
+	assert.NotContains(redisConf, "maxmemory")
+
+	// Redis runs from a copy of the file it can rewrite
+	assert.Len(ss.Spec.Template.Spec.InitContainers, 1)
+	initContainer := ss.Spec.Template.Spec.InitContainers[0]
+	assert.Equal("redis-config-copy", initContainer.Name)
+	assert.Equal([]string{"cp", "/redis/redis.conf", "/redis-writable/redis.conf"}, initContainer.Command)
+	assert.Contains(ss.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "redis-config-writable", MountPath: "/redis-writable"})
+	assert.Contains(ss.Spec.Template.Spec.Volumes, corev1.Volume{Name: "redis-config-writable", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+	ms.AssertExpectations(t)
+}
+
+func TestRedisBackupAgent(t *testing.T) {
+	tests := []struct {
+		name        string
+		backup      *redisfailoverv1.BackupSettings
+		storage     redisfailoverv1.RedisStorage
+		expAgent    bool
+		expDataName string
+	}{
+		{
+			name: "Backups disabled",
+		},
+		{
+			name: "Backups enabled",
+			backup: &redisfailoverv1.BackupSettings{
+				Image:   "redis-operator:test",
+				Storage: redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", Prefix: "redis/", CredentialsSecret: "minio"}},
+			},
+			expAgent:    true,
+			expDataName: "redis-data",
+		},
+		{
+			name: "Backups enabled with a persistent volume",
+			backup: &redisfailoverv1.BackupSettings{
+				Image:   "redis-operator:test",
+				Storage: redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", Prefix: "redis/", CredentialsSecret: "minio"}},
+			},
+			storage: redisfailoverv1.RedisStorage{
+				PersistentVolumeClaim: &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+					EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "pvc-data"},
+				},
+			},
+			expAgent:    true,
+			expDataName: "pvc-data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Backup = test.backup
+			rf.Spec.Redis.Storage = test.storage
+
+			var agent *corev1.Container
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss := args.Get(1).(*appsv1.StatefulSet)
+				for i, c := range ss.Spec.Template.Spec.Containers {
+					if c.Name == "backup-agent" {
+						agent = &ss.Spec.Template.Spec.Containers[i]
+					}
+				}
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+			assert.NoError(err)
+
+			if !test.expAgent {
+				assert.Nil(agent)
+				return
+			}
+			if assert.NotNil(agent) {
+				assert.Equal("redis-operator:test", agent.Image)
+				assert.Equal([]string{
+					"/usr/local/bin/redis-backup-agent",
+					"--rdb-path=/data/dump.rdb",
+					"--s3-endpoint=http://minio:9000",
+					"--s3-region=",
+					"--s3-bucket=backups",
+					"--s3-prefix=redis/",
+					"--key-prefix=testns/test/",
+				}, agent.Command)
+				assert.Equal([]corev1.ContainerPort{{Name: "backup-agent", ContainerPort: 9122, Protocol: corev1.ProtocolTCP}}, agent.Ports)
+				assert.Equal([]corev1.VolumeMount{{Name: test.expDataName, MountPath: "/data", ReadOnly: true}}, agent.VolumeMounts)
+				if assert.Len(agent.Env, 3) {
+					assert.Equal("minio", agent.Env[0].ValueFrom.SecretKeyRef.Name)
+					assert.Equal("accessKeyId", agent.Env[0].ValueFrom.SecretKeyRef.Key)
+					assert.Equal("secretAccessKey", agent.Env[1].ValueFrom.SecretKeyRef.Key)
+					assert.Equal("BACKUP_AGENT_TOKEN", agent.Env[2].Name)
+					assert.Equal("rfr-backup-agent-test", agent.Env[2].ValueFrom.SecretKeyRef.Name)
+					assert.Equal("token", agent.Env[2].ValueFrom.SecretKeyRef.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestBackupAgentSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	var agentSecret *corev1.Secret
+
+	ms := &mK8SService.Services{}
+	ms.On("CreateIfNotExistsSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		agentSecret = args.Get(1).(*corev1.Secret)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureBackupAgentSecret(rf, nil, []metav1.OwnerReference{}))
+
+	assert.Equal("rfr-backup-agent-test", agentSecret.Name)
+	assert.Len(agentSecret.Data["token"], 64)
+
+	ms.On("GetSecret", namespace, "rfr-backup-agent-test").Once().Return(agentSecret, nil)
+	token, err := rfservice.GetBackupAgentToken(ms, rf)
+	assert.NoError(err)
+	assert.Equal(string(agentSecret.Data["token"]), token)
+	ms.AssertExpectations(t)
+}
+
+func TestRedisRestoreContainer(t *testing.T) {
+	tests := []struct {
+		name       string
+		restore    *redisfailoverv1.RestoreSettings
+		status     *redisfailoverv1.RestoreStatus
+		backup     *redisfailoverv1.BackupSettings
+		expCommand []string
+		expMounts  []corev1.VolumeMount
+		expEnv     int
+		expVolume  bool
+	}{
+		{
+			name:    "Restore skipped",
+			restore: &redisfailoverv1.RestoreSettings{URL: "https://backups/dump.rdb", Image: "redis-operator:test"},
+			status:  &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreSkipped},
+		},
+		{
+			name:    "Restore completed",
+			restore: &redisfailoverv1.RestoreSettings{PersistentVolumeClaim: &redisfailoverv1.RestoreVolumeSource{ClaimName: "backups", Path: "dump.rdb"}, Image: "redis-operator:test"},
+			status:  &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreCompleted, Source: "file:///restore/dump.rdb"},
+		},
+		{
+			name:    "Restore from a URL",
+			restore: &redisfailoverv1.RestoreSettings{URL: "https://backups/dump.rdb", Image: "redis-operator:test"},
+			status:  &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreRestoring, Source: "https://backups/dump.rdb"},
+			expCommand: []string{
+				"/usr/local/bin/redis-backup-agent",
+				"--restore-from=https://backups/dump.rdb",
+				"--restore-pod=rfr-test-0",
+				"--rdb-path=/data/dump.rdb",
+			},
+			expMounts: []corev1.VolumeMount{{Name: "redis-data", MountPath: "/data"}},
+			expEnv:    1,
+		},
+		{
+			name:    "Restore from a backup",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "backup", Image: "redis-operator:test"},
+			status:  &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreRestoring, Source: "s3://backups/testns/backup.rdb"},
+			backup: &redisfailoverv1.BackupSettings{
+				Image:   "redis-operator:test",
+				Storage: redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3BackupStorage{Endpoint: "http://minio:9000", Bucket: "backups", CredentialsSecret: "minio"}},
+			},
+			expCommand: []string{
+				"/usr/local/bin/redis-backup-agent",
+				"--restore-from=s3://backups/testns/backup.rdb",
+				"--restore-pod=rfr-test-0",
+				"--rdb-path=/data/dump.rdb",
+				"--s3-endpoint=http://minio:9000",
+				"--s3-region=",
+			},
+			expMounts: []corev1.VolumeMount{{Name: "redis-data", MountPath: "/data"}},
+			expEnv:    3,
+		},
+		{
+			name:    "Restore from a volume",
+			restore: &redisfailoverv1.RestoreSettings{PersistentVolumeClaim: &redisfailoverv1.RestoreVolumeSource{ClaimName: "backups", Path: "dump.rdb"}, Image: "redis-operator:test"},
+			status:  &redisfailoverv1.RestoreStatus{Phase: redisfailoverv1.RestoreRestoring, Source: "file:///restore/dump.rdb"},
+			expCommand: []string{
+				"/usr/local/bin/redis-backup-agent",
+				"--restore-from=file:///restore/dump.rdb",
+				"--restore-pod=rfr-test-0",
+				"--rdb-path=/data/dump.rdb",
+			},
+			expMounts: []corev1.VolumeMount{
+				{Name: "redis-data", MountPath: "/data"},
+				{Name: "restore-source", MountPath: "/restore", ReadOnly: true},
+			},
+			expEnv:    1,
+			expVolume: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Restore = test.restore
+			rf.Spec.Backup = test.backup
+			rf.Status.Restore = test.status
+
+			var ss *appsv1.StatefulSet
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+			assert.NoError(err)
+
+			var restore *corev1.Container
+			for i, c := range ss.Spec.Template.Spec.InitContainers {
+				if c.Name == "restore" {
+					restore = &ss.Spec.Template.Spec.InitContainers[i]
+				}
+			}
+			var volume *corev1.Volume
+			for i, v := range ss.Spec.Template.Spec.Volumes {
+				if v.Name == "restore-source" {
+					volume = &ss.Spec.Template.Spec.Volumes[i]
+				}
+			}
+
+			if test.expCommand == nil {
+				assert.Nil(restore)
+				assert.Nil(volume)
+				return
+			}
+			if assert.NotNil(restore) {
+				assert.Equal("redis-operator:test", restore.Image)
+				assert.Equal(test.expCommand, restore.Command)
+				assert.Equal(test.expMounts, restore.VolumeMounts)
+				assert.Len(restore.Env, test.expEnv)
+			}
+			if test.expVolume {
+				if assert.NotNil(volume) {
+					assert.Equal("backups", volume.PersistentVolumeClaim.ClaimName)
+					assert.True(volume.PersistentVolumeClaim.ReadOnly)
+				}
+			} else {
+				assert.Nil(volume)
+			}
+		})
+	}
+}
+
+func TestSentinelStatefulSet(t *testing.T) {
+	storageClass := "standard"
+	size := resource.MustParse("1Mi")
+
+	tests := []struct {
+		name         string
+		storage      redisfailoverv1.SentinelConfigStorage
+		expSize      resource.Quantity
+		expClassName *string
+	}{
+		{
+			name:    "Default config storage",
+			expSize: resource.MustParse("10Mi"),
+		},
+		{
+			name:         "Custom config storage",
+			storage:      redisfailoverv1.SentinelConfigStorage{Size: &size, StorageClassName: &storageClass},
+			expSize:      size,
+			expClassName: &storageClass,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Sentinel.WorkloadType = redisfailoverv1.SentinelWorkloadStatefulSet
+			rf.Spec.Sentinel.ConfigStorage = test.storage
+
+			var ss *appsv1.StatefulSet
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureSentinelStatefulSet(rf, nil, []metav1.OwnerReference{{Name: "testing"}})
+			assert.NoError(err)
+
+			assert.Equal(sentinelName, ss.Name)
+			assert.Equal(sentinelName+"-headless", ss.Spec.ServiceName)
+			assert.Equal(int32(3), *ss.Spec.Replicas)
+			assert.Equal(appsv1.ParallelPodManagement, ss.Spec.PodManagementPolicy)
+			assert.Equal(map[string]string{
+				"app.kubernetes.io/component": "sentinel",
+				"app.kubernetes.io/name":      name,
+				"app.kubernetes.io/part-of":   "redis-failover",
+			}, ss.Spec.Selector.MatchLabels)
+
+			if assert.Len(ss.Spec.VolumeClaimTemplates, 1) {
+				claim := ss.Spec.VolumeClaimTemplates[0]
+				assert.Equal("sentinel-config-writable", claim.Name)
+				assert.Equal([]metav1.OwnerReference{{Name: "testing"}}, claim.OwnerReferences)
+				assert.Equal(test.expSize, claim.Spec.Resources.Requests[corev1.ResourceStorage])
+				assert.Equal(test.expClassName, claim.Spec.StorageClassName)
+			}
+			for _, volume := range ss.Spec.Template.Spec.Volumes {
+				assert.NotEqual("sentinel-config-writable", volume.Name)
+			}
+			// The config is kept on the volume across restarts
+			assert.Equal([]string{"sh", "-c", "[ -f /redis-writable/sentinel.conf ] || cp /redis/sentinel.conf /redis-writable/sentinel.conf"}, ss.Spec.Template.Spec.InitContainers[0].Command)
+		})
+	}
+}
+
+func TestSentinelHeadlessService(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.WorkloadType = redisfailoverv1.SentinelWorkloadStatefulSet
+
+	var svc *corev1.Service
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateService", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		svc = args.Get(1).(*corev1.Service)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	err := client.EnsureSentinelHeadlessService(rf, nil, []metav1.OwnerReference{})
+	assert.NoError(err)
+
+	assert.Equal(sentinelName+"-headless", svc.Name)
+	assert.Equal(corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	assert.True(svc.Spec.PublishNotReadyAddresses)
+	assert.Equal(int32(26379), svc.Spec.Ports[0].Port)
+}
+
+func TestHostnameMode(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Port = 6379
+	rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+	var sentinelConf, shutdown string
+	var ss *appsv1.StatefulSet
+	var svc *corev1.Service
+
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Return(nil, nil)
+	ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		ss = args.Get(1).(*appsv1.StatefulSet)
+	}).Return(nil)
+	ms.On("CreateOrUpdateService", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		svc = args.Get(1).(*corev1.Service)
+	}).Return(nil)
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Run(func(args mock.Arguments) {
+		cm := args.Get(1).(*corev1.ConfigMap)
+		if c, ok := cm.Data["sentinel.conf"]; ok {
+			sentinelConf = c
+		}
+		if c, ok := cm.Data["shutdown.sh"]; ok {
+			shutdown = c
+		}
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisService(rf, nil, []metav1.OwnerReference{}))
+
+	assert.Equal("sentinel resolve-hostnames yes\nsentinel announce-hostnames yes\nsentinel monitor mymaster 127.0.0.1 6379 2\nsentinel down-after-milliseconds mymaster 1000\nsentinel failover-timeout mymaster 3000\nsentinel parallel-syncs mymaster 2", sentinelConf)
+	assert.Contains(shutdown, "if [ \"$master\" = \"$(hostname).rfr-test.testns.svc\" ]; then")
+
+	redis := ss.Spec.Template.Spec.Containers[0]
+	assert.Equal([]string{"redis-server", "/redis/redis.conf", "--replica-announce-ip", "$(POD_NAME).rfr-test.testns.svc"}, redis.Command)
+	found := false
+	for _, e := range redis.Env {
+		if e.Name == "POD_NAME" && e.ValueFrom != nil && e.ValueFrom.FieldRef.FieldPath == "metadata.name" {
+			found = true
+		}
+	}
+	assert.True(found, "the pod name has to be given to the redis container")
+
+	assert.Equal(redisName, svc.Name)
+	assert.Equal(corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	assert.True(svc.Spec.PublishNotReadyAddresses)
+	assert.Contains(svc.Spec.Ports, corev1.ServicePort{Name: "redis", Port: 6379, Protocol: corev1.ProtocolTCP})
+}
+
+func TestServicesIPFamilies(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := corev1.IPFamilyPolicyPreferDualStack
+	families := []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+
+	rf := generateRF()
+	rf.Spec.IPFamilyPolicy = &policy
+	rf.Spec.IPFamilies = families
+
+	services := []*corev1.Service{}
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateService", namespace, mock.Anything).Run(func(args mock.Arguments) {
+		services = append(services, args.Get(1).(*corev1.Service))
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureSentinelService(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelHeadlessService(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisService(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisMasterService(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisSlaveService(rf, nil, []metav1.OwnerReference{}))
+
+	if assert.Len(services, 5) {
+		for _, svc := range services {
+			assert.Equal(&policy, svc.Spec.IPFamilyPolicy, svc.Name)
+			assert.Equal(families, svc.Spec.IPFamilies, svc.Name)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"sort"
 	"strconv"
@@ -10,35 +12,48 @@ import (
 	"github.com/freshworks/redis-operator/service/k8s"
 	"github.com/freshworks/redis-operator/service/redis"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 // RedisFailoverHeal defines the interface able to fix the problems on the redis failovers
 type RedisFailoverHeal interface {
-	MakeMaster(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetOldestAsMaster(rFailover *redisfailoverv1.RedisFailover) error
-	SetMasterOnAll(masterIP string, rFailover *redisfailoverv1.RedisFailover) error
-	SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitor(ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
-	RestoreSentinel(ip string) error
-	SetSentinelCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetRedisCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
+	MakeMaster(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetOldestAsMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	MakeSlaveOf(ctx context.Context, ip string, masterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SetMasterOnAll(ctx context.Context, masterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
+	RestoreSentinel(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisUsers(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	AddRedisPassword(ctx context.Context, ip string, previous string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisMasterAuth(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelAuthPass(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	RemoveRedisPreviousPassword(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
 	DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error
+	SetReplicaPriority(ctx context.Context, ip string, priority string, rFailover *redisfailoverv1.RedisFailover) error
+	SentinelFailover(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	BackgroundSave(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRoleLabel(podName string, master bool, rFailover *redisfailoverv1.RedisFailover) error
 }
 
 // RedisFailoverHealer is our implementation of RedisFailoverCheck interface
 type RedisFailoverHealer struct {
 	k8sService  k8s.Services
 	redisClient redis.Client
+	recorder    record.EventRecorder
 	logger      log.Logger
 }
 
 // NewRedisFailoverHealer creates an object of the RedisFailoverChecker struct
-func NewRedisFailoverHealer(k8sService k8s.Services, redisClient redis.Client, logger log.Logger) *RedisFailoverHealer {
+func NewRedisFailoverHealer(k8sService k8s.Services, redisClient redis.Client, recorder record.EventRecorder, logger log.Logger) *RedisFailoverHealer {
 	logger = logger.With("service", "redis.healer")
 	return &RedisFailoverHealer{
 		k8sService:  k8sService,
 		redisClient: redisClient,
+		recorder:    recorder,
 		logger:      logger,
 	}
 }
@@ -61,14 +76,27 @@ func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.
 	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisSlaveRoleLabel())
 }
 
-func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFailover) error {
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+// SetRoleLabel labels the redis pod with the role it works as
+func (r *RedisFailoverHealer) SetRoleLabel(podName string, master bool, rf *redisfailoverv1.RedisFailover) error {
+	if master {
+		return r.k8sService.UpdatePodLabels(rf.Namespace, podName, generateRedisMasterRoleLabel())
+	}
+	return r.k8sService.UpdatePodLabels(rf.Namespace, podName, generateRedisSlaveRoleLabel())
+}
+
+func (r *RedisFailoverHealer) MakeMaster(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	err = r.redisClient.MakeMaster(ip, port, password)
+	err = r.redisClient.MakeMaster(ctx, ip, port, username, password, tlsConfig)
 	if err != nil {
 		return err
 	}
@@ -79,14 +107,52 @@ func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFai
 	}
 	for _, rp := range rps.Items {
 		if rp.Status.PodIP == ip {
+			r.recorder.Eventf(rf, v1.EventTypeNormal, EventReasonMasterPromoted, "Promoted pod %s (%s) to master", rp.Name, ip)
 			return r.setMasterLabelIfNecessary(rf.Namespace, rp)
 		}
 	}
+	r.recorder.Eventf(rf, v1.EventTypeNormal, EventReasonMasterPromoted, "Promoted redis %s to master", ip)
+	return nil
+}
+
+// MakeSlaveOf makes the redis a replica of the given master of the failover
+func (r *RedisFailoverHealer) MakeSlaveOf(ctx context.Context, ip string, masterIP string, rf *redisfailoverv1.RedisFailover) error {
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	masterAddress, err := getRedisAddress(r.k8sService, rf, masterIP)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	if err := r.redisClient.MakeSlaveOfWithPort(ctx, ip, masterAddress, port, username, password, tlsConfig); err != nil {
+		return err
+	}
+
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+	for _, rp := range rps.Items {
+		if rp.Status.PodIP == ip {
+			r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonReplicaReconfigured, "Made pod %s replica of master %s", rp.Name, masterIP)
+			return r.setSlaveLabelIfNecessary(rf.Namespace, rp)
+		}
+	}
 	return nil
 }
 
-// SetOldestAsMaster puts all redis to the same master, choosen by order of appearance
-func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailover) error {
+// SetOldestAsMaster puts all redis to the same master. The master is chosen with the masterElection
+// strategy of the spec: the oldest pod, or the one holding the most data.
+func (r *RedisFailoverHealer) SetOldestAsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -100,23 +166,45 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 		return ssp.Items[i].CreationTimestamp.Before(&ssp.Items[j].CreationTimestamp)
 	})
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
+	pods := ssp.Items
+	candidates := len(pods)
+	var offsets map[string]int64
+	if rf.Spec.Redis.MasterElection == redisfailoverv1.MasterElectionHighestOffset {
+		pods, candidates, offsets = r.orderByReplicationOffset(ctx, rf, pods, port, username, password, tlsConfig)
+	}
+
 	newMasterIP := ""
-	for _, pod := range ssp.Items {
+	for i, pod := range pods {
 		if newMasterIP == "" {
+			// None of the redises that can be promoted accepted to be the master
+			if i >= candidates {
+				break
+			}
 			newMasterIP = pod.Status.PodIP
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("New master is %s with ip %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeMaster(newMasterIP, port, password); err != nil {
+			if err := r.redisClient.MakeMaster(ctx, newMasterIP, port, username, password, tlsConfig); err != nil {
 				newMasterIP = ""
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", pod.Status.PodIP, err)
 				continue
 			}
 
+			if offsets != nil {
+				r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonPromotedHighestOffsetPod, "Promoted pod %s (%s) with the highest replication offset %d to master", pod.Name, newMasterIP, offsets[newMasterIP])
+			} else {
+				r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonPromotedOldestPod, "Promoted oldest pod %s (%s) to master", pod.Name, newMasterIP)
+			}
+
 			err = r.setMasterLabelIfNecessary(rf.Namespace, pod)
 			if err != nil {
 				return err
@@ -125,7 +213,7 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 			newMasterIP = pod.Status.PodIP
 		} else {
 			r.logger.Infof("Making pod %s slave of %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, newMasterIP, port, password); err != nil {
+			if err := r.redisClient.MakeSlaveOfWithPort(ctx, pod.Status.PodIP, getRedisAddressFromPods(rf, pods, newMasterIP), port, username, password, tlsConfig); err != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", pod.Status.PodIP, newMasterIP, err)
 			}
 
@@ -142,14 +230,68 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 	}
 }
 
+// orderByReplicationOffset puts first the redises that can be promoted, by decreasing replication offset.
+// The pods are given from the oldest, and the order is kept between redises with the same offset.
+// Redises that do not answer or have a replica-priority of 0 are left at the end, they are not promoted.
+// It returns the ordered pods, how many of them can be promoted and their offsets by IP.
+func (r *RedisFailoverHealer) orderByReplicationOffset(ctx context.Context, rf *redisfailoverv1.RedisFailover, pods []v1.Pod, port, username, password string, tlsConfig *tls.Config) ([]v1.Pod, int, map[string]int64) {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	offsets := make(map[string]int64, len(pods))
+	replIDs := make(map[string]bool, len(pods))
+	candidates := []v1.Pod{}
+	others := []v1.Pod{}
+	for _, pod := range pods {
+		ip := pod.Status.PodIP
+		if ip == "" {
+			others = append(others, pod)
+			continue
+		}
+		priority, err := r.redisClient.GetReplicaPriority(ctx, ip, port, username, password, tlsConfig)
+		if err != nil {
+			logger.Warningf("Unable to get the replica priority of %s, it won't be promoted: %v", pod.Name, err)
+			others = append(others, pod)
+			continue
+		}
+		if priority == 0 {
+			logger.Infof("Pod %s has a replica-priority of 0, it won't be promoted", pod.Name)
+			others = append(others, pod)
+			continue
+		}
+		replication, err := r.redisClient.GetReplicationInfo(ctx, ip, port, username, password, tlsConfig)
+		if err != nil {
+			logger.Warningf("Unable to get the replication offset of %s, it won't be promoted: %v", pod.Name, err)
+			others = append(others, pod)
+			continue
+		}
+		logger.Infof("Pod %s is at offset %d of replication %s", pod.Name, replication.MasterReplOffset, replication.MasterReplID)
+		offsets[ip] = replication.MasterReplOffset
+		replIDs[replication.MasterReplID] = true
+		candidates = append(candidates, pod)
+	}
+	if len(replIDs) > 1 {
+		logger.Warningf("Redises do not share the same replication history, their offsets may not be comparable")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return offsets[candidates[i].Status.PodIP] > offsets[candidates[j].Status.PodIP]
+	})
+	return append(candidates, others...), len(candidates), offsets
+}
+
 // SetMasterOnAll puts all redis nodes as a slave of a given master
-func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetMasterOnAll(ctx context.Context, masterIP string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
@@ -157,7 +299,7 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 	port := getRedisPort(rf.Spec.Redis.Port)
 	for _, pod := range ssp.Items {
 		//During this configuration process if there is a new master selected , bailout
-		isMaster, err := r.redisClient.IsMaster(masterIP, port, password)
+		isMaster, err := r.redisClient.IsMaster(ctx, masterIP, port, username, password, tlsConfig)
 		if err != nil || !isMaster {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("check master failed maybe this node is not ready(ip changed), or sentinel made a switch: %s", masterIP)
 			return err
@@ -166,10 +308,11 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 				continue
 			}
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s", pod.Name, masterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, port, password); err != nil {
+			if err := r.redisClient.MakeSlaveOfWithPort(ctx, pod.Status.PodIP, getRedisAddressFromPods(rf, ssp.Items, masterIP), port, username, password, tlsConfig); err != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", pod.Status.PodIP, masterIP, err)
 				return err
 			}
+			r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonReplicaReconfigured, "Made pod %s replica of master %s", pod.Name, masterIP)
 
 			err = r.setSlaveLabelIfNecessary(rf.Namespace, pod)
 			if err != nil {
@@ -182,20 +325,25 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 
 // SetExternalMasterOnAll puts all redis nodes as a slave of a given master outside of
 // the current RedisFailover instance
-func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetExternalMasterOnAll(ctx context.Context, masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	for _, pod := range ssp.Items {
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s:%s", pod.Name, masterIP, masterPort)
-		if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, masterPort, password); err != nil {
+		if err := r.redisClient.MakeSlaveOfWithPort(ctx, pod.Status.PodIP, masterIP, masterPort, username, password, tlsConfig); err != nil {
 			return err
 		}
 
@@ -204,7 +352,7 @@ func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string
 }
 
 // NewSentinelMonitor changes the master that Sentinel has to monitor
-func (r *RedisFailoverHealer) NewSentinelMonitor(ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
@@ -212,12 +360,32 @@ func (r *RedisFailoverHealer) NewSentinelMonitor(ip string, monitor string, rf *
 		return err
 	}
 
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	address, err := getRedisAddress(r.k8sService, rf, monitor)
+	if err != nil {
+		return err
+	}
+	if rf.HostnameMode() {
+		// Sentinels started before the hostname mode was set would not resolve the address
+		if err := r.redisClient.EnableSentinelHostnames(ctx, ip, tlsConfig); err != nil {
+			return err
+		}
+	}
+
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, port, quorum, password, rf.MasterName())
+	if err := r.redisClient.MonitorRedisWithPort(ctx, ip, address, port, quorum, password, rf.MasterName(), tlsConfig); err != nil {
+		return err
+	}
+	r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonSentinelMonitorUpdated, "Sentinel %s now monitors master %s:%s", ip, monitor, port)
+	return nil
 }
 
 // NewSentinelMonitorWithPort changes the master that Sentinel has to monitor by the provided IP and Port
-func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
 	password, err := k8s.GetRedisPassword(r.k8sService, rf)
@@ -225,36 +393,182 @@ func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ip string, monitor stri
 		return err
 	}
 
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, monitorPort, quorum, password, rf.MasterName())
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	if err := r.redisClient.MonitorRedisWithPort(ctx, ip, monitor, monitorPort, quorum, password, rf.MasterName(), tlsConfig); err != nil {
+		return err
+	}
+	r.recorder.Eventf(rf, v1.EventTypeWarning, EventReasonSentinelMonitorUpdated, "Sentinel %s now monitors master %s:%s", ip, monitor, monitorPort)
+	return nil
 }
 
 // RestoreSentinel clear the number of sentinels on memory
-func (r *RedisFailoverHealer) RestoreSentinel(ip string) error {
+func (r *RedisFailoverHealer) RestoreSentinel(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.Debugf("Restoring sentinel %s", ip)
-	return r.redisClient.ResetSentinel(ip)
+
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.ResetSentinel(ctx, ip, tlsConfig)
 }
 
 // SetSentinelCustomConfig will call sentinel to set the configuration given in config
-func (r *RedisFailoverHealer) SetSentinelCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetSentinelCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on sentinel %s...", ip)
-	return r.redisClient.SetCustomSentinelConfig(ip, rf.MasterName(), rf.Spec.Sentinel.CustomConfig)
+
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.SetCustomSentinelConfig(ctx, ip, rf.MasterName(), rf.Spec.Sentinel.CustomConfig, tlsConfig)
 }
 
 // SetRedisCustomConfig will call redis to set the configuration given in config
-func (r *RedisFailoverHealer) SetRedisCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetRedisCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on redis %s...", ip)
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	return r.redisClient.SetCustomRedisConfig(ctx, ip, port, rf.Spec.Redis.CustomConfig, rf.Spec.Redis.ConfigRewrite, username, password, tlsConfig)
+}
+
+// SetRedisUsers creates or updates the ACL users on the redis as the operator user, and deletes the users
+// of the spec it created before that are no longer defined. The users created by anyone else are left alone.
+func (r *RedisFailoverHealer) SetRedisUsers(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the users on redis %s...", ip)
+
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	users, err := getRedisUsers(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	err = r.setRedisUsers(ctx, ip, port, rf, users, username, password, tlsConfig)
+	if username == "" || !errors.Is(err, redis.ErrNoPerm) {
+		return err
+	}
+
+	// The redis started with the operator user of a release not allowed to manage the users, the user is
+	// given its current rules through the default user first
+	defaultPassword, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if user.name != redisfailoverv1.OperatorUser {
+			continue
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Updating the rules of the operator user on redis %s", ip)
+		rules := append([]string{"reset"}, user.aclRules()...)
+		if err := r.redisClient.SetRedisUser(ctx, ip, port, user.name, rules, "", defaultPassword, tlsConfig); err != nil {
+			return err
+		}
+	}
+	return r.setRedisUsers(ctx, ip, port, rf, users, username, password, tlsConfig)
+}
+
+func (r *RedisFailoverHealer) setRedisUsers(ctx context.Context, ip, port string, rf *redisfailoverv1.RedisFailover, users []redisUser, username, password string, tlsConfig *tls.Config) error {
+	defined := map[string]bool{defaultRedisUser: true}
+	for _, user := range users {
+		defined[user.name] = true
+		// Reset first so the rules removed from the spec are removed from the user as well
+		rules := append([]string{"reset"}, user.aclRules()...)
+		if err := r.redisClient.SetRedisUser(ctx, ip, port, user.name, rules, username, password, tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range rf.Status.Users {
+		if defined[name] {
+			continue
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Deleting user %s no longer defined from redis %s", name, ip)
+		if err := r.redisClient.DeleteRedisUser(ctx, ip, port, name, username, password, tlsConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetReplicaPriority sets the priority used by sentinel to choose the replica to promote, the lower the sooner
+func (r *RedisFailoverHealer) SetReplicaPriority(ctx context.Context, ip string, priority string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting replica priority %s on redis %s...", priority, ip)
+
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.SetCustomRedisConfig(ip, port, rf.Spec.Redis.CustomConfig, password)
+	return r.redisClient.SetCustomRedisConfig(ctx, ip, port, []string{"replica-priority " + priority}, false, username, password, tlsConfig)
+}
+
+// SentinelFailover asks a sentinel to failover the master to the best replica available
+func (r *RedisFailoverHealer) SentinelFailover(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Requesting failover to sentinel %s", sentinel)
+
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.SentinelFailover(ctx, sentinel, rf.MasterName(), tlsConfig)
+}
+
+// BackgroundSave asks a redis to write its RDB file in the background
+func (r *RedisFailoverHealer) BackgroundSave(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Requesting background save to redis %s", ip)
+
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	return r.redisClient.BackgroundSave(ctx, ip, port, username, password, tlsConfig)
 }
 
 // DeletePod delete a failing pod so kubernetes relaunch it again
 func (r *RedisFailoverHealer) DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rFailover.ObjectMeta.Name).WithField("namespace", rFailover.ObjectMeta.Namespace).Infof("Deleting pods %s...", podName)
-	return r.k8sService.DeletePod(rFailover.Namespace, podName)
+	if err := r.k8sService.DeletePod(rFailover.Namespace, podName); err != nil {
+		return err
+	}
+	r.recorder.Eventf(rFailover, v1.EventTypeNormal, EventReasonPodDeleted, "Deleted pod %s so it is recreated with the current spec", podName)
+	return nil
 }
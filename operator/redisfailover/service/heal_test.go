@@ -1,7 +1,14 @@
 package service_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,11 +17,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
 	"github.com/freshworks/redis-operator/log"
 	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
 	mRedisService "github.com/freshworks/redis-operator/mocks/service/redis"
 	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
 )
 
 func TestSetOldestAsMasterNewMasterError(t *testing.T) {
@@ -36,11 +46,11 @@ func TestSetOldestAsMasterNewMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(errors.New(""))
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -52,6 +62,9 @@ func TestSetOldestAsMaster(t *testing.T) {
 	pods := &corev1.PodList{
 		Items: []corev1.Pod{
 			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
 				Status: corev1.PodStatus{
 					PodIP: "0.0.0.0",
 				},
@@ -63,12 +76,14 @@ func TestSetOldestAsMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	recorder := record.NewFakeRecorder(1)
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, recorder, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
+	assert.Equal("Warning PromotedOldestPod Promoted oldest pod rfr-test-0 (0.0.0.0) to master", <-recorder.Events)
 }
 
 func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
@@ -95,12 +110,12 @@ func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(errors.New(""))
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -128,12 +143,12 @@ func TestSetOldestAsMasterMultiplePods(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -171,15 +186,213 @@ func TestSetOldestAsMasterOrdering(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "1.1.1.1", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
+func TestSetOldestAsMasterHighestOffset(t *testing.T) {
+	tests := []struct {
+		name          string
+		priorities    map[string]int
+		offsets       map[string]int64
+		failingMaster string
+		expMaster     string
+		expEvent      string
+		expErr        bool
+	}{
+		{
+			name:       "Redis with the highest offset is promoted",
+			priorities: map[string]int{"0.0.0.0": 100, "1.1.1.1": 100, "2.2.2.2": 100},
+			offsets:    map[string]int64{"0.0.0.0": 10, "1.1.1.1": 50, "2.2.2.2": 30},
+			expMaster:  "1.1.1.1",
+			expEvent:   "Warning PromotedHighestOffsetPod Promoted pod rfr-test-1 (1.1.1.1) with the highest replication offset 50 to master",
+		},
+		{
+			name:       "Oldest redis is promoted between equal offsets",
+			priorities: map[string]int{"0.0.0.0": 100, "1.1.1.1": 100, "2.2.2.2": 100},
+			offsets:    map[string]int64{"0.0.0.0": 50, "1.1.1.1": 50, "2.2.2.2": 10},
+			expMaster:  "0.0.0.0",
+			expEvent:   "Warning PromotedHighestOffsetPod Promoted pod rfr-test-0 (0.0.0.0) with the highest replication offset 50 to master",
+		},
+		{
+			name:       "Redis with a replica priority of 0 is not promoted",
+			priorities: map[string]int{"0.0.0.0": 100, "1.1.1.1": 0, "2.2.2.2": 100},
+			offsets:    map[string]int64{"0.0.0.0": 10, "2.2.2.2": 30},
+			expMaster:  "2.2.2.2",
+			expEvent:   "Warning PromotedHighestOffsetPod Promoted pod rfr-test-2 (2.2.2.2) with the highest replication offset 30 to master",
+		},
+		{
+			name:       "Redis not answering is not promoted",
+			priorities: map[string]int{"0.0.0.0": 100, "2.2.2.2": 100},
+			offsets:    map[string]int64{"0.0.0.0": 30, "2.2.2.2": 10},
+			expMaster:  "0.0.0.0",
+			expEvent:   "Warning PromotedHighestOffsetPod Promoted pod rfr-test-0 (0.0.0.0) with the highest replication offset 30 to master",
+		},
+		{
+			name:          "Next redis is promoted when the promotion fails",
+			priorities:    map[string]int{"0.0.0.0": 100, "1.1.1.1": 100, "2.2.2.2": 100},
+			offsets:       map[string]int64{"0.0.0.0": 10, "1.1.1.1": 50, "2.2.2.2": 30},
+			failingMaster: "1.1.1.1",
+			expMaster:     "2.2.2.2",
+			expEvent:      "Warning PromotedHighestOffsetPod Promoted pod rfr-test-2 (2.2.2.2) with the highest replication offset 30 to master",
+		},
+		{
+			name:       "No redis can be promoted",
+			priorities: map[string]int{"0.0.0.0": 0, "1.1.1.1": 0, "2.2.2.2": 0},
+			expErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.MasterElection = redisfailoverv1.MasterElectionHighestOffset
+
+			now := time.Now()
+			pods := &corev1.PodList{}
+			for i, ip := range []string{"0.0.0.0", "1.1.1.1", "2.2.2.2"} {
+				pods.Items = append(pods.Items, corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              rfservice.GetRedisName(rf) + "-" + strconv.Itoa(i),
+						CreationTimestamp: metav1.Time{Time: now.Add(time.Duration(i) * time.Minute)},
+					},
+					Status: corev1.PodStatus{PodIP: ip},
+				})
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+			ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+			mr := &mRedisService.Client{}
+			for _, pod := range pods.Items {
+				ip := pod.Status.PodIP
+				priority, ok := test.priorities[ip]
+				if !ok {
+					mr.On("GetReplicaPriority", mock.Anything, ip, "0", "", "", (*tls.Config)(nil)).Once().Return(0, errors.New("i/o timeout"))
+					if ip != test.expMaster && test.expMaster != "" {
+						mr.On("MakeSlaveOfWithPort", mock.Anything, ip, test.expMaster, "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+					}
+					continue
+				}
+				mr.On("GetReplicaPriority", mock.Anything, ip, "0", "", "", (*tls.Config)(nil)).Once().Return(priority, nil)
+				if offset, ok := test.offsets[ip]; ok {
+					mr.On("GetReplicationInfo", mock.Anything, ip, "0", "", "", (*tls.Config)(nil)).Once().Return(redis.ReplicationInfo{Role: "master", MasterReplID: "replid", MasterReplOffset: offset}, nil)
+				}
+				if ip == test.failingMaster {
+					mr.On("MakeMaster", mock.Anything, ip, "0", "", "", (*tls.Config)(nil)).Once().Return(errors.New(""))
+				}
+				if test.expMaster == "" {
+					continue
+				}
+				if ip == test.expMaster {
+					mr.On("MakeMaster", mock.Anything, ip, "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+				} else if ip != test.failingMaster {
+					mr.On("MakeSlaveOfWithPort", mock.Anything, ip, test.expMaster, "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+				}
+			}
+
+			recorder := record.NewFakeRecorder(1)
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, recorder, log.DummyLogger{})
+
+			err := healer.SetOldestAsMaster(context.TODO(), rf)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expEvent, <-recorder.Events)
+			}
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+// aclAllows tells if the ACL rules let the user run the command, given as "command" or "command|subcommand".
+// Only the rules the operator gives its users are understood.
+func aclAllows(rules []string, command string) bool {
+	allowed := false
+	for _, rule := range rules {
+		switch rule {
+		case "+@all":
+			allowed = true
+		case "-@all", "reset":
+			allowed = false
+		case "+" + command:
+			allowed = true
+		case "-" + command:
+			allowed = false
+		}
+		if parent, _, ok := strings.Cut(command, "|"); ok && rule == "+"+parent {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+func TestSetOldestAsMasterHighestOffsetWithAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	rf.Spec.Redis.MasterElection = redisfailoverv1.MasterElectionHighestOffset
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, "redis-auth").Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("pass")}}, nil)
+	ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Return(&corev1.Secret{Data: map[string][]byte{"pinger": []byte("pingpass"), "redis-operator": []byte("operatorpass")}}, nil)
+	mr := &mRedisService.Client{}
+
+	// The redises only let the operator user run the commands its ACL rules allow
+	var rules []string
+	mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", mock.Anything, mock.Anything, "redis-operator", "operatorpass", (*tls.Config)(nil)).Run(func(args mock.Arguments) {
+		if args.String(3) == "redis-operator" {
+			rules = args.Get(4).([]string)
+		}
+	}).Return(nil)
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, record.NewFakeRecorder(1), log.DummyLogger{})
+	assert.NoError(healer.SetRedisUsers(context.TODO(), "0.0.0.0", rf))
+
+	noPerm := func(command string) error {
+		if aclAllows(rules, command) {
+			return nil
+		}
+		return errors.New("NOPERM this user has no permissions to run the '" + command + "' command")
+	}
+	pods := &corev1.PodList{}
+	for i, ip := range []string{"0.0.0.0", "1.1.1.1"} {
+		pods.Items = append(pods.Items, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: rfservice.GetRedisName(rf) + "-" + strconv.Itoa(i)},
+			Status:     corev1.PodStatus{PodIP: ip},
+		})
+		mr.On("GetReplicaPriority", mock.Anything, ip, "0", "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(func(context.Context, string, string, string, string, *tls.Config) (int, error) {
+			return 100, noPerm("config|get")
+		})
+		offset := int64(10 * (i + 1))
+		mr.On("GetReplicationInfo", mock.Anything, ip, "0", "redis-operator", "operatorpass", (*tls.Config)(nil)).Maybe().Return(func(context.Context, string, string, string, string, *tls.Config) (redis.ReplicationInfo, error) {
+			return redis.ReplicationInfo{Role: "slave", MasterReplID: "replid", MasterReplOffset: offset}, noPerm("info")
+		})
+	}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "redis-operator", "operatorpass", (*tls.Config)(nil)).Maybe().Return(func(context.Context, string, string, string, string, *tls.Config) error {
+		return noPerm("slaveof")
+	})
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "redis-operator", "operatorpass", (*tls.Config)(nil)).Maybe().Return(func(context.Context, string, string, string, string, string, *tls.Config) error {
+		return noPerm("slaveof")
+	})
+
+	// The redis with the highest offset is promoted, none of the commands is refused to the operator
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
+	assert.NoError(err)
+	mr.AssertCalled(t, "MakeMaster", mock.Anything, "1.1.1.1", "0", "redis-operator", "operatorpass", (*tls.Config)(nil))
+	mr.AssertExpectations(t)
+}
+
 func TestSetMasterOnAllMakeMasterError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -204,10 +417,10 @@ func TestSetMasterOnAllMakeMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(false, errors.New(""))
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Return(false, errors.New(""))
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -235,12 +448,12 @@ func TestSetMasterOnAllMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(errors.New(""))
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -268,13 +481,46 @@ func TestSetMasterOnAll(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+}
+
+func TestSetMasterOnAllHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"},
+				Status:     corev1.PodStatus{PodIP: "0.0.0.0"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"},
+				Status:     corev1.PodStatus{PodIP: "1.1.1.1"},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "", "", (*tls.Config)(nil)).Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "rfr-test-0.rfr-test.testns.svc", "0", "", "", (*tls.Config)(nil)).Once().Return(nil)
 
-	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.NoError(err)
+	mr.AssertExpectations(t)
 }
 
 func TestSetExternalMasterOnAll(t *testing.T) {
@@ -327,18 +573,18 @@ func TestSetExternalMasterOnAll(t *testing.T) {
 
 			mr := &mRedisService.Client{}
 			if !expectError {
-				mr.On("MakeSlaveOfWithPort", "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
+				mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "5.5.5.5", "6379", "", "", (*tls.Config)(nil)).Once().Return(nil)
 				if test.errorOnMakeSlaveOf {
 					expectError = true
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "", "", (*tls.Config)(nil)).Once().Return(errors.New(""))
 				} else {
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "", "", (*tls.Config)(nil)).Once().Return(nil)
 				}
 			}
 
-			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-			err := healer.SetExternalMasterOnAll("5.5.5.5", "6379", rf)
+			err := healer.SetExternalMasterOnAll(context.TODO(), "5.5.5.5", "6379", rf)
 
 			if expectError {
 				assert.Error(err)
@@ -375,14 +621,14 @@ func TestNewSentinelMonitor(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "", "mymaster").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "", "mymaster", (*tls.Config)(nil)).Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "", "mymaster").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "", "mymaster", (*tls.Config)(nil)).Once().Return(nil)
 			}
 
-			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitor("0.0.0.0", "1.1.1.1", rf)
+			err := healer.NewSentinelMonitor(context.TODO(), "0.0.0.0", "1.1.1.1", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -395,6 +641,35 @@ func TestNewSentinelMonitor(t *testing.T) {
 	}
 }
 
+func TestNewSentinelMonitorHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AddressMode = redisfailoverv1.AddressModeHostname
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"},
+				Status:     corev1.PodStatus{PodIP: "1.1.1.1"},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("EnableSentinelHostnames", mock.Anything, "0.0.0.0", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "rfr-test-1.rfr-test.testns.svc", "0", "2", "", "mymaster", (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+
+	err := healer.NewSentinelMonitor(context.TODO(), "0.0.0.0", "1.1.1.1", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
 func TestNewSentinelMonitorWithPort(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -419,22 +694,147 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "", "mymaster").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "", "mymaster", (*tls.Config)(nil)).Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "", "mymaster").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "", "mymaster", (*tls.Config)(nil)).Once().Return(nil)
 			}
 
-			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+			recorder := record.NewFakeRecorder(1)
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, recorder, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitorWithPort("0.0.0.0", "1.1.1.1", "6379", rf)
+			err := healer.NewSentinelMonitorWithPort(context.TODO(), "0.0.0.0", "1.1.1.1", "6379", rf)
 
 			if errorExpected {
 				assert.Error(err)
+				assert.Empty(recorder.Events)
 			} else {
 				assert.NoError(err)
+				assert.Equal("Warning SentinelMonitorUpdated Sentinel 0.0.0.0 now monitors master 1.1.1.1:6379", <-recorder.Events)
 			}
 			ms.AssertExpectations(t)
 			mr.AssertExpectations(t)
 		})
 	}
 }
+
+func TestSetRedisUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{{Name: "app", SecretPath: "app-auth", Rules: []string{"~app:*", "+@all"}}}
+	// Only the users the operator created are deleted, not the ones created by anyone else on the redis
+	rf.Status.Users = []string{"app", "old"}
+
+	usersSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: rfservice.GetRedisUsersSecretName(rf)},
+		Data:       map[string][]byte{"pinger": []byte("pingpass"), "redis-operator": []byte("operatorpass")},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Once().Return(usersSecret, nil)
+	ms.On("GetSecret", namespace, "app-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("apppass")}}, nil)
+
+	mr := &mRedisService.Client{}
+	mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "pinger", []string{"reset", "on", hashPassword("pingpass"), "-@all", "+ping"}, "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "redis-operator", append([]string{"reset", "on", hashPassword("operatorpass")}, operatorRules...), "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "app", []string{"reset", "on", hashPassword("apppass"), "~app:*", "+@all"}, "", "", (*tls.Config)(nil)).Once().Return(nil)
+	mr.On("DeleteRedisUser", mock.Anything, "0.0.0.0", "0", "old", "", "", (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+
+	err := healer.SetRedisUsers(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSetRedisUsersWithAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		// operatorNoPerm tells the operator user was created by a release not allowed to manage the users
+		operatorNoPerm bool
+	}{
+		{
+			name: "Users set as the operator user",
+		},
+		{
+			name:           "Operator user updated through the default user first",
+			operatorNoPerm: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+			rf.Status.Users = []string{"old"}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Return(&corev1.Secret{Data: map[string][]byte{"pinger": []byte("pingpass"), "redis-operator": []byte("operatorpass")}}, nil)
+			operatorUser := append([]string{"reset", "on", hashPassword("operatorpass")}, operatorRules...)
+
+			mr := &mRedisService.Client{}
+			if test.operatorNoPerm {
+				ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("pass")}}, nil)
+				mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "pinger", mock.Anything, "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(fmt.Errorf("%w: NOPERM", redis.ErrNoPerm))
+				mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "redis-operator", operatorUser, "", "pass", (*tls.Config)(nil)).Once().Return(nil)
+			}
+			mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "pinger", []string{"reset", "on", hashPassword("pingpass"), "-@all", "+ping"}, "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(nil)
+			mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "redis-operator", operatorUser, "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(nil)
+			mr.On("DeleteRedisUser", mock.Anything, "0.0.0.0", "0", "old", "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+
+			err := healer.SetRedisUsers(context.TODO(), "0.0.0.0", rf)
+			assert.NoError(err)
+			ms.AssertExpectations(t)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+// operatorRules are the ACL rules of the operator user
+var operatorRules = []string{"-@all", "+ping", "+info", "+slaveof", "+replicaof", "+config|get", "+config|set", "+config|rewrite", "+bgsave", "+lastsave", "+acl|setuser", "+acl|deluser"}
+
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return "#" + hex.EncodeToString(sum[:])
+}
+
+func TestSetRedisCustomConfigWithAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Once().Return(&corev1.Secret{Data: map[string][]byte{"redis-operator": []byte("operatorpass")}}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", rf.Spec.Redis.CustomConfig, false, "redis-operator", "operatorpass", (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+
+	err := healer.SetRedisCustomConfig(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestDeletePod(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("DeletePod", namespace, "rfr-test-0").Once().Return(nil)
+	mr := &mRedisService.Client{}
+
+	recorder := record.NewFakeRecorder(1)
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, recorder, log.DummyLogger{})
+
+	err := healer.DeletePod("rfr-test-0", rf)
+	assert.NoError(err)
+	assert.Equal("Normal PodDeleted Deleted pod rfr-test-0 so it is recreated with the current spec", <-recorder.Events)
+	ms.AssertExpectations(t)
+}
@@ -19,6 +19,16 @@ func GetRedisName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisName, rf.Name)
 }
 
+// GetRedisRestorePodName returns the name of the redis pod the data is restored in, the first one of the statefulset
+func GetRedisRestorePodName(rf *redisfailoverv1.RedisFailover) string {
+	return GetRedisName(rf) + "-0"
+}
+
+// GetRedisPodHostname returns the DNS name of the redis pod behind the headless service of the statefulset
+func GetRedisPodHostname(rf *redisfailoverv1.RedisFailover, pod string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", pod, GetRedisName(rf), rf.Namespace)
+}
+
 // GetRedisShutdownName returns the name for redis resources
 func GetRedisShutdownName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisShutdownName, rf.Name)
@@ -29,11 +39,31 @@ func GetRedisReadinessName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisReadinessName, rf.Name)
 }
 
+// GetRedisUsersSecretName returns the name of the secret with the passwords of the users created by the operator
+func GetRedisUsersSecretName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisUsersName, rf.Name)
+}
+
+// GetBackupAgentSecretName returns the name of the secret with the token the backup agents of the failover accept
+func GetBackupAgentSecretName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisBackupAgentName, rf.Name)
+}
+
+// GetBackupKeyPrefix returns the prefix of the keys the backups of the failover are uploaded under
+func GetBackupKeyPrefix(rf *redisfailoverv1.RedisFailover) string {
+	return fmt.Sprintf("%s/%s/", rf.Namespace, rf.Name)
+}
+
 // GetSentinelName returns the name for sentinel resources
 func GetSentinelName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(sentinelName, rf.Name)
 }
 
+// GetSentinelHeadlessName returns the name of the headless service of the sentinel statefulset
+func GetSentinelHeadlessName(rf *redisfailoverv1.RedisFailover) string {
+	return fmt.Sprintf("%s-%s", GetSentinelName(rf), sentinelHeadlessSuffix)
+}
+
 func GetRedisMasterName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisMasterName, rf.Name)
 }
@@ -42,6 +72,16 @@ func GetRedisSlaveName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisSlaveName, rf.Name)
 }
 
+// GetRedisClusterName returns the name for the resources shared by all the shards of a redis cluster
+func GetRedisClusterName(rc *redisfailoverv1.RedisCluster) string {
+	return generateName(clusterName, rc.Name)
+}
+
+// GetRedisClusterShardName returns the name of the statefulset of a shard of a redis cluster
+func GetRedisClusterShardName(rc *redisfailoverv1.RedisCluster, shard int) string {
+	return fmt.Sprintf("%s-%d", GetRedisClusterName(rc), shard)
+}
+
 func generateName(typeName, metaName string) string {
 	return fmt.Sprintf("%s%s-%s", baseName, typeName, metaName)
 }
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/service/k8s"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// observeConcurrency is the number of redises and sentinels called at the same time by Observe
+const observeConcurrency = 8
+
+// RedisObservation is what a redis pod answered to INFO replication
+type RedisObservation struct {
+	Pod         corev1.Pod
+	Replication redis.ReplicationInfo
+	// Err is the failure to get the replication of the redis, the replication is empty then
+	Err error
+}
+
+// IP returns the IP of the redis pod
+func (o RedisObservation) IP() string {
+	return o.Pod.Status.PodIP
+}
+
+// IsMaster tells if the redis answered it works as a master
+func (o RedisObservation) IsMaster() bool {
+	return o.Err == nil && o.Replication.IsMaster()
+}
+
+// HasRoleLabel tells if the pod is already labeled with the role given
+func (o RedisObservation) HasRoleLabel(master bool) bool {
+	role := redisRoleLabelSlave
+	if master {
+		role = redisRoleLabelMaster
+	}
+	return o.Pod.Labels[redisRoleLabelKey] == role
+}
+
+// SentinelObservation is what a sentinel pod answered about the master it monitors
+type SentinelObservation struct {
+	IP string
+	// MonitorAddress and MonitorPort are the master monitored, MonitorErr the failure to get it
+	MonitorAddress string
+	MonitorPort    string
+	MonitorErr     error
+	// Sentinels is the number of sentinels known, SentinelsErr the failure to get it
+	Sentinels    int32
+	SentinelsErr error
+	// Slaves is the number of replicas known, SlavesErr the failure to get it
+	Slaves    int32
+	SlavesErr error
+	// QuorumErr tells the sentinel can't reach the quorum to failover the master, or did not answer
+	QuorumErr error
+}
+
+// ClusterObservation is a snapshot of a redis failover: the replication of every running redis and what
+// every running sentinel knows, all of them called once. The decisions of a reconcile are all made on it.
+type ClusterObservation struct {
+	// RedisPods are all the pods of the redis statefulset, running or not
+	RedisPods []corev1.Pod
+	Redises   []RedisObservation
+	Sentinels []SentinelObservation
+}
+
+// RedisesIPs returns the IPs of the redises observed
+func (o *ClusterObservation) RedisesIPs() []string {
+	ips := make([]string, 0, len(o.Redises))
+	for _, r := range o.Redises {
+		ips = append(ips, r.IP())
+	}
+	return ips
+}
+
+// MastersIPs returns the IPs of the redises working as a master. The redises that did not answer are
+// not counted.
+func (o *ClusterObservation) MastersIPs() []string {
+	masters := []string{}
+	for _, r := range o.Redises {
+		if r.IsMaster() {
+			masters = append(masters, r.IP())
+		}
+	}
+	return masters
+}
+
+// SentinelsIPs returns the IPs of the sentinels observed
+func (o *ClusterObservation) SentinelsIPs() []string {
+	ips := make([]string, 0, len(o.Sentinels))
+	for _, s := range o.Sentinels {
+		ips = append(ips, s.IP)
+	}
+	return ips
+}
+
+// Redis returns the observation of the redis with the given IP
+func (o *ClusterObservation) Redis(ip string) (RedisObservation, bool) {
+	for _, r := range o.Redises {
+		if r.IP() == ip {
+			return r, true
+		}
+	}
+	return RedisObservation{}, false
+}
+
+// RedisAddress returns how the redis with the given IP is addressed by the other redises and the sentinels
+func (o *ClusterObservation) RedisAddress(rf *redisfailoverv1.RedisFailover, ip string) string {
+	return getRedisAddressFromPods(rf, o.RedisPods, ip)
+}
+
+// MaxRedisPodTime returns the max uptime among the redis pods
+func (o *ClusterObservation) MaxRedisPodTime() time.Duration {
+	maxTime := 0 * time.Hour
+	for _, pod := range o.RedisPods {
+		if pod.Status.StartTime == nil {
+			continue
+		}
+		if alive := time.Since(pod.Status.StartTime.Round(time.Second)); alive > maxTime {
+			maxTime = alive
+		}
+	}
+	return maxTime
+}
+
+// SentinelQuorum returns the number of sentinels unable to reach the quorum, and an error when too many of
+// them are to choose a master.
+func (o *ClusterObservation) SentinelQuorum(rf *redisfailoverv1.RedisFailover) (int, error) {
+	quorum := int(getQuorum(rf))
+	if len(o.Sentinels) < quorum {
+		return quorum - len(o.Sentinels), errors.New("insufficnet sentinel to reach Quorum")
+	}
+	unhealthy := 0
+	for _, s := range o.Sentinels {
+		if s.QuorumErr != nil {
+			unhealthy++
+		}
+	}
+	if unhealthy >= quorum {
+		return unhealthy, errors.New("insufficnet sentinel to reach Quorum")
+	}
+	return unhealthy, nil
+}
+
+// MastersOnLocalhost tells if all the redises replicate from a loopback address, as they do on their first
+// boot. It fails when a redis did not answer or works as a master.
+func (o *ClusterObservation) MastersOnLocalhost() (bool, error) {
+	if len(o.Redises) == 0 {
+		return false, errors.New("unable to fetch any redis Ips Currently")
+	}
+	for _, r := range o.Redises {
+		if r.Err != nil {
+			return false, r.Err
+		}
+		if r.Replication.MasterHost == "" {
+			return false, errors.New("unexpected master state, fix manually")
+		}
+		if !redis.IsLoopback(r.Replication.MasterHost) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Observe lists the redis and sentinel pods once, and calls all the running ones in parallel to get a
+// snapshot of the failover. The sentinels are only observed when they are allowed. A redis or a sentinel
+// that does not answer is part of the observation with the error, only failing to list the pods or to get
+// the credentials fails.
+func (r *RedisFailoverChecker) Observe(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*ClusterObservation, error) {
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return nil, err
+	}
+	username, password, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	redisTLSConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	obs := &ClusterObservation{RedisPods: rps.Items}
+	for _, rp := range rps.Items {
+		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running pods
+			obs.Redises = append(obs.Redises, RedisObservation{Pod: rp})
+		}
+	}
+
+	if rf.SentinelsAllowed() {
+		sps, err := r.getSentinelPods(rf)
+		if err != nil {
+			return nil, err
+		}
+		for _, sp := range sps.Items {
+			if sp.Status.Phase == corev1.PodRunning && sp.DeletionTimestamp == nil { // Only work with running pods
+				obs.Sentinels = append(obs.Sentinels, SentinelObservation{IP: sp.Status.PodIP})
+			}
+		}
+	}
+	sentinelTLSConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	masterName := rf.MasterName()
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, observeConcurrency)
+	run := func(observe func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			observe()
+		}()
+	}
+	// Every goroutine writes only its own element
+	for i := range obs.Redises {
+		o := &obs.Redises[i]
+		run(func() {
+			o.Replication, o.Err = r.redisClient.GetReplicationInfo(ctx, o.IP(), port, username, password, redisTLSConfig)
+		})
+	}
+	for i := range obs.Sentinels {
+		o := &obs.Sentinels[i]
+		run(func() {
+			o.MonitorAddress, o.MonitorPort, o.MonitorErr = r.redisClient.GetSentinelMonitor(ctx, o.IP, masterName, sentinelTLSConfig)
+			o.Sentinels, o.SentinelsErr = r.redisClient.GetNumberSentinelsInMemory(ctx, o.IP, sentinelTLSConfig)
+			o.Slaves, o.SlavesErr = r.redisClient.GetNumberSentinelSlavesInMemory(ctx, o.IP, sentinelTLSConfig)
+			o.QuorumErr = r.redisClient.SentinelCheckQuorum(ctx, o.IP, masterName, sentinelTLSConfig)
+		})
+	}
+	wg.Wait()
+
+	for _, o := range obs.Redises {
+		if o.Err != nil {
+			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", o.IP())
+		}
+	}
+	return obs, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+// Keys of the users secret keeping track of the auth password applied on the redises
+const (
+	appliedPasswordKey  = "default"
+	previousPasswordKey = "default-previous"
+)
+
+// EnsureRedisPasswordRotation keeps track in the users secret of the auth password applied on the redises.
+// When the password of the auth secret changes, the one applied so far is kept as the previous password
+// until the rotation finishes. It returns the previous password while a rotation is in progress.
+func (r *RedisFailoverKubeClient) EnsureRedisPasswordRotation(rf *redisfailoverv1.RedisFailover) (string, error) {
+	password, err := k8s.GetRedisPassword(r.K8SService, rf)
+	if err != nil || password == "" {
+		return "", err
+	}
+
+	secret, err := r.K8SService.GetSecret(rf.Namespace, GetRedisUsersSecretName(rf))
+	if err != nil {
+		return "", err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	applied, ok := secret.Data[appliedPasswordKey]
+	switch {
+	case !ok:
+		// First time the password is seen, the redises were created with it and there is nothing to rotate
+		secret.Data[appliedPasswordKey] = []byte(password)
+		return "", r.K8SService.UpdateSecret(rf.Namespace, secret)
+	case string(applied) != password:
+		// When the password changes again in the middle of a rotation, the redises still accept the
+		// password they had before it started, so that one is kept as the previous password.
+		if _, rotating := secret.Data[previousPasswordKey]; !rotating {
+			secret.Data[previousPasswordKey] = applied
+		}
+		secret.Data[appliedPasswordKey] = []byte(password)
+		if err := r.K8SService.UpdateSecret(rf.Namespace, secret); err != nil {
+			return "", err
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Auth password changed, starting its rotation")
+	}
+	return string(secret.Data[previousPasswordKey]), nil
+}
+
+// FinishRedisPasswordRotation forgets the previous auth password once no redis accepts it anymore.
+func (r *RedisFailoverKubeClient) FinishRedisPasswordRotation(rf *redisfailoverv1.RedisFailover) error {
+	secret, err := r.K8SService.GetSecret(rf.Namespace, GetRedisUsersSecretName(rf))
+	if err != nil {
+		return err
+	}
+	if _, ok := secret.Data[previousPasswordKey]; !ok {
+		return nil
+	}
+	delete(secret.Data, previousPasswordKey)
+	return r.K8SService.UpdateSecret(rf.Namespace, secret)
+}
+
+// AddRedisPassword adds the auth password to the default user of the redis next to the previous one,
+// so the clients still using the previous password are not refused.
+func (r *RedisFailoverHealer) AddRedisPassword(ctx context.Context, ip string, previous string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Adding the new password on redis %s...", ip)
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	rules := redisUser{name: defaultRedisUser, password: password}.aclRules()
+	if err := r.redisClient.SetRedisUser(ctx, ip, port, defaultRedisUser, rules, "", previous, tlsConfig); err != nil {
+		// Redises started after the change only know the new password
+		return r.redisClient.SetRedisUser(ctx, ip, port, defaultRedisUser, rules, "", password, tlsConfig)
+	}
+	return nil
+}
+
+// SetRedisMasterAuth makes the redis authenticate against its master with the auth password.
+func (r *RedisFailoverHealer) SetRedisMasterAuth(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting masterauth on redis %s...", ip)
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	username, operatorPassword, err := getOperatorCredentials(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	return r.redisClient.SetCustomRedisConfig(ctx, ip, port, []string{"masterauth " + password}, false, username, operatorPassword, tlsConfig)
+}
+
+// SetSentinelAuthPass makes the sentinel authenticate against the redises with the auth password.
+func (r *RedisFailoverHealer) SetSentinelAuthPass(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting auth-pass on sentinel %s...", ip)
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetSentinelTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.SetCustomSentinelConfig(ctx, ip, rf.MasterName(), []string{"auth-pass " + password}, tlsConfig)
+}
+
+// RemoveRedisPreviousPassword leaves the auth password as the only one accepted by the default user of the redis.
+func (r *RedisFailoverHealer) RemoveRedisPreviousPassword(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Removing the previous password from redis %s...", ip)
+
+	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := k8s.GetRedisTLSConfig(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	rules := append([]string{"resetpass"}, redisUser{name: defaultRedisUser, password: password}.aclRules()...)
+	return r.redisClient.SetRedisUser(ctx, ip, port, defaultRedisUser, rules, "", password, tlsConfig)
+}
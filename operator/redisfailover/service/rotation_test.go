@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/freshworks/redis-operator/mocks/service/redis"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+func TestEnsureRedisPasswordRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		usersData   map[string][]byte
+		expPrevious string
+		expUpdate   bool
+		expData     map[string][]byte
+	}{
+		{
+			name:      "Password seen for the first time",
+			usersData: map[string][]byte{"pinger": []byte("pingpass")},
+			expUpdate: true,
+			expData:   map[string][]byte{"pinger": []byte("pingpass"), "default": []byte("newpass")},
+		},
+		{
+			name:      "Password unchanged",
+			usersData: map[string][]byte{"default": []byte("newpass")},
+		},
+		{
+			name:        "Password changed",
+			usersData:   map[string][]byte{"default": []byte("oldpass")},
+			expPrevious: "oldpass",
+			expUpdate:   true,
+			expData:     map[string][]byte{"default": []byte("newpass"), "default-previous": []byte("oldpass")},
+		},
+		{
+			name:        "Rotation in progress",
+			usersData:   map[string][]byte{"default": []byte("newpass"), "default-previous": []byte("oldpass")},
+			expPrevious: "oldpass",
+		},
+		{
+			name:        "Password changed again during a rotation",
+			usersData:   map[string][]byte{"default": []byte("midpass"), "default-previous": []byte("oldpass")},
+			expPrevious: "oldpass",
+			expUpdate:   true,
+			expData:     map[string][]byte{"default": []byte("newpass"), "default-previous": []byte("oldpass")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+
+			usersSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: rfservice.GetRedisUsersSecretName(rf), Namespace: namespace},
+				Data:       test.usersData,
+			}
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("newpass")}}, nil)
+			ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Once().Return(usersSecret, nil)
+			if test.expUpdate {
+				ms.On("UpdateSecret", namespace, mock.MatchedBy(func(s *corev1.Secret) bool {
+					return assert.Equal(test.expData, s.Data)
+				})).Once().Return(nil)
+			}
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			previous, err := client.EnsureRedisPasswordRotation(rf)
+			assert.NoError(err)
+			assert.Equal(test.expPrevious, previous)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishRedisPasswordRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+
+	usersSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: rfservice.GetRedisUsersSecretName(rf), Namespace: namespace},
+		Data:       map[string][]byte{"default": []byte("newpass"), "default-previous": []byte("oldpass")},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, rfservice.GetRedisUsersSecretName(rf)).Once().Return(usersSecret, nil)
+	ms.On("UpdateSecret", namespace, usersSecret).Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	err := client.FinishRedisPasswordRotation(rf)
+	assert.NoError(err)
+	assert.Equal(map[string][]byte{"default": []byte("newpass")}, usersSecret.Data)
+	ms.AssertExpectations(t)
+}
+
+func TestAddRedisPassword(t *testing.T) {
+	sum := sha256.Sum256([]byte("newpass"))
+	rules := []string{"on", "#" + hex.EncodeToString(sum[:])}
+
+	tests := []struct {
+		name        string
+		previousErr error
+		newErr      error
+		expErr      bool
+	}{
+		{
+			name: "Redis knows the previous password",
+		},
+		{
+			name:        "Redis only knows the new password",
+			previousErr: errors.New("WRONGPASS"),
+		},
+		{
+			name:        "Redis knows none of them",
+			previousErr: errors.New("WRONGPASS"),
+			newErr:      errors.New("WRONGPASS"),
+			expErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("newpass")}}, nil)
+			mr := &mRedisService.Client{}
+			mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "default", rules, "", "oldpass", (*tls.Config)(nil)).Once().Return(test.previousErr)
+			if test.previousErr != nil {
+				mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "default", rules, "", "newpass", (*tls.Config)(nil)).Once().Return(test.newErr)
+			}
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+			err := healer.AddRedisPassword(context.TODO(), "0.0.0.0", "oldpass", rf)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			ms.AssertExpectations(t)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoveRedisPreviousPassword(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	sum := sha256.Sum256([]byte("newpass"))
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("newpass")}}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("SetRedisUser", mock.Anything, "0.0.0.0", "0", "default", []string{"resetpass", "on", "#" + hex.EncodeToString(sum[:])}, "", "newpass", (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+	err := healer.RemoveRedisPreviousPassword(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSetSentinelAuthPass(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, "redis-auth").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("newpass")}}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomSentinelConfig", mock.Anything, "0.0.0.0", "mymaster", []string{"auth-pass newpass"}, (*tls.Config)(nil)).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, &record.FakeRecorder{}, log.DummyLogger{})
+	err := healer.SetSentinelAuthPass(context.TODO(), "0.0.0.0", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
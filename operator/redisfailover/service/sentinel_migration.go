@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+)
+
+// EnsureSentinelMigration moves the sentinels off the workload type no longer requested. Its sentinels
+// are removed one at a time, once the ones of the workload requested are ready and all the sentinels
+// agree on the topology. The remaining sentinels are always a majority of the ones they know, so the
+// quorum is kept while they are moved. The progress is kept in the status of the RF.
+func (r *RedisFailoverKubeClient) EnsureSentinelMigration(rf *redisfailoverv1.RedisFailover) error {
+	name := GetSentinelName(rf)
+
+	var from redisfailoverv1.SentinelWorkloadType
+	var replicas, running int32
+	var deleting bool
+	var scale func(replicas int32) error
+	var remove func() error
+
+	if rf.SentinelStatefulSet() {
+		from = redisfailoverv1.SentinelWorkloadDeployment
+		d, err := r.K8SService.GetDeployment(rf.Namespace, name)
+		if errors.IsNotFound(err) {
+			rf.Status.SentinelMigration = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		replicas, running, deleting = getReplicas(d.Spec.Replicas), d.Status.Replicas, d.DeletionTimestamp != nil
+		scale = func(replicas int32) error {
+			d.Spec.Replicas = &replicas
+			return r.K8SService.UpdateDeployment(rf.Namespace, d)
+		}
+		remove = func() error {
+			return r.K8SService.DeleteDeployment(rf.Namespace, name)
+		}
+	} else {
+		from = redisfailoverv1.SentinelWorkloadStatefulSet
+		ss, err := r.K8SService.GetStatefulSet(rf.Namespace, name)
+		if errors.IsNotFound(err) {
+			rf.Status.SentinelMigration = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		replicas, running, deleting = getReplicas(ss.Spec.Replicas), ss.Status.Replicas, ss.DeletionTimestamp != nil
+		scale = func(replicas int32) error {
+			ss.Spec.Replicas = &replicas
+			if err := r.K8SService.UpdateStatefulSet(rf.Namespace, ss); err != nil {
+				return err
+			}
+			// The config of the sentinel removed is of no use anymore, its claim is kept until the pod is gone
+			return r.K8SService.DeletePersistentVolumeClaim(rf.Namespace, fmt.Sprintf("%s-%s-%d", sentinelConfigWritableVolumeName, name, replicas))
+		}
+		remove = func() error {
+			return r.K8SService.DeleteStatefulSet(rf.Namespace, name)
+		}
+	}
+
+	rf.Status.SentinelMigration = &redisfailoverv1.SentinelMigrationStatus{From: from, Sentinels: replicas}
+	if deleting {
+		return nil
+	}
+	// The last sentinel removed has to be gone, and the others have to know each other again
+	if running != replicas || rf.Status.ReadySentinels < rf.Spec.Sentinel.Replicas || !rf.IsStatusConditionTrue(redisfailoverv1.ConditionSentinelsConsistent) {
+		return nil
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	if replicas == 0 {
+		logger.Infof("All the sentinels moved from the %s, removing it", from)
+		return remove()
+	}
+	logger.Infof("Moving the sentinels from the %s, %d left", from, replicas-1)
+	if err := scale(replicas - 1); err != nil {
+		return err
+	}
+	rf.Status.SentinelMigration.Sentinels = replicas - 1
+	return nil
+}
+
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+func TestEnsureSentinelMigration(t *testing.T) {
+	tests := []struct {
+		name           string
+		workload       redisfailoverv1.SentinelWorkloadType
+		previous       bool
+		replicas       int32
+		running        int32
+		readySentinels int32
+		inconsistent   bool
+		expScale       bool
+		expDelete      bool
+		expMigration   *redisfailoverv1.SentinelMigrationStatus
+	}{
+		{
+			name:     "No deployment left",
+			workload: redisfailoverv1.SentinelWorkloadStatefulSet,
+		},
+		{
+			name:           "Sentinels of the statefulset not ready yet",
+			workload:       redisfailoverv1.SentinelWorkloadStatefulSet,
+			previous:       true,
+			replicas:       3,
+			running:        3,
+			readySentinels: 1,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment, Sentinels: 3},
+		},
+		{
+			name:           "Sentinels do not agree yet",
+			workload:       redisfailoverv1.SentinelWorkloadStatefulSet,
+			previous:       true,
+			replicas:       3,
+			running:        3,
+			readySentinels: 3,
+			inconsistent:   true,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment, Sentinels: 3},
+		},
+		{
+			name:           "Sentinel removed from the deployment",
+			workload:       redisfailoverv1.SentinelWorkloadStatefulSet,
+			previous:       true,
+			replicas:       3,
+			running:        3,
+			readySentinels: 3,
+			expScale:       true,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment, Sentinels: 2},
+		},
+		{
+			name:           "Last sentinel removed not gone yet",
+			workload:       redisfailoverv1.SentinelWorkloadStatefulSet,
+			previous:       true,
+			replicas:       2,
+			running:        3,
+			readySentinels: 3,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment, Sentinels: 2},
+		},
+		{
+			name:           "Empty deployment removed",
+			workload:       redisfailoverv1.SentinelWorkloadStatefulSet,
+			previous:       true,
+			readySentinels: 3,
+			expDelete:      true,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadDeployment},
+		},
+		{
+			name:     "No statefulset left",
+			workload: redisfailoverv1.SentinelWorkloadDeployment,
+		},
+		{
+			name:           "Sentinel removed from the statefulset",
+			workload:       redisfailoverv1.SentinelWorkloadDeployment,
+			previous:       true,
+			replicas:       3,
+			running:        3,
+			readySentinels: 3,
+			expScale:       true,
+			expMigration:   &redisfailoverv1.SentinelMigrationStatus{From: redisfailoverv1.SentinelWorkloadStatefulSet, Sentinels: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Sentinel.WorkloadType = test.workload
+			rf.Status.ReadySentinels = test.readySentinels
+			rf.Status.SentinelMigration = &redisfailoverv1.SentinelMigrationStatus{}
+			if test.inconsistent {
+				rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionFalse, "SentinelsHealed", "")
+			} else {
+				rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionTrue, "SentinelsAgree", "")
+			}
+
+			replicas := test.replicas
+			ms := &mK8SService.Services{}
+			if test.workload == redisfailoverv1.SentinelWorkloadStatefulSet {
+				if test.previous {
+					d := &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Name: sentinelName, Namespace: namespace},
+						Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+						Status:     appsv1.DeploymentStatus{Replicas: test.running},
+					}
+					ms.On("GetDeployment", namespace, sentinelName).Once().Return(d, nil)
+				} else {
+					ms.On("GetDeployment", namespace, sentinelName).Once().Return(nil, errors.NewNotFound(schema.GroupResource{}, sentinelName))
+				}
+				if test.expScale {
+					ms.On("UpdateDeployment", namespace, mock.MatchedBy(func(d *appsv1.Deployment) bool {
+						return *d.Spec.Replicas == test.replicas-1
+					})).Once().Return(nil)
+				}
+				if test.expDelete {
+					ms.On("DeleteDeployment", namespace, sentinelName).Once().Return(nil)
+				}
+			} else {
+				if test.previous {
+					ss := &appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Name: sentinelName, Namespace: namespace},
+						Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+						Status:     appsv1.StatefulSetStatus{Replicas: test.running},
+					}
+					ms.On("GetStatefulSet", namespace, sentinelName).Once().Return(ss, nil)
+				} else {
+					ms.On("GetStatefulSet", namespace, sentinelName).Once().Return(nil, errors.NewNotFound(schema.GroupResource{}, sentinelName))
+				}
+				if test.expScale {
+					ms.On("UpdateStatefulSet", namespace, mock.MatchedBy(func(ss *appsv1.StatefulSet) bool {
+						return *ss.Spec.Replicas == test.replicas-1
+					})).Once().Return(nil)
+					ms.On("DeletePersistentVolumeClaim", namespace, "sentinel-config-writable-rfs-test-2").Once().Return(nil)
+				}
+			}
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureSentinelMigration(rf)
+			assert.NoError(err)
+			assert.Equal(test.expMigration, rf.Status.SentinelMigration)
+			ms.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/service/k8s"
+)
+
+const (
+	defaultRedisUser       = "default"
+	generatedPasswordBytes = 32
+)
+
+var (
+	pingerUserRules = []string{"-@all", "+ping"}
+	// operatorUserRules only allow the commands the operator runs to check and heal the redises. CONFIG GET
+	// reads the replica-priority of the candidates to promote and the custom config applied. CONFIG SET
+	// applies the custom config, the replica-priority of the switchovers and the masterauth of the password
+	// rotations. ACL can't restrict it to some parameters, so it can rewrite requirepass as well: that gives
+	// nothing the operator doesn't already have, it holds the auth secret. ACL SETUSER and DELUSER manage the
+	// users of the spec.
+	operatorUserRules = []string{"-@all", "+ping", "+info", "+slaveof", "+replicaof", "+config|get", "+config|set", "+config|rewrite", "+bgsave", "+lastsave", "+acl|setuser", "+acl|deluser"}
+)
+
+// redisUser is an ACL user that must exist on every redis of the failover
+type redisUser struct {
+	name     string
+	password string
+	rules    []string
+}
+
+// aclRules returns the ACL rules defining the user. The password is given hashed so it is never
+// written in clear in the redis configuration.
+func (u redisUser) aclRules() []string {
+	sum := sha256.Sum256([]byte(u.password))
+	return append([]string{"on", "#" + hex.EncodeToString(sum[:])}, u.rules...)
+}
+
+// getRedisUsers returns the users of the operator followed by the ones defined in the spec, with their passwords
+// read from the secrets.
+func getRedisUsers(s k8s.Services, rf *redisfailoverv1.RedisFailover) ([]redisUser, error) {
+	secret, err := s.GetSecret(rf.Namespace, GetRedisUsersSecretName(rf))
+	if err != nil {
+		return nil, err
+	}
+	users := make([]redisUser, 0, len(rf.Spec.Auth.Users)+2)
+	for _, user := range []redisUser{
+		{name: redisfailoverv1.PingerUser, rules: pingerUserRules},
+		{name: redisfailoverv1.OperatorUser, rules: operatorUserRules},
+	} {
+		password, ok := secret.Data[user.name]
+		if !ok {
+			return nil, fmt.Errorf("secret \"%s\" does not have a %s field", secret.Name, user.name)
+		}
+		user.password = string(password)
+		users = append(users, user)
+	}
+
+	for _, user := range rf.Spec.Auth.Users {
+		userSecret, err := s.GetSecret(rf.Namespace, user.SecretPath)
+		if err != nil {
+			return nil, err
+		}
+		password, ok := userSecret.Data["password"]
+		if !ok {
+			return nil, fmt.Errorf("secret \"%s\" does not have a password field", user.SecretPath)
+		}
+		users = append(users, redisUser{name: user.Name, password: string(password), rules: user.Rules})
+	}
+	return users, nil
+}
+
+// getOperatorCredentials returns the user and password the operator connects to the redises with.
+// Without auth the default user has no password and the operator keeps using it.
+func getOperatorCredentials(s k8s.Services, rf *redisfailoverv1.RedisFailover) (string, string, error) {
+	if rf.Spec.Auth.SecretPath == "" {
+		return "", "", nil
+	}
+	secret, err := s.GetSecret(rf.Namespace, GetRedisUsersSecretName(rf))
+	if err != nil {
+		return "", "", err
+	}
+	password, ok := secret.Data[redisfailoverv1.OperatorUser]
+	if !ok {
+		return "", "", fmt.Errorf("secret \"%s\" does not have a %s field", secret.Name, redisfailoverv1.OperatorUser)
+	}
+	return redisfailoverv1.OperatorUser, string(password), nil
+}
+
+// GetBackupAgentToken returns the token the backup agents of the failover accept the requests of the operator with
+func GetBackupAgentToken(s k8s.Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	secret, err := s.GetSecret(rf.Namespace, GetBackupAgentSecretName(rf))
+	if err != nil {
+		return "", err
+	}
+	token, ok := secret.Data[backupAgentTokenKey]
+	if !ok {
+		return "", fmt.Errorf("secret \"%s\" does not have a %s field", secret.Name, backupAgentTokenKey)
+	}
+	return string(token), nil
+}
+
+func generatePassword() ([]byte, error) {
+	b := make([]byte, generatedPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,100 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+// resolveSplitBrain keeps a single master when more than one redis works as master. The master kept is
+// the one monitored by a majority of the sentinels or, when there is no majority, the one with the
+// highest replication offset. The other masters are made replicas of it, which drops the writes they
+// received since the split, so the offsets of the demoted masters are published in an event.
+func (r *RedisFailoverHandler) resolveSplitBrain(ctx context.Context, rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	masters := obs.MastersIPs()
+	if len(masters) < 2 {
+		return nil
+	}
+
+	// Only the redises that answered are masters of the observation, so every master has its offset
+	offsets := make(map[string]int64, len(masters))
+	for _, ip := range masters {
+		observed, _ := obs.Redis(ip)
+		offsets[ip] = observed.Replication.MasterReplOffset
+	}
+
+	master, reason := electSplitBrainMaster(rf, obs, masters, offsets)
+	logger.Warningf("Resolving split-brain between masters %s, keeping %s as %s", strings.Join(masters, ", "), master, reason)
+
+	podNames := map[string]string{}
+	for _, pod := range obs.RedisPods {
+		podNames[pod.Status.PodIP] = pod.Name
+	}
+
+	demoted := []string{}
+	for _, ip := range masters {
+		if ip == master {
+			continue
+		}
+		if err := r.rfHealer.MakeSlaveOf(ctx, ip, master, rf); err != nil {
+			return err
+		}
+		demoted = append(demoted, fmt.Sprintf("%s at offset %d (%s)", podDescription(podNames, ip), offsets[ip], offsetDelta(offsets[ip]-offsets[master])))
+	}
+
+	r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonSplitBrainResolved,
+		"Kept %s at offset %d as master, %s. Writes received by the demoted masters may be lost: %s",
+		podDescription(podNames, master), offsets[master], reason, strings.Join(demoted, ", "))
+	return nil
+}
+
+// electSplitBrainMaster returns the master to keep and why it was chosen
+func electSplitBrainMaster(rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation, masters []string, offsets map[string]int64) (string, string) {
+	votes := map[string]int{}
+	for _, s := range obs.Sentinels {
+		if s.MonitorErr != nil {
+			continue
+		}
+		for _, ip := range masters {
+			if redis.SameAddress(s.MonitorAddress, obs.RedisAddress(rf, ip)) {
+				votes[ip]++
+				break
+			}
+		}
+	}
+	for _, ip := range masters {
+		if votes[ip] > len(obs.Sentinels)/2 {
+			return ip, fmt.Sprintf("monitored by %d of %d sentinels", votes[ip], len(obs.Sentinels))
+		}
+	}
+
+	master := masters[0]
+	for _, ip := range masters[1:] {
+		if offsets[ip] > offsets[master] {
+			master = ip
+		}
+	}
+	return master, "sentinels have no majority and it has the highest replication offset"
+}
+
+func podDescription(podNames map[string]string, ip string) string {
+	if name, ok := podNames[ip]; ok {
+		return fmt.Sprintf("pod %s (%s)", name, ip)
+	}
+	return ip
+}
+
+func offsetDelta(delta int64) string {
+	if delta > 0 {
+		return fmt.Sprintf("%d bytes ahead of the master kept", delta)
+	}
+	return fmt.Sprintf("%d bytes behind the master kept", -delta)
+}
@@ -0,0 +1,101 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+func TestCheckAndHealSplitBrain(t *testing.T) {
+	tests := []struct {
+		name        string
+		resolution  redisfailoverv1.SplitBrainResolutionMode
+		monitors    []string
+		expErr      bool
+		expMaster   string
+		expDemoted  string
+		expEventMsg string
+	}{
+		{
+			name:       "Manual resolution",
+			resolution: redisfailoverv1.SplitBrainResolutionManual,
+			expErr:     true,
+		},
+		{
+			name:        "Master of the sentinels majority is kept",
+			resolution:  redisfailoverv1.SplitBrainResolutionAutomatic,
+			monitors:    []string{"0.0.0.2", "0.0.0.2", "0.0.0.1"},
+			expMaster:   "0.0.0.2",
+			expDemoted:  "0.0.0.1",
+			expEventMsg: "Warning SplitBrainResolved Kept pod rfr-test-1 (0.0.0.2) at offset 100 as master, monitored by 2 of 3 sentinels. Writes received by the demoted masters may be lost: pod rfr-test-0 (0.0.0.1) at offset 150 (50 bytes ahead of the master kept)",
+		},
+		{
+			name:        "Master with the highest offset is kept without majority",
+			resolution:  redisfailoverv1.SplitBrainResolutionAutomatic,
+			monitors:    []string{"0.0.0.2", "0.0.0.1", "0.0.0.3"},
+			expMaster:   "0.0.0.1",
+			expDemoted:  "0.0.0.2",
+			expEventMsg: "Warning SplitBrainResolved Kept pod rfr-test-0 (0.0.0.1) at offset 150 as master, sentinels have no majority and it has the highest replication offset. Writes received by the demoted masters may be lost: pod rfr-test-1 (0.0.0.2) at offset 100 (50 bytes behind the master kept)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Spec.Redis.SplitBrainResolution = test.resolution
+
+			sentinels := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
+			mrfh.On("SetRedisUsers", mock.Anything, mock.Anything, rf).Times(3).Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, mock.Anything, rf).Times(3).Return(nil)
+			obs := generateObservation(rf, "0.0.0.1", []string{"0.0.0.2", "0.0.0.3"}, sentinels)
+			obs.Redises[0].Replication = redis.ReplicationInfo{Role: "master", MasterReplID: "a", MasterReplOffset: 150}
+			obs.Redises[1].Replication = redis.ReplicationInfo{Role: "master", MasterReplID: "b", MasterReplOffset: 100}
+			for i, monitor := range test.monitors {
+				obs.Sentinels[i].MonitorAddress = monitor
+			}
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
+			if test.resolution == redisfailoverv1.SplitBrainResolutionAutomatic {
+				mrfh.On("MakeSlaveOf", mock.Anything, test.expDemoted, test.expMaster, rf).Once().Return(nil)
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, recorder, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				<-recorder.Events // MultipleMasters
+				assert.Equal(test.expEventMsg, <-recorder.Events)
+			}
+			assert.False(rf.IsStatusConditionTrue(redisfailoverv1.ConditionMasterElected))
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+// UpdateStatus completes the status observed during the reconcile (ready pods, replicas and selector of
+// the scale subresource, update progress, master pod, conditions and phase) and persists it on the status subresource if it differs from oldStatus.
+// A write refused because the failover changed meanwhile is tried again on the current failover. Failing to write the status
+// never fails the reconcile, it queues another one instead.
+func (r *RedisFailoverHandler) UpdateStatus(ctx context.Context, rf *redisfailoverv1.RedisFailover, oldStatus *redisfailoverv1.RedisFailoverStatus, reconcileErr error) {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	rf.Status.ObservedGeneration = rf.Generation
+
+	if ss, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf)); err == nil {
+		rf.Status.ReadyRedises = ss.Status.ReadyReplicas
+		rf.Status.Replicas = ss.Status.Replicas
+		if selector, err := metav1.LabelSelectorAsSelector(ss.Spec.Selector); err == nil {
+			rf.Status.Selector = selector.String()
+		}
+		if ss.Status.UpdatedReplicas < ss.Status.Replicas || rf.RequestedRedisPods() != rf.Spec.Redis.Replicas {
+			update := getUpdateStatus(rf)
+			update.UpdatedRedises = ss.Status.UpdatedReplicas
+			update.Redises = ss.Status.Replicas
+		} else {
+			// Every pod runs the last revision and the surge replica is gone, there is no update in progress
+			rf.Status.Update = nil
+		}
+	}
+	if rf.SentinelsAllowed() {
+		if rf.SentinelStatefulSet() {
+			if ss, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetSentinelName(rf)); err == nil {
+				rf.Status.ReadySentinels = ss.Status.ReadyReplicas
+			}
+		} else if d, err := r.k8sservice.GetDeployment(rf.Namespace, rfservice.GetSentinelName(rf)); err == nil {
+			rf.Status.ReadySentinels = d.Status.ReadyReplicas
+		}
+	} else {
+		rf.Status.ReadySentinels = 0
+	}
+
+	rf.Status.Master.PodName = ""
+	if rf.Status.Master.IP != "" && !rf.Bootstrapping() {
+		if pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf)); err == nil {
+			for _, pod := range pods.Items {
+				if pod.Status.PodIP == rf.Status.Master.IP {
+					rf.Status.Master.PodName = pod.Name
+					break
+				}
+			}
+		}
+	}
+	if oldStatus.Master.PodName != "" && rf.Status.Master.PodName != "" && oldStatus.Master.PodName != rf.Status.Master.PodName {
+		now := metav1.Now()
+		rf.Status.LastFailoverTime = &now
+		logger.Infof("Master moved from %s to %s", oldStatus.Master.PodName, rf.Status.Master.PodName)
+	}
+
+	podsReady := rf.Status.ReadyRedises >= rf.Spec.Redis.Replicas
+	if rf.SentinelsAllowed() {
+		podsReady = podsReady && rf.Status.ReadySentinels >= rf.Spec.Sentinel.Replicas
+	}
+
+	switch {
+	case reconcileErr != nil:
+		rf.SetStatusCondition(redisfailoverv1.ConditionDegraded, metav1.ConditionTrue, "ReconcileError", reconcileErr.Error())
+	case !podsReady:
+		rf.SetStatusCondition(redisfailoverv1.ConditionDegraded, metav1.ConditionTrue, "PodsNotReady", fmt.Sprintf("%d/%d redis and %d/%d sentinel pods ready", rf.Status.ReadyRedises, rf.Spec.Redis.Replicas, rf.Status.ReadySentinels, rf.Spec.Sentinel.Replicas))
+	default:
+		rf.SetStatusCondition(redisfailoverv1.ConditionDegraded, metav1.ConditionFalse, "AsExpected", "")
+	}
+
+	ready := reconcileErr == nil && podsReady && rf.IsStatusConditionTrue(redisfailoverv1.ConditionMasterElected) &&
+		(!rf.SentinelsAllowed() || rf.IsStatusConditionTrue(redisfailoverv1.ConditionSentinelsConsistent))
+	if ready {
+		rf.SetStatusCondition(redisfailoverv1.ConditionReady, metav1.ConditionTrue, "Converged", "")
+	} else {
+		rf.SetStatusCondition(redisfailoverv1.ConditionReady, metav1.ConditionFalse, "NotConverged", "waiting for the failover to converge")
+	}
+
+	switch {
+	case ready && rf.Bootstrapping():
+		rf.Status.Phase = redisfailoverv1.PhaseBootstrapping
+	case ready:
+		rf.Status.Phase = redisfailoverv1.PhaseReady
+	case oldStatus.Phase == "" || oldStatus.Phase == redisfailoverv1.PhasePending:
+		// Never converged yet, the failover is still being created
+		rf.Status.Phase = redisfailoverv1.PhasePending
+	default:
+		rf.Status.Phase = redisfailoverv1.PhaseDegraded
+	}
+
+	if equality.Semantic.DeepEqual(oldStatus, &rf.Status) {
+		return
+	}
+
+	update := rf
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := r.k8sservice.UpdateRedisFailoverStatus(ctx, rf.Namespace, update, metav1.UpdateOptions{})
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+		// The failover changed since it was read, the status is written again on top of the current one
+		current, getErr := r.k8sservice.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+		if getErr != nil {
+			return getErr
+		}
+		current.Status = *rf.Status.DeepCopy()
+		update = current
+		return err
+	})
+	if err != nil {
+		// The status is not lost, the next reconcile computes it again
+		logger.Warningf("Unable to update status, reconciling again: %s", err.Error())
+		r.queue.AddRateLimited(rf.Namespace, rf.Name)
+	}
+}
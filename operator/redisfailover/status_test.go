@@ -0,0 +1,311 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		readyRedises       int32
+		readySentinels     int32
+		masterIP           string
+		oldMasterPod       string
+		oldPhase           redisfailoverv1.RedisFailoverPhase
+		reconcileErr       error
+		expPhase           redisfailoverv1.RedisFailoverPhase
+		expMasterPod       string
+		expReady           bool
+		expDegraded        bool
+		expFailoverTimeSet bool
+	}{
+		{
+			name:           "Everything ready",
+			readyRedises:   3,
+			readySentinels: 3,
+			masterIP:       "0.0.0.1",
+			expPhase:       redisfailoverv1.PhaseReady,
+			expMasterPod:   "redis-0",
+			expReady:       true,
+		},
+		{
+			name:           "Pods not ready on creation",
+			readyRedises:   1,
+			readySentinels: 3,
+			masterIP:       "0.0.0.1",
+			expPhase:       redisfailoverv1.PhasePending,
+			expMasterPod:   "redis-0",
+			expDegraded:    true,
+		},
+		{
+			name:           "Reconcile error after being ready",
+			readyRedises:   3,
+			readySentinels: 3,
+			masterIP:       "0.0.0.1",
+			oldPhase:       redisfailoverv1.PhaseReady,
+			reconcileErr:   errors.New(""),
+			expPhase:       redisfailoverv1.PhaseDegraded,
+			expMasterPod:   "redis-0",
+			expDegraded:    true,
+		},
+		{
+			name:               "Master moved",
+			readyRedises:       3,
+			readySentinels:     3,
+			masterIP:           "0.0.0.2",
+			oldMasterPod:       "redis-0",
+			oldPhase:           redisfailoverv1.PhaseReady,
+			expPhase:           redisfailoverv1.PhaseReady,
+			expMasterPod:       "redis-1",
+			expReady:           true,
+			expFailoverTimeSet: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Status.Phase = test.oldPhase
+			rf.Status.Master.PodName = test.oldMasterPod
+			oldStatus := rf.Status.DeepCopy()
+
+			rf.Status.Master.IP = test.masterIP
+			rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionTrue, "MasterElected", "")
+			rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionTrue, "SentinelsAgree", "")
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "redis-0"}, Status: corev1.PodStatus{PodIP: "0.0.0.1"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "redis-1"}, Status: corev1.PodStatus{PodIP: "0.0.0.2"}},
+				},
+			}
+
+			mk := &mK8SService.Services{}
+			mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ReadyReplicas: test.readyRedises}}, nil)
+			mk.On("GetDeployment", namespace, mock.Anything).Once().Return(&appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: test.readySentinels}}, nil)
+			mk.On("GetStatefulSetPods", namespace, mock.Anything).Once().Return(pods, nil)
+			mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(rf, nil)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			handler.UpdateStatus(context.TODO(), rf, oldStatus, test.reconcileErr)
+
+			assert.Equal(test.expPhase, rf.Status.Phase)
+			assert.Equal(test.expMasterPod, rf.Status.Master.PodName)
+			assert.Equal(test.readyRedises, rf.Status.ReadyRedises)
+			assert.Equal(test.readySentinels, rf.Status.ReadySentinels)
+			assert.Equal(test.expReady, rf.IsStatusConditionTrue(redisfailoverv1.ConditionReady))
+			assert.Equal(test.expDegraded, rf.IsStatusConditionTrue(redisfailoverv1.ConditionDegraded))
+			assert.Equal(test.expFailoverTimeSet, rf.Status.LastFailoverTime != nil)
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateStatusUnchanged(t *testing.T) {
+	rf := generateRF(false, false, false)
+	rf.Status.Master.IP = "0.0.0.1"
+	rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionTrue, "MasterElected", "")
+	rf.SetStatusCondition(redisfailoverv1.ConditionSentinelsConsistent, metav1.ConditionTrue, "SentinelsAgree", "")
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-0"}, Status: corev1.PodStatus{PodIP: "0.0.0.1"}},
+		},
+	}
+
+	mk := &mK8SService.Services{}
+	mk.On("GetStatefulSet", namespace, mock.Anything).Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ReadyReplicas: 3}}, nil)
+	mk.On("GetDeployment", namespace, mock.Anything).Return(&appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: 3}}, nil)
+	mk.On("GetStatefulSetPods", namespace, mock.Anything).Return(pods, nil)
+	mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(rf, nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+
+	// First call converges the status and writes it, the second one finds nothing to update.
+	handler.UpdateStatus(context.TODO(), rf, rf.Status.DeepCopy(), nil)
+	handler.UpdateStatus(context.TODO(), rf, rf.Status.DeepCopy(), nil)
+
+	mk.AssertExpectations(t)
+	mk.AssertNumberOfCalls(t, "UpdateRedisFailoverStatus", 1)
+}
+
+func TestUpdateStatusWriteFailure(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Group: "databases.spotahome.com", Resource: "redisfailovers"}, "test", errors.New(""))
+
+	tests := []struct {
+		name     string
+		writeErr error
+		expQueue bool
+	}{
+		{
+			name:     "Failover changed meanwhile",
+			writeErr: conflict,
+		},
+		{
+			name:     "Status not written",
+			writeErr: errors.New(""),
+			expQueue: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.ResourceVersion = "1"
+			oldStatus := rf.Status.DeepCopy()
+
+			mk := &mK8SService.Services{}
+			mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ReadyReplicas: 3}}, nil)
+			mk.On("GetDeployment", namespace, mock.Anything).Once().Return(&appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: 3}}, nil)
+			mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(nil, test.writeErr)
+			if test.writeErr == conflict {
+				// The status is written on the failover read again
+				current := generateRF(false, false, false)
+				current.ResourceVersion = "2"
+				mk.On("GetRedisFailover", mock.Anything, namespace, rf.Name).Once().Return(current, nil)
+				mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, mock.MatchedBy(func(update *redisfailoverv1.RedisFailover) bool {
+					return update.ResourceVersion == "2" && update.Status.Phase == rf.Status.Phase
+				}), metav1.UpdateOptions{}).Once().Return(current, nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			keys := make(chan types.NamespacedName, 1)
+			go handler.ReconcileQueue().Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+				keys <- key
+				return nil
+			}, log.Dummy)
+
+			handler.UpdateStatus(context.TODO(), rf, oldStatus, nil)
+
+			select {
+			case key := <-keys:
+				assert.True(test.expQueue, "reconcile queued")
+				assert.Equal(types.NamespacedName{Namespace: namespace, Name: rf.Name}, key)
+			case <-time.After(100 * time.Millisecond):
+				assert.False(test.expQueue, "reconcile not queued")
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateStatusUpdateProgress(t *testing.T) {
+	tests := []struct {
+		name            string
+		replicas        int32
+		updatedReplicas int32
+		expUpdate       bool
+	}{
+		{
+			name:            "Update in progress",
+			replicas:        3,
+			updatedReplicas: 1,
+			expUpdate:       true,
+		},
+		{
+			name:            "All pods updated",
+			replicas:        3,
+			updatedReplicas: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Status.Update = &redisfailoverv1.RedisUpdateStatus{BlockedReason: "update is paused"}
+			oldStatus := rf.Status.DeepCopy()
+
+			mk := &mK8SService.Services{}
+			mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{Replicas: test.replicas, UpdatedReplicas: test.updatedReplicas}}, nil)
+			mk.On("GetDeployment", namespace, mock.Anything).Once().Return(&appsv1.Deployment{}, nil)
+			mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(rf, nil)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			handler.UpdateStatus(context.TODO(), rf, oldStatus, nil)
+
+			if test.expUpdate {
+				if assert.NotNil(rf.Status.Update) {
+					assert.Equal(test.updatedReplicas, rf.Status.Update.UpdatedRedises)
+					assert.Equal(test.replicas, rf.Status.Update.Redises)
+					assert.Equal("update is paused", rf.Status.Update.BlockedReason)
+				}
+			} else {
+				assert.Nil(rf.Status.Update)
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateStatusScale(t *testing.T) {
+	tests := []struct {
+		name      string
+		scaleDown *redisfailoverv1.RedisScaleDownStatus
+	}{
+		{
+			name: "Replicas running",
+		},
+		{
+			name:      "Scale down waiting for the master",
+			scaleDown: &redisfailoverv1.RedisScaleDownStatus{Phase: redisfailoverv1.ScaleDownMovingMaster, Redises: 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Status.ScaleDown = test.scaleDown
+			oldStatus := rf.Status.DeepCopy()
+
+			ss := &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": name}},
+				},
+				Status: appsv1.StatefulSetStatus{Replicas: rf.RedisPods(), UpdatedReplicas: rf.RedisPods()},
+			}
+			mk := &mK8SService.Services{}
+			mk.On("GetStatefulSet", namespace, mock.Anything).Once().Return(ss, nil)
+			mk.On("GetDeployment", namespace, mock.Anything).Once().Return(&appsv1.Deployment{}, nil)
+			mk.On("UpdateRedisFailoverStatus", mock.Anything, namespace, rf, metav1.UpdateOptions{}).Once().Return(rf, nil)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, mk, metrics.Dummy, &record.FakeRecorder{}, log.Dummy)
+			handler.UpdateStatus(context.TODO(), rf, oldStatus, nil)
+
+			assert.Equal(rf.RedisPods(), rf.Status.Replicas)
+			assert.Equal("app.kubernetes.io/component=redis,app.kubernetes.io/name=test", rf.Status.Selector)
+			// The pods kept by a scale down are not taken for an update
+			assert.Nil(rf.Status.Update)
+			mk.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+const (
+	// switchoverReplicaPriority is given to the target of a switchover so sentinel promotes it
+	// before any other replica, that keeps the default priority.
+	switchoverReplicaPriority = "1"
+	// defaultReplicaPriority is given back to the targets of the switchovers started by a release not
+	// keeping their priority in the status.
+	defaultReplicaPriority = "100"
+)
+
+var (
+	// switchoverTimeout is how long the sentinels have to agree on the new master once one accepted the failover.
+	switchoverTimeout = 30 * time.Second
+	// switchoverPollInterval is the wait before checking again the sentinels of a switchover in progress.
+	switchoverPollInterval = time.Second
+)
+
+// checkAndHealSwitchover moves the master to the pod requested through the switchover annotation.
+// The target needs to be in sync with the current master, otherwise the request is kept until it is.
+// Once a sentinel accepts the failover, the switchover is followed by the next reconciles. When it succeeds
+// or fails the annotation is removed and the outcome is kept in the status.
+func (r *RedisFailoverHandler) checkAndHealSwitchover(ctx context.Context, rf *redisfailoverv1.RedisFailover, master string, sentinels []string) error {
+	target := rf.SwitchoverTarget()
+	if target == "" {
+		return nil
+	}
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	if rf.Bootstrapping() {
+		return r.finishSwitchover(rf, target, redisfailoverv1.SwitchoverFailed, "switchover is not supported while bootstrapping")
+	}
+
+	pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+	targetIP := ""
+	for _, pod := range pods.Items {
+		if pod.Name == target {
+			targetIP = pod.Status.PodIP
+			break
+		}
+	}
+	if targetIP == "" {
+		return r.finishSwitchover(rf, target, redisfailoverv1.SwitchoverFailed, fmt.Sprintf("pod %s is not a running redis of this failover", target))
+	}
+	if targetIP == master {
+		return r.finishSwitchover(rf, target, redisfailoverv1.SwitchoverSucceeded, fmt.Sprintf("pod %s is the master", target))
+	}
+
+	ready, err := r.rfChecker.CheckRedisSlavesReady(ctx, targetIP, rf)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		logger.Infof("Switchover to %s waiting for it to be in sync with the master", target)
+		r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonSwitchoverPending, "Waiting for pod %s to be in sync with the master before switching over", target)
+		return nil
+	}
+
+	logger.Infof("Switching over master from %s to %s (%s)", master, target, targetIP)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonSwitchoverStarted, "Switching over master from %s to pod %s", master, target)
+
+	priority, err := r.raiseReplicaPriority(ctx, rf, targetIP)
+	if err != nil {
+		return err
+	}
+	if err := r.sentinelFailover(ctx, rf, sentinels); err != nil {
+		r.restoreReplicaPriority(ctx, rf, target, targetIP, priority)
+		return r.finishSwitchover(rf, target, redisfailoverv1.SwitchoverFailed, fmt.Sprintf("sentinel failover failed: %s", err.Error()))
+	}
+	r.setSwitchoverInProgress(rf, target, priority, fmt.Sprintf("switching over master from %s to pod %s", master, target))
+	return nil
+}
+
+// sentinelFailover asks the sentinels, one after the other, to failover until one of them accepts.
+func (r *RedisFailoverHandler) sentinelFailover(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string) error {
+	err := fmt.Errorf("no sentinel available")
+	for _, sip := range sentinels {
+		if err = r.rfHealer.SentinelFailover(ctx, sip, rf); err == nil {
+			return nil
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s refused the failover: %s", sip, err.Error())
+	}
+	return err
+}
+
+// setSwitchoverInProgress keeps the switchover a sentinel accepted in the status, with the priority to give
+// the target back. The next reconciles follow it until all the sentinels monitor the target, instead of
+// waiting for them in this one.
+func (r *RedisFailoverHandler) setSwitchoverInProgress(rf *redisfailoverv1.RedisFailover, target string, priority string, message string) {
+	rf.Status.LastSwitchover = &redisfailoverv1.SwitchoverStatus{
+		Target:          target,
+		Result:          redisfailoverv1.SwitchoverInProgress,
+		Message:         message,
+		Time:            metav1.Now(),
+		ReplicaPriority: priority,
+	}
+	r.queue.AddAfter(rf.Namespace, rf.Name, switchoverPollInterval)
+}
+
+// checkSwitchoverInProgress follows the switchover accepted by a sentinel on a previous reconcile. It
+// succeeds once all the sentinels monitor the target, and fails when they don't after switchoverTimeout.
+// Meanwhile nothing else is fixed, the sentinels are moving the master.
+func (r *RedisFailoverHandler) checkSwitchoverInProgress(ctx context.Context, rf *redisfailoverv1.RedisFailover, obs *rfservice.ClusterObservation) error {
+	switchover := rf.Status.LastSwitchover
+	targetIP := ""
+	for _, pod := range obs.RedisPods {
+		if pod.Name == switchover.Target {
+			targetIP = pod.Status.PodIP
+			break
+		}
+	}
+
+	pending := len(obs.Sentinels)
+	if targetIP != "" {
+		pending = len(decideSentinelActions(rf, obs, targetIP, getRedisPort(rf.Spec.Redis.Port)).of(healSentinelMonitor))
+	}
+	switch {
+	case pending == 0:
+		r.restoreReplicaPriority(ctx, rf, switchover.Target, targetIP, switchover.ReplicaPriority)
+		rf.Status.Master = redisfailoverv1.MasterStatus{IP: targetIP}
+		rf.SetStatusCondition(redisfailoverv1.ConditionMasterElected, metav1.ConditionTrue, "MasterElected", fmt.Sprintf("redis %s is the master", targetIP))
+		// The replicas and the sentinels are checked against the new master right away
+		r.queue.Add(rf.Namespace, rf.Name)
+		return r.finishSwitchover(rf, switchover.Target, redisfailoverv1.SwitchoverSucceeded, fmt.Sprintf("master moved to pod %s", switchover.Target))
+	case time.Since(switchover.Time.Time) > switchoverTimeout:
+		if targetIP != "" {
+			r.restoreReplicaPriority(ctx, rf, switchover.Target, targetIP, switchover.ReplicaPriority)
+		}
+		return r.finishSwitchover(rf, switchover.Target, redisfailoverv1.SwitchoverFailed, fmt.Sprintf("%d of %d sentinels do not monitor pod %s after %s", pending, len(obs.Sentinels), switchover.Target, switchoverTimeout))
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Switchover to %s waiting for %d sentinels to monitor it", switchover.Target, pending)
+	r.queue.AddAfter(rf.Namespace, rf.Name, switchoverPollInterval)
+	return nil
+}
+
+// raiseReplicaPriority gives the target of a switchover the priority making sentinel promote it, and returns
+// the priority it had before, a replica-priority of the custom config included.
+func (r *RedisFailoverHandler) raiseReplicaPriority(ctx context.Context, rf *redisfailoverv1.RedisFailover, targetIP string) (string, error) {
+	priority, err := r.rfChecker.GetRedisReplicaPriority(ctx, targetIP, rf)
+	if err != nil {
+		return "", err
+	}
+	if err := r.rfHealer.SetReplicaPriority(ctx, targetIP, switchoverReplicaPriority, rf); err != nil {
+		return "", err
+	}
+	return priority, nil
+}
+
+// restoreReplicaPriority gives the target of a switchover the priority it had before back, whatever the outcome.
+func (r *RedisFailoverHandler) restoreReplicaPriority(ctx context.Context, rf *redisfailoverv1.RedisFailover, target string, targetIP string, priority string) {
+	if priority == "" {
+		priority = defaultReplicaPriority
+	}
+	if err := r.rfHealer.SetReplicaPriority(ctx, targetIP, priority, rf); err != nil {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Unable to restore the replica priority of %s: %s", target, err.Error())
+	}
+}
+
+// finishSwitchover keeps the outcome of the switchover in the status and removes the request, unless
+// another pod was requested meanwhile.
+func (r *RedisFailoverHandler) finishSwitchover(rf *redisfailoverv1.RedisFailover, target string, result redisfailoverv1.SwitchoverResult, message string) error {
+	rf.Status.LastSwitchover = &redisfailoverv1.SwitchoverStatus{
+		Target:  target,
+		Result:  result,
+		Message: message,
+		Time:    metav1.Now(),
+	}
+
+	if result == redisfailoverv1.SwitchoverSucceeded {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Switchover to %s succeeded: %s", target, message)
+		r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonSwitchoverSucceeded, "Switchover to pod %s succeeded: %s", target, message)
+	} else {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Switchover to %s failed: %s", target, message)
+		r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonSwitchoverFailed, "Switchover to pod %s failed: %s", target, message)
+	}
+
+	if rf.SwitchoverTarget() != target {
+		return nil
+	}
+	updated, err := r.k8sservice.RemoveRedisFailoverAnnotation(context.TODO(), rf.Namespace, rf.Name, redisfailoverv1.SwitchoverAnnotation)
+	if err != nil {
+		return err
+	}
+	// Keep the new resource version so the status can still be written at the end of the reconcile.
+	rf.ResourceVersion = updated.ResourceVersion
+	rf.Annotations = updated.Annotations
+	return nil
+}
@@ -0,0 +1,287 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+)
+
+func TestCheckAndHealSwitchover(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		targetReady        bool
+		sentinelFailoverOK bool
+		expFailover        bool
+		expRequestCleared  bool
+		expResult          redisfailoverv1.SwitchoverResult
+		expMaster          string
+	}{
+		{
+			name:              "Target is not a redis of the failover",
+			target:            "rfr-other-0",
+			expRequestCleared: true,
+			expResult:         redisfailoverv1.SwitchoverFailed,
+			expMaster:         "0.0.0.0",
+		},
+		{
+			name:              "Target is already the master",
+			target:            "rfr-test-0",
+			expRequestCleared: true,
+			expResult:         redisfailoverv1.SwitchoverSucceeded,
+			expMaster:         "0.0.0.0",
+		},
+		{
+			name:        "Target not in sync waits",
+			target:      "rfr-test-1",
+			targetReady: false,
+			expMaster:   "0.0.0.0",
+		},
+		{
+			name:               "Sentinel refuses the failover",
+			target:             "rfr-test-1",
+			targetReady:        true,
+			expFailover:        true,
+			sentinelFailoverOK: false,
+			expRequestCleared:  true,
+			expResult:          redisfailoverv1.SwitchoverFailed,
+			expMaster:          "0.0.0.0",
+		},
+		{
+			name:               "Switchover accepted is followed by the next reconciles",
+			target:             "rfr-test-1",
+			targetReady:        true,
+			expFailover:        true,
+			sentinelFailoverOK: true,
+			expResult:          redisfailoverv1.SwitchoverInProgress,
+			expMaster:          "0.0.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Annotations = map[string]string{redisfailoverv1.SwitchoverAnnotation: test.target}
+
+			master := "0.0.0.0"
+			targetIP := "0.0.0.1"
+			sentinel := "1.1.1.1"
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			// Healthy failover with a single sentinel
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(generateObservation(rf, master, nil, []string{sentinel}), nil)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
+			mrfc.On("GetRedisRevisionHash", "rfr-test-0", rf).Once().Return("1", nil)
+			mrfh.On("SetRedisUsers", mock.Anything, master, rf).Once().Return(nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
+			mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"}, Status: corev1.PodStatus{PodIP: master}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"}, Status: corev1.PodStatus{PodIP: targetIP}},
+				},
+			}
+			mk.On("GetStatefulSetPods", namespace, mock.Anything).Once().Return(pods, nil)
+
+			if test.target == "rfr-test-1" {
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, targetIP, rf).Once().Return(test.targetReady, nil)
+			}
+			if test.expFailover {
+				// The target has a replica-priority of 0 from the custom config, given back after the switchover
+				mrfc.On("GetRedisReplicaPriority", mock.Anything, targetIP, rf).Once().Return("0", nil)
+				mrfh.On("SetReplicaPriority", mock.Anything, targetIP, "1", rf).Once().Return(nil)
+				if test.sentinelFailoverOK {
+					mrfh.On("SentinelFailover", mock.Anything, sentinel, rf).Once().Return(nil)
+				} else {
+					// The priority is only restored once the switchover is over
+					mrfh.On("SentinelFailover", mock.Anything, sentinel, rf).Once().Return(errors.New("NOGOODSLAVE"))
+					mrfh.On("SetReplicaPriority", mock.Anything, targetIP, "0", rf).Once().Return(nil)
+				}
+			}
+			if test.expRequestCleared {
+				cleared := rf.DeepCopy()
+				cleared.ResourceVersion = "2"
+				cleared.Annotations = map[string]string{}
+				mk.On("RemoveRedisFailoverAnnotation", mock.Anything, namespace, name, redisfailoverv1.SwitchoverAnnotation).Once().Return(cleared, nil)
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, recorder, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			assert.NoError(err)
+
+			if test.expRequestCleared {
+				assert.Empty(rf.SwitchoverTarget())
+				assert.Equal("2", rf.ResourceVersion)
+				if assert.NotNil(rf.Status.LastSwitchover) {
+					assert.Equal(test.target, rf.Status.LastSwitchover.Target)
+					assert.Equal(test.expResult, rf.Status.LastSwitchover.Result)
+				}
+			} else {
+				assert.Equal(test.target, rf.SwitchoverTarget())
+				if test.expResult == "" {
+					assert.Nil(rf.Status.LastSwitchover)
+				} else if assert.NotNil(rf.Status.LastSwitchover) {
+					assert.Equal(test.expResult, rf.Status.LastSwitchover.Result)
+					assert.Equal("0", rf.Status.LastSwitchover.ReplicaPriority)
+				}
+			}
+			assert.Equal(test.expMaster, rf.Status.Master.IP)
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckSwitchoverInProgress(t *testing.T) {
+	tests := []struct {
+		name              string
+		sentinelsMoved    bool
+		startedAgo        time.Duration
+		replicaPriority   string
+		expRequestCleared bool
+		expPriority       string
+		expResult         redisfailoverv1.SwitchoverResult
+		expMaster         string
+	}{
+		{
+			name:       "Sentinels not monitoring the target yet are waited for",
+			startedAgo: time.Second,
+			expResult:  redisfailoverv1.SwitchoverInProgress,
+		},
+		{
+			name:              "Switchover succeeds once all the sentinels monitor the target",
+			sentinelsMoved:    true,
+			startedAgo:        time.Second,
+			replicaPriority:   "0",
+			expRequestCleared: true,
+			expPriority:       "0",
+			expResult:         redisfailoverv1.SwitchoverSucceeded,
+			expMaster:         "0.0.0.1",
+		},
+		{
+			name:              "Switchover fails when the sentinels do not monitor the target in time",
+			startedAgo:        time.Minute,
+			replicaPriority:   "50",
+			expRequestCleared: true,
+			expPriority:       "50",
+			expResult:         redisfailoverv1.SwitchoverFailed,
+		},
+		{
+			name:              "Switchover started without the priority kept gives the default one back",
+			startedAgo:        time.Minute,
+			expRequestCleared: true,
+			expPriority:       "100",
+			expResult:         redisfailoverv1.SwitchoverFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Annotations = map[string]string{redisfailoverv1.SwitchoverAnnotation: "rfr-test-1"}
+			rf.Status.LastSwitchover = &redisfailoverv1.SwitchoverStatus{
+				Target:          "rfr-test-1",
+				Result:          redisfailoverv1.SwitchoverInProgress,
+				Time:            metav1.NewTime(time.Now().Add(-test.startedAgo)),
+				ReplicaPriority: test.replicaPriority,
+			}
+
+			master := "0.0.0.0"
+			targetIP := "0.0.0.1"
+			sentinels := []string{"1.1.1.1", "1.1.1.2"}
+			obs := generateObservation(rf, master, []string{targetIP}, sentinels)
+			if test.sentinelsMoved {
+				for i := range obs.Sentinels {
+					obs.Sentinels[i].MonitorAddress = targetIP
+				}
+			}
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			// Nothing is fixed while the sentinels move the master
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master, targetIP}, nil)
+			for _, ip := range []string{master, targetIP} {
+				mrfh.On("SetRedisUsers", mock.Anything, ip, rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, ip, rf).Once().Return(nil)
+			}
+			mrfc.On("Observe", mock.Anything, rf).Once().Return(obs, nil)
+			if test.expRequestCleared {
+				mrfh.On("SetReplicaPriority", mock.Anything, targetIP, test.expPriority, rf).Once().Return(nil)
+				cleared := rf.DeepCopy()
+				cleared.ResourceVersion = "2"
+				cleared.Annotations = map[string]string{}
+				mk.On("RemoveRedisFailoverAnnotation", mock.Anything, namespace, name, redisfailoverv1.SwitchoverAnnotation).Once().Return(cleared, nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, mk, metrics.Dummy, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+			assert.NoError(err)
+
+			if test.expRequestCleared {
+				assert.Empty(rf.SwitchoverTarget())
+			} else {
+				assert.Equal("rfr-test-1", rf.SwitchoverTarget())
+			}
+			if assert.NotNil(rf.Status.LastSwitchover) {
+				assert.Equal(test.expResult, rf.Status.LastSwitchover.Result)
+			}
+			assert.Equal(test.expMaster, rf.Status.Master.IP)
+
+			if test.expResult != redisfailoverv1.SwitchoverFailed {
+				// The next reconcile is asked for, either to check the sentinels again or the new master
+				ctx, cancel := context.WithCancel(context.TODO())
+				keys := make(chan types.NamespacedName, 1)
+				go handler.ReconcileQueue().Run(ctx, 1, func(_ context.Context, key types.NamespacedName) error {
+					keys <- key
+					return nil
+				}, log.Dummy)
+				select {
+				case key := <-keys:
+					assert.Equal(name, key.Name)
+				case <-time.After(2 * time.Second):
+					t.Fatal("no reconcile queued")
+				}
+				cancel()
+			}
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+)
+
+// getReplicationLagReason returns why the update has to wait when a replica is more than maxLag bytes
+// behind the master, or an empty string when all of them are close enough.
+func getReplicationLagReason(obs *rfservice.ClusterObservation, masterIP string, maxLag int64) string {
+	master, _ := obs.Redis(masterIP)
+	for _, redis := range obs.Redises {
+		if redis.IP() == masterIP {
+			continue
+		}
+		if lag := master.Replication.MasterReplOffset - redis.Replication.MasterReplOffset; lag > maxLag {
+			return fmt.Sprintf("redis %s is %d bytes behind the master, more than the %d allowed", redis.IP(), lag, maxLag)
+		}
+	}
+	return ""
+}
+
+// switchoverForUpdate moves the master to an updated replica with a sentinel failover, so the master
+// pod is restarted or removed as a replica on a later round instead of failing over when it is deleted.
+func (r *RedisFailoverHandler) switchoverForUpdate(ctx context.Context, rf *redisfailoverv1.RedisFailover, master string, masterIP string, target string) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+	targetIP := ""
+	for _, pod := range pods.Items {
+		if pod.Name == target {
+			targetIP = pod.Status.PodIP
+			break
+		}
+	}
+	if targetIP == "" {
+		setUpdateBlocked(rf, fmt.Sprintf("pod %s to switch over to is not running", target), true)
+		return nil
+	}
+
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Switching over master from %s to %s (%s) for the update", master, target, targetIP)
+	r.recorder.Eventf(rf, corev1.EventTypeNormal, rfservice.EventReasonSwitchoverStarted, "Switching over master from pod %s to pod %s for the update", master, target)
+
+	priority, err := r.raiseReplicaPriority(ctx, rf, targetIP)
+	if err != nil {
+		return err
+	}
+	if err := r.sentinelFailover(ctx, rf, sentinels); err != nil {
+		r.restoreReplicaPriority(ctx, rf, target, targetIP, priority)
+		logger.Warningf("Switchover to %s for the update failed: %s", target, err.Error())
+		r.recorder.Eventf(rf, corev1.EventTypeWarning, rfservice.EventReasonSwitchoverFailed, "Switchover to pod %s for the update failed: %s", target, err.Error())
+		setUpdateBlocked(rf, fmt.Sprintf("switchover to %s failed: %s", target, err.Error()), true)
+		return nil
+	}
+	r.setSwitchoverInProgress(rf, target, priority, fmt.Sprintf("switching over master from pod %s (%s) to pod %s for the update", master, masterIP, target))
+	// The replicas have to sync with the new master before the old one is restarted
+	setUpdateBlocked(rf, fmt.Sprintf("waiting for the sentinels to move the master to %s", target), false)
+	return nil
+}
+
+// waitSurgePod tells if the surge replica runs, asking for it when it was not yet. The replicas given are
+// the running ones, checked to be in sync with the master before.
+func waitSurgePod(rf *redisfailoverv1.RedisFailover, replicas []string) bool {
+	update := getUpdateStatus(rf)
+	if update.SurgePod == "" {
+		// The statefulset is scaled up by the next ensure
+		update.SurgePod = fmt.Sprintf("%s-%d", rfservice.GetRedisName(rf), rf.Spec.Redis.Replicas)
+	}
+	for _, pod := range replicas {
+		if pod == update.SurgePod {
+			return true
+		}
+	}
+	setUpdateBlocked(rf, fmt.Sprintf("waiting for the surge pod %s to be in sync", update.SurgePod), true)
+	return false
+}
+
+// removeSurgePod scales the redis back to the requested replicas once all the pods are updated, and
+// deletes the claim of the surge replica. The claim is kept by kubernetes until the pod is gone.
+func (r *RedisFailoverHandler) removeSurgePod(rf *redisfailoverv1.RedisFailover) error {
+	update := getUpdateStatus(rf)
+	if rf.Spec.Redis.Storage.PersistentVolumeClaim != nil {
+		claim := fmt.Sprintf("%s-%s", rf.Spec.Redis.Storage.PersistentVolumeClaim.Name, update.SurgePod)
+		if err := r.k8sservice.DeletePersistentVolumeClaim(rf.Namespace, claim); err != nil {
+			return err
+		}
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("All the redis pods are updated, removing the surge pod %s", update.SurgePod)
+	update.SurgePod = ""
+	update.BlockedReason = ""
+	return nil
+}
+
+// getSwitchoverTarget returns the first of the pods that is not the surge replica, which is removed
+// at the end of the update.
+func getSwitchoverTarget(rf *redisfailoverv1.RedisFailover, pods []string) string {
+	for _, pod := range pods {
+		if pod != getUpdateStatus(rf).SurgePod {
+			return pod
+		}
+	}
+	return ""
+}
+
+// getUpdateBlockedReason returns why the next stale pod can't be restarted yet, or an empty string
+// when it can.
+func getUpdateBlockedReason(rf *redisfailoverv1.RedisFailover) string {
+	strategy := rf.Spec.Redis.UpdateStrategy
+	if strategy.Paused {
+		return "update is paused"
+	}
+	if strategy.MinSyncedSeconds > 0 {
+		minSynced := time.Duration(strategy.MinSyncedSeconds) * time.Second
+		syncedSince := getUpdateStatus(rf).SyncedSince
+		if syncedSince == nil {
+			return "waiting for the replicas to be in sync"
+		}
+		if wait := minSynced - time.Since(syncedSince.Time); wait > 0 {
+			return fmt.Sprintf("waiting %s more for the replicas to be in sync for %s", wait.Round(time.Second), minSynced)
+		}
+	}
+	return ""
+}
+
+// isBelowPartition tells if the ordinal of the redis pod is lower than the partition of the update
+func isBelowPartition(pod string, partition int32) bool {
+	if partition <= 0 {
+		return false
+	}
+	ordinal, err := strconv.Atoi(pod[strings.LastIndex(pod, "-")+1:])
+	return err == nil && int32(ordinal) < partition
+}
+
+func getUpdateStatus(rf *redisfailoverv1.RedisFailover) *redisfailoverv1.RedisUpdateStatus {
+	if rf.Status.Update == nil {
+		rf.Status.Update = &redisfailoverv1.RedisUpdateStatus{}
+	}
+	return rf.Status.Update
+}
+
+// setUpdateBlocked keeps why the update can't go on. The time the replicas are in sync since is
+// forgotten unless they still are.
+func setUpdateBlocked(rf *redisfailoverv1.RedisFailover, reason string, synced bool) {
+	update := getUpdateStatus(rf)
+	update.BlockedReason = reason
+	if !synced {
+		update.SyncedSince = nil
+	}
+}
+
+// setReplicasSynced keeps the time the replicas were first seen in sync with the master
+func setReplicasSynced(rf *redisfailoverv1.RedisFailover) {
+	update := getUpdateStatus(rf)
+	update.BlockedReason = ""
+	if update.SyncedSince == nil {
+		now := metav1.Now()
+		update.SyncedSince = &now
+	}
+}
+
+// setPodRestarted waits for the replicas to be in sync again after a pod was restarted
+func setPodRestarted(rf *redisfailoverv1.RedisFailover) {
+	update := getUpdateStatus(rf)
+	update.BlockedReason = ""
+	update.SyncedSince = nil
+}
@@ -0,0 +1,228 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+	mRFService "github.com/freshworks/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/freshworks/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/freshworks/redis-operator/operator/redisfailover"
+	rfservice "github.com/freshworks/redis-operator/operator/redisfailover/service"
+	"github.com/freshworks/redis-operator/service/redis"
+)
+
+func TestUpdateRedisesPodsStrategy(t *testing.T) {
+	maxReplicationLag := int64(100)
+
+	tests := []struct {
+		name          string
+		strategy      redisfailoverv1.RedisUpdateStrategy
+		syncedFor     time.Duration
+		revisions     map[string]string
+		offsets       map[string]int64
+		expDeleted    string
+		expSwitchover bool
+		master        string
+		replicas      []string
+		surgePod      string
+		expBlocked    string
+		expNotSynced  bool
+		expSurgePod   string
+		expClaim      bool
+	}{
+		{
+			name:       "Stale replica is restarted",
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			expDeleted: "rfr-test-1",
+		},
+		{
+			name:       "Paused update does not restart pods",
+			strategy:   redisfailoverv1.RedisUpdateStrategy{Paused: true},
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			expBlocked: "update is paused",
+		},
+		{
+			name:       "Pods below the partition are kept",
+			strategy:   redisfailoverv1.RedisUpdateStrategy{Partition: 2},
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "1"},
+			expDeleted: "rfr-test-2",
+		},
+		{
+			name:      "Master below the partition is kept",
+			strategy:  redisfailoverv1.RedisUpdateStrategy{Partition: 1},
+			revisions: map[string]string{"rfr-test-0": "1", "rfr-test-1": "2", "rfr-test-2": "2"},
+		},
+		{
+			name:       "Replicas not in sync for long enough",
+			strategy:   redisfailoverv1.RedisUpdateStrategy{MinSyncedSeconds: 60},
+			syncedFor:  10 * time.Second,
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			expBlocked: "waiting 50s more for the replicas to be in sync for 1m0s",
+		},
+		{
+			name:       "Replicas in sync for long enough",
+			strategy:   redisfailoverv1.RedisUpdateStrategy{MinSyncedSeconds: 60},
+			syncedFor:  2 * time.Minute,
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			expDeleted: "rfr-test-1",
+		},
+		{
+			name:         "Replica lagging behind the master",
+			strategy:     redisfailoverv1.RedisUpdateStrategy{MaxReplicationLag: &maxReplicationLag},
+			revisions:    map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			offsets:      map[string]int64{"0.0.0.0": 1000, "0.0.0.1": 950, "0.0.0.2": 800},
+			expBlocked:   "redis 0.0.0.2 is 200 bytes behind the master, more than the 100 allowed",
+			expNotSynced: true,
+		},
+		{
+			name:       "Replicas close to the master",
+			strategy:   redisfailoverv1.RedisUpdateStrategy{MaxReplicationLag: &maxReplicationLag},
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			offsets:    map[string]int64{"0.0.0.0": 1000, "0.0.0.1": 950, "0.0.0.2": 990},
+			expDeleted: "rfr-test-1",
+		},
+		{
+			name:       "Stale master is restarted",
+			revisions:  map[string]string{"rfr-test-0": "1", "rfr-test-1": "2", "rfr-test-2": "2"},
+			expDeleted: "rfr-test-0",
+		},
+		{
+			name:          "Stale master is switched over before the restart",
+			strategy:      redisfailoverv1.RedisUpdateStrategy{MasterSwitchover: true},
+			revisions:     map[string]string{"rfr-test-0": "1", "rfr-test-1": "2", "rfr-test-2": "2"},
+			expBlocked:    "waiting for the sentinels to move the master to rfr-test-1",
+			expSwitchover: true,
+		},
+		{
+			name:        "Surge pod requested before restarting a replica",
+			strategy:    redisfailoverv1.RedisUpdateStrategy{Surge: true},
+			revisions:   map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2"},
+			expBlocked:  "waiting for the surge pod rfr-test-3 to be in sync",
+			expSurgePod: "rfr-test-3",
+		},
+		{
+			name:        "Stale replica restarted once the surge pod is in sync",
+			strategy:    redisfailoverv1.RedisUpdateStrategy{Surge: true},
+			replicas:    []string{"rfr-test-1", "rfr-test-2", "rfr-test-3"},
+			surgePod:    "rfr-test-3",
+			revisions:   map[string]string{"rfr-test-0": "1", "rfr-test-1": "1", "rfr-test-2": "2", "rfr-test-3": "2"},
+			expDeleted:  "rfr-test-1",
+			expSurgePod: "rfr-test-3",
+		},
+		{
+			name:      "Surge pod removed once all the pods are updated",
+			strategy:  redisfailoverv1.RedisUpdateStrategy{Surge: true},
+			replicas:  []string{"rfr-test-1", "rfr-test-2", "rfr-test-3"},
+			surgePod:  "rfr-test-3",
+			revisions: map[string]string{"rfr-test-0": "2", "rfr-test-1": "2", "rfr-test-2": "2", "rfr-test-3": "2"},
+			expClaim:  true,
+		},
+		{
+			name:          "Surge pod promoted is switched over before its removal",
+			strategy:      redisfailoverv1.RedisUpdateStrategy{Surge: true},
+			master:        "rfr-test-3",
+			replicas:      []string{"rfr-test-0", "rfr-test-1", "rfr-test-2"},
+			surgePod:      "rfr-test-3",
+			revisions:     map[string]string{"rfr-test-0": "2", "rfr-test-1": "2", "rfr-test-2": "2", "rfr-test-3": "2"},
+			expBlocked:    "waiting for the sentinels to move the master to rfr-test-0",
+			expSwitchover: true,
+			expSurgePod:   "rfr-test-3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false, false)
+			rf.Spec.Redis.UpdateStrategy = test.strategy
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "data"},
+			}
+			if test.syncedFor > 0 || test.surgePod != "" {
+				rf.Status.Update = &redisfailoverv1.RedisUpdateStatus{SurgePod: test.surgePod}
+			}
+			if test.syncedFor > 0 {
+				syncedSince := metav1.NewTime(time.Now().Add(-test.syncedFor))
+				rf.Status.Update.SyncedSince = &syncedSince
+			}
+
+			master := test.master
+			if master == "" {
+				master = "rfr-test-0"
+			}
+			replicas := test.replicas
+			if replicas == nil {
+				replicas = []string{"rfr-test-1", "rfr-test-2"}
+			}
+			sentinel := "1.1.1.1"
+			ips := map[string]string{"rfr-test-0": "0.0.0.0", "rfr-test-1": "0.0.0.1", "rfr-test-2": "0.0.0.2", "rfr-test-3": "0.0.0.3"}
+			obs := &rfservice.ClusterObservation{}
+			for _, pod := range append([]string{master}, replicas...) {
+				replication := redis.ReplicationInfo{Role: "slave", MasterHost: ips[master], MasterLinkUp: true, MasterReplOffset: test.offsets[ips[pod]]}
+				if pod == master {
+					replication = redis.ReplicationInfo{Role: "master", MasterReplOffset: test.offsets[ips[pod]]}
+				}
+				obs.Redises = append(obs.Redises, rfservice.RedisObservation{
+					Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: pod}, Status: corev1.PodStatus{PodIP: ips[pod]}},
+					Replication: replication,
+				})
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Maybe().Return("2", nil)
+			for pod, revision := range test.revisions {
+				mrfc.On("GetRedisRevisionHash", pod, rf).Maybe().Return(revision, nil)
+			}
+			if test.expDeleted != "" {
+				mrfh.On("DeletePod", test.expDeleted, rf).Once().Return(nil)
+			}
+			if test.expClaim {
+				mk.On("DeletePersistentVolumeClaim", namespace, "data-"+test.surgePod).Once().Return(nil)
+			}
+			if test.expSwitchover {
+				target := ips[replicas[0]]
+				pods := &corev1.PodList{}
+				for pod, ip := range ips {
+					pods.Items = append(pods.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: pod}, Status: corev1.PodStatus{PodIP: ip}})
+				}
+				mk.On("GetStatefulSetPods", namespace, mock.Anything).Once().Return(pods, nil)
+				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
+				mrfc.On("GetRedisReplicaPriority", mock.Anything, target, rf).Once().Return("100", nil)
+				mrfh.On("SetReplicaPriority", mock.Anything, target, "1", rf).Once().Return(nil)
+				mrfh.On("SentinelFailover", mock.Anything, sentinel, rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, mk, metrics.Dummy, record.NewFakeRecorder(10), log.Dummy)
+			err := handler.UpdateRedisesPods(context.TODO(), rf, obs)
+			assert.NoError(err)
+
+			if assert.NotNil(rf.Status.Update) {
+				assert.Equal(test.expBlocked, rf.Status.Update.BlockedReason)
+				// The replicas have to be in sync again after a pod is restarted
+				assert.Equal(test.expNotSynced || test.expDeleted != "" || test.expSwitchover, rf.Status.Update.SyncedSince == nil)
+				assert.Equal(test.expSurgePod, rf.Status.Update.SurgePod)
+			}
+			if test.expSwitchover && assert.True(rf.SwitchoverInProgress()) {
+				assert.Equal(replicas[0], rf.Status.LastSwitchover.Target)
+			}
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	redisfailoverv2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
+)
+
+// handleConversion decodes the ConversionReview sent by the API server and writes back the objects
+// converted to the version requested
+func (s *Server) handleConversion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cr := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, cr); err != nil || cr.Request == nil {
+		http.Error(w, "the body is not a ConversionReview request", http.StatusBadRequest)
+		return
+	}
+
+	resp := &apiextensionsv1.ConversionResponse{
+		UID:    cr.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, object := range cr.Request.Objects {
+		converted, err := convert(object.Raw, cr.Request.DesiredAPIVersion)
+		if err != nil {
+			s.logger.Errorf("Unable to convert the RedisFailover to %s: %s", cr.Request.DesiredAPIVersion, err)
+			resp.ConvertedObjects = nil
+			resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	out, err := json.Marshal(&apiextensionsv1.ConversionReview{TypeMeta: cr.TypeMeta, Response: resp})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(out); err != nil {
+		s.logger.Errorf("Unable to write the conversion response: %s", err)
+	}
+}
+
+// convert returns the RF given in the API version requested
+func convert(raw []byte, apiVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.Kind != redisfailoverv1.RFKind {
+		return nil, fmt.Errorf("kind %s can't be converted", typeMeta.Kind)
+	}
+
+	v1 := redisfailoverv1.SchemeGroupVersion.String()
+	v2 := redisfailoverv2.SchemeGroupVersion.String()
+	switch {
+	case typeMeta.APIVersion == apiVersion:
+		return raw, nil
+	case typeMeta.APIVersion == v1 && apiVersion == v2:
+		src := &redisfailoverv1.RedisFailover{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+		dst := &redisfailoverv2.RedisFailover{}
+		if err := dst.ConvertFrom(src); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	case typeMeta.APIVersion == v2 && apiVersion == v1:
+		src := &redisfailoverv2.RedisFailover{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+		dst := &redisfailoverv1.RedisFailover{}
+		if err := src.ConvertTo(dst); err != nil {
+			return nil, err
+		}
+		return json.Marshal(dst)
+	default:
+		return nil, fmt.Errorf("conversion from %s to %s is not supported", typeMeta.APIVersion, apiVersion)
+	}
+}
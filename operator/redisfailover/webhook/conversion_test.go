@@ -0,0 +1,134 @@
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/operator/redisfailover/webhook"
+)
+
+func convert(t *testing.T, apiVersion string, objects ...string) *apiextensionsv1.ConversionResponse {
+	req := &apiextensionsv1.ConversionRequest{
+		UID:               types.UID("1234"),
+		DesiredAPIVersion: apiVersion,
+	}
+	for _, object := range objects {
+		req.Objects = append(req.Objects, runtime.RawExtension{Raw: []byte(object)})
+	}
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  req,
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.NewServer(log.Dummy).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, webhook.ConvertPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cr := &apiextensionsv1.ConversionReview{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), cr))
+	assert.Equal(t, "ConversionReview", cr.Kind)
+	require.NotNil(t, cr.Response)
+	assert.Equal(t, types.UID("1234"), cr.Response.UID)
+	return cr.Response
+}
+
+func TestConvert(t *testing.T) {
+	v1RF := rfJSON("test", `{
+		"sentinel": {"customConfig": ["down-after-milliseconds 5000"], "disableMyMaster": true},
+		"auth": {"secretPath": "redis-auth"},
+		"bootstrapNode": {"host": "10.0.0.1", "port": "6380"}
+	}`)
+	v2RF := `{
+		"apiVersion": "databases.spotahome.com/v2",
+		"kind": "RedisFailover",
+		"metadata": {"name": "test", "namespace": "testns", "annotations": {"team/owner": "cache"}},
+		"spec": {
+			"sentinel": {"masterNamePolicy": "ResourceName", "tuning": {"downAfterMilliseconds": 5000}},
+			"auth": {"passwordSecret": {"name": "redis-auth"}},
+			"bootstrapNode": {"host": "10.0.0.1", "port": 6380}
+		},
+		"status": {}
+	}`
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		object     string
+		expObject  string
+	}{
+		{
+			name:       "v1 to v2",
+			apiVersion: "databases.spotahome.com/v2",
+			object:     v1RF,
+			expObject:  v2RF,
+		},
+		{
+			name:       "v2 to v1",
+			apiVersion: "databases.spotahome.com/v1",
+			object:     v2RF,
+			expObject:  v1RF,
+		},
+		{
+			name:       "Same version",
+			apiVersion: "databases.spotahome.com/v1",
+			object:     v1RF,
+			expObject:  v1RF,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			resp := convert(t, test.apiVersion, test.object)
+			assert.Equal(metav1.StatusSuccess, resp.Result.Status)
+			require.Len(t, resp.ConvertedObjects, 1)
+
+			// The structs marshalled have the empty fields not omitted
+			assert.Equal(unmarshalPruned(t, []byte(test.expObject)), unmarshalPruned(t, resp.ConvertedObjects[0].Raw))
+		})
+	}
+}
+
+func unmarshalPruned(t *testing.T, raw []byte) interface{} {
+	var object interface{}
+	require.NoError(t, json.Unmarshal(raw, &object))
+	return pruneEmpty(object)
+}
+
+func pruneEmpty(value interface{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	pruned := map[string]interface{}{}
+	for key, v := range object {
+		v = pruneEmpty(v)
+		if m, ok := v.(map[string]interface{}); v == nil || ok && len(m) == 0 {
+			continue
+		}
+		pruned[key] = v
+	}
+	return pruned
+}
+
+func TestConvertNotSupported(t *testing.T) {
+	assert := assert.New(t)
+
+	resp := convert(t, "databases.spotahome.com/v3", rfJSON("test", `{}`))
+	assert.Equal(metav1.StatusFailure, resp.Result.Status)
+	assert.Equal("conversion from databases.spotahome.com/v1 to databases.spotahome.com/v3 is not supported", resp.Result.Message)
+	assert.Empty(resp.ConvertedObjects)
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionscli "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+)
+
+var redisFailoverCRD = redisfailoverv1.RFNamePlural + "." + redisfailoverv1.SchemeGroupVersion.Group
+
+// ConfigureConversion points the conversion webhook of the RedisFailover CRD at the service given, the
+// CRDs can't be templated by the chart. The CA bundle of the CRD is kept when none is given, so the one
+// injected by cert-manager is not removed.
+func ConfigureConversion(ctx context.Context, cli apiextensionscli.Interface, namespace, service string, caBundle []byte) error {
+	crd, err := cli.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, redisFailoverCRD, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	clientConfig := &apiextensionsv1.WebhookClientConfig{
+		Service: &apiextensionsv1.ServiceReference{
+			Namespace: namespace,
+			Name:      service,
+			Path:      ptr.To(ConvertPath),
+			Port:      ptr.To(int32(443)),
+		},
+		CABundle: caBundle,
+	}
+	if c := crd.Spec.Conversion; len(caBundle) == 0 && c != nil && c.Webhook != nil && c.Webhook.ClientConfig != nil {
+		clientConfig.CABundle = c.Webhook.ClientConfig.CABundle
+	}
+	if c := crd.Spec.Conversion; c != nil && c.Strategy == apiextensionsv1.WebhookConverter && c.Webhook != nil &&
+		equalClientConfig(c.Webhook.ClientConfig, clientConfig) {
+		return nil
+	}
+
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig:             clientConfig,
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	if _, err := cli.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, crd, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("unable to configure the conversion webhook of %s: %w", redisFailoverCRD, err)
+	}
+	return nil
+}
+
+func equalClientConfig(a, b *apiextensionsv1.WebhookClientConfig) bool {
+	if a == nil || a.Service == nil || a.URL != nil {
+		return false
+	}
+	return a.Service.Namespace == b.Service.Namespace && a.Service.Name == b.Service.Name &&
+		ptr.Deref(a.Service.Path, "") == *b.Service.Path && ptr.Deref(a.Service.Port, 443) == *b.Service.Port &&
+		bytes.Equal(a.CABundle, b.CABundle)
+}
@@ -0,0 +1,118 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/freshworks/redis-operator/operator/redisfailover/webhook"
+)
+
+func TestConfigureConversion(t *testing.T) {
+	tests := []struct {
+		name        string
+		conversion  *apiextensionsv1.CustomResourceConversion
+		caBundle    []byte
+		expCABundle []byte
+	}{
+		{
+			name:        "No conversion",
+			caBundle:    []byte("ca"),
+			expCABundle: []byte("ca"),
+		},
+		{
+			name: "CA bundle injected kept",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service:  &apiextensionsv1.ServiceReference{Namespace: "default", Name: "redisoperator"},
+						CABundle: []byte("injected"),
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			expCABundle: []byte("injected"),
+		},
+		{
+			name: "CA bundle replaced",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service:  &apiextensionsv1.ServiceReference{Namespace: "operators", Name: "redis-operator", Path: ptr.To("/convert")},
+						CABundle: []byte("old"),
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			caBundle:    []byte("ca"),
+			expCABundle: []byte("ca"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cli := apiextensionsfake.NewSimpleClientset(&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "redisfailovers.databases.spotahome.com"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Conversion: test.conversion},
+			})
+			require.NoError(t, webhook.ConfigureConversion(context.TODO(), cli, "operators", "redis-operator", test.caBundle))
+
+			crd, err := cli.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), "redisfailovers.databases.spotahome.com", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(&apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service: &apiextensionsv1.ServiceReference{
+							Namespace: "operators",
+							Name:      "redis-operator",
+							Path:      ptr.To("/convert"),
+							Port:      ptr.To(int32(443)),
+						},
+						CABundle: test.expCABundle,
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			}, crd.Spec.Conversion)
+		})
+	}
+}
+
+func TestConfigureConversionUpToDate(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "redisfailovers.databases.spotahome.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service:  &apiextensionsv1.ServiceReference{Namespace: "operators", Name: "redis-operator", Path: ptr.To("/convert"), Port: ptr.To(int32(443))},
+						CABundle: []byte("ca"),
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+		},
+	}
+	cli := apiextensionsfake.NewSimpleClientset(crd)
+	require.NoError(t, webhook.ConfigureConversion(context.TODO(), cli, "operators", "redis-operator", nil))
+
+	for _, action := range cli.Actions() {
+		assert.NotEqual(t, "update", action.GetVerb())
+	}
+}
+
+func TestConfigureConversionNoCRD(t *testing.T) {
+	cli := apiextensionsfake.NewSimpleClientset()
+	assert.Error(t, webhook.ConfigureConversion(context.TODO(), cli, "operators", "redis-operator", nil))
+}
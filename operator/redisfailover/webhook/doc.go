@@ -0,0 +1,9 @@
+/*
+Package webhook serves the admission webhooks of the RedisFailovers. The mutating one
+persists the values by default into the objects, the validating one rejects the
+ones the operator can't run with and the changes the redis can't follow. It also
+serves the conversion webhook of the CRD, converting the objects between the v1
+and the v2 versions.
+*/
+
+package webhook
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// createPatch returns the JSON patch setting the values the object defaulted has and the original one
+// lacks. The fields already given are never replaced, so the ones the API server would encode another
+// way, like the quantities, are left as they are.
+func createPatch(original []byte, defaulted interface{}) ([]byte, error) {
+	var from map[string]interface{}
+	if err := json.Unmarshal(original, &from); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(defaulted)
+	if err != nil {
+		return nil, err
+	}
+	var to map[string]interface{}
+	if err := json.Unmarshal(raw, &to); err != nil {
+		return nil, err
+	}
+
+	ops := diffObjects("", from, to)
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ops)
+}
+
+func diffObjects(path string, from, to map[string]interface{}) []patchOperation {
+	keys := make([]string, 0, len(to))
+	for key := range to {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ops := []patchOperation{}
+	for _, key := range keys {
+		keyPath := path + "/" + escapePathKey(key)
+		value := prune(to[key])
+		if isEmpty(value) {
+			continue
+		}
+		current, ok := from[key]
+		switch {
+		case !ok:
+			ops = append(ops, patchOperation{Op: "add", Path: keyPath, Value: value})
+		case isEmpty(current):
+			ops = append(ops, patchOperation{Op: "replace", Path: keyPath, Value: value})
+		default:
+			fromObject, fromIsObject := current.(map[string]interface{})
+			toObject, toIsObject := value.(map[string]interface{})
+			if fromIsObject && toIsObject {
+				ops = append(ops, diffObjects(keyPath, fromObject, toObject)...)
+			}
+		}
+	}
+	return ops
+}
+
+// prune removes the empty fields of the objects, that the marshalling of the structs adds when they
+// are not omitted
+func prune(value interface{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	pruned := map[string]interface{}{}
+	for key, v := range object {
+		v = prune(v)
+		if !isEmpty(v) {
+			pruned[key] = v
+		}
+	}
+	return pruned
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// escapePathKey escapes a key for a JSON pointer, as in RFC 6901
+func escapePathKey(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	redisfailoverv2 "github.com/freshworks/redis-operator/api/redisfailover/v2"
+	"github.com/freshworks/redis-operator/log"
+)
+
+const (
+	// MutatePath is the path of the webhook setting the values by default of the RedisFailovers
+	MutatePath = "/mutate-redisfailover"
+	// ValidatePath is the path of the webhook rejecting the RedisFailovers not valid
+	ValidatePath = "/validate-redisfailover"
+	// ConvertPath is the path of the webhook converting the RedisFailovers between the versions of the CRD
+	ConvertPath = "/convert"
+
+	maxRequestSize = 3 * 1024 * 1024
+)
+
+// Server serves the mutating and validating admission webhooks of the RedisFailovers, and the
+// conversion webhook of their CRD
+type Server struct {
+	mux    *http.ServeMux
+	logger log.Logger
+}
+
+// NewServer returns a new admission webhook server
+func NewServer(logger log.Logger) *Server {
+	s := &Server{
+		mux:    http.NewServeMux(),
+		logger: logger.WithField("component", "webhook"),
+	}
+	s.mux.HandleFunc(MutatePath, s.handle(s.mutate))
+	s.mux.HandleFunc(ValidatePath, s.handle(s.validate))
+	s.mux.HandleFunc(ConvertPath, s.handleConversion)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServeTLS serves the webhooks on the address given. The certificate is read again from the
+// files when they change, so the one of a mounted secret can be renewed without a restart.
+func (s *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	loader := &certificateLoader{certFile: certFile, keyFile: keyFile}
+	if _, err := loader.GetCertificate(nil); err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   s,
+		TLSConfig: loader.tlsConfig(),
+	}
+	s.logger.Infof("Listening on %s for the webhooks", addr)
+	return server.ListenAndServeTLS("", "")
+}
+
+type reviewFunc func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// handle decodes the AdmissionReview sent by the API server and writes back the response of the
+// review given
+func (s *Server) handle(review reviewFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ar := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, ar); err != nil || ar.Request == nil {
+			http.Error(w, "the body is not an AdmissionReview request", http.StatusBadRequest)
+			return
+		}
+
+		resp := review(ar.Request)
+		resp.UID = ar.Request.UID
+		out, err := json.Marshal(&admissionv1.AdmissionReview{TypeMeta: ar.TypeMeta, Response: resp})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(out); err != nil {
+			s.logger.Errorf("Unable to write the admission response: %s", err)
+		}
+	}
+}
+
+// mutate patches the RF with the values by default of the fields not given
+func (s *Server) mutate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	rf, err := decodeRedisFailover(req.Object.Raw)
+	if err != nil {
+		return denied(http.StatusBadRequest, err)
+	}
+	rf.Default()
+
+	defaulted, err := defaultedObject(req.Object.Raw, rf)
+	if err != nil {
+		return denied(http.StatusInternalServerError, err)
+	}
+	patch, err := createPatch(req.Object.Raw, defaulted)
+	if err != nil {
+		return denied(http.StatusInternalServerError, err)
+	}
+	resp := &admissionv1.AdmissionResponse{Allowed: true}
+	if len(patch) > 0 {
+		patchType := admissionv1.PatchTypeJSONPatch
+		resp.Patch = patch
+		resp.PatchType = &patchType
+	}
+	return resp
+}
+
+// validate rejects the RF created or updated when it is not valid
+func (s *Server) validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+	rf, err := decodeRedisFailover(req.Object.Raw)
+	if err != nil {
+		return denied(http.StatusBadRequest, err)
+	}
+
+	switch {
+	case req.Operation == admissionv1.Create:
+		err = rf.ValidateCreate()
+	case rf.DeletionTimestamp != nil:
+		// The finalizers of a RF deleted have to be removed even if it is not valid
+	default:
+		old, oldErr := decodeRedisFailover(req.OldObject.Raw)
+		if oldErr != nil {
+			return denied(http.StatusBadRequest, oldErr)
+		}
+		err = rf.ValidateUpdate(old)
+	}
+	if err != nil {
+		s.logger.WithField("redisfailover", req.Name).WithField("namespace", req.Namespace).Infof("RedisFailover %s rejected: %s", req.Operation, err)
+		return denied(http.StatusUnprocessableEntity, fmt.Errorf("RedisFailover %s is not valid: %w", rf.Name, err))
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// decodeRedisFailover returns the RF of the request as a v1 RF, the one sent as a v2 RF is converted
+func decodeRedisFailover(raw []byte) (*redisfailoverv1.RedisFailover, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	rf := &redisfailoverv1.RedisFailover{}
+	if typeMeta.APIVersion != redisfailoverv2.SchemeGroupVersion.String() {
+		if err := json.Unmarshal(raw, rf); err != nil {
+			return nil, err
+		}
+		return rf, nil
+	}
+	src := &redisfailoverv2.RedisFailover{}
+	if err := json.Unmarshal(raw, src); err != nil {
+		return nil, err
+	}
+	if err := src.ConvertTo(rf); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// defaultedObject returns the v1 RF defaulted in the version of the request, so the patch applies to it.
+// Only the spec of a v2 RF is taken from the conversion, the annotations it keeps are not patched.
+func defaultedObject(raw []byte, rf *redisfailoverv1.RedisFailover) (interface{}, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion != redisfailoverv2.SchemeGroupVersion.String() {
+		return rf, nil
+	}
+	dst := &redisfailoverv2.RedisFailover{}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return nil, err
+	}
+	converted := &redisfailoverv2.RedisFailover{}
+	if err := converted.ConvertFrom(rf); err != nil {
+		return nil, err
+	}
+	dst.Spec = converted.Spec
+	return dst, nil
+}
+
+func denied(code int32, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,246 @@
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/operator/redisfailover/webhook"
+)
+
+const rfTemplate = `{
+	"apiVersion": "databases.spotahome.com/v1",
+	"kind": "RedisFailover",
+	"metadata": {"name": %q, "namespace": "testns", "annotations": {"team/owner": "cache"}},
+	"spec": %s
+}`
+
+const rfV2Template = `{
+	"apiVersion": "databases.spotahome.com/v2",
+	"kind": "RedisFailover",
+	"metadata": {"name": %q, "namespace": "testns"},
+	"spec": %s
+}`
+
+func review(t *testing.T, path string, operation admissionv1.Operation, object string, oldObject string) *admissionv1.AdmissionResponse {
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("1234"),
+		Name:      "test",
+		Namespace: "testns",
+		Operation: operation,
+	}
+	if object != "" {
+		req.Object = runtime.RawExtension{Raw: []byte(object)}
+	}
+	if oldObject != "" {
+		req.OldObject = runtime.RawExtension{Raw: []byte(oldObject)}
+	}
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.NewServer(log.Dummy).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	ar := &admissionv1.AdmissionReview{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), ar))
+	assert.Equal(t, "AdmissionReview", ar.Kind)
+	require.NotNil(t, ar.Response)
+	assert.Equal(t, types.UID("1234"), ar.Response.UID)
+	return ar.Response
+}
+
+func rfJSON(name string, spec string) string {
+	return fmt.Sprintf(rfTemplate, name, spec)
+}
+
+func rfV2JSON(name string, spec string) string {
+	return fmt.Sprintf(rfV2Template, name, spec)
+}
+
+func TestMutate(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   func(name string, spec string) string
+		spec     string
+		expPatch []map[string]interface{}
+	}{
+		{
+			name: "Values by default added",
+			spec: `{"redis": {"replicas": 0, "customConfig": ["maxmemory 1gb"], "storage": {"persistentVolumeClaim": {"spec": {"resources": {"requests": {"storage": "1024Mi"}}}}}}}`,
+			expPatch: []map[string]interface{}{
+				{"op": "add", "path": "/spec/addressMode", "value": "ip"},
+				{"op": "add", "path": "/spec/redis/exporter", "value": map[string]interface{}{"image": "quay.io/oliver006/redis_exporter:v1.43.0"}},
+				{"op": "add", "path": "/spec/redis/image", "value": "redis:6.2.6-alpine"},
+				{"op": "add", "path": "/spec/redis/masterElection", "value": "oldest"},
+				{"op": "add", "path": "/spec/redis/port", "value": float64(6379)},
+				{"op": "replace", "path": "/spec/redis/replicas", "value": float64(3)},
+				{"op": "add", "path": "/spec/redis/splitBrainResolution", "value": "manual"},
+				{"op": "add", "path": "/spec/sentinel", "value": map[string]interface{}{
+					"image":        "redis:6.2.6-alpine",
+					"replicas":     float64(3),
+					"customConfig": []interface{}{"down-after-milliseconds 5000", "failover-timeout 10000"},
+					"exporter":     map[string]interface{}{"image": "quay.io/oliver006/redis_exporter:v1.43.0"},
+					"workloadType": "Deployment",
+				}},
+			},
+		},
+		{
+			name: "Values given kept",
+			spec: `{
+				"addressMode": "hostname",
+				"redis": {"image": "redis:7", "replicas": 5, "port": 6380, "masterElection": "highestOffset", "splitBrainResolution": "automatic", "exporter": {"image": "exporter"}},
+				"sentinel": {"image": "redis:7", "replicas": 5, "customConfig": ["failover-timeout 500"], "exporter": {"image": "exporter"}, "workloadType": "StatefulSet"}
+			}`,
+		},
+		{
+			name:   "Values by default added to a v2 RF",
+			object: rfV2JSON,
+			spec:   `{"network": {"addressMode": "hostname"}, "redis": {"image": "redis:7", "port": 6380, "masterElection": "oldest", "splitBrainResolution": "manual", "exporter": {"image": "exporter"}}, "sentinel": {"image": "redis:7", "replicas": 5, "exporter": {"image": "exporter"}, "workloadType": "StatefulSet"}}`,
+			expPatch: []map[string]interface{}{
+				{"op": "add", "path": "/spec/redis/replicas", "value": float64(3)},
+				{"op": "add", "path": "/spec/sentinel/tuning", "value": map[string]interface{}{"downAfterMilliseconds": float64(5000), "failoverTimeout": float64(10000)}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			object := test.object
+			if object == nil {
+				object = rfJSON
+			}
+			resp := review(t, webhook.MutatePath, admissionv1.Create, object("test", test.spec), "")
+			assert.True(resp.Allowed)
+			if test.expPatch == nil {
+				assert.Nil(resp.Patch)
+				assert.Nil(resp.PatchType)
+				return
+			}
+
+			if assert.NotNil(resp.PatchType) {
+				assert.Equal(admissionv1.PatchTypeJSONPatch, *resp.PatchType)
+			}
+			var patch []map[string]interface{}
+			require.NoError(t, json.Unmarshal(resp.Patch, &patch))
+			assert.Equal(test.expPatch, patch)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		operation  admissionv1.Operation
+		object     string
+		oldObject  string
+		expAllowed bool
+		expMessage string
+	}{
+		{
+			name:       "Valid RF created",
+			operation:  admissionv1.Create,
+			object:     rfJSON("test", `{"redis": {"customConfig": ["maxmemory 1gb"]}}`),
+			expAllowed: true,
+		},
+		{
+			name:       "Name too long",
+			operation:  admissionv1.Create,
+			object:     rfJSON("some-super-absurdely-unnecessarily-long-name-that-will-fail", `{}`),
+			expMessage: "RedisFailover some-super-absurdely-unnecessarily-long-name-that-will-fail is not valid: name length can't be higher than 48",
+		},
+		{
+			name:       "Malformed custom config",
+			operation:  admissionv1.Create,
+			object:     rfJSON("test", `{"redis": {"customConfig": ["maxmemory"]}}`),
+			expMessage: `RedisFailover test is not valid: redis customConfig "maxmemory" is malformed, must be a parameter and a value`,
+		},
+		{
+			name:       "Even sentinels",
+			operation:  admissionv1.Create,
+			object:     rfJSON("test", `{"sentinel": {"replicas": 2}}`),
+			expMessage: "RedisFailover test is not valid: sentinel replicas can't be lower than 3, got 2",
+		},
+		{
+			name:       "Invalid bootstrap port",
+			operation:  admissionv1.Create,
+			object:     rfJSON("test", `{"bootstrapNode": {"host": "10.0.0.1", "port": "0"}}`),
+			expMessage: `RedisFailover test is not valid: BootstrapNode port "0" is not valid`,
+		},
+		{
+			name:       "Port changed",
+			operation:  admissionv1.Update,
+			object:     rfJSON("test", `{"redis": {"port": 6380}}`),
+			oldObject:  rfJSON("test", `{}`),
+			expMessage: "RedisFailover test is not valid: redis port can't be changed from 6379 to 6380",
+		},
+		{
+			name:       "Even sentinels of a v2 RF",
+			operation:  admissionv1.Create,
+			object:     rfV2JSON("test", `{"sentinel": {"replicas": 2}}`),
+			expMessage: "RedisFailover test is not valid: sentinel replicas can't be lower than 3, got 2",
+		},
+		{
+			name:       "Port of a v2 RF changed",
+			operation:  admissionv1.Update,
+			object:     rfV2JSON("test", `{"redis": {"port": 6380}}`),
+			oldObject:  rfV2JSON("test", `{}`),
+			expMessage: "RedisFailover test is not valid: redis port can't be changed from 6379 to 6380",
+		},
+		{
+			name:       "Replicas changed",
+			operation:  admissionv1.Update,
+			object:     rfJSON("test", `{"redis": {"replicas": 5}}`),
+			oldObject:  rfJSON("test", `{}`),
+			expAllowed: true,
+		},
+		{
+			name:       "RF deleted",
+			operation:  admissionv1.Update,
+			object:     `{"metadata": {"name": "test", "deletionTimestamp": "2024-01-01T00:00:00Z"}, "spec": {"redis": {"port": 6380}}}`,
+			oldObject:  rfJSON("test", `{}`),
+			expAllowed: true,
+		},
+		{
+			name:       "Delete allowed",
+			operation:  admissionv1.Delete,
+			oldObject:  rfJSON("test", `{"sentinel": {"replicas": 2}}`),
+			expAllowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			resp := review(t, webhook.ValidatePath, test.operation, test.object, test.oldObject)
+			assert.Equal(test.expAllowed, resp.Allowed)
+			if !test.expAllowed && assert.NotNil(resp.Result) {
+				assert.Equal(test.expMessage, resp.Result.Message)
+				assert.Equal(int32(http.StatusUnprocessableEntity), resp.Result.Code)
+			}
+		})
+	}
+}
+
+func TestNotAnAdmissionReview(t *testing.T) {
+	rec := httptest.NewRecorder()
+	webhook.NewServer(log.Dummy).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, webhook.ValidatePath, bytes.NewReader([]byte(`{}`))))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
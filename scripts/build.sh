@@ -5,6 +5,8 @@ set -o nounset
 
 src=./cmd/redisoperator
 out=./bin/redis-operator
+agent_src=./cmd/backupagent
+agent_out=./bin/redis-backup-agent
 
 # Set default values if not defined
 TARGETOS=${TARGETOS:-$(go env GOOS)}
@@ -27,3 +29,6 @@ f_ver="-X main.Version=${VERSION:-dev}"
 
 echo "Building binary at ${out}"
 CGO_ENABLED=0 go build -o ${out} --ldflags "${ldf_cmp} ${f_ver}"  ${src}
+
+echo "Building binary at ${agent_out}"
+CGO_ENABLED=0 go build -o ${agent_out} --ldflags "${ldf_cmp} ${f_ver}"  ${agent_src}
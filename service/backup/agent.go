@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/freshworks/redis-operator/log"
+)
+
+// AgentPort is the port the agent listens on, next to every redis.
+const AgentPort = 9122
+
+// AgentTokenEnv is the environment variable the agent reads the token the operator authenticates with from.
+const AgentTokenEnv = "BACKUP_AGENT_TOKEN"
+
+const uploadsPath = "/uploads"
+
+// uploadRetention is how long a finished upload is kept for the operator to read its state, it is
+// forgotten afterwards.
+const uploadRetention = time.Hour
+
+// States of an upload
+const (
+	UploadRunning   = "Running"
+	UploadCompleted = "Completed"
+	UploadFailed    = "Failed"
+)
+
+// Upload is the progress and the result of the upload of an RDB file.
+type Upload struct {
+	Key      string `json:"key"`
+	State    string `json:"state"`
+	Location string `json:"location,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	finished time.Time
+}
+
+// Agent runs next to a redis and uploads its RDB file when the operator asks for it.
+// Uploads run in the background, the operator polls their state. Only the requests bearing the token
+// are served, and only for the keys under the prefix of the redis failover of the agent.
+type Agent struct {
+	rdbPath   string
+	token     string
+	keyPrefix string
+	uploader  Uploader
+	logger    log.Logger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	uploads map[string]*Upload
+}
+
+// NewAgent returns an agent uploading the RDB file found at the given path to the keys under the prefix.
+func NewAgent(rdbPath string, token string, keyPrefix string, uploader Uploader, logger log.Logger) *Agent {
+	return &Agent{
+		rdbPath:   rdbPath,
+		token:     token,
+		keyPrefix: keyPrefix,
+		uploader:  uploader,
+		logger:    logger.With("service", "backup.agent"),
+		now:       time.Now,
+		uploads:   map[string]*Upload{},
+	}
+}
+
+// ServeHTTP starts an upload on POST and returns its state on GET, the key is given in the key parameter.
+func (a *Agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != uploadsPath {
+		http.NotFound(w, r)
+		return
+	}
+	if !a.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "key is required", http.StatusBadRequest)
+		return
+	}
+	if !a.allowedKey(key) {
+		http.Error(w, "key must be a clean path under "+a.keyPrefix, http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		writeUpload(w, http.StatusAccepted, a.start(key))
+	case http.MethodGet:
+		upload, ok := a.get(key)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeUpload(w, http.StatusOK, upload)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized tells whether the request bears the token of the agent. An agent without a token serves nothing.
+func (a *Agent) authorized(r *http.Request) bool {
+	if a.token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) == 1
+}
+
+// allowedKey tells whether the key is under the prefix of the agent, without any element moving out of it.
+func (a *Agent) allowedKey(key string) bool {
+	return strings.HasPrefix(key, a.keyPrefix) && path.Clean(key) == key && !strings.HasPrefix(key, "/")
+}
+
+// start begins the upload of the key unless it is already known, so the operator can retry its request.
+func (a *Agent) start(key string) Upload {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prune()
+	if upload, ok := a.uploads[key]; ok {
+		return *upload
+	}
+	upload := &Upload{Key: key, State: UploadRunning}
+	a.uploads[key] = upload
+	go a.upload(upload)
+	return *upload
+}
+
+func (a *Agent) get(key string) (Upload, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prune()
+	upload, ok := a.uploads[key]
+	if !ok {
+		return Upload{}, false
+	}
+	return *upload, true
+}
+
+func (a *Agent) upload(upload *Upload) {
+	location, size, checksum, err := a.uploadRDB(upload.Key)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	upload.finished = a.now()
+	if err != nil {
+		a.logger.Errorf("Upload of %s failed: %s", upload.Key, err.Error())
+		upload.State = UploadFailed
+		upload.Error = err.Error()
+		return
+	}
+	a.logger.Infof("Uploaded %s to %s (%d bytes)", upload.Key, location, size)
+	upload.State = UploadCompleted
+	upload.Location = location
+	upload.Size = size
+	upload.Checksum = checksum
+}
+
+// prune forgets the uploads finished for longer than the retention, the lock must be held.
+func (a *Agent) prune() {
+	for key, upload := range a.uploads {
+		if !upload.finished.IsZero() && a.now().Sub(upload.finished) > uploadRetention {
+			delete(a.uploads, key)
+		}
+	}
+}
+
+// uploadRDB streams the RDB file to the uploader, computing its checksum on the way. Redis replaces the
+// file with a rename once a save is done, so the file opened is never modified while it is read.
+func (a *Agent) uploadRDB(key string) (string, int64, string, error) {
+	file, err := os.Open(a.rdbPath)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	hash := sha256.New()
+	location, err := a.uploader.Upload(context.Background(), key, io.TeeReader(file, hash), info.Size())
+	if err != nil {
+		return "", 0, "", err
+	}
+	return location, info.Size(), "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func writeUpload(w http.ResponseWriter, status int, upload Upload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(upload)
+}
@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/freshworks/redis-operator/log"
+)
+
+func TestAgentUpload(t *testing.T) {
+	assert := assert.New(t)
+
+	dataDir := t.TempDir()
+	rdbPath := filepath.Join(dataDir, "dump.rdb")
+	require.NoError(t, os.WriteFile(rdbPath, []byte("REDIS0009 data"), 0o600))
+	storageDir := t.TempDir()
+
+	server := httptest.NewServer(NewAgent(rdbPath, "token", "testns/test/", NewFilesystemUploader(storageDir), log.Dummy))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	client := &agentClient{httpClient: server.Client(), port: portNumber}
+
+	_, err := client.GetUpload(host, "token", "testns/test/backup.rdb")
+	assert.Equal(ErrUploadNotFound, err)
+
+	upload, err := client.StartUpload(host, "token", "testns/test/backup.rdb")
+	require.NoError(t, err)
+	assert.Equal("testns/test/backup.rdb", upload.Key)
+
+	assert.Eventually(func() bool {
+		upload, err = client.GetUpload(host, "token", "testns/test/backup.rdb")
+		return err == nil && upload.State != UploadRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sum := sha256.Sum256([]byte("REDIS0009 data"))
+	assert.Equal(UploadCompleted, upload.State)
+	assert.Equal("file://"+filepath.Join(storageDir, "testns", "test", "backup.rdb"), upload.Location)
+	assert.Equal(int64(14), upload.Size)
+	assert.Equal("sha256:"+hex.EncodeToString(sum[:]), upload.Checksum)
+
+	// Starting it again returns the same upload
+	again, err := client.StartUpload(host, "token", "testns/test/backup.rdb")
+	assert.NoError(err)
+	assert.Equal(upload, again)
+}
+
+func TestAgentUploadPruned(t *testing.T) {
+	assert := assert.New(t)
+
+	rdbPath := filepath.Join(t.TempDir(), "dump.rdb")
+	require.NoError(t, os.WriteFile(rdbPath, []byte("REDIS0009 data"), 0o600))
+	agent := NewAgent(rdbPath, "token", "testns/test/", NewFilesystemUploader(t.TempDir()), log.Dummy)
+	server := httptest.NewServer(agent)
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	client := &agentClient{httpClient: server.Client(), port: portNumber}
+
+	_, err := client.StartUpload(host, "token", "testns/test/backup.rdb")
+	require.NoError(t, err)
+	assert.Eventually(func() bool {
+		upload, err := client.GetUpload(host, "token", "testns/test/backup.rdb")
+		return err == nil && upload.State == UploadCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The finished upload is forgotten once the retention passed
+	agent.mu.Lock()
+	agent.now = func() time.Time { return time.Now().Add(uploadRetention + time.Minute) }
+	agent.mu.Unlock()
+	_, err = client.GetUpload(host, "token", "testns/test/backup.rdb")
+	assert.Equal(ErrUploadNotFound, err)
+}
+
+func TestAgentUploadMissingRDB(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(NewAgent(filepath.Join(t.TempDir(), "dump.rdb"), "token", "testns/test/", NewFilesystemUploader(t.TempDir()), log.Dummy))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	client := &agentClient{httpClient: server.Client(), port: portNumber}
+
+	_, err := client.StartUpload(host, "token", "testns/test/backup.rdb")
+	require.NoError(t, err)
+
+	var upload *Upload
+	assert.Eventually(func() bool {
+		upload, err = client.GetUpload(host, "token", "testns/test/backup.rdb")
+		return err == nil && upload.State != UploadRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(UploadFailed, upload.State)
+	assert.NotEmpty(upload.Error)
+}
+
+func TestAgentUploadRefused(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		key   string
+		exp   string
+	}{
+		{
+			name:  "Request without the token",
+			token: "",
+			key:   "testns/test/backup.rdb",
+			exp:   "answered 401",
+		},
+		{
+			name:  "Request with another token",
+			token: "other",
+			key:   "testns/test/backup.rdb",
+			exp:   "answered 401",
+		},
+		{
+			name:  "Key of another redis failover",
+			token: "token",
+			key:   "testns/other/backup.rdb",
+			exp:   "answered 403",
+		},
+		{
+			name:  "Key moving out of the prefix",
+			token: "token",
+			key:   "testns/test/../other/backup.rdb",
+			exp:   "answered 403",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rdbPath := filepath.Join(t.TempDir(), "dump.rdb")
+			require.NoError(t, os.WriteFile(rdbPath, []byte("REDIS0009 data"), 0o600))
+			server := httptest.NewServer(NewAgent(rdbPath, "token", "testns/test/", NewFilesystemUploader(t.TempDir()), log.Dummy))
+			defer server.Close()
+			host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+			portNumber, _ := strconv.Atoi(port)
+			client := &agentClient{httpClient: server.Client(), port: portNumber}
+
+			_, err := client.StartUpload(host, test.token, test.key)
+			if assert.Error(err) {
+				assert.Contains(err.Error(), test.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUploadNotFound is returned when the agent does not know the upload, e.g. because its pod restarted.
+var ErrUploadNotFound = errors.New("upload not found")
+
+// AgentClient asks the backup agents running next to the redises to upload their RDB file
+type AgentClient interface {
+	StartUpload(ip string, token string, key string) (*Upload, error)
+	GetUpload(ip string, token string, key string) (*Upload, error)
+}
+
+type agentClient struct {
+	httpClient *http.Client
+	port       int
+}
+
+// NewAgentClient returns a client of the backup agents
+func NewAgentClient() AgentClient {
+	return &agentClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		port:       AgentPort,
+	}
+}
+
+// StartUpload satisfies AgentClient interface.
+func (c *agentClient) StartUpload(ip string, token string, key string) (*Upload, error) {
+	return c.do(http.MethodPost, ip, token, key)
+}
+
+// GetUpload satisfies AgentClient interface.
+func (c *agentClient) GetUpload(ip string, token string, key string) (*Upload, error) {
+	return c.do(http.MethodGet, ip, token, key)
+}
+
+func (c *agentClient) do(method, ip, token, key string) (*Upload, error) {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(ip, strconv.Itoa(c.port)),
+		Path:     uploadsPath,
+		RawQuery: url.Values{"key": []string{key}}.Encode(),
+	}
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUploadNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("backup agent %s answered %d: %s", ip, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	upload := &Upload{}
+	if err := json.NewDecoder(resp.Body).Decode(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
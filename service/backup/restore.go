@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Restore writes the RDB file found at the source to the given path. The source is a location returned by
+// an uploader (s3:// or file://) or an http(s) URL. Objects of s3:// sources are downloaded from the endpoint
+// and with the credentials of the configuration, the bucket is taken from the source.
+func Restore(ctx context.Context, source string, rdbPath string, s3Config S3Config, httpClient *http.Client) (int64, error) {
+	content, err := openSource(ctx, source, s3Config, httpClient)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = content.Close() }()
+
+	// Write to a temporary file first so redis never starts with a partial dataset
+	tmp := rdbPath + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return written, os.Rename(tmp, rdbPath)
+}
+
+func openSource(ctx context.Context, source string, s3Config S3Config, httpClient *http.Client) (io.ReadCloser, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	switch u.Scheme {
+	case "file":
+		return os.Open(u.Path)
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode/100 != 2 {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("download of %s failed with status %d", source, resp.StatusCode)
+		}
+		return resp.Body, nil
+	case "s3":
+		s3Config.Bucket = u.Host
+		s3Config.Prefix = ""
+		storage, err := NewS3Storage(s3Config, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return storage.Download(ctx, strings.TrimPrefix(u.Path, "/"))
+	default:
+		return nil, fmt.Errorf("restore source %q is not supported, it must be an s3, http(s) or file URL", source)
+	}
+}
@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/backups/testns/backup.rdb" && strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"):
+			_, _ = w.Write([]byte("REDIS0009 s3"))
+		case r.URL.Path == "/dump.rdb":
+			_, _ = w.Write([]byte("REDIS0009 http"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sourceDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "dump.rdb"), []byte("REDIS0009 file"), 0o600))
+
+	tests := []struct {
+		name       string
+		source     string
+		expContent string
+		expErr     bool
+	}{
+		{
+			name:       "From a file",
+			source:     "file://" + filepath.Join(sourceDir, "dump.rdb"),
+			expContent: "REDIS0009 file",
+		},
+		{
+			name:       "From an http URL",
+			source:     server.URL + "/dump.rdb",
+			expContent: "REDIS0009 http",
+		},
+		{
+			name:       "From an s3 location",
+			source:     "s3://backups/testns/backup.rdb",
+			expContent: "REDIS0009 s3",
+		},
+		{
+			name:   "Missing s3 object",
+			source: "s3://backups/testns/missing.rdb",
+			expErr: true,
+		},
+		{
+			name:   "Unsupported source",
+			source: "ftp://backups/dump.rdb",
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rdbPath := filepath.Join(t.TempDir(), "dump.rdb")
+
+			size, err := Restore(context.TODO(), test.source, rdbPath, S3Config{Endpoint: server.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"}, server.Client())
+			if test.expErr {
+				assert.Error(err)
+				_, err := os.Stat(rdbPath)
+				assert.True(os.IsNotExist(err))
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(int64(len(test.expContent)), size)
+			content, err := os.ReadFile(rdbPath)
+			assert.NoError(err)
+			assert.Equal(test.expContent, string(content))
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	// unsignedPayload lets the body be streamed without hashing it before the request
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	// defaultS3PartSize is the size of the parts of the files uploaded in parts, the files up to it are
+	// uploaded with a single PUT, which is limited to 5GiB.
+	defaultS3PartSize = 64 << 20
+	// s3MaxParts is the number of parts a file can be uploaded in, the parts of larger files are made bigger.
+	s3MaxParts = 10000
+)
+
+// S3Config is the bucket of an S3-compatible object storage to upload to.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Storage uploads and downloads the files of an S3-compatible object storage, like AWS S3 or MinIO.
+// Objects are addressed with the path style and the requests signed with AWS signature v4.
+type S3Storage struct {
+	config     S3Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	partSize   int64
+	now        func() time.Time
+}
+
+// NewS3Storage returns a storage using the bucket of the configuration.
+func NewS3Storage(config S3Config, httpClient *http.Client) (*S3Storage, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q must include the scheme and the host", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &S3Storage{
+		config:     config,
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		partSize:   defaultS3PartSize,
+		now:        time.Now,
+	}, nil
+}
+
+// Upload satisfies Uploader interface. The files larger than a part are uploaded in parts.
+func (s *S3Storage) Upload(ctx context.Context, key string, content io.Reader, size int64) (string, error) {
+	key = s.config.Prefix + key
+	var err error
+	if size > s.partSize {
+		err = s.uploadParts(ctx, key, content, size)
+	} else {
+		_, _, err = s.do(ctx, http.MethodPut, key, nil, content, size)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", s.config.Bucket, key), nil
+}
+
+// completedPart is a part of a multipart upload, given back to the storage to complete it.
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts uploads the content with a multipart upload, aborted on failure so the storage does not
+// keep the parts already uploaded.
+func (s *S3Storage) uploadParts(ctx context.Context, key string, content io.Reader, size int64) error {
+	body, _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0)
+	if err != nil {
+		return err
+	}
+	initiated := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	if err := xml.Unmarshal(body, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("s3 upload of %s was not given an upload id: %s", key, strings.TrimSpace(string(body)))
+	}
+
+	if err := s.sendParts(ctx, key, initiated.UploadID, content, size); err != nil {
+		_, _, _ = s.do(context.Background(), http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil, 0)
+		return err
+	}
+	return nil
+}
+
+func (s *S3Storage) sendParts(ctx context.Context, key, uploadID string, content io.Reader, size int64) error {
+	partSize := max(s.partSize, (size+s3MaxParts-1)/s3MaxParts)
+	parts := []completedPart{}
+	for offset := int64(0); offset < size; offset += partSize {
+		length := min(partSize, size-offset)
+		number := len(parts) + 1
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		_, header, err := s.do(ctx, http.MethodPut, key, query, io.LimitReader(content, length), length)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: header.Get("ETag")})
+	}
+
+	complete, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	body, _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, strings.NewReader(string(complete)), int64(len(complete)))
+	if err != nil {
+		return err
+	}
+	// The completion can fail after the storage answered with a success status, the error is in the body
+	result := struct {
+		XMLName xml.Name
+		Message string `xml:"Message"`
+	}{}
+	if err := xml.Unmarshal(body, &result); err == nil && result.XMLName.Local == "Error" {
+		return fmt.Errorf("s3 upload of %s failed: %s", key, result.Message)
+	}
+	return nil
+}
+
+// do sends the signed request for the object and returns the body and the headers of the response.
+func (s *S3Storage) do(ctx context.Context, method, key string, query url.Values, content io.Reader, size int64) ([]byte, http.Header, error) {
+	u := s.objectURL(key)
+	if query != nil {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.ContentLength = size
+	s.sign(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		if len(body) > 1024 {
+			body = body[:1024]
+		}
+		return nil, nil, fmt.Errorf("s3 upload of %s failed with status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, resp.Header, nil
+}
+
+// Download returns the content of the object with the given key, the prefix is added to it.
+func (s *S3Storage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	key = s.config.Prefix + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 download of %s failed with status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// objectURL returns the path style URL of the object.
+func (s *S3Storage) objectURL(key string) string {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket + "/" + key
+	u.RawPath = uriEncode(u.Path)
+	return u.String()
+}
+
+// sign adds the AWS signature v4 headers to the request.
+func (s *S3Storage) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	date := now.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.config.Region)
+
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+	req.Header.Set("x-amz-date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" + "x-amz-content-sha256:" + unsignedPayload + "\n" + "x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uriEncode escapes the path as required by the signature, keeping only the unreserved characters and the slashes.
+func uriEncode(path string) string {
+	var b strings.Builder
+	for _, c := range []byte(path) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
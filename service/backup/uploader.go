@@ -0,0 +1,74 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Uploader stores the RDB files of the backups, the target is pluggable so a local directory can stand
+// in for the object storage.
+type Uploader interface {
+	// Upload stores the content under the given key and returns its location.
+	Upload(ctx context.Context, key string, content io.Reader, size int64) (string, error)
+}
+
+// FilesystemUploader stores the files in a local directory.
+type FilesystemUploader struct {
+	dir string
+}
+
+// NewFilesystemUploader returns an uploader storing the files in the given directory.
+func NewFilesystemUploader(dir string) *FilesystemUploader {
+	return &FilesystemUploader{dir: dir}
+}
+
+// Upload satisfies Uploader interface.
+func (f *FilesystemUploader) Upload(ctx context.Context, key string, content io.Reader, size int64) (string, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so an interrupted upload never looks complete
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	written, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != size {
+		err = fmt.Errorf("wrote %d bytes out of %d", written, size)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return "file://" + path, nil
+}
+
+// path returns the path of the file of the key, refusing the keys that would be written out of the directory.
+func (f *FilesystemUploader) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("key %s is not relative", key)
+	}
+	path := filepath.Join(f.dir, name)
+	rel, err := filepath.Rel(f.dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("key %s is out of %s", key, f.dir)
+	}
+	return path, nil
+}
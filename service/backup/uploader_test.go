@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemUploader(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	uploader := NewFilesystemUploader(dir)
+	location, err := uploader.Upload(context.TODO(), "testns/backup.rdb", strings.NewReader("REDIS0009"), 9)
+	assert.NoError(err)
+	assert.Equal("file://"+filepath.Join(dir, "testns", "backup.rdb"), location)
+
+	content, err := os.ReadFile(filepath.Join(dir, "testns", "backup.rdb"))
+	assert.NoError(err)
+	assert.Equal("REDIS0009", string(content))
+
+	// A short read leaves no file behind
+	_, err = uploader.Upload(context.TODO(), "testns/short.rdb", strings.NewReader("REDIS"), 9)
+	assert.Error(err)
+	_, err = os.Stat(filepath.Join(dir, "testns", "short.rdb"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestFilesystemUploaderKeyOutOfDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "backups")
+	uploader := NewFilesystemUploader(dir)
+
+	for _, key := range []string{"../escaped.rdb", "testns/../../escaped.rdb", "/tmp/escaped.rdb", "..", ""} {
+		_, err := uploader.Upload(context.TODO(), key, strings.NewReader("REDIS0009"), 9)
+		assert.Error(t, err, key)
+	}
+	_, err := os.Stat(filepath.Join(parent, "escaped.rdb"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestS3Storage(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		expErr bool
+	}{
+		{
+			name:   "Upload accepted",
+			status: http.StatusOK,
+		},
+		{
+			name:   "Upload refused",
+			status: http.StatusForbidden,
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var received *http.Request
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				content, _ := io.ReadAll(r.Body)
+				body = string(content)
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			uploader, err := NewS3Storage(S3Config{
+				Endpoint:        server.URL,
+				Bucket:          "backups",
+				Prefix:          "redis/",
+				AccessKeyID:     "AKID",
+				SecretAccessKey: "secret",
+			}, server.Client())
+			require.NoError(t, err)
+			uploader.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+			location, err := uploader.Upload(context.TODO(), "testns/backup 1.rdb", strings.NewReader("REDIS0009"), 9)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal("s3://backups/redis/testns/backup 1.rdb", location)
+
+			assert.Equal(http.MethodPut, received.Method)
+			assert.Equal("/backups/redis/testns/backup%201.rdb", received.URL.EscapedPath())
+			assert.Equal("REDIS0009", body)
+			assert.Equal(int64(9), received.ContentLength)
+			assert.Equal("UNSIGNED-PAYLOAD", received.Header.Get("x-amz-content-sha256"))
+			assert.Equal("20240102T030405Z", received.Header.Get("x-amz-date"))
+			assert.True(strings.HasPrefix(received.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20240102/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+		})
+	}
+}
+
+func TestS3StorageMultipartUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		partStatus  int
+		expErr      bool
+		expRequests []string
+	}{
+		{
+			name:       "Upload in parts",
+			partStatus: http.StatusOK,
+			expRequests: []string{
+				"POST uploads=",
+				"PUT partNumber=1&uploadId=42",
+				"PUT partNumber=2&uploadId=42",
+				"PUT partNumber=3&uploadId=42",
+				"POST uploadId=42",
+			},
+		},
+		{
+			name:       "Upload of a part refused",
+			partStatus: http.StatusForbidden,
+			expErr:     true,
+			expRequests: []string{
+				"POST uploads=",
+				"PUT partNumber=1&uploadId=42",
+				"DELETE uploadId=42",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			requests := []string{}
+			parts := []string{}
+			var complete string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.RawQuery)
+				content, _ := io.ReadAll(r.Body)
+				switch {
+				case r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+					_, _ = w.Write([]byte("<InitiateMultipartUploadResult><UploadId>42</UploadId></InitiateMultipartUploadResult>"))
+				case r.Method == http.MethodPut:
+					parts = append(parts, string(content))
+					w.Header().Set("ETag", "\"etag"+r.URL.Query().Get("partNumber")+"\"")
+					w.WriteHeader(test.partStatus)
+				case r.Method == http.MethodPost:
+					complete = string(content)
+				}
+			}))
+			defer server.Close()
+
+			uploader, err := NewS3Storage(S3Config{Endpoint: server.URL, Bucket: "backups"}, server.Client())
+			require.NoError(t, err)
+			uploader.partSize = 4
+
+			location, err := uploader.Upload(context.TODO(), "testns/backup.rdb", strings.NewReader("REDIS0009"), 9)
+			assert.Equal(test.expRequests, requests)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal("s3://backups/testns/backup.rdb", location)
+			assert.Equal([]string{"REDI", "S000", "9"}, parts)
+			assert.Equal(`<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>&#34;etag1&#34;</ETag></Part><Part><PartNumber>2</PartNumber><ETag>&#34;etag2&#34;</ETag></Part><Part><PartNumber>3</PartNumber><ETag>&#34;etag3&#34;</ETag></Part></CompleteMultipartUpload>`, complete)
+		})
+	}
+}
+
+func TestNewS3StorageValidation(t *testing.T) {
+	_, err := NewS3Storage(S3Config{Endpoint: "minio:9000", Bucket: "backups"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewS3Storage(S3Config{Endpoint: "http://minio:9000"}, nil)
+	assert.Error(t, err)
+}
@@ -16,6 +16,8 @@ type Services interface {
 	Pod
 	PodDisruptionBudget
 	RedisFailover
+	RedisFailoverBackup
+	RedisCluster
 	Service
 	RBAC
 	Deployment
@@ -28,6 +30,8 @@ type services struct {
 	Pod
 	PodDisruptionBudget
 	RedisFailover
+	RedisFailoverBackup
+	RedisCluster
 	Service
 	RBAC
 	Deployment
@@ -42,6 +46,8 @@ func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface,
 		Pod:                 NewPodService(kubecli, logger, metricsRecorder),
 		PodDisruptionBudget: NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
 		RedisFailover:       NewRedisFailoverService(crdcli, logger, metricsRecorder),
+		RedisFailoverBackup: NewRedisFailoverBackupService(crdcli, logger, metricsRecorder),
+		RedisCluster:        NewRedisClusterService(crdcli, logger, metricsRecorder),
 		Service:             NewServiceService(kubecli, logger, metricsRecorder),
 		RBAC:                NewRBACService(kubecli, logger, metricsRecorder),
 		Deployment:          NewDeploymentService(kubecli, logger, metricsRecorder),
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/freshworks/redis-operator/log"
@@ -24,6 +25,7 @@ type Pod interface {
 	DeletePod(namespace string, name string) error
 	ListPods(namespace string) (*corev1.PodList, error)
 	UpdatePodLabels(namespace, podName string, labels map[string]string) error
+	WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
 }
 
 // PodService is the pod service implementation using API calls to kubernetes.
@@ -128,3 +130,8 @@ func (p *PodService) UpdatePodLabels(namespace, podName string, labels map[strin
 	}
 	return err
 }
+
+// WatchPods watches the pods of the namespace, all of them when the namespace is empty.
+func (p *PodService) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return p.kubeClient.CoreV1().Pods(namespace).Watch(ctx, opts)
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/freshworks/redis-operator/client/k8s/clientset/versioned"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+)
+
+// RedisCluster the RC service that knows how to interact with k8s to get them
+type RedisCluster interface {
+	// ListRedisClusters lists the redisclusters on a cluster.
+	ListRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisClusterList, error)
+	// WatchRedisClusters watches the redisclusters on a cluster.
+	WatchRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// GetRedisCluster gets a rediscluster.
+	GetRedisCluster(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisCluster, error)
+	// UpdateRedisClusterStatus updates the status subresource of a rediscluster.
+	UpdateRedisClusterStatus(ctx context.Context, namespace string, cluster *redisfailoverv1.RedisCluster) (*redisfailoverv1.RedisCluster, error)
+}
+
+// RedisClusterService is the RedisCluster service implementation using API calls to kubernetes.
+type RedisClusterService struct {
+	k8sCli          redisfailoverclientset.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewRedisClusterService returns a new RedisCluster KubeService.
+func NewRedisClusterService(k8scli redisfailoverclientset.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *RedisClusterService {
+	logger = logger.With("service", "k8s.rediscluster")
+	return &RedisClusterService{
+		k8sCli:          k8scli,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// ListRedisClusters satisfies rediscluster.Service interface.
+func (r *RedisClusterService) ListRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisClusterList, error) {
+	clusterList, err := r.k8sCli.DatabasesV1().RedisClusters(namespace).List(ctx, opts)
+	recordMetrics(namespace, "RedisCluster", metrics.NOT_APPLICABLE, "LIST", err, r.metricsRecorder)
+	return clusterList, err
+}
+
+// WatchRedisClusters satisfies rediscluster.Service interface.
+func (r *RedisClusterService) WatchRedisClusters(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := r.k8sCli.DatabasesV1().RedisClusters(namespace).Watch(ctx, opts)
+	recordMetrics(namespace, "RedisCluster", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
+	return watcher, err
+}
+
+// GetRedisCluster satisfies rediscluster.Service interface.
+func (r *RedisClusterService) GetRedisCluster(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisCluster, error) {
+	cluster, err := r.k8sCli.DatabasesV1().RedisClusters(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisCluster", name, "GET", err, r.metricsRecorder)
+	return cluster, err
+}
+
+// UpdateRedisClusterStatus satisfies rediscluster.Service interface.
+func (r *RedisClusterService) UpdateRedisClusterStatus(ctx context.Context, namespace string, cluster *redisfailoverv1.RedisCluster) (*redisfailoverv1.RedisCluster, error) {
+	updated, err := r.k8sCli.DatabasesV1().RedisClusters(namespace).UpdateStatus(ctx, cluster, metav1.UpdateOptions{})
+	recordMetrics(namespace, "RedisCluster", cluster.Name, "UPDATE_STATUS", err, r.metricsRecorder)
+	return updated, err
+}
@@ -2,8 +2,10 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
@@ -14,10 +16,16 @@ import (
 
 // RedisFailover the RF service that knows how to interact with k8s to get them
 type RedisFailover interface {
+	// GetRedisFailover gets a redisfailover.
+	GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error)
 	// ListRedisFailovers lists the redisfailovers on a cluster.
 	ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error)
 	// WatchRedisFailovers watches the redisfailovers on a cluster.
 	WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// UpdateRedisFailoverStatus updates the status subresource of a redisfailover.
+	UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error)
+	// RemoveRedisFailoverAnnotation removes an annotation from a redisfailover.
+	RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) (*redisfailoverv1.RedisFailover, error)
 }
 
 // RedisFailoverService is the RedisFailover service implementation using API calls to kubernetes.
@@ -37,6 +45,13 @@ func NewRedisFailoverService(k8scli redisfailoverclientset.Interface, logger log
 	}
 }
 
+// GetRedisFailover satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailover", name, "GET", err, r.metricsRecorder)
+	return redisFailover, err
+}
+
 // ListRedisFailovers satisfies redisfailover.Service interface.
 func (r *RedisFailoverService) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
 	redisFailoverList, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).List(ctx, opts)
@@ -50,3 +65,26 @@ func (r *RedisFailoverService) WatchRedisFailovers(ctx context.Context, namespac
 	recordMetrics(namespace, "RedisFailover", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
 	return watcher, err
 }
+
+// UpdateRedisFailoverStatus satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts metav1.UpdateOptions) (*redisfailoverv1.RedisFailover, error) {
+	updated, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).UpdateStatus(ctx, redisFailover, opts)
+	recordMetrics(namespace, "RedisFailover", redisFailover.Name, "UPDATE_STATUS", err, r.metricsRecorder)
+	return updated, err
+}
+
+// RemoveRedisFailoverAnnotation satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) (*redisfailoverv1.RedisFailover, error) {
+	payload := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				key: nil,
+			},
+		},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	updated, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Patch(ctx, name, types.MergePatchType, payloadBytes, metav1.PatchOptions{})
+	recordMetrics(namespace, "RedisFailover", name, "PATCH", err, r.metricsRecorder)
+	return updated, err
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/freshworks/redis-operator/client/k8s/clientset/versioned"
+	"github.com/freshworks/redis-operator/log"
+	"github.com/freshworks/redis-operator/metrics"
+)
+
+// RedisFailoverBackup the RFB service that knows how to interact with k8s to get them
+type RedisFailoverBackup interface {
+	// ListRedisFailoverBackups lists the redisfailoverbackups on a cluster.
+	ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error)
+	// WatchRedisFailoverBackups watches the redisfailoverbackups on a cluster.
+	WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// GetRedisFailoverBackup gets a redisfailoverbackup.
+	GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error)
+	// CreateRedisFailoverBackup creates a redisfailoverbackup.
+	CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)
+	// UpdateRedisFailoverBackupStatus updates the status subresource of a redisfailoverbackup.
+	UpdateRedisFailoverBackupStatus(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)
+}
+
+// RedisFailoverBackupService is the RedisFailoverBackup service implementation using API calls to kubernetes.
+type RedisFailoverBackupService struct {
+	k8sCli          redisfailoverclientset.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewRedisFailoverBackupService returns a new RedisFailoverBackup KubeService.
+func NewRedisFailoverBackupService(k8scli redisfailoverclientset.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *RedisFailoverBackupService {
+	logger = logger.With("service", "k8s.redisfailoverbackup")
+	return &RedisFailoverBackupService{
+		k8sCli:          k8scli,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// ListRedisFailoverBackups satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error) {
+	backupList, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).List(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverBackup", metrics.NOT_APPLICABLE, "LIST", err, r.metricsRecorder)
+	return backupList, err
+}
+
+// WatchRedisFailoverBackups satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Watch(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverBackup", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
+	return watcher, err
+}
+
+// GetRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error) {
+	backup, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", name, "GET", err, r.metricsRecorder)
+	return backup, err
+}
+
+// CreateRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	created, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Create(ctx, backup, metav1.CreateOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", backup.Name, "CREATE", err, r.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	r.logger.WithField("namespace", namespace).WithField("redisfailoverbackup", backup.Name).Debugf("redisfailoverbackup created")
+	return created, nil
+}
+
+// UpdateRedisFailoverBackupStatus satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) UpdateRedisFailoverBackupStatus(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	updated, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).UpdateStatus(ctx, backup, metav1.UpdateOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", backup.Name, "UPDATE_STATUS", err, r.metricsRecorder)
+	return updated, err
+}
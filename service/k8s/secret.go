@@ -6,13 +6,20 @@ import (
 	"github.com/freshworks/redis-operator/log"
 	"github.com/freshworks/redis-operator/metrics"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
-// Secret interacts with k8s to get secrets
+// Secret interacts with k8s to get, create, update, list and watch secrets
 type Secret interface {
 	GetSecret(namespace, name string) (*corev1.Secret, error)
+	CreateSecret(namespace string, secret *corev1.Secret) error
+	CreateIfNotExistsSecret(namespace string, secret *corev1.Secret) error
+	UpdateSecret(namespace string, secret *corev1.Secret) error
+	ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.SecretList, error)
+	WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
 }
 
 // SecretService is the secret service implementation using API calls to kubernetes.
@@ -42,3 +49,47 @@ func (s *SecretService) GetSecret(namespace, name string) (*corev1.Secret, error
 
 	return secret, err
 }
+
+func (s *SecretService) CreateSecret(namespace string, secret *corev1.Secret) error {
+	_, err := s.kubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	recordMetrics(namespace, "Secret", secret.GetName(), "CREATE", err, s.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	s.logger.WithField("namespace", namespace).WithField("secret", secret.Name).Debugf("secret created")
+	return nil
+}
+
+// CreateIfNotExistsSecret creates the secret unless it already exists, an existing secret is never updated.
+func (s *SecretService) CreateIfNotExistsSecret(namespace string, secret *corev1.Secret) error {
+	if _, err := s.GetSecret(namespace, secret.Name); err != nil {
+		// If no resource we need to create.
+		if errors.IsNotFound(err) {
+			return s.CreateSecret(namespace, secret)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SecretService) UpdateSecret(namespace string, secret *corev1.Secret) error {
+	_, err := s.kubeClient.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	recordMetrics(namespace, "Secret", secret.GetName(), "UPDATE", err, s.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	s.logger.WithField("namespace", namespace).WithField("secret", secret.Name).Debugf("secret updated")
+	return nil
+}
+
+// ListSecrets lists the secrets of the namespace, all of them when the namespace is empty.
+func (s *SecretService) ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	secrets, err := s.kubeClient.CoreV1().Secrets(namespace).List(ctx, opts)
+	recordMetrics(namespace, "Secret", metrics.NOT_APPLICABLE, "LIST", err, s.metricsRecorder)
+	return secrets, err
+}
+
+// WatchSecrets watches the secrets of the namespace, all of them when the namespace is empty.
+func (s *SecretService) WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return s.kubeClient.CoreV1().Secrets(namespace).Watch(ctx, opts)
+}
@@ -12,6 +12,7 @@ import (
 	errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kubernetes "k8s.io/client-go/kubernetes/fake"
 	kubetesting "k8s.io/client-go/testing"
 )
@@ -58,3 +59,60 @@ func TestSecretServiceGet(t *testing.T) {
 		assert.True(errors.IsNotFound(err))
 	})
 }
+
+func TestSecretServiceCreateIfNotExists(t *testing.T) {
+	secretsGroup := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+	testSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testsecret1",
+		},
+		Data: map[string][]byte{
+			"foo": []byte("bar"),
+		},
+	}
+
+	testns := "testns"
+
+	tests := []struct {
+		name            string
+		getSecretResult *corev1.Secret
+		errorOnGet      error
+		expActions      []kubetesting.Action
+	}{
+		{
+			name:       "A new secret should be created.",
+			errorOnGet: errors.NewNotFound(schema.GroupResource{}, ""),
+			expActions: []kubetesting.Action{
+				kubetesting.NewGetAction(secretsGroup, testns, testSecret.ObjectMeta.Name),
+				kubetesting.NewCreateAction(secretsGroup, testns, testSecret),
+			},
+		},
+		{
+			name:            "An existent secret should be kept as is.",
+			getSecretResult: testSecret,
+			expActions: []kubetesting.Action{
+				kubetesting.NewGetAction(secretsGroup, testns, testSecret.ObjectMeta.Name),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			mcli := &kubernetes.Clientset{}
+			mcli.AddReactor("get", "secrets", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, test.getSecretResult, test.errorOnGet
+			})
+			mcli.AddReactor("create", "secrets", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, nil, nil
+			})
+
+			service := NewSecretService(mcli, log.Dummy, metrics.Dummy)
+			err := service.CreateIfNotExistsSecret(testns, testSecret)
+
+			assert.NoError(err)
+			assert.Equal(test.expActions, mcli.Actions())
+		})
+	}
+}
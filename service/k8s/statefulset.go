@@ -29,6 +29,8 @@ type StatefulSet interface {
 	CreateOrUpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error
 	DeleteStatefulSet(namespace string, name string) error
 	ListStatefulSets(namespace string) (*appsv1.StatefulSetList, error)
+	GetPersistentVolumeClaim(namespace string, name string) (*corev1.PersistentVolumeClaim, error)
+	DeletePersistentVolumeClaim(namespace string, name string) error
 }
 
 // StatefulSetService is the service account service implementation using API calls to kubernetes.
@@ -188,3 +190,23 @@ func (s *StatefulSetService) ListStatefulSets(namespace string) (*appsv1.Statefu
 	recordMetrics(namespace, "StatefulSet", metrics.NOT_APPLICABLE, "LIST", err, s.metricsRecorder)
 	return stsList, err
 }
+
+// GetPersistentVolumeClaim will retrieve the requested claim based on namespace and name
+func (s *StatefulSetService) GetPersistentVolumeClaim(namespace string, name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := s.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "GET", err, s.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return pvc, err
+}
+
+// DeletePersistentVolumeClaim deletes a claim left by a statefulset pod, a claim already deleted is not an error
+func (s *StatefulSetService) DeletePersistentVolumeClaim(namespace string, name string) error {
+	err := s.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "DELETE", err, s.metricsRecorder)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -221,3 +221,61 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestStatefulSetServiceGetPersistentVolumeClaim(t *testing.T) {
+	assert := assert.New(t)
+
+	mcli := &kubernetes.Clientset{}
+	mcli.AddReactor("get", "persistentvolumeclaims", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		return true, &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "backups"}}, nil
+	})
+
+	service := k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
+	pvc, err := service.GetPersistentVolumeClaim("testns", "backups")
+	assert.NoError(err)
+	assert.Equal("backups", pvc.Name)
+}
+
+func TestStatefulSetServiceDeletePersistentVolumeClaim(t *testing.T) {
+	tests := []struct {
+		name          string
+		errorOnDelete error
+		expErr        bool
+	}{
+		{
+			name: "Deleting a claim should succeed.",
+		},
+		{
+			name:          "Deleting a claim already gone should not error.",
+			errorOnDelete: kubeerrors.NewNotFound(schema.GroupResource{}, ""),
+		},
+		{
+			name:          "Deleting a claim should error when the delete fails.",
+			errorOnDelete: errors.New("wanted error"),
+			expErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			mcli := &kubernetes.Clientset{}
+			mcli.AddReactor("delete", "persistentvolumeclaims", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, nil, test.errorOnDelete
+			})
+
+			service := k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
+			err := service.DeletePersistentVolumeClaim("testns", "data-rfr-test-3")
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if assert.Len(mcli.Actions(), 1) {
+				assert.Equal("data-rfr-test-3", mcli.Actions()[0].(kubetesting.DeleteAction).GetName())
+			}
+		})
+	}
+}
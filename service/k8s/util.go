@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 
 	redisfailoverv1 "github.com/freshworks/redis-operator/api/redisfailover/v1"
@@ -29,6 +31,111 @@ func GetRedisPassword(s Services, rf *redisfailoverv1.RedisFailover) (string, er
 	return "", fmt.Errorf("secret \"%s\" does not have a password field", rf.Spec.Auth.SecretPath)
 }
 
+// GetRedisClusterPassword retreives the password of the redis of a cluster from its secret or, if
+// unspecified, returns a blank string
+func GetRedisClusterPassword(s Services, rc *redisfailoverv1.RedisCluster) (string, error) {
+	if rc.Spec.Auth.SecretPath == "" {
+		return "", nil
+	}
+
+	secret, err := s.GetSecret(rc.ObjectMeta.Namespace, rc.Spec.Auth.SecretPath)
+	if err != nil {
+		return "", err
+	}
+
+	if password, ok := secret.Data["password"]; ok {
+		return string(password), nil
+	}
+
+	return "", fmt.Errorf("secret \"%s\" does not have a password field", rc.Spec.Auth.SecretPath)
+}
+
+// Keys of the TLS secrets, following the kubernetes.io/tls layout used by cert-manager
+const (
+	TLSCertKey = "tls.crt"
+	TLSKeyKey  = "tls.key"
+	TLSCAKey   = "ca.crt"
+)
+
+// GetRedisTLSConfig builds the TLS configuration used to connect to the redises from the
+// secret given in the spec or, if unspecified, returns nil
+func GetRedisTLSConfig(s Services, rf *redisfailoverv1.RedisFailover) (*tls.Config, error) {
+	if rf.Spec.Redis.TLS == nil {
+		return nil, nil
+	}
+	return getTLSConfig(s, rf.ObjectMeta.Namespace, rf.Spec.Redis.TLS.SecretName)
+}
+
+// GetSentinelTLSConfig builds the TLS configuration used to connect to the sentinels from the
+// secret given in the spec or, if unspecified, returns nil
+func GetSentinelTLSConfig(s Services, rf *redisfailoverv1.RedisFailover) (*tls.Config, error) {
+	settings := rf.SentinelTLS()
+	if settings == nil {
+		return nil, nil
+	}
+	return getTLSConfig(s, rf.ObjectMeta.Namespace, settings.SecretName)
+}
+
+func getTLSConfig(s Services, namespace, secretName string) (*tls.Config, error) {
+	secret, err := s.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+	return newTLSConfig(secretName, secret.Data)
+}
+
+func newTLSConfig(secretName string, data map[string][]byte) (*tls.Config, error) {
+	for _, key := range []string{TLSCertKey, TLSKeyKey, TLSCAKey} {
+		if _, ok := data[key]; !ok {
+			return nil, fmt.Errorf("secret \"%s\" does not have a %s field", secretName, key)
+		}
+	}
+
+	cert, err := tls.X509KeyPair(data[TLSCertKey], data[TLSKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("secret \"%s\" has an invalid key pair: %w", secretName, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data[TLSCAKey]) {
+		return nil, fmt.Errorf("secret \"%s\" has an invalid %s", secretName, TLSCAKey)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		MinVersion:   tls.VersionTLS12,
+		// The operator connects to the pods by IP, which the certificates don't usually hold, so the
+		// hostname check is replaced by the verification of the chain against the CA of the secret.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeerCertificate(rawCerts, roots)
+		},
+	}, nil
+}
+
+func verifyPeerCertificate(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no certificate presented by the server")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
 func recordMetrics(namespace string, kind string, object string, operation string, err error, metricsRecorder metrics.Recorder) {
 	if nil == err {
 		metricsRecorder.RecordK8sOperation(namespace, kind, object, operation, metrics.SUCCESS, metrics.NOT_APPLICABLE)
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              []string{cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "rfr-test.testns.svc", ca)
+	otherCA := newTestCert(t, "other-ca", nil)
+	other := newTestCert(t, "rfr-test.testns.svc", otherCA)
+
+	t.Run("Missing keys", func(t *testing.T) {
+		_, err := newTLSConfig("redis-tls", map[string][]byte{TLSCertKey: server.certPEM, TLSKeyKey: server.keyPEM})
+		assert.EqualError(t, err, `secret "redis-tls" does not have a ca.crt field`)
+	})
+
+	t.Run("Invalid CA", func(t *testing.T) {
+		_, err := newTLSConfig("redis-tls", map[string][]byte{TLSCertKey: server.certPEM, TLSKeyKey: server.keyPEM, TLSCAKey: []byte("foo")})
+		assert.EqualError(t, err, `secret "redis-tls" has an invalid ca.crt`)
+	})
+
+	t.Run("Peers are verified against the CA without hostname", func(t *testing.T) {
+		assert := assert.New(t)
+		config, err := newTLSConfig("redis-tls", map[string][]byte{TLSCertKey: server.certPEM, TLSKeyKey: server.keyPEM, TLSCAKey: ca.certPEM})
+		require.NoError(t, err)
+		assert.Len(config.Certificates, 1)
+		assert.NoError(config.VerifyPeerCertificate([][]byte{server.cert.Raw}, nil))
+		assert.Error(config.VerifyPeerCertificate([][]byte{other.cert.Raw}, nil))
+		assert.Error(config.VerifyPeerCertificate(nil, nil))
+	})
+}
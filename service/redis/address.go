@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NormalizeAddress returns an IP in its canonical form, the one kubernetes gives the IPs of the pods in,
+// so an IPv6 address written another way by redis is still recognized. Addresses that are not IPs, like
+// DNS names, are returned as they are.
+func NormalizeAddress(address string) string {
+	if ip := net.ParseIP(strings.Trim(address, "[]")); ip != nil {
+		return ip.String()
+	}
+	return address
+}
+
+// SameAddress tells if both addresses are the same IP, whatever the form they are written in, or the
+// same name
+func SameAddress(a, b string) bool {
+	return NormalizeAddress(a) == NormalizeAddress(b)
+}
+
+// IsLoopback tells if the address is the one of the local host, like the master the redises replicate
+// from until the operator gives them one
+func IsLoopback(address string) bool {
+	ip := net.ParseIP(strings.Trim(address, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// getMasterHost returns the master given by INFO replication, or an empty string when the redis is a master
+func getMasterHost(info string) string {
+	match := redisMasterHostRE.FindStringSubmatch(info)
+	if len(match) == 0 {
+		return ""
+	}
+	return NormalizeAddress(match[1])
+}
+
+// getAddrHost returns the host of an address with a port, the IPv6 ones included
+func getAddrHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// splitNodeAddr splits the address of a redis cluster node, which is not bracketed when it is an IPv6
+func splitNodeAddr(addr string) (string, string, error) {
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("missing port in address %s", addr)
+	}
+	return strings.Trim(addr[:i], "[]"), addr[i+1:], nil
+}
+
+// parseSentinelMaster returns the address and the port of the master in the reply of SENTINEL master,
+// a list of fields and their values
+func parseSentinelMaster(reply []interface{}) (string, string, error) {
+	var ip, port string
+	for i := 0; i+1 < len(reply); i += 2 {
+		field, _ := reply[i].(string)
+		value, _ := reply[i+1].(string)
+		switch field {
+		case "ip":
+			ip = value
+		case "port":
+			port = value
+		}
+	}
+	if ip == "" || port == "" {
+		return "", "", fmt.Errorf("master address not found in %v", reply)
+	}
+	return NormalizeAddress(ip), port, nil
+}
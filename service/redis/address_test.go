@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMasterHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     string
+		expected string
+	}{
+		{
+			name:     "Master",
+			info:     "# Replication\r\nrole:master\r\nconnected_slaves:0\r\n",
+			expected: "",
+		},
+		{
+			name:     "Replica of an IPv4 master",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\n",
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "Replica of an IPv6 master",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:fd00:10:244::5\r\nmaster_port:6379\r\n",
+			expected: "fd00:10:244::5",
+		},
+		{
+			name:     "Replica of an IPv6 master in its long form",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:fd00:10:244:0:0:0:0:5\r\nmaster_port:6379\r\n",
+			expected: "fd00:10:244::5",
+		},
+		{
+			name:     "Replica of an IPv6 loopback",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:::1\r\nmaster_port:6379\r\n",
+			expected: "::1",
+		},
+		{
+			name:     "Replica of a DNS name",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:rfr-test-0.rfr-test.ns.svc\r\nmaster_port:6379\r\n",
+			expected: "rfr-test-0.rfr-test.ns.svc",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, getMasterHost(test.info))
+		})
+	}
+}
+
+func TestSameAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(SameAddress("10.0.0.1", "10.0.0.1"))
+	assert.True(SameAddress("fd00::5", "fd00:0:0:0:0:0:0:5"))
+	assert.True(SameAddress("[fd00::5]", "fd00::5"))
+	assert.True(SameAddress("rfr-test-0.rfr-test.ns.svc", "rfr-test-0.rfr-test.ns.svc"))
+	assert.False(SameAddress("fd00::5", "fd00::50"))
+	assert.False(SameAddress("10.0.0.1", "10.0.0.10"))
+}
+
+func TestIsLoopback(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsLoopback("127.0.0.1"))
+	assert.True(IsLoopback("::1"))
+	assert.False(IsLoopback("fd00::1"))
+	assert.False(IsLoopback("localhost"))
+	assert.False(IsLoopback(""))
+}
+
+func TestGetAddrHost(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("10.0.0.1", getAddrHost("10.0.0.1:6379"))
+	assert.Equal("fd00::5", getAddrHost("[fd00::5]:6379"))
+}
+
+func TestParseSentinelMaster(t *testing.T) {
+	tests := []struct {
+		name         string
+		reply        []interface{}
+		expectedIP   string
+		expectedPort string
+		expectedErr  bool
+	}{
+		{
+			name:         "IPv4 master",
+			reply:        []interface{}{"name", "mymaster", "ip", "10.0.0.1", "port", "6379", "runid", "abc", "flags", "master"},
+			expectedIP:   "10.0.0.1",
+			expectedPort: "6379",
+		},
+		{
+			name:         "IPv6 master",
+			reply:        []interface{}{"name", "mymaster", "ip", "fd00:10:244::5", "port", "6379", "runid", "abc", "flags", "master"},
+			expectedIP:   "fd00:10:244::5",
+			expectedPort: "6379",
+		},
+		{
+			name:         "Master by DNS name",
+			reply:        []interface{}{"name", "mymaster", "ip", "rfr-test-0.rfr-test.ns.svc", "port", "6379"},
+			expectedIP:   "rfr-test-0.rfr-test.ns.svc",
+			expectedPort: "6379",
+		},
+		{
+			name:        "No address",
+			reply:       []interface{}{"name", "mymaster"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ip, port, err := parseSentinelMaster(test.reply)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expectedIP, ip)
+			assert.Equal(test.expectedPort, port)
+		})
+	}
+}
+
+func TestSentinelInfoIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	info := "# Sentinel\r\nsentinel_masters:1\r\nmaster0:name=mymaster,status=ok,address=fd00:10:244::5:6379,slaves=2,sentinels=3\r\n"
+
+	assert.NoError(isSentinelReady(info))
+	assert.Equal("3", sentinelNumberRE.FindStringSubmatch(info)[1])
+	assert.Equal("2", slaveNumberRE.FindStringSubmatch(info)[1])
+}
+
+func TestParseClusterNodesIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	out := "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca fd00:10:244::5:6379@16379 myself,master - 0 0 1 connected 0-1\n" +
+		"67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 [fd00:10:244::6]:6379@16379 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 1 connected\n"
+
+	nodes, err := parseClusterNodes(out)
+	assert.NoError(err)
+	if assert.Len(nodes, 2) {
+		assert.Equal("fd00:10:244::5", nodes[0].IP)
+		assert.Equal([]int{0, 1}, nodes[0].Slots)
+		assert.Equal("fd00:10:244::6", nodes[1].IP)
+		assert.Equal("e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca", nodes[1].MasterID)
+	}
+}
+
+func TestParseReplicationInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        string
+		expected    ReplicationInfo
+		expectedErr bool
+	}{
+		{
+			name:     "Master",
+			info:     "# Replication\r\nrole:master\r\nconnected_slaves:2\r\nmaster_replid:abc\r\nmaster_repl_offset:42\r\n",
+			expected: ReplicationInfo{Role: "master", MasterReplID: "abc", MasterReplOffset: 42},
+		},
+		{
+			name:     "Replica in sync with an IPv6 master",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:fd00:10:244:0:0:0:0:5\r\nmaster_port:6379\r\nmaster_link_status:up\r\nmaster_sync_in_progress:0\r\nmaster_replid:abc\r\nmaster_repl_offset:42\r\n",
+			expected: ReplicationInfo{Role: "slave", MasterReplID: "abc", MasterReplOffset: 42, MasterHost: "fd00:10:244::5", MasterLinkUp: true},
+		},
+		{
+			name:     "Replica syncing",
+			info:     "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:down\r\nmaster_sync_in_progress:1\r\nmaster_replid:abc\r\nmaster_repl_offset:0\r\n",
+			expected: ReplicationInfo{Role: "slave", MasterReplID: "abc", MasterHost: "10.0.0.1", MasterSyncInProgress: true},
+		},
+		{
+			name:        "No replication id",
+			info:        "# Replication\r\nrole:master\r\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			replication, err := parseReplicationInfo(test.info)
+			if test.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, replication)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -9,38 +10,87 @@ import (
 	"strconv"
 	"strings"
 
-	rediscli "github.com/go-redis/redis/v8"
 	"github.com/freshworks/redis-operator/log"
 	"github.com/freshworks/redis-operator/metrics"
+	rediscli "github.com/go-redis/redis/v8"
 )
 
-// Client defines the functions neccesary to connect to redis and sentinel to get or set what we nned
+// Client defines the functions neccesary to connect to redis and sentinel to get or set what we nned.
+// The connections to every address are kept between the calls, until Evict is called for it.
 type Client interface {
-	GetNumberSentinelsInMemory(ip string) (int32, error)
-	GetNumberSentinelSlavesInMemory(ip string) (int32, error)
-	ResetSentinel(ip string) error
-	GetSlaveOf(ip, port, password string) (string, error)
-	IsMaster(ip, port, password string) (bool, error)
-	MonitorRedis(ip, monitor, quorum, password, masterName string) error
-	MonitorRedisWithPort(ip, monitor, port, quorum, password, masterName string) error
-	MakeMaster(ip, port, password string) error
-	MakeSlaveOf(ip, masterIP, password string) error
-	MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error
-	GetSentinelMonitor(ip, masterName string) (string, string, error)
-	SetCustomSentinelConfig(ip, masterName string, configs []string) error
-	SetCustomRedisConfig(ip string, port string, configs []string, password string) error
-	SlaveIsReady(ip, port, password string) (bool, error)
-	SentinelCheckQuorum(ip, masterName string) error
+	GetNumberSentinelsInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error)
+	GetNumberSentinelSlavesInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error)
+	ResetSentinel(ctx context.Context, ip string, tlsConfig *tls.Config) error
+	GetSlaveOf(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (string, error)
+	IsMaster(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (bool, error)
+	MonitorRedis(ctx context.Context, ip, monitor, quorum, password, masterName string, tlsConfig *tls.Config) error
+	MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password, masterName string, tlsConfig *tls.Config) error
+	MakeMaster(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) error
+	MakeSlaveOf(ctx context.Context, ip, masterIP, username, password string, tlsConfig *tls.Config) error
+	MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, username, password string, tlsConfig *tls.Config) error
+	GetSentinelMonitor(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) (string, string, error)
+	SetCustomSentinelConfig(ctx context.Context, ip, masterName string, configs []string, tlsConfig *tls.Config) error
+	SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, rewrite bool, username, password string, tlsConfig *tls.Config) error
+	SlaveIsReady(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (bool, error)
+	SentinelCheckQuorum(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) error
+	SentinelFailover(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) error
+	SubscribeSentinelEvents(ctx context.Context, ip string, channels []string, tlsConfig *tls.Config, onEvent func(channel string)) error
+	EnableSentinelHostnames(ctx context.Context, ip string, tlsConfig *tls.Config) error
+	SetRedisUser(ctx context.Context, ip, port, name string, rules []string, username, password string, tlsConfig *tls.Config) error
+	DeleteRedisUser(ctx context.Context, ip, port, name, username, password string, tlsConfig *tls.Config) error
+	BackgroundSave(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) error
+	GetLastSave(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (int64, error)
+	GetReplicationInfo(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (ReplicationInfo, error)
+	GetReplicaPriority(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (int, error)
+	GetClusterNodes(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) ([]ClusterNode, error)
+	ClusterMeet(ctx context.Context, ip, port, nodeIP, username, password string, tlsConfig *tls.Config) error
+	ClusterAddSlots(ctx context.Context, ip, port string, slots []int, username, password string, tlsConfig *tls.Config) error
+	ClusterReplicate(ctx context.Context, ip, port, masterID, username, password string, tlsConfig *tls.Config) error
+	ClusterForget(ctx context.Context, ip, port, nodeID, username, password string, tlsConfig *tls.Config) error
+	ClusterMigrateSlot(ctx context.Context, sourceIP, targetIP, port string, slot int, username, password string, tlsConfig *tls.Config) error
+	Evict(ip string)
+}
+
+// ReplicationInfo is the position of a redis in its replication stream, as given by INFO replication
+type ReplicationInfo struct {
+	Role             string
+	MasterReplID     string
+	MasterReplOffset int64
+	// MasterHost is the master a replica replicates from, empty on a master
+	MasterHost string
+	// MasterLinkUp tells if a replica is connected to its master
+	MasterLinkUp bool
+	// MasterSyncInProgress tells if a replica is still loading the data of its master
+	MasterSyncInProgress bool
+}
+
+// IsMaster tells if the redis works as a master
+func (i ReplicationInfo) IsMaster() bool {
+	return i.Role == "master"
+}
+
+// IsSynced tells if the redis is a replica connected to a master other than itself, and done loading its data
+func (i ReplicationInfo) IsSynced() bool {
+	return i.MasterLinkUp && !i.MasterSyncInProgress && !IsLoopback(i.MasterHost)
 }
 
 type client struct {
 	metricsRecorder metrics.Recorder
+	config          Config
+	conns           *connCache
 }
 
 // New returns a redis client
 func New(metricsRecorder metrics.Recorder) Client {
+	return NewWithConfig(metricsRecorder, DefaultConfig)
+}
+
+// NewWithConfig returns a redis client making its calls as the config given tells
+func NewWithConfig(metricsRecorder metrics.Recorder, config Config) Client {
 	return &client{
 		metricsRecorder: metricsRecorder,
+		config:          config,
+		conns:           newConnCache(config),
 	}
 }
 
@@ -48,10 +98,9 @@ const (
 	sentinelsNumberREString = "sentinels=([0-9]+)"
 	slaveNumberREString     = "slaves=([0-9]+)"
 	sentinelStatusREString  = "status=([a-z]+)"
-	redisMasterHostREString = "master_host:([0-9.]+)"
+	redisMasterHostREString = `master_host:(\S+)`
 	redisRoleMaster         = "role:master"
 	redisSyncing            = "master_sync_in_progress:1"
-	redisMasterSillPending  = "master_host:127.0.0.1"
 	redisLinkUp             = "master_link_status:up"
 	redisPort               = "6379"
 	sentinelPort            = "26379"
@@ -64,16 +113,24 @@ var (
 	redisMasterHostRE = regexp.MustCompile(redisMasterHostREString)
 )
 
+// Evict closes the connections kept to the redis or the sentinel of the IP, like when its pod is gone
+func (c *client) Evict(ip string) {
+	c.conns.evictIP(ip)
+}
+
+// getInfo returns the section of INFO given by the endpoint
+func (c *client) getInfo(ctx context.Context, e endpoint, section string) (string, error) {
+	var info string
+	err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		info, err = rClient.Info(ctx, section).Result()
+		return err
+	})
+	return info, err
+}
+
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelsInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error) {
+	info, err := c.getInfo(ctx, sentinelEndpoint(ip, tlsConfig), "sentinel")
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -97,15 +154,8 @@ func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 }
 
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string, tlsConfig *tls.Config) (int32, error) {
+	info, err := c.getInfo(ctx, sentinelEndpoint(ip, tlsConfig), "sentinel")
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -137,21 +187,10 @@ func isSentinelReady(info string) error {
 }
 
 // ResetSentinel sends a sentinel reset * for the given sentinel
-func (c *client) ResetSentinel(ip string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	cmd := rediscli.NewIntCmd(context.TODO(), "SENTINEL", "reset", "*")
-	err := rClient.Process(context.TODO(), cmd)
-	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.RESET_SENTINEL, metrics.FAIL, getRedisError(err))
-		return err
-	}
-	_, err = cmd.Result()
+func (c *client) ResetSentinel(ctx context.Context, ip string, tlsConfig *tls.Config) error {
+	err := c.do(ctx, sentinelEndpoint(ip, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "SENTINEL", "reset", "*").Err()
+	})
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.RESET_SENTINEL, metrics.FAIL, getRedisError(err))
 		return err
@@ -161,39 +200,19 @@ func (c *client) ResetSentinel(ip string) error {
 }
 
 // GetSlaveOf returns the master of the given redis, or nil if it's master
-func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
-
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) GetSlaveOf(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (string, error) {
+	info, err := c.getInfo(ctx, redisEndpoint(ip, port, username, password, tlsConfig), "replication")
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.FAIL, getRedisError(err))
 		log.Errorf("error while getting masterIP : Failed to get info replication while querying redis instance %v", ip)
 		return "", err
 	}
-	match := redisMasterHostRE.FindStringSubmatch(info)
-	if len(match) == 0 {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-		return "", nil
-	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return match[1], nil
+	return getMasterHost(info), nil
 }
 
-func (c *client) IsMaster(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) IsMaster(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (bool, error) {
+	info, err := c.getInfo(ctx, redisEndpoint(ip, port, username, password, tlsConfig), "replication")
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.IS_MASTER, metrics.FAIL, getRedisError(err))
 		return false, err
@@ -202,41 +221,29 @@ func (c *client) IsMaster(ip, port, password string) (bool, error) {
 	return strings.Contains(info, redisRoleMaster), nil
 }
 
-func (c *client) MonitorRedis(ip, monitor, quorum, password, masterName string) error {
-	return c.MonitorRedisWithPort(ip, monitor, redisPort, quorum, password, masterName)
+func (c *client) MonitorRedis(ctx context.Context, ip, monitor, quorum, password, masterName string, tlsConfig *tls.Config) error {
+	return c.MonitorRedisWithPort(ctx, ip, monitor, redisPort, quorum, password, masterName, tlsConfig)
 }
 
-func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password, masterName string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	cmd := rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "REMOVE", masterName)
-	_ = rClient.Process(context.TODO(), cmd)
+func (c *client) MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password, masterName string, tlsConfig *tls.Config) error {
+	e := sentinelEndpoint(ip, tlsConfig)
 	// We'll continue even if it fails, the priority is to have the redises monitored
-	cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "MONITOR", masterName, monitor, port, quorum)
-	err := rClient.Process(context.TODO(), cmd)
-	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
-		return err
-	}
-	_, err = cmd.Result()
+	_ = c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "SENTINEL", "REMOVE", masterName).Err()
+	})
+	// Not tried again, a monitor that got through would be refused as a duplicate
+	err := c.do(ctx, e, false, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "SENTINEL", "MONITOR", masterName, monitor, port, quorum).Err()
+	})
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 		return err
 	}
 
 	if password != "" {
-		cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "SET", masterName, "auth-pass", password)
-		err := rClient.Process(context.TODO(), cmd)
-		if err != nil {
-			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
-			return err
-		}
-		_, err = cmd.Result()
+		err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.Do(ctx, "SENTINEL", "SET", masterName, "auth-pass", password).Err()
+		})
 		if err != nil {
 			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 			return err
@@ -246,99 +253,99 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password, maste
 	return nil
 }
 
-func (c *client) MakeMaster(ip string, port string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	if res := rClient.SlaveOf(context.TODO(), "NO", "ONE"); res.Err() != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(res.Err()))
-		return res.Err()
+func (c *client) MakeMaster(ctx context.Context, ip string, port string, username, password string, tlsConfig *tls.Config) error {
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.SlaveOf(ctx, "NO", "ONE").Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(err))
+		return err
 	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) MakeSlaveOf(ip, masterIP, password string) error {
-	return c.MakeSlaveOfWithPort(ip, masterIP, redisPort, password)
+func (c *client) MakeSlaveOf(ctx context.Context, ip, masterIP, username, password string, tlsConfig *tls.Config) error {
+	return c.MakeSlaveOfWithPort(ctx, ip, masterIP, redisPort, username, password, tlsConfig)
 }
 
-func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, masterPort), // this is IP and Port for the RedisFailover redis
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	if res := rClient.SlaveOf(context.TODO(), masterIP, masterPort); res.Err() != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(res.Err()))
-		return res.Err()
+func (c *client) MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, username, password string, tlsConfig *tls.Config) error {
+	// this is IP and Port for the RedisFailover redis
+	err := c.do(ctx, redisEndpoint(ip, masterPort, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.SlaveOf(ctx, masterIP, masterPort).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(err))
+		return err
 	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) GetSentinelMonitor(ip, masterName string) (string, string, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	cmd := rediscli.NewSliceCmd(context.TODO(), "SENTINEL", "master", masterName)
-	err := rClient.Process(context.TODO(), cmd)
+// getSentinelMaster returns the fields of the master monitored by the sentinel as given by SENTINEL master
+func (c *client) getSentinelMaster(ctx context.Context, e endpoint, masterName string) ([]interface{}, error) {
+	var res []interface{}
+	err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		res, err = rClient.Do(ctx, "SENTINEL", "master", masterName).Slice()
+		return err
+	})
+	return res, err
+}
+
+func (c *client) GetSentinelMonitor(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) (string, string, error) {
+	res, err := c.getSentinelMaster(ctx, sentinelEndpoint(ip, tlsConfig), masterName)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
 		return "", "", err
 	}
-	res, err := cmd.Result()
+	masterIP, masterPort, err := parseSentinelMaster(res)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, metrics.MISC)
 		return "", "", err
 	}
-	masterIP := res[3].(string)
-	masterPort := res[5].(string)
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return masterIP, masterPort, nil
 }
 
-func (c *client) SetCustomSentinelConfig(ip, masterName string, configs []string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
+// SetCustomSentinelConfig sets the given config on the master monitored by the sentinel. Only the values
+// differing from the ones given by SENTINEL MASTER are set, the others are always set as they are not given.
+func (c *client) SetCustomSentinelConfig(ctx context.Context, ip, masterName string, configs []string, tlsConfig *tls.Config) error {
+	e := sentinelEndpoint(ip, tlsConfig)
+
+	parameters, values, err := parseConfigs(configs)
+	if err != nil {
+		return err
+	}
+	if len(parameters) == 0 {
+		return nil
 	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
 
-	for _, config := range configs {
-		param, value, err := c.getConfigParameters(config)
-		if err != nil {
-			return err
+	res, err := c.getSentinelMaster(ctx, e, masterName)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	running := parseConfigReply(res)
+
+	var errs []error
+	for _, param := range parameters {
+		if current, ok := running[param]; ok && !configChanged(param, values[param], current) {
+			continue
 		}
-		if err := c.applySentinelConfig(param, value, masterName, rClient); err != nil {
-			return err
+		if err := c.applySentinelConfig(ctx, e, param, values[param], masterName); err != nil {
+			errs = append(errs, fmt.Errorf("setting %s: %w", param, err))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-func (c *client) SentinelCheckQuorum(ip, masterName string) error {
-
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewSentinelClient(options)
-	defer func() { _ = rClient.Close() }()
-	cmd := rClient.CkQuorum(context.TODO(), masterName)
-	res, err := cmd.Result()
+func (c *client) SentinelCheckQuorum(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) error {
+	var res string
+	err := c.do(ctx, sentinelEndpoint(ip, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		res, err = rClient.Do(ctx, "SENTINEL", "CKQUORUM", masterName).Text()
+		return err
+	})
 
 	if err != nil {
 		log.Warnf("Unable to get result for CKQUORUM comand")
@@ -348,7 +355,10 @@ func (c *client) SentinelCheckQuorum(ip, masterName string) error {
 	log.Debugf("SentinelCheckQuorum cmd result: %s", res)
 	s := strings.Split(res, " ")
 	status := s[0]
-	quorum := s[1]
+	quorum := ""
+	if len(s) > 1 {
+		quorum = s[1]
+	}
 
 	if status == "" {
 		log.Errorf("quorum command result unexpected output")
@@ -369,97 +379,325 @@ func (c *client) SentinelCheckQuorum(ip, masterName string) error {
 	}
 
 }
-func (c *client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+
+// SentinelFailover asks the given sentinel to force a failover of the master, without
+// requiring the agreement of the other sentinels
+func (c *client) SentinelFailover(ctx context.Context, ip, masterName string, tlsConfig *tls.Config) error {
+	// Not tried again, it would be refused while the first failover is in progress
+	err := c.do(ctx, sentinelEndpoint(ip, tlsConfig), false, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "SENTINEL", "FAILOVER", masterName).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.FAIL, getRedisError(err))
+		return err
 	}
-	rClient := rediscli.NewClient(options)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// SubscribeSentinelEvents calls onEvent with the channel of every event the sentinel publishes on the
+// channels given. The subscription has a connection of its own, kept apart from the ones of the calls. It
+// returns once the context is done, nil then, or with the error losing the connection.
+func (c *client) SubscribeSentinelEvents(ctx context.Context, ip string, channels []string, tlsConfig *tls.Config, onEvent func(channel string)) error {
+	rClient := rediscli.NewClient(&rediscli.Options{
+		Addr:        net.JoinHostPort(ip, sentinelPort),
+		TLSConfig:   tlsConfig,
+		DialTimeout: c.config.Timeout,
+		MaxRetries:  -1,
+	})
 	defer func() { _ = rClient.Close() }()
+	pubsub := rClient.Subscribe(ctx, channels...)
+	// Closing the subscription unblocks the wait for the next event
+	stop := context.AfterFunc(ctx, func() { _ = pubsub.Close() })
+	defer stop()
+	defer func() { _ = pubsub.Close() }()
 
-	for _, config := range configs {
-		param, value, err := c.getConfigParameters(config)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return c.sentinelEventsError(ctx, ip, err)
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SUBSCRIBE_SENTINEL_EVENTS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+
+	// A sentinel gone without closing the connection is only noticed by a ping left unanswered
+	pinged := false
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, sentinelEventsHealthCheck)
 		if err != nil {
-			return err
+			if !pinged && errors.Is(classifyError(err), ErrTimeout) && ctx.Err() == nil {
+				pinged = true
+				if err = pubsub.Ping(ctx); err == nil {
+					continue
+				}
+			}
+			return c.sentinelEventsError(ctx, ip, err)
 		}
-		// If the configuration is an empty line , it will result in an incorrect configSet, which will not run properly down the line.
-		// `config set save ""` should support
-		if strings.TrimSpace(param) == "" {
-			continue
+		pinged = false
+		if m, ok := msg.(*rediscli.Message); ok {
+			onEvent(m.Channel)
 		}
-		if err := c.applyRedisConfig(param, value, rClient); err != nil {
+	}
+}
+
+func (c *client) sentinelEventsError(ctx context.Context, ip string, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	err = classifyError(err)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SUBSCRIBE_SENTINEL_EVENTS, metrics.FAIL, getRedisError(err))
+	return err
+}
+
+// EnableSentinelHostnames makes the given sentinel accept and give the redises by DNS name. It
+// needs redis 6.2 or later.
+func (c *client) EnableSentinelHostnames(ctx context.Context, ip string, tlsConfig *tls.Config) error {
+	e := sentinelEndpoint(ip, tlsConfig)
+	for _, parameter := range []string{"resolve-hostnames", "announce-hostnames"} {
+		err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.Do(ctx, "SENTINEL", "CONFIG", "SET", parameter, "yes").Err()
+		})
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_CONFIG_SET, metrics.FAIL, getRedisError(err))
 			return err
 		}
 	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_CONFIG_SET, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) applyRedisConfig(parameter string, value string, rClient *rediscli.Client) error {
-	result := rClient.ConfigSet(context.TODO(), parameter, value)
-	if nil != result.Err() {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
-		return result.Err()
+// SetCustomRedisConfig sets the given config on the redis. Only the values differing from the running ones
+// are set, and the directives only read at startup are left to the config file. The directives redis refuses
+// to set at runtime are left to it from then on, and returned in a RestartRequiredError. The config file is
+// rewritten with the values set when rewrite is given.
+func (c *client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, rewrite bool, username, password string, tlsConfig *tls.Config) error {
+	e := redisEndpoint(ip, port, username, password, tlsConfig)
+
+	parameters, values, err := parseConfigs(configs)
+	if err != nil {
+		return err
+	}
+	if len(parameters) == 0 {
+		return nil
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return result.Err()
-}
 
-func (c *client) applySentinelConfig(parameter, value, masterName string, rClient *rediscli.Client) error {
-	cmd := rediscli.NewStatusCmd(context.TODO(), "SENTINEL", "set", masterName, parameter, value)
-	err := rClient.Process(context.TODO(), cmd)
+	var running []interface{}
+	err = c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		running, err = rClient.ConfigGet(ctx, "*").Result()
+		return err
+	})
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
 		return err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
-	return cmd.Err()
-}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	config := parseConfigReply(running)
+
+	var errs []error
+	var refused []string
+	changed := false
+	for _, param := range parameters {
+		if isRestartRequired(param) {
+			continue
+		}
+		if current, ok := config[param]; ok && !configChanged(param, values[param], current) {
+			continue
+		}
+		if err := c.applyRedisConfig(ctx, e, param, values[param]); err != nil {
+			if errors.Is(err, ErrConfigRefused) {
+				refusedParameters.Store(param, true)
+				refused = append(refused, param)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("setting %s: %w", param, err))
+			continue
+		}
+		changed = true
+	}
 
-func (c *client) getConfigParameters(config string) (parameter string, value string, err error) {
-	s := strings.Split(config, " ")
-	if len(s) < 2 {
-		return "", "", fmt.Errorf("configuration '%s' malformed", config)
+	if changed && rewrite {
+		err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.ConfigRewrite(ctx).Err()
+		})
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.REWRITE_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+			errs = append(errs, fmt.Errorf("rewriting the config: %w", err))
+		} else {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.REWRITE_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+		}
 	}
-	if len(s) == 2 && s[1] == `""` {
-		return s[0], "", nil
+	if len(refused) > 0 {
+		if len(errs) == 0 {
+			return &RestartRequiredError{Parameters: refused}
+		}
+		errs = append(errs, &RestartRequiredError{Parameters: refused})
 	}
-	return s[0], strings.Join(s[1:], " "), nil
+	return errors.Join(errs...)
 }
 
-func (c *client) SlaveIsReady(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+func (c *client) applyRedisConfig(ctx context.Context, e endpoint, parameter string, value string) error {
+	err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.ConfigSet(ctx, parameter, value).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, e.ip, metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+		return err
 	}
-	rClient := rediscli.NewClient(options)
-	defer func() { _ = rClient.Close() }()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, e.ip, metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) applySentinelConfig(ctx context.Context, e endpoint, parameter, value, masterName string) error {
+	err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "SENTINEL", "set", masterName, parameter, value).Err()
+	})
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, e.ip, metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, e.ip, metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) SlaveIsReady(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (bool, error) {
+	info, err := c.getInfo(ctx, redisEndpoint(ip, port, username, password, tlsConfig), "replication")
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
 		return false, err
 	}
 
 	ok := !strings.Contains(info, redisSyncing) &&
-		!strings.Contains(info, redisMasterSillPending) &&
+		!IsLoopback(getMasterHost(info)) &&
 		strings.Contains(info, redisLinkUp)
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SLAVE_IS_READY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return ok, nil
 }
 
-func getRedisError(err error) string {
-	if strings.Contains(err.Error(), "NOAUTH") {
-		return metrics.NOAUTH
-	} else if strings.Contains(err.Error(), "WRONGPASS") {
-		return metrics.WRONG_PASSWORD_USED
-	} else if strings.Contains(err.Error(), "NOPERM") {
-		return metrics.NOPERM
-	} else if strings.Contains(err.Error(), "i/o timeout") {
-		return metrics.IO_TIMEOUT
-	} else if strings.Contains(err.Error(), "connection refused") {
-		return metrics.CONNECTION_REFUSED
-	} else {
-		return "MISC"
+// SetRedisUser creates the ACL user or applies the rules given on top of the existing one
+func (c *client) SetRedisUser(ctx context.Context, ip, port, name string, rules []string, username, password string, tlsConfig *tls.Config) error {
+	args := []interface{}{"ACL", "SETUSER", name}
+	for _, rule := range rules {
+		args = append(args, rule)
+	}
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, args...).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_REDIS_USER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_REDIS_USER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) DeleteRedisUser(ctx context.Context, ip, port, name, username, password string, tlsConfig *tls.Config) error {
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.Do(ctx, "ACL", "DELUSER", name).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DELETE_REDIS_USER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DELETE_REDIS_USER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// BackgroundSave asks the redis to save its dataset to disk in the background
+func (c *client) BackgroundSave(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) error {
+	// Not tried again, it would be refused while the first save is in progress
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), false, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.BgSave(ctx).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.BACKGROUND_SAVE, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.BACKGROUND_SAVE, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// GetLastSave returns the unix time of the last successful save of the redis to disk
+func (c *client) GetLastSave(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (int64, error) {
+	var lastSave int64
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		lastSave, err = rClient.LastSave(ctx).Result()
+		return err
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_LAST_SAVE, metrics.FAIL, getRedisError(err))
+		return 0, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_LAST_SAVE, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return lastSave, nil
+}
+
+// GetReplicationInfo returns the replication id and offset of the redis
+func (c *client) GetReplicationInfo(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (ReplicationInfo, error) {
+	info, err := c.getInfo(ctx, redisEndpoint(ip, port, username, password, tlsConfig), "replication")
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.FAIL, getRedisError(err))
+		return ReplicationInfo{}, err
+	}
+
+	replication, err := parseReplicationInfo(info)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.FAIL, metrics.REGEX_NOT_FOUND)
+		return ReplicationInfo{}, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return replication, nil
+}
+
+func parseReplicationInfo(info string) (ReplicationInfo, error) {
+	replication := ReplicationInfo{}
+	for _, line := range strings.Split(info, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "role":
+			replication.Role = value
+		case "master_host":
+			replication.MasterHost = NormalizeAddress(value)
+		case "master_link_status":
+			replication.MasterLinkUp = value == "up"
+		case "master_sync_in_progress":
+			replication.MasterSyncInProgress = value == "1"
+		case "master_replid":
+			replication.MasterReplID = value
+		case "master_repl_offset":
+			offset, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ReplicationInfo{}, fmt.Errorf("invalid master_repl_offset %q: %w", value, err)
+			}
+			replication.MasterReplOffset = offset
+		}
+	}
+	if replication.MasterReplID == "" {
+		return ReplicationInfo{}, errors.New("master_replid not found in the replication info")
+	}
+	return replication, nil
+}
+
+// GetReplicaPriority returns the replica-priority of the redis, 0 means it is never promoted
+func (c *client) GetReplicaPriority(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) (int, error) {
+	var config []interface{}
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		config, err = rClient.ConfigGet(ctx, "replica-priority").Result()
+		return err
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICA_PRIORITY, metrics.FAIL, getRedisError(err))
+		return 0, err
+	}
+	// CONFIG GET answers with a flat list of parameters and values
+	if len(config) != 2 {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICA_PRIORITY, metrics.FAIL, metrics.MISC)
+		return 0, errors.New("replica-priority not found in the configuration")
+	}
+	priority, err := strconv.Atoi(fmt.Sprint(config[1]))
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICA_PRIORITY, metrics.FAIL, metrics.MISC)
+		return 0, err
 	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICA_PRIORITY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return priority, nil
 }
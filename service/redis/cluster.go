@@ -0,0 +1,267 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/freshworks/redis-operator/metrics"
+	rediscli "github.com/go-redis/redis/v8"
+)
+
+const (
+	// clusterMigrateBatch is the number of keys moved by every MIGRATE of a slot migration
+	clusterMigrateBatch = 100
+	// clusterMigrateTimeout is the MIGRATE timeout in milliseconds
+	clusterMigrateTimeout = 5000
+)
+
+// ClusterNode is a node of a Redis cluster, as seen by the node CLUSTER NODES is asked to
+type ClusterNode struct {
+	ID       string
+	IP       string
+	Flags    []string
+	MasterID string
+	// Slots served by the node, the ones being imported or migrated are not included
+	Slots []int
+}
+
+// Myself tells if the node is the one that answered CLUSTER NODES
+func (n ClusterNode) Myself() bool {
+	return n.hasFlag("myself")
+}
+
+// IsMaster tells if the node works as master
+func (n ClusterNode) IsMaster() bool {
+	return n.hasFlag("master")
+}
+
+// Failed tells if the cluster agreed the node is unreachable
+func (n ClusterNode) Failed() bool {
+	return n.hasFlag("fail") || n.hasFlag("noaddr")
+}
+
+func (n ClusterNode) hasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClusterNodes returns the nodes of the cluster the redis belongs to, including itself
+func (c *client) GetClusterNodes(ctx context.Context, ip, port, username, password string, tlsConfig *tls.Config) ([]ClusterNode, error) {
+	var out string
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+		out, err = rClient.ClusterNodes(ctx).Result()
+		return err
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_CLUSTER_NODES, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	nodes, err := parseClusterNodes(out)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_CLUSTER_NODES, metrics.FAIL, metrics.MISC)
+		return nil, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_CLUSTER_NODES, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nodes, nil
+}
+
+// parseClusterNodes reads the output of CLUSTER NODES, one node per line:
+// <id> <ip:port@cport> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ...
+func parseClusterNodes(out string) ([]ClusterNode, error) {
+	nodes := []ClusterNode{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("invalid cluster node %q", line)
+		}
+
+		addr, _, _ := strings.Cut(fields[1], "@")
+		addr, _, _ = strings.Cut(addr, ",")
+		ip, _, err := splitNodeAddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address of cluster node %s: %w", fields[0], err)
+		}
+
+		node := ClusterNode{
+			ID:    fields[0],
+			IP:    ip,
+			Flags: strings.Split(fields[2], ","),
+		}
+		if fields[3] != "-" {
+			node.MasterID = fields[3]
+		}
+		for _, slots := range fields[8:] {
+			// Slots being imported or migrated are shown as [slot-<-id] or [slot->-id]
+			if strings.HasPrefix(slots, "[") {
+				continue
+			}
+			first, last, isRange := strings.Cut(slots, "-")
+			start, err := strconv.Atoi(first)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slots %q of cluster node %s", slots, node.ID)
+			}
+			end := start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return nil, fmt.Errorf("invalid slots %q of cluster node %s", slots, node.ID)
+				}
+			}
+			for slot := start; slot <= end; slot++ {
+				node.Slots = append(node.Slots, slot)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// ClusterMeet makes the redis join the cluster of the given node
+func (c *client) ClusterMeet(ctx context.Context, ip, port, nodeIP, username, password string, tlsConfig *tls.Config) error {
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.ClusterMeet(ctx, nodeIP, port).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_MEET, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_MEET, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// ClusterAddSlots assigns the slots to the redis, they must not be served by another node
+func (c *client) ClusterAddSlots(ctx context.Context, ip, port string, slots []int, username, password string, tlsConfig *tls.Config) error {
+	// Not tried again, the slots added by an attempt that got through would be refused as busy
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), false, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.ClusterAddSlots(ctx, slots...).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_ADD_SLOTS, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_ADD_SLOTS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// ClusterReplicate makes the redis a replica of the given master node
+func (c *client) ClusterReplicate(ctx context.Context, ip, port, masterID, username, password string, tlsConfig *tls.Config) error {
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.ClusterReplicate(ctx, masterID).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_REPLICATE, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_REPLICATE, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// ClusterForget removes the node from the nodes known by the redis
+func (c *client) ClusterForget(ctx context.Context, ip, port, nodeID, username, password string, tlsConfig *tls.Config) error {
+	// Not tried again, an attempt that got through would make the next one fail as the node is unknown
+	err := c.do(ctx, redisEndpoint(ip, port, username, password, tlsConfig), false, func(ctx context.Context, rClient *rediscli.Client) error {
+		return rClient.ClusterForget(ctx, nodeID).Err()
+	})
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_FORGET, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.CLUSTER_FORGET, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// ClusterMigrateSlot moves a slot and its keys from the source master to the target master
+func (c *client) ClusterMigrateSlot(ctx context.Context, sourceIP, targetIP, port string, slot int, username, password string, tlsConfig *tls.Config) error {
+	err := c.clusterMigrateSlot(ctx, sourceIP, targetIP, port, slot, username, password, tlsConfig)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, sourceIP, metrics.CLUSTER_MIGRATE_SLOT, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, sourceIP, metrics.CLUSTER_MIGRATE_SLOT, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) clusterMigrateSlot(ctx context.Context, sourceIP, targetIP, port string, slot int, username, password string, tlsConfig *tls.Config) error {
+	source := redisEndpoint(sourceIP, port, username, password, tlsConfig)
+	target := redisEndpoint(targetIP, port, username, password, tlsConfig)
+	text := func(e endpoint, args ...interface{}) (string, error) {
+		var res string
+		err := c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+			res, err = rClient.Do(ctx, args...).Text()
+			return err
+		})
+		return res, err
+	}
+	// Setting the state of a slot again is harmless, the calls are tried again
+	setSlot := func(e endpoint, args ...interface{}) error {
+		return c.do(ctx, e, true, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.Do(ctx, append([]interface{}{"CLUSTER", "SETSLOT", slot}, args...)...).Err()
+		})
+	}
+
+	sourceID, err := text(source, "CLUSTER", "MYID")
+	if err != nil {
+		return err
+	}
+	targetID, err := text(target, "CLUSTER", "MYID")
+	if err != nil {
+		return err
+	}
+
+	if err := setSlot(target, "IMPORTING", sourceID); err != nil {
+		return err
+	}
+	if err := setSlot(source, "MIGRATING", targetID); err != nil {
+		return err
+	}
+
+	for {
+		var keys []string
+		err := c.do(ctx, source, true, func(ctx context.Context, rClient *rediscli.Client) (err error) {
+			keys, err = rClient.ClusterGetKeysInSlot(ctx, slot, clusterMigrateBatch).Result()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			break
+		}
+		args := []interface{}{"MIGRATE", targetIP, port, "", 0, clusterMigrateTimeout, "REPLACE"}
+		if password != "" {
+			if username != "" {
+				args = append(args, "AUTH2", username, password)
+			} else {
+				args = append(args, "AUTH", password)
+			}
+		}
+		args = append(args, "KEYS")
+		for _, key := range keys {
+			args = append(args, key)
+		}
+		// MIGRATE waits for the target up to its own timeout, and is safe to run again as it replaces the keys
+		timeout := clusterMigrateTimeout*time.Millisecond + c.config.Timeout
+		err = c.doWithTimeout(ctx, source, true, timeout, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.Do(ctx, args...).Err()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The target owns the slot first, so the source redirects the clients to it once it gives it away
+	if err := setSlot(target, "NODE", targetID); err != nil {
+		return err
+	}
+	return setSlot(source, "NODE", targetID)
+}
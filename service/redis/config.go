@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// restartRequiredParameters are the redis directives only read at startup, CONFIG SET refuses them.
+var restartRequiredParameters = map[string]bool{
+	"aclfile":                  true,
+	"always-show-logo":         true,
+	"appenddirname":            true,
+	"appendfilename":           true,
+	"cluster-config-file":      true,
+	"cluster-enabled":          true,
+	"daemonize":                true,
+	"databases":                true,
+	"disable-thp":              true,
+	"enable-debug-command":     true,
+	"enable-module-command":    true,
+	"enable-protected-configs": true,
+	"include":                  true,
+	"io-threads":               true,
+	"io-threads-do-reads":      true,
+	"loadmodule":               true,
+	"logfile":                  true,
+	"pidfile":                  true,
+	"rename-command":           true,
+	"supervised":               true,
+	"syslog-enabled":           true,
+	"syslog-facility":          true,
+	"syslog-ident":             true,
+	"tcp-backlog":              true,
+	"unixsocket":               true,
+	"unixsocketperm":           true,
+}
+
+// clientOutputBufferLimit is given once for every class of clients, the classes given are set together
+const clientOutputBufferLimit = "client-output-buffer-limit"
+
+// memoryValueRegexp matches the memory values given with a unit, as redis answers them in bytes
+var memoryValueRegexp = regexp.MustCompile(`^([0-9]+)(k|kb|m|mb|g|gb)$`)
+
+var memoryUnits = map[string]int64{
+	"k":  1000,
+	"kb": 1024,
+	"m":  1000 * 1000,
+	"mb": 1024 * 1024,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1024 * 1024 * 1024,
+}
+
+// refusedParameters are the directives CONFIG SET refused although they are not known to be only read at
+// startup, like the ones of another redis release or of a module. They are handled like the ones of
+// restartRequiredParameters from then on.
+var refusedParameters sync.Map
+
+// RestartRequiredError lists the directives of the custom config redis refused to set at runtime. They are
+// written in the config file from then on, and applied by restarting the pods.
+type RestartRequiredError struct {
+	Parameters []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return fmt.Sprintf("redis refused to set %s at runtime, a restart is required", strings.Join(e.Parameters, ", "))
+}
+
+// RestartRequired tells if the custom config line can only be applied by restarting redis
+func RestartRequired(config string) bool {
+	parameter, _, _ := strings.Cut(strings.TrimSpace(config), " ")
+	return isRestartRequired(strings.ToLower(parameter))
+}
+
+func isRestartRequired(parameter string) bool {
+	if restartRequiredParameters[parameter] {
+		return true
+	}
+	_, refused := refusedParameters.Load(parameter)
+	return refused
+}
+
+// parseConfigs returns the parameters of the custom config in the order given, and their value. The
+// last value given to a parameter is the one kept, but for the client output buffer limits of every class.
+func parseConfigs(configs []string) ([]string, map[string]string, error) {
+	parameters := []string{}
+	values := map[string]string{}
+	for _, config := range configs {
+		parameter, value, err := getConfigParameters(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		// If the configuration is an empty line , it will result in an incorrect configSet, which will not run properly down the line.
+		// `config set save ""` should support
+		if strings.TrimSpace(parameter) == "" {
+			continue
+		}
+		parameter = strings.ToLower(parameter)
+		current, ok := values[parameter]
+		if !ok {
+			parameters = append(parameters, parameter)
+		}
+		if ok && parameter == clientOutputBufferLimit {
+			value = current + " " + value
+		}
+		values[parameter] = value
+	}
+	return parameters, values, nil
+}
+
+func getConfigParameters(config string) (parameter string, value string, err error) {
+	s := strings.Split(config, " ")
+	if len(s) < 2 {
+		return "", "", fmt.Errorf("configuration '%s' malformed", config)
+	}
+	if len(s) == 2 && s[1] == `""` {
+		return s[0], "", nil
+	}
+	return s[0], strings.Join(s[1:], " "), nil
+}
+
+// parseConfigReply returns the parameters and values of a flat list answered by CONFIG GET or SENTINEL MASTER
+func parseConfigReply(reply []interface{}) map[string]string {
+	config := map[string]string{}
+	for i := 0; i+1 < len(reply); i += 2 {
+		parameter := strings.ToLower(fmt.Sprint(reply[i]))
+		config[parameter] = fmt.Sprint(reply[i+1])
+	}
+	return config
+}
+
+// configChanged tells if the value wanted for the parameter is not the one running. The values are compared
+// regardless of their case, spacing and memory units.
+func configChanged(parameter, wanted, running string) bool {
+	if parameter == clientOutputBufferLimit {
+		return clientOutputBufferLimitChanged(wanted, running)
+	}
+	return normalizeConfigValue(wanted) != normalizeConfigValue(running)
+}
+
+// clientOutputBufferLimitChanged only compares the limits of the classes wanted, the others are left as they are
+func clientOutputBufferLimitChanged(wanted, running string) bool {
+	limits := func(value string) map[string]string {
+		fields := strings.Fields(normalizeConfigValue(value))
+		classes := map[string]string{}
+		for i := 0; i+3 < len(fields); i += 4 {
+			class := fields[i]
+			if class == "replica" {
+				class = "slave"
+			}
+			classes[class] = strings.Join(fields[i+1:i+4], " ")
+		}
+		return classes
+	}
+	runningLimits := limits(running)
+	for class, limit := range limits(wanted) {
+		if runningLimits[class] != limit {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeConfigValue(value string) string {
+	fields := strings.Fields(strings.ToLower(value))
+	for i, field := range fields {
+		if field == `""` {
+			fields[i] = ""
+			continue
+		}
+		match := memoryValueRegexp.FindStringSubmatch(field)
+		if match == nil {
+			continue
+		}
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[i] = strconv.FormatInt(n*memoryUnits[match[2]], 10)
+	}
+	return strings.TrimSpace(strings.Join(fields, " "))
+}
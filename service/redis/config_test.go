@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(RestartRequired("databases 32"))
+	assert.True(RestartRequired("IO-THREADS 4"))
+	assert.False(RestartRequired("maxmemory 1gb"))
+	assert.False(RestartRequired(`save ""`))
+	assert.True(RestartRequired("rename-command FLUSHALL \"\""))
+	assert.True(RestartRequired("loadmodule /usr/lib/redis/modules/redisbloom.so"))
+
+	// The directives redis refused to set at runtime are left to the config file from then on
+	assert.False(RestartRequired("bf-error-rate 0.01"))
+	refusedParameters.Store("bf-error-rate", true)
+	defer refusedParameters.Delete("bf-error-rate")
+	assert.True(RestartRequired("bf-error-rate 0.01"))
+}
+
+func TestParseConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	parameters, values, err := parseConfigs([]string{
+		"maxmemory 1gb",
+		`save ""`,
+		"Maxmemory-Policy allkeys-lru",
+		"maxmemory 2gb",
+		" ",
+		"client-output-buffer-limit normal 0 0 0",
+		"client-output-buffer-limit pubsub 32mb 8mb 60",
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"maxmemory", "save", "maxmemory-policy", "client-output-buffer-limit"}, parameters)
+	assert.Equal(map[string]string{
+		"maxmemory":                  "2gb",
+		"save":                       "",
+		"maxmemory-policy":           "allkeys-lru",
+		"client-output-buffer-limit": "normal 0 0 0 pubsub 32mb 8mb 60",
+	}, values)
+
+	_, _, err = parseConfigs([]string{"maxmemory"})
+	assert.Error(err)
+}
+
+func TestConfigChanged(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter string
+		wanted    string
+		running   string
+		changed   bool
+	}{
+		{
+			name:    "Same value",
+			wanted:  "allkeys-lru",
+			running: "allkeys-lru",
+		},
+		{
+			name:    "Different case",
+			wanted:  "YES",
+			running: "yes",
+		},
+		{
+			name:    "Memory unit",
+			wanted:  "1gb",
+			running: "1073741824",
+		},
+		{
+			name:      "Client output buffer limits",
+			parameter: "client-output-buffer-limit",
+			wanted:    "normal 0 0 0 replica 256mb 64mb 60",
+			running:   "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+		},
+		{
+			name:      "Client output buffer limits changed",
+			parameter: "client-output-buffer-limit",
+			wanted:    "pubsub 32mb 8mb 30",
+			running:   "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+			changed:   true,
+		},
+		{
+			name:    "Memory unit in powers of ten",
+			wanted:  "100m",
+			running: "100000000",
+		},
+		{
+			name:    "Spacing",
+			wanted:  "3600 1  300 100",
+			running: "3600 1 300 100",
+		},
+		{
+			name:    "Empty value",
+			wanted:  `""`,
+			running: "",
+		},
+		{
+			name:    "Different value",
+			wanted:  "2gb",
+			running: "1073741824",
+			changed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.changed, configChanged(test.parameter, test.wanted, test.running))
+		})
+	}
+}
+
+func TestParseConfigReply(t *testing.T) {
+	assert := assert.New(t)
+
+	config := parseConfigReply([]interface{}{"name", "mymaster", "down-after-milliseconds", "1000", "parallel-syncs", int64(2)})
+	assert.Equal(map[string]string{"name": "mymaster", "down-after-milliseconds": "1000", "parallel-syncs": "2"}, config)
+}
@@ -0,0 +1,211 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"sync"
+	"time"
+
+	rediscli "github.com/go-redis/redis/v8"
+)
+
+// connPoolSize is the number of connections kept open to every address, the operator rarely runs more
+// than one call at a time on the same redis
+const connPoolSize = 2
+
+// sentinelEventsHealthCheck is how long a subscription to the events of a sentinel waits for one before
+// pinging the sentinel
+const sentinelEventsHealthCheck = 15 * time.Second
+
+// Config tunes the calls of the client to the redises and the sentinels
+type Config struct {
+	// Timeout is the deadline of every attempt of a call
+	Timeout time.Duration
+	// Retries is the number of times a call failing for a transient reason is tried again, when it is
+	// safe to run it again
+	Retries int
+	// Backoff is the wait before the first retry, doubled on every one after
+	Backoff time.Duration
+	// IdleTimeout is how long the connections to an address are kept once they are not used anymore
+	IdleTimeout time.Duration
+}
+
+// DefaultConfig is the config of the client given by New
+var DefaultConfig = Config{
+	Timeout:     3 * time.Second,
+	Retries:     2,
+	Backoff:     100 * time.Millisecond,
+	IdleTimeout: 5 * time.Minute,
+}
+
+// endpoint is a redis or a sentinel, and the credentials to connect to it with
+type endpoint struct {
+	ip        string
+	port      string
+	username  string
+	password  string
+	tlsConfig *tls.Config
+}
+
+func redisEndpoint(ip, port, username, password string, tlsConfig *tls.Config) endpoint {
+	return endpoint{ip: ip, port: port, username: username, password: password, tlsConfig: tlsConfig}
+}
+
+func sentinelEndpoint(ip string, tlsConfig *tls.Config) endpoint {
+	return endpoint{ip: ip, port: sentinelPort, tlsConfig: tlsConfig}
+}
+
+// connKey identifies the connections to an endpoint. The TLS config is built again on every call, its
+// client certificates are part of the key so the ones issued again get their own connections. The CA
+// can't be read back from the config: a client failing the handshake with an outdated one is evicted.
+type connKey struct {
+	addr     string
+	username string
+	password string
+	tls      string
+}
+
+func (e endpoint) key() connKey {
+	return connKey{
+		addr:     net.JoinHostPort(e.ip, e.port),
+		username: e.username,
+		password: e.password,
+		tls:      tlsFingerprint(e.tlsConfig),
+	}
+}
+
+// tlsFingerprint returns the hash of the client certificates of the config, empty without TLS
+func tlsFingerprint(config *tls.Config) string {
+	if config == nil {
+		return ""
+	}
+	hash := sha256.New()
+	for _, cert := range config.Certificates {
+		for _, der := range cert.Certificate {
+			hash.Write(der)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type cachedConn struct {
+	ip       string
+	client   *rediscli.Client
+	lastUsed time.Time
+}
+
+// connCache keeps a pool of connections to every endpoint the client calls
+type connCache struct {
+	config    Config
+	mu        sync.Mutex
+	conns     map[connKey]*cachedConn
+	lastSweep time.Time
+}
+
+func newConnCache(config Config) *connCache {
+	return &connCache{
+		config: config,
+		conns:  map[connKey]*cachedConn{},
+	}
+}
+
+// get returns the client connected to the endpoint, created on the first call
+func (c *connCache) get(e endpoint) *rediscli.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweep(now)
+	key := e.key()
+	if conn, ok := c.conns[key]; ok {
+		conn.lastUsed = now
+		return conn.client
+	}
+	client := rediscli.NewClient(&rediscli.Options{
+		Addr:         key.addr,
+		Username:     e.username,
+		Password:     e.password,
+		DB:           0,
+		TLSConfig:    e.tlsConfig,
+		DialTimeout:  c.config.Timeout,
+		ReadTimeout:  c.config.Timeout,
+		WriteTimeout: c.config.Timeout,
+		PoolSize:     connPoolSize,
+		IdleTimeout:  c.config.IdleTimeout,
+		// The calls are tried again by the client, knowing which ones are safe to
+		MaxRetries: -1,
+	})
+	c.conns[key] = &cachedConn{ip: NormalizeAddress(e.ip), client: client, lastUsed: now}
+	return client
+}
+
+// evict closes the connections to the endpoint
+func (c *connCache) evict(e endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := e.key()
+	if conn, ok := c.conns[key]; ok {
+		_ = conn.client.Close()
+		delete(c.conns, key)
+	}
+}
+
+// evictIP closes the connections to all the endpoints of the IP
+func (c *connCache) evictIP(ip string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ip = NormalizeAddress(ip)
+	for key, conn := range c.conns {
+		if conn.ip == ip {
+			_ = conn.client.Close()
+			delete(c.conns, key)
+		}
+	}
+}
+
+// sweep closes the connections not used for the idle timeout. It runs at most once per idle timeout.
+func (c *connCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.config.IdleTimeout {
+		return
+	}
+	c.lastSweep = now
+	for key, conn := range c.conns {
+		if now.Sub(conn.lastUsed) >= c.config.IdleTimeout {
+			_ = conn.client.Close()
+			delete(c.conns, key)
+		}
+	}
+}
+
+// do runs the call on the endpoint with the deadline of an attempt. The call is tried again with a backoff
+// when it fails for a transient reason and is idempotent. The error returned is classified.
+func (c *client) do(ctx context.Context, e endpoint, idempotent bool, call func(ctx context.Context, rClient *rediscli.Client) error) error {
+	return c.doWithTimeout(ctx, e, idempotent, c.config.Timeout, call)
+}
+
+func (c *client) doWithTimeout(ctx context.Context, e endpoint, idempotent bool, timeout time.Duration, call func(ctx context.Context, rClient *rediscli.Client) error) error {
+	backoff := c.config.Backoff
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := classifyError(call(callCtx, c.conns.get(e)))
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if isConnectionError(err) {
+			c.conns.evict(e)
+		}
+		if !idempotent || !isTransient(err) || attempt >= c.config.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,248 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rediscli "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/freshworks/redis-operator/metrics"
+)
+
+func newTestClient(retries int) *client {
+	config := Config{Timeout: time.Second, Retries: retries, Backoff: time.Millisecond, IdleTimeout: time.Minute}
+	return NewWithConfig(metrics.Dummy, config).(*client)
+}
+
+func TestConnCacheGet(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newTestClient(0)
+	e := redisEndpoint("10.0.0.1", "6379", "", "pass", nil)
+
+	first := c.conns.get(e)
+	assert.Same(first, c.conns.get(e), "the connections to an endpoint are kept")
+	assert.NotSame(first, c.conns.get(redisEndpoint("10.0.0.1", "6379", "", "other", nil)), "other credentials get their own connections")
+	sentinel := c.conns.get(sentinelEndpoint("10.0.0.1", nil))
+	other := c.conns.get(redisEndpoint("10.0.0.2", "6379", "", "pass", nil))
+
+	c.Evict("10.0.0.1")
+	assert.Len(c.conns.conns, 1, "only the connections to the other IP are kept")
+	assert.ErrorIs(first.Ping(context.TODO()).Err(), rediscli.ErrClosed)
+	assert.ErrorIs(sentinel.Ping(context.TODO()).Err(), rediscli.ErrClosed)
+	assert.Same(other, c.conns.get(redisEndpoint("10.0.0.2", "6379", "", "pass", nil)))
+	assert.NotSame(first, c.conns.get(e), "new connections are made once evicted")
+}
+
+func TestConnCacheSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newTestClient(0)
+	idle := c.conns.get(redisEndpoint("10.0.0.1", "6379", "", "", nil))
+	c.conns.get(redisEndpoint("10.0.0.2", "6379", "", "", nil))
+
+	now := time.Now()
+	c.conns.conns[redisEndpoint("10.0.0.1", "6379", "", "", nil).key()].lastUsed = now.Add(-2 * time.Minute)
+	c.conns.sweep(now)
+	assert.Len(c.conns.conns, 2, "the connections are swept once per idle timeout")
+
+	c.conns.lastSweep = now.Add(-time.Minute)
+	c.conns.sweep(now)
+	assert.Len(c.conns.conns, 1)
+	assert.ErrorIs(idle.Ping(context.TODO()).Err(), rediscli.ErrClosed)
+}
+
+func TestDoRetries(t *testing.T) {
+	loading := errors.New("LOADING Redis is loading the dataset in memory")
+	tests := []struct {
+		name       string
+		idempotent bool
+		errs       []error
+		attempts   int
+		class      error
+	}{
+		{name: "transient error tried again", idempotent: true, errs: []error{loading, nil}, attempts: 2},
+		{name: "retries are bounded", idempotent: true, errs: []error{loading, loading, loading, loading}, attempts: 3, class: ErrLoading},
+		{name: "not idempotent not tried again", idempotent: false, errs: []error{loading, nil}, attempts: 1, class: ErrLoading},
+		{name: "not transient not tried again", idempotent: true, errs: []error{errors.New("WRONGPASS invalid password"), nil}, attempts: 1, class: ErrAuth},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			c := newTestClient(2)
+			attempts := 0
+			err := c.do(context.TODO(), redisEndpoint("10.0.0.1", "6379", "", "", nil), test.idempotent, func(ctx context.Context, rClient *rediscli.Client) error {
+				_, ok := ctx.Deadline()
+				assert.True(ok, "every attempt has a deadline")
+				attempts++
+				return test.errs[attempts-1]
+			})
+			assert.Equal(test.attempts, attempts)
+			if test.class == nil {
+				assert.NoError(err)
+			} else {
+				assert.ErrorIs(err, test.class)
+			}
+		})
+	}
+}
+
+func TestDoConnectionRefused(t *testing.T) {
+	assert := assert.New(t)
+
+	// A port nobody listens on anymore
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	_ = listener.Close()
+
+	c := newTestClient(1)
+	_, err = c.IsMaster(context.TODO(), "127.0.0.1", port, "", "", nil)
+	assert.ErrorIs(err, ErrConnectionRefused)
+	assert.Empty(c.conns.conns, "the connections to an unreachable redis are not kept")
+}
+
+func TestDoContextCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newTestClient(5)
+	c.config.Backoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := c.do(ctx, redisEndpoint("10.0.0.1", "6379", "", "", nil), true, func(ctx context.Context, rClient *rediscli.Client) error {
+		attempts++
+		cancel()
+		return context.DeadlineExceeded
+	})
+	assert.ErrorIs(err, ErrTimeout)
+	assert.Equal(1, attempts, "no retry once the context is done")
+}
+
+// newTestCA returns a CA and the certificate it issued for 127.0.0.1
+func newTestCA(t *testing.T) (*x509.CertPool, tls.Certificate) {
+	newCert := func(template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert, key, der
+	}
+	ca, caKey, _ := newCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	_, key, der := newCert(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "redis"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return roots, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// serveTLSPong answers PONG to every command over TLS, with the certificate given when connected to
+func serveTLSPong(t *testing.T, cert func() tls.Certificate) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			c := cert()
+			return &c, nil
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					// Every command is an array of bulk strings, each one a length line and a value line
+					args, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+					for i := 0; i < 2*args; i++ {
+						if _, err := reader.ReadString('\n'); err != nil {
+							return
+						}
+					}
+					if _, err := conn.Write([]byte("+PONG\r\n")); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return port
+}
+
+func TestDoCARotation(t *testing.T) {
+	assert := assert.New(t)
+
+	oldRoots, oldCert := newTestCA(t)
+	newRoots, newCert := newTestCA(t)
+	var served atomic.Value
+	served.Store(oldCert)
+	port := serveTLSPong(t, func() tls.Certificate { return served.Load().(tls.Certificate) })
+
+	ping := func(c *client, roots *x509.CertPool, cert tls.Certificate) error {
+		config := &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{cert}}
+		return c.do(context.TODO(), redisEndpoint("127.0.0.1", port, "", "", config), true, func(ctx context.Context, rClient *rediscli.Client) error {
+			return rClient.Ping(ctx).Err()
+		})
+	}
+
+	c := newTestClient(0)
+	assert.NoError(ping(c, oldRoots, oldCert))
+
+	// Once rotated the new certificates get their own connections
+	served.Store(newCert)
+	assert.NoError(ping(c, newRoots, newCert))
+	assert.Len(c.conns.conns, 2)
+
+	// The connections failing the handshake with the previous CA are not kept
+	c = newTestClient(0)
+	err := ping(c, oldRoots, newCert)
+	assert.ErrorIs(err, ErrTLS)
+	assert.Empty(c.conns.conns, "the connections failing the handshake are not kept")
+	assert.NoError(ping(c, newRoots, newCert))
+}
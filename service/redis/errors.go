@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/freshworks/redis-operator/metrics"
+	rediscli "github.com/go-redis/redis/v8"
+)
+
+// Errors the failures of the calls to the redises and the sentinels are classified in, to be checked
+// with errors.Is
+var (
+	ErrAuth              = errors.New("redis authentication failed")
+	ErrNoPerm            = errors.New("redis user without permission")
+	ErrTimeout           = errors.New("redis timeout")
+	ErrConnectionRefused = errors.New("redis connection refused")
+	ErrLoading           = errors.New("redis loading the dataset")
+	ErrReadOnly          = errors.New("redis read only replica")
+	ErrTLS               = errors.New("redis TLS handshake failed")
+	ErrConfigRefused     = errors.New("redis config not settable at runtime")
+)
+
+// classifiedError keeps the message of the error it wraps, and is also the class it was given
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// classifyError wraps the error in the class it belongs to, when it has one
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var class error
+	var netErr net.Error
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var alertErr tls.AlertError
+	msg := err.Error()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout(), strings.Contains(msg, "i/o timeout"):
+		class = ErrTimeout
+	case errors.Is(err, syscall.ECONNREFUSED), strings.Contains(msg, "connection refused"):
+		class = ErrConnectionRefused
+	case errors.As(err, &verifyErr), errors.As(err, &authorityErr), errors.As(err, &invalidErr), errors.As(err, &alertErr),
+		strings.Contains(msg, "tls: "), strings.Contains(msg, "x509: "):
+		class = ErrTLS
+	case strings.HasPrefix(msg, "NOAUTH"), strings.HasPrefix(msg, "WRONGPASS"):
+		class = ErrAuth
+	case strings.HasPrefix(msg, "NOPERM"):
+		class = ErrNoPerm
+	case strings.HasPrefix(msg, "LOADING"):
+		class = ErrLoading
+	case strings.HasPrefix(msg, "READONLY"):
+		class = ErrReadOnly
+	case strings.Contains(msg, "can't set immutable config"), strings.Contains(msg, "can't set protected config"),
+		strings.Contains(msg, "Unsupported CONFIG parameter"), strings.Contains(msg, "Unknown option or number of arguments for CONFIG SET"):
+		class = ErrConfigRefused
+	default:
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// isTransient tells if the call failed for a reason that may be gone when it is tried again. The client
+// may also have been closed by a call evicting it at the same time.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrConnectionRefused) || errors.Is(err, ErrLoading) || errors.Is(err, rediscli.ErrClosed)
+}
+
+// isConnectionError tells if the call failed before getting an answer, so the connections to the address
+// are not worth keeping. A TLS handshake failing is one, the certificates may have been rotated since
+// the connections were made.
+func isConnectionError(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrConnectionRefused) || errors.Is(err, ErrTLS)
+}
+
+func getRedisError(err error) string {
+	switch {
+	case errors.Is(err, ErrAuth) && strings.HasPrefix(err.Error(), "WRONGPASS"):
+		return metrics.WRONG_PASSWORD_USED
+	case errors.Is(err, ErrAuth):
+		return metrics.NOAUTH
+	case errors.Is(err, ErrNoPerm):
+		return metrics.NOPERM
+	case errors.Is(err, ErrTimeout):
+		return metrics.IO_TIMEOUT
+	case errors.Is(err, ErrConnectionRefused):
+		return metrics.CONNECTION_REFUSED
+	case errors.Is(err, ErrTLS):
+		return metrics.TLS_HANDSHAKE
+	case errors.Is(err, ErrLoading):
+		return metrics.LOADING
+	case errors.Is(err, ErrReadOnly):
+		return metrics.READONLY
+	case errors.Is(err, ErrConfigRefused):
+		return metrics.CONFIG_REFUSED
+	default:
+		return "MISC"
+	}
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/freshworks/redis-operator/metrics"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		class  error
+		metric string
+	}{
+		{name: "wrong password", err: errors.New("WRONGPASS invalid username-password pair or user is disabled."), class: ErrAuth, metric: metrics.WRONG_PASSWORD_USED},
+		{name: "no auth", err: errors.New("NOAUTH Authentication required."), class: ErrAuth, metric: metrics.NOAUTH},
+		{name: "no permission", err: errors.New("NOPERM this user has no permissions to run the 'bgsave' command"), class: ErrNoPerm, metric: metrics.NOPERM},
+		{name: "deadline", err: fmt.Errorf("calling: %w", context.DeadlineExceeded), class: ErrTimeout, metric: metrics.IO_TIMEOUT},
+		{name: "i/o timeout", err: errors.New("dial tcp 10.0.0.1:6379: i/o timeout"), class: ErrTimeout, metric: metrics.IO_TIMEOUT},
+		{name: "refused", err: errors.New("dial tcp 10.0.0.1:6379: connect: connection refused"), class: ErrConnectionRefused, metric: metrics.CONNECTION_REFUSED},
+		{name: "unknown authority", err: fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{}), class: ErrTLS, metric: metrics.TLS_HANDSHAKE},
+		{name: "bad certificate", err: errors.New("remote error: tls: bad certificate"), class: ErrTLS, metric: metrics.TLS_HANDSHAKE},
+		{name: "loading", err: errors.New("LOADING Redis is loading the dataset in memory"), class: ErrLoading, metric: metrics.LOADING},
+		{name: "read only", err: errors.New("READONLY You can't write against a read only replica."), class: ErrReadOnly, metric: metrics.READONLY},
+		{name: "immutable config", err: errors.New("ERR CONFIG SET failed (possibly related to argument 'daemonize') - can't set immutable config"), class: ErrConfigRefused, metric: metrics.CONFIG_REFUSED},
+		{name: "protected config", err: errors.New("ERR CONFIG SET failed (possibly related to argument 'enable-debug-command') - can't set protected config"), class: ErrConfigRefused, metric: metrics.CONFIG_REFUSED},
+		{name: "unknown config", err: errors.New("ERR Unknown option or number of arguments for CONFIG SET - 'rename-command'"), class: ErrConfigRefused, metric: metrics.CONFIG_REFUSED},
+		{name: "unsupported config", err: errors.New("ERR Unsupported CONFIG parameter: loadmodule"), class: ErrConfigRefused, metric: metrics.CONFIG_REFUSED},
+		{name: "other", err: errors.New("ERR unknown command"), metric: "MISC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := classifyError(test.err)
+			assert.Equal(test.err.Error(), err.Error())
+			assert.ErrorIs(err, test.err)
+			if test.class != nil {
+				assert.ErrorIs(err, test.class)
+			}
+			assert.Equal(test.metric, getRedisError(err))
+		})
+	}
+
+	assert.NoError(t, classifyError(nil))
+}
+
+func TestIsTransient(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isTransient(classifyError(errors.New("dial tcp 10.0.0.1:6379: i/o timeout"))))
+	assert.True(isTransient(classifyError(errors.New("LOADING Redis is loading the dataset in memory"))))
+	assert.False(isTransient(classifyError(errors.New("WRONGPASS invalid username-password pair"))))
+	assert.False(isTransient(classifyError(errors.New("READONLY You can't write against a read only replica."))))
+
+	assert.True(isConnectionError(classifyError(errors.New("connect: connection refused"))))
+	assert.True(isConnectionError(classifyError(errors.New("tls: failed to verify certificate: x509: certificate signed by unknown authority"))))
+	assert.False(isConnectionError(classifyError(errors.New("LOADING Redis is loading the dataset in memory"))))
+}
@@ -367,7 +367,7 @@ func (c *clients) testRedisMaster(t *testing.T, currentNamespace string) {
 
 	for _, pod := range redisPodList.Items {
 		ip := pod.Status.PodIP
-		if ok, _ := c.redisClient.IsMaster(ip, "6379", testPass); ok {
+		if ok, _ := c.redisClient.IsMaster(context.TODO(), ip, "6379", "", testPass, nil); ok {
 			masters = append(masters, ip)
 		}
 	}
@@ -400,7 +400,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T, currentNamespace string,
 
 	for _, pod := range sentinelPodList.Items {
 		ip := pod.Status.PodIP
-		master, _, _ := c.redisClient.GetSentinelMonitor(ip, masterName)
+		master, _, _ := c.redisClient.GetSentinelMonitor(context.TODO(), ip, masterName, nil)
 		masters = append(masters, master)
 	}
 
@@ -408,7 +408,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T, currentNamespace string,
 		assert.Equal(masters[0], masterIP, "all master ip monitoring should equal")
 	}
 
-	isMaster, err := c.redisClient.IsMaster(masters[0], "6379", testPass)
+	isMaster, err := c.redisClient.IsMaster(context.TODO(), masters[0], "6379", "", testPass, nil)
 	assert.NoError(err)
 	assert.True(isMaster, "Sentinel should monitor the Redis master")
 }